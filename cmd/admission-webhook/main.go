@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// admission-webhook serves a validating admission webhook rejecting invalid
+// Ingresses, BackendConfigs and FrontendConfigs when they are applied, and
+// the conversion webhook of the BackendConfig CRD.
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	flag "github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/ingress-gce/cmd/glbc/app"
+	"k8s.io/ingress-gce/pkg/admission"
+	"k8s.io/ingress-gce/pkg/backendconfig"
+	"k8s.io/ingress-gce/pkg/flags"
+	_ "k8s.io/ingress-gce/pkg/klog"
+	"k8s.io/ingress-gce/pkg/version"
+	"k8s.io/klog/v2"
+)
+
+var (
+	port        = flag.Int("webhook-port", 8443, "Port the webhook listens on.")
+	tlsCertFile = flag.String("tls-cert-file", "", "File containing the x509 certificate of the webhook server.")
+	tlsKeyFile  = flag.String("tls-private-key-file", "", "File containing the x509 private key matching --tls-cert-file.")
+)
+
+func main() {
+	flags.Register()
+	flag.Parse()
+
+	if flags.F.Version {
+		fmt.Printf("Controller version: %s\n", version.Version)
+		os.Exit(0)
+	}
+
+	rootLogger := klog.TODO()
+	rootLogger.V(0).Info("Starting admission webhook", "version", version.Version)
+	defer klog.Flush()
+
+	if *tlsCertFile == "" || *tlsKeyFile == "" {
+		klog.Fatalf("--tls-cert-file and --tls-private-key-file are required")
+	}
+
+	kubeConfig, err := app.NewKubeConfig(rootLogger)
+	if err != nil {
+		klog.Fatalf("Failed to create kubernetes client config: %v", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		klog.Fatalf("Failed to create kubernetes client: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(admission.ValidatePath, admission.NewHandler(admission.NewValidator(kubeClient), rootLogger))
+	mux.Handle(backendconfig.ConversionPath, backendconfig.NewConversionHandler(rootLogger))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rootLogger.V(0).Info("Running webhook server", "port", *port)
+	klog.Fatal(http.ListenAndServeTLS(fmt.Sprintf(":%d", *port), *tlsCertFile, *tlsKeyFile, mux))
+}
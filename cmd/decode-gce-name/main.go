@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// decode-gce-name maps the names of GCE resources created by the controllers
+// back to the Kubernetes objects owning them.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/namer"
+)
+
+var (
+	description = flag.String("description", "", "Optional, the description of the resource, used to resolve its owner. Only valid with a single name.")
+	jsonOutput  = flag.Bool("json", false, "Print the decoded names as JSON.")
+	prefix      = flag.String("prefix", "k8s", "The prefix of the names created by the controllers, as passed to namer.NewNamerWithPrefix.")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] NAME...\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 || (*description != "" && flag.NArg() > 1) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var decoded []*namer.DecodedName
+	failed := false
+	for _, name := range flag.Args() {
+		d, err := utils.DecodeResourceName(name, *prefix, *description)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			failed = true
+			continue
+		}
+		decoded = append(decoded, d)
+	}
+
+	if *jsonOutput {
+		out, err := json.MarshalIndent(decoded, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding the decoded names: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(out))
+	} else {
+		for _, d := range decoded {
+			fmt.Println(format(d))
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// format returns the non empty fields of a decoded name on one line.
+func format(d *namer.DecodedName) string {
+	fields := []string{d.ResourceName + ":"}
+	for _, f := range []struct{ key, value string }{
+		{"scheme", d.Scheme},
+		{"resource", d.Resource},
+		{"kind", d.Kind},
+		{"namespace", d.Namespace},
+		{"name", d.Name},
+		{"descriptor", d.Descriptor},
+		{"cluster-uid", d.ClusterUID},
+		{"port", d.Port},
+		{"protocol", d.Protocol},
+		{"hash", d.Hash},
+	} {
+		if f.value != "" {
+			fields = append(fields, f.key+"="+f.value)
+		}
+	}
+	if d.IPv6 {
+		fields = append(fields, "ipv6=true")
+	}
+	return strings.Join(fields, " ")
+}
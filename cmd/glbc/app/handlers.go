@@ -30,7 +30,9 @@ import (
 	"k8s.io/klog/v2"
 
 	"k8s.io/ingress-gce/pkg/context"
+	"k8s.io/ingress-gce/pkg/export"
 	"k8s.io/ingress-gce/pkg/flags"
+	"k8s.io/ingress-gce/pkg/utils/namer"
 	"k8s.io/ingress-gce/pkg/version"
 )
 
@@ -45,6 +47,19 @@ func RunHTTPServer(healthChecker func() context.HealthCheckResults, logger klog.
 	klog.Fatal(http.ListenAndServe(fmt.Sprintf(":%v", flags.F.HealthzPort), nil))
 }
 
+// RunExportServer serves the GCE resources of Ingresses and L4 Services, see
+// the export package. The export has no authentication, so it is served on
+// localhost only, to be reached e.g. through kubectl port-forward.
+func RunExportServer(ctx *context.ControllerContext, logger klog.Logger) {
+	exporter := export.NewExporter(ctx.Cloud, ctx.ClusterNamer, namer.NewFrontendNamerFactory(ctx.ClusterNamer, ctx.KubeSystemUID, logger), ctx.L4Namer, logger)
+	mux := http.NewServeMux()
+	mux.Handle(export.PathPrefix, export.NewHandler(exporter, ctx.IngressInformer.GetIndexer(), ctx.ServiceInformer.GetIndexer(), logger))
+
+	addr := fmt.Sprintf("127.0.0.1:%v", flags.F.ResourceExportPort)
+	logger.V(0).Info("Running resource export server", "address", addr)
+	klog.Fatal(http.ListenAndServe(addr, mux))
+}
+
 func RunSIGTERMHandler(closeStopCh func(), logger klog.Logger) {
 	// Multiple SIGTERMs will get dropped
 	signalChan := make(chan os.Signal, 1)
@@ -29,6 +29,7 @@ import (
 	flag "github.com/spf13/pflag"
 	crdclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	clientset "k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
@@ -40,6 +41,8 @@ import (
 	backendconfigclient "k8s.io/ingress-gce/pkg/backendconfig/client/clientset/versioned"
 	"k8s.io/ingress-gce/pkg/frontendconfig"
 	frontendconfigclient "k8s.io/ingress-gce/pkg/frontendconfig/client/clientset/versioned"
+	"k8s.io/ingress-gce/pkg/gcsbackend"
+	gcsbackendclient "k8s.io/ingress-gce/pkg/gcsbackend/client/clientset/versioned"
 	"k8s.io/ingress-gce/pkg/ingparams"
 	ingparamsclient "k8s.io/ingress-gce/pkg/ingparams/client/clientset/versioned"
 	"k8s.io/ingress-gce/pkg/instancegroups"
@@ -123,6 +126,11 @@ func main() {
 	// TODO(rramkumar): Reuse this CRD handler for other CRD's coming.
 	crdHandler := crd.NewCRDHandler(crdClient, rootLogger)
 	backendConfigCRDMeta := backendconfig.CRDMeta()
+	if flags.F.BackendConfigConversionService != "" {
+		if err := backendconfig.SetConversionWebhook(backendConfigCRDMeta, flags.F.BackendConfigConversionService, flags.F.BackendConfigConversionCAFile); err != nil {
+			klog.Fatalf("Failed to configure the BackendConfig conversion webhook: %v", err)
+		}
+	}
 	if _, err := crdHandler.EnsureCRD(backendConfigCRDMeta, true); err != nil {
 		klog.Fatalf("Failed to ensure BackendConfig CRD: %v", err)
 	}
@@ -145,6 +153,19 @@ func main() {
 		}
 	}
 
+	var gcsBackendClient gcsbackendclient.Interface
+	if flags.F.EnableGCSBackends {
+		gcsBackendCRDMeta := gcsbackend.CRDMeta()
+		if _, err := crdHandler.EnsureCRD(gcsBackendCRDMeta, true); err != nil {
+			klog.Fatalf("Failed to ensure GCSBackend CRD: %v", err)
+		}
+
+		gcsBackendClient, err = gcsbackendclient.NewForConfig(kubeConfig)
+		if err != nil {
+			klog.Fatalf("Failed to create GCSBackend client: %v", err)
+		}
+	}
+
 	var firewallCRClient firewallcrclient.Interface
 	if flags.F.EnableFirewallCR {
 		firewallCRClient, err = firewallcrclient.NewForConfig(kubeConfig)
@@ -185,6 +206,14 @@ func main() {
 		}
 	}
 
+	var gatewayClient dynamic.Interface
+	if flags.F.EnableGatewayAPI {
+		gatewayClient, err = dynamic.NewForConfig(kubeConfig)
+		if err != nil {
+			klog.Fatalf("Failed to create Gateway API client: %v", err)
+		}
+	}
+
 	ingClassEnabled := flags.F.EnableIngressGAFields && app.IngressClassEnabled(kubeClient, rootLogger)
 	var ingParamsClient ingparamsclient.Interface
 	if ingClassEnabled {
@@ -243,7 +272,10 @@ func main() {
 		EnableMultinetworking:         flags.F.EnableMultiNetworking,
 		EnableIngressRegionalExternal: flags.F.EnableIngressRegionalExternal,
 	}
-	ctx := ingctx.NewControllerContext(kubeConfig, kubeClient, backendConfigClient, frontendConfigClient, firewallCRClient, svcNegClient, ingParamsClient, svcAttachmentClient, networkClient, cloud, namer, kubeSystemUID, ctxConfig, rootLogger)
+	ctx := ingctx.NewControllerContext(kubeConfig, kubeClient, backendConfigClient, frontendConfigClient, firewallCRClient, svcNegClient, ingParamsClient, svcAttachmentClient, networkClient, gcsBackendClient, gatewayClient, cloud, namer, kubeSystemUID, ctxConfig, rootLogger)
+	if flags.F.EnableResourceExport {
+		go app.RunExportServer(ctx, rootLogger)
+	}
 	go app.RunHTTPServer(ctx.HealthCheck, rootLogger)
 
 	if !flags.F.LeaderElection.LeaderElect {
@@ -353,9 +385,11 @@ func runControllers(ctx *ingctx.ControllerContext, option runOption, logger klog
 		if !flags.F.EnableFirewallCR && flags.F.DisableFWEnforcement {
 			klog.Fatalf("We can only disable the ingress controller FW enforcement when enabling the FW CR")
 		}
-		fwc := firewalls.NewFirewallController(ctx, flags.F.NodePortRanges.Values(), flags.F.EnableFirewallCR, flags.F.DisableFWEnforcement, ctx.EnableIngressRegionalExternal, stopCh, logger)
-		runWithWg(fwc.Run, wg)
-		logger.V(0).Info("firewall controller started")
+		if !skipInDryRun("firewall controller", logger) {
+			fwc := firewalls.NewFirewallController(ctx, flags.F.NodePortRanges.Values(), flags.F.EnableFirewallCR, flags.F.DisableFWEnforcement, ctx.EnableIngressRegionalExternal, stopCh, logger)
+			runWithWg(fwc.Run, wg)
+			logger.V(0).Info("firewall controller started")
+		}
 	}
 
 	if ctx.EnableASMConfigMap {
@@ -370,7 +404,7 @@ func runControllers(ctx *ingctx.ControllerContext, option runOption, logger klog
 		logger.V(0).Info("L4 controller started")
 	}
 
-	if flags.F.EnablePSC {
+	if flags.F.EnablePSC && !skipInDryRun("PSC controller", logger) {
 		pscController := psc.NewController(ctx, stopCh, logger)
 		runWithWg(pscController.Run, wg)
 		logger.V(0).Info("PSC Controller started")
@@ -386,7 +420,7 @@ func runControllers(ctx *ingctx.ControllerContext, option runOption, logger klog
 
 	ctx.Start(stopCh)
 
-	if flags.F.EnableIGController {
+	if flags.F.EnableIGController && !skipInDryRun("instance group controller", logger) {
 		igControllerParams := &instancegroups.ControllerConfig{
 			NodeInformer: ctx.NodeInformer,
 			IGManager:    ctx.InstancePool,
@@ -435,6 +469,9 @@ func runControllers(ctx *ingctx.ControllerContext, option runOption, logger klog
 // If GateNEGByLock is true, NEG controller is run in the leader election.
 // Otherwise, it is run with other controllers together.
 func runNEGController(ctx *ingctx.ControllerContext, id string, option runOption, logger klog.Logger) {
+	if skipInDryRun("negController", logger) {
+		return
+	}
 	negController := createNEGController(ctx, option.stopCh, logger)
 	if !option.leaderElect {
 		runWithWg(negController.Run, option.wg)
@@ -567,6 +604,16 @@ func createNEGController(ctx *ingctx.ControllerContext, stopCh <-chan struct{},
 	return negController
 }
 
+// skipInDryRun returns true if the controller must not run because of
+// --dry-run. Only the Ingress and L4 controllers can report the changes they
+// would make, the other controllers change GCE resources directly.
+func skipInDryRun(name string, logger klog.Logger) bool {
+	if flags.F.DryRun {
+		logger.V(0).Info("Not starting controller in dry run mode", "controller", name)
+	}
+	return flags.F.DryRun
+}
+
 // runWithWg is a convenience wrapper that do a wg.Add(1), and runs the given
 // function in a goroutine with a deferred wg.Done().
 // We need to make sure wg.Add(1) when the counter is zero is executed before
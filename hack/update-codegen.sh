@@ -94,6 +94,20 @@ ${OPENAPI_PKG}/openapi-gen \
   --output-package k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1 \
   --go-header-file ${SCRIPT_ROOT}/boilerplate.go.txt
 
+echo "Performing code generation for GCSBackend CRD"
+${CODEGEN_PKG}/generate-groups.sh \
+  "deepcopy,client,informer,lister" \
+  k8s.io/ingress-gce/pkg/gcsbackend/client k8s.io/ingress-gce/pkg/apis \
+  "gcsbackend:v1beta1" \
+  --go-header-file ${SCRIPT_ROOT}/boilerplate.go.txt
+
+echo "Generating openapi for GCSBackend v1beta1"
+${OPENAPI_PKG}/openapi-gen \
+  --output-file-base zz_generated.openapi \
+  --input-dirs k8s.io/ingress-gce/pkg/apis/gcsbackend/v1beta1 \
+  --output-package k8s.io/ingress-gce/pkg/apis/gcsbackend/v1beta1 \
+  --go-header-file ${SCRIPT_ROOT}/boilerplate.go.txt
+
 echo "Performing code generation for ServiceNetworkEndpointGroup CRD"
 ${CODEGEN_PKG}/generate-groups.sh \
   "deepcopy,client,informer,lister" \
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
+	frontendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1"
+	"k8s.io/klog/v2"
+)
+
+// ValidatePath is the path of the validating webhook.
+const ValidatePath = "/validate"
+
+// Handler serves the AdmissionReviews of the validating webhook.
+type Handler struct {
+	validator *Validator
+	logger    klog.Logger
+}
+
+// NewHandler returns a Handler validating objects with the given Validator.
+func NewHandler(validator *Validator, logger klog.Logger) *Handler {
+	return &Handler{validator: validator, logger: logger}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	review := &admissionv1.AdmissionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+		http.Error(w, fmt.Sprintf("invalid AdmissionReview: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "AdmissionReview has no request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = h.review(review.Request)
+	review.Request = nil
+	out, err := json.Marshal(review)
+	if err != nil {
+		h.logger.Error(err, "Failed to encode AdmissionReview")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(out); err != nil {
+		h.logger.Error(err, "Failed to write admission response")
+	}
+}
+
+// review returns the response to an admission request. Objects of unknown
+// kinds are allowed.
+func (h *Handler) review(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	allowed := &admissionv1.AdmissionResponse{UID: req.UID, Allowed: true}
+	if req.Operation == admissionv1.Delete || len(req.Object.Raw) == 0 {
+		return allowed
+	}
+
+	// OldObject is only set on UPDATE. It is decoded into the same type as
+	// Object so that the validators can skip unchanged objects.
+	hasOld := req.Operation == admissionv1.Update && len(req.OldObject.Raw) != 0
+	var err error
+	switch req.Kind.Kind {
+	case "Ingress":
+		ing, old := &v1.Ingress{}, (*v1.Ingress)(nil)
+		if hasOld {
+			old = &v1.Ingress{}
+			err = json.Unmarshal(req.OldObject.Raw, old)
+		}
+		if err == nil {
+			err = json.Unmarshal(req.Object.Raw, ing)
+		}
+		if err == nil {
+			err = utilerrors.NewAggregate(h.validator.ValidateIngress(ing, old))
+		}
+	case "BackendConfig":
+		// v1beta1 BackendConfigs are a subset of v1 and are decoded as such.
+		beConfig, old := &backendconfigv1.BackendConfig{}, (*backendconfigv1.BackendConfig)(nil)
+		if hasOld {
+			old = &backendconfigv1.BackendConfig{}
+			err = json.Unmarshal(req.OldObject.Raw, old)
+		}
+		if err == nil {
+			err = json.Unmarshal(req.Object.Raw, beConfig)
+		}
+		if err == nil {
+			err = h.validator.ValidateBackendConfig(beConfig, old)
+		}
+	case "FrontendConfig":
+		feConfig, old := &frontendconfigv1beta1.FrontendConfig{}, (*frontendconfigv1beta1.FrontendConfig)(nil)
+		if hasOld {
+			old = &frontendconfigv1beta1.FrontendConfig{}
+			err = json.Unmarshal(req.OldObject.Raw, old)
+		}
+		if err == nil {
+			err = json.Unmarshal(req.Object.Raw, feConfig)
+		}
+		if err == nil {
+			err = h.validator.ValidateFrontendConfig(feConfig, old)
+		}
+	default:
+		return allowed
+	}
+	if err == nil {
+		return allowed
+	}
+
+	h.logger.V(2).Info("Rejected object", "kind", req.Kind.Kind, "namespace", req.Namespace, "name", req.Name, "err", err)
+	return &admissionv1.AdmissionResponse{
+		UID:     req.UID,
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: err.Error(),
+			Reason:  metav1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+		},
+	}
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/ingress-gce/pkg/annotations"
+	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
+	frontendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1"
+	"k8s.io/ingress-gce/pkg/backendconfig"
+	"k8s.io/klog/v2"
+)
+
+func TestHandler(t *testing.T) {
+	prefix := v1.PathTypePrefix
+	ingress := func(ingAnnotations map[string]string, path string) *v1.Ingress {
+		return &v1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ing", Annotations: ingAnnotations},
+			Spec: v1.IngressSpec{
+				Rules: []v1.IngressRule{{
+					IngressRuleValue: v1.IngressRuleValue{HTTP: &v1.HTTPIngressRuleValue{
+						Paths: []v1.HTTPIngressPath{{
+							Path:     path,
+							PathType: &prefix,
+							Backend:  v1.IngressBackend{Service: &v1.IngressServiceBackend{Name: "svc", Port: v1.ServiceBackendPort{Number: 80}}},
+						}},
+					}},
+				}},
+			},
+		}
+	}
+	iapConfig := func(secretName string) *backendconfigv1.BackendConfig {
+		return &backendconfigv1.BackendConfig{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "config"},
+			Spec: backendconfigv1.BackendConfigSpec{
+				Iap: &backendconfigv1.IAPConfig{
+					Enabled:                true,
+					OAuthClientCredentials: &backendconfigv1.OAuthClientCredentials{SecretName: secretName},
+				},
+			},
+		}
+	}
+	badTTL := int64(86401)
+	deleting := func(ing *v1.Ingress) *v1.Ingress {
+		now := metav1.Now()
+		ing.DeletionTimestamp = &now
+		return ing
+	}
+
+	testCases := []struct {
+		desc      string
+		kind      string
+		operation admissionv1.Operation
+		object    runtime.Object
+		oldObject runtime.Object
+		allowed   bool
+	}{
+		{
+			desc:    "valid ingress",
+			kind:    "Ingress",
+			object:  ingress(nil, "/foo"),
+			allowed: true,
+		},
+		{
+			desc:    "ingress with invalid prefix path",
+			kind:    "Ingress",
+			object:  ingress(nil, "/foo/*"),
+			allowed: false,
+		},
+		{
+			desc:    "ingress with invalid route rules annotation",
+			kind:    "Ingress",
+			object:  ingress(map[string]string{annotations.RouteRulesKey: "{"}, "/foo"),
+			allowed: false,
+		},
+		{
+			desc: "ingress with both static ip annotations",
+			kind: "Ingress",
+			object: ingress(map[string]string{
+				annotations.GlobalStaticIPNameKey:   "global",
+				annotations.RegionalStaticIPNameKey: "regional",
+			}, "/foo"),
+			allowed: false,
+		},
+		{
+			desc:    "ingress of another controller",
+			kind:    "Ingress",
+			object:  ingress(map[string]string{annotations.IngressClassKey: "nginx"}, "/foo/*"),
+			allowed: true,
+		},
+		{
+			desc:      "deleted ingress",
+			kind:      "Ingress",
+			operation: admissionv1.Delete,
+			object:    ingress(nil, "/foo/*"),
+			allowed:   true,
+		},
+		{
+			desc:      "ingress being deleted",
+			kind:      "Ingress",
+			operation: admissionv1.Update,
+			object:    deleting(ingress(nil, "/foo/*")),
+			oldObject: ingress(nil, "/foo/*"),
+			allowed:   true,
+		},
+		{
+			desc:      "update of an invalid ingress which leaves its spec and annotations unchanged",
+			kind:      "Ingress",
+			operation: admissionv1.Update,
+			object:    ingress(map[string]string{annotations.RouteRulesKey: "{", "team": "shop"}, "/foo/*"),
+			oldObject: ingress(map[string]string{annotations.RouteRulesKey: "{"}, "/foo/*"),
+			allowed:   true,
+		},
+		{
+			desc:      "update to an invalid path",
+			kind:      "Ingress",
+			operation: admissionv1.Update,
+			object:    ingress(nil, "/foo/*"),
+			oldObject: ingress(nil, "/foo"),
+			allowed:   false,
+		},
+		{
+			desc:      "update to an invalid route rules annotation",
+			kind:      "Ingress",
+			operation: admissionv1.Update,
+			object:    ingress(map[string]string{annotations.RouteRulesKey: "{"}, "/foo"),
+			oldObject: ingress(nil, "/foo"),
+			allowed:   false,
+		},
+		{
+			desc:    "valid backend config",
+			kind:    "BackendConfig",
+			object:  iapConfig("oauth"),
+			allowed: true,
+		},
+		{
+			desc:    "backend config with missing secret",
+			kind:    "BackendConfig",
+			object:  iapConfig("missing"),
+			allowed: false,
+		},
+		{
+			desc:      "update of a backend config with missing secret which leaves its spec unchanged",
+			kind:      "BackendConfig",
+			operation: admissionv1.Update,
+			object:    iapConfig("missing"),
+			oldObject: iapConfig("missing"),
+			allowed:   true,
+		},
+		{
+			desc:      "update of a backend config to a missing secret",
+			kind:      "BackendConfig",
+			operation: admissionv1.Update,
+			object:    iapConfig("missing"),
+			oldObject: iapConfig("oauth"),
+			allowed:   false,
+		},
+		{
+			desc: "backend config with invalid session affinity",
+			kind: "BackendConfig",
+			object: &backendconfigv1.BackendConfig{
+				Spec: backendconfigv1.BackendConfigSpec{
+					SessionAffinity: &backendconfigv1.SessionAffinityConfig{AffinityCookieTtlSec: &badTTL},
+				},
+			},
+			allowed: false,
+		},
+		{
+			desc: "frontend config with invalid redirect",
+			kind: "FrontendConfig",
+			object: &frontendconfigv1beta1.FrontendConfig{
+				Spec: frontendconfigv1beta1.FrontendConfigSpec{
+					RedirectToHttps: &frontendconfigv1beta1.HttpsRedirectConfig{Enabled: true, ResponseCodeName: "301"},
+				},
+			},
+			allowed: false,
+		},
+		{
+			desc:    "unknown kind",
+			kind:    "Service",
+			object:  &corev1.Service{},
+			allowed: true,
+		},
+	}
+
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "oauth"},
+		Data: map[string][]byte{
+			backendconfig.OAuthClientIDKey:     []byte("id"),
+			backendconfig.OAuthClientSecretKey: []byte("secret"),
+		},
+	})
+	handler := NewHandler(NewValidator(kubeClient), klog.TODO())
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			raw, err := json.Marshal(tc.object)
+			if err != nil {
+				t.Fatalf("json.Marshal() = %v", err)
+			}
+			var oldRaw []byte
+			if tc.oldObject != nil {
+				if oldRaw, err = json.Marshal(tc.oldObject); err != nil {
+					t.Fatalf("json.Marshal() = %v", err)
+				}
+			}
+			operation := tc.operation
+			if operation == "" {
+				operation = admissionv1.Create
+			}
+			review := &admissionv1.AdmissionReview{
+				Request: &admissionv1.AdmissionRequest{
+					UID:       "uid",
+					Kind:      metav1.GroupVersionKind{Kind: tc.kind},
+					Operation: operation,
+					Object:    runtime.RawExtension{Raw: raw},
+					OldObject: runtime.RawExtension{Raw: oldRaw},
+				},
+			}
+			body, err := json.Marshal(review)
+			if err != nil {
+				t.Fatalf("json.Marshal() = %v", err)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, ValidatePath, bytes.NewReader(body)))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("ServeHTTP() returned status %d: %s", rec.Code, rec.Body.String())
+			}
+			got := &admissionv1.AdmissionReview{}
+			if err := json.Unmarshal(rec.Body.Bytes(), got); err != nil {
+				t.Fatalf("json.Unmarshal() = %v", err)
+			}
+			if got.Response == nil || got.Response.UID != "uid" {
+				t.Fatalf("Response = %+v, want a response for request uid", got.Response)
+			}
+			if got.Response.Allowed != tc.allowed {
+				t.Errorf("Response.Allowed = %t, want %t (result: %+v)", got.Response.Allowed, tc.allowed, got.Response.Result)
+			}
+			if !tc.allowed && (got.Response.Result == nil || got.Response.Result.Message == "") {
+				t.Errorf("Response.Result = %+v, want a message", got.Response.Result)
+			}
+		})
+	}
+}
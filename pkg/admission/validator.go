@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package admission validates Ingresses, BackendConfigs and FrontendConfigs
+// when they are written to the API server, with the checks the controllers
+// otherwise only run at sync time.
+package admission
+
+import (
+	"fmt"
+	"reflect"
+
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/ingress-gce/pkg/annotations"
+	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
+	frontendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1"
+	"k8s.io/ingress-gce/pkg/backendconfig"
+	"k8s.io/ingress-gce/pkg/controller/translator"
+	"k8s.io/ingress-gce/pkg/frontendconfig"
+	"k8s.io/ingress-gce/pkg/utils"
+)
+
+// Validator validates the objects handled by the controllers.
+type Validator struct {
+	kubeClient kubernetes.Interface
+}
+
+// NewValidator returns a Validator reading the Secrets referenced by
+// BackendConfigs with the given client.
+func NewValidator(kubeClient kubernetes.Interface) *Validator {
+	return &Validator{kubeClient: kubeClient}
+}
+
+// validatedIngressAnnotations are the annotations read by ValidateIngress.
+var validatedIngressAnnotations = []string{
+	annotations.IngressClassKey,
+	annotations.GlobalStaticIPNameKey,
+	annotations.RegionalStaticIPNameKey,
+	annotations.DriftPolicyKey,
+	annotations.AdoptResourcesKey,
+	annotations.RouteRulesKey,
+	annotations.PathActionsKey,
+	annotations.CustomErrorResponsesKey,
+}
+
+// ValidateIngress returns the errors of the annotations and paths of the
+// Ingress. old is the Ingress being updated, nil on creation. Ingresses of
+// other controllers, Ingresses being deleted and updates which change
+// neither the spec nor the validated annotations are not validated, so
+// that Ingresses created before the webhook can still be updated and
+// deleted.
+func (v *Validator) ValidateIngress(ing, old *v1.Ingress) []error {
+	if !utils.IsGLBCIngress(ing) || ing.DeletionTimestamp != nil {
+		return nil
+	}
+	if old != nil && reflect.DeepEqual(ing.Spec, old.Spec) && !annotationsChanged(ing, old, validatedIngressAnnotations) {
+		return nil
+	}
+
+	var errs []error
+	ingAnnotations := annotations.FromIngress(ing)
+	if _, err := ingAnnotations.StaticIPName(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := ingAnnotations.DriftPolicy(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, _, err := ingAnnotations.AdoptedResources(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := ingAnnotations.RouteRules(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := ingAnnotations.PathActions(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := ingAnnotations.CustomErrorResponses(); err != nil {
+		errs = append(errs, err)
+	}
+	return append(errs, translator.ValidatePaths(ing)...)
+}
+
+// ValidateBackendConfig returns an error if the BackendConfig is invalid.
+// The Secrets it references must exist. old is the BackendConfig being
+// updated, nil on creation. As for Ingresses, BackendConfigs being deleted
+// and updates which do not change the spec are not validated.
+func (v *Validator) ValidateBackendConfig(beConfig, old *backendconfigv1.BackendConfig) error {
+	if beConfig.DeletionTimestamp != nil || (old != nil && reflect.DeepEqual(beConfig.Spec, old.Spec)) {
+		return nil
+	}
+	// Validate fills in the credentials of the Secrets, which must not end up
+	// in the response.
+	if err := backendconfig.Validate(v.kubeClient, beConfig.DeepCopy(), nil); err != nil {
+		return fmt.Errorf("invalid BackendConfig %s/%s: %w", beConfig.Namespace, beConfig.Name, err)
+	}
+	return nil
+}
+
+// ValidateFrontendConfig returns an error if the FrontendConfig is invalid.
+// old is the FrontendConfig being updated, nil on creation. FrontendConfigs
+// being deleted and updates which do not change the spec are not validated.
+func (v *Validator) ValidateFrontendConfig(feConfig, old *frontendconfigv1beta1.FrontendConfig) error {
+	if feConfig.DeletionTimestamp != nil || (old != nil && reflect.DeepEqual(feConfig.Spec, old.Spec)) {
+		return nil
+	}
+	if err := frontendconfig.Validate(feConfig); err != nil {
+		return fmt.Errorf("invalid FrontendConfig %s/%s: %w", feConfig.Namespace, feConfig.Name, err)
+	}
+	return nil
+}
+
+// annotationsChanged returns true if any of the keys has a different value,
+// or is only set, on one of the objects.
+func annotationsChanged(obj, old metav1.Object, keys []string) bool {
+	objAnnotations, oldAnnotations := obj.GetAnnotations(), old.GetAnnotations()
+	for _, key := range keys {
+		val, ok := objAnnotations[key]
+		oldVal, oldOk := oldAnnotations[key]
+		if ok != oldOk || val != oldVal {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotations
+
+import (
+	"encoding/json"
+	"errors"
+
+	v1 "k8s.io/api/networking/v1"
+)
+
+// ErrCustomErrorResponsesInvalidJSON is returned when the
+// CustomErrorResponsesKey annotation cannot be parsed.
+var ErrCustomErrorResponsesInvalidJSON = errors.New("custom error responses annotation is invalid json")
+
+// CustomErrorResponsePolicy describes the error pages the load balancer
+// returns instead of the error responses of the backends. Exactly one of
+// Service and BackendBucket must be set.
+type CustomErrorResponsePolicy struct {
+	// Service serves the error content.
+	Service *v1.IngressServiceBackend `json:"service,omitempty"`
+	// BackendBucket is the name of an existing backend bucket serving the
+	// error content. Backend buckets are only supported by the "gce" class.
+	BackendBucket string `json:"backendBucket,omitempty"`
+	// Rules maps response codes to the error content.
+	Rules []ErrorResponseRule `json:"rules"`
+}
+
+// ErrorResponseRule maps response codes to a path on the error service.
+type ErrorResponseRule struct {
+	// MatchResponseCodes are the response codes the rule applies to. Codes
+	// are either a single code between 400 and 599, "4xx" or "5xx".
+	MatchResponseCodes []string `json:"matchResponseCodes"`
+	// Path is the path of the error content, such as "/errors/404.html".
+	Path string `json:"path"`
+	// OverrideResponseCode, if set, replaces the response code returned to
+	// the client.
+	OverrideResponseCode int64 `json:"overrideResponseCode,omitempty"`
+}
+
+// CustomErrorResponses returns the custom error response policy configured
+// on the Ingress. Nil is returned if the annotation is not set.
+func (ing *Ingress) CustomErrorResponses() (*CustomErrorResponsePolicy, error) {
+	val, ok := ing.v[CustomErrorResponsesKey]
+	if !ok {
+		return nil, nil
+	}
+
+	policy := &CustomErrorResponsePolicy{}
+	if err := json.Unmarshal([]byte(val), policy); err != nil {
+		return nil, ErrCustomErrorResponsesInvalidJSON
+	}
+	return policy, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotations
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCustomErrorResponses(t *testing.T) {
+	for _, tc := range []struct {
+		desc    string
+		ing     *v1.Ingress
+		want    *CustomErrorResponsePolicy
+		wantErr error
+	}{
+		{
+			desc: "No annotation",
+			ing:  &v1.Ingress{},
+		},
+		{
+			desc: "Invalid json",
+			ing: &v1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{CustomErrorResponsesKey: `{"rules":`},
+				},
+			},
+			wantErr: ErrCustomErrorResponsesInvalidJSON,
+		},
+		{
+			desc: "Service with 4xx and 5xx rules",
+			ing: &v1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						CustomErrorResponsesKey: `{"service":{"name":"errors","port":{"number":80}},"rules":[{"matchResponseCodes":["4xx"],"path":"/404.html","overrideResponseCode":404},{"matchResponseCodes":["5xx"],"path":"/500.html"}]}`,
+					},
+				},
+			},
+			want: &CustomErrorResponsePolicy{
+				Service: &v1.IngressServiceBackend{Name: "errors", Port: v1.ServiceBackendPort{Number: 80}},
+				Rules: []ErrorResponseRule{
+					{MatchResponseCodes: []string{"4xx"}, Path: "/404.html", OverrideResponseCode: 404},
+					{MatchResponseCodes: []string{"5xx"}, Path: "/500.html"},
+				},
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := FromIngress(tc.ing).CustomErrorResponses()
+			if err != tc.wantErr {
+				t.Fatalf("CustomErrorResponses() = _, %v, want %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("CustomErrorResponses() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
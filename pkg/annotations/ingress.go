@@ -17,8 +17,11 @@ limitations under the License.
 package annotations
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 
 	v1 "k8s.io/api/networking/v1"
 )
@@ -87,6 +90,101 @@ const (
 	//     networking.gke.io/v1beta1.FrontendConfig: 'my-frontendconfig'
 	FrontendConfigKey = "networking.gke.io/v1beta1.FrontendConfig"
 
+	// RouteRulesKey is the annotation key used to configure advanced routing
+	// on request headers and query parameters. The value must be a JSON list
+	// of RouteRule. Hosts without route rules keep plain path rules.
+	// Examples:
+	// - annotations:
+	//     networking.gke.io/route-rules: '[{"host":"foo.com","priority":1,"matches":[{"prefixMatch":"/","headerMatches":[{"headerName":"x-canary","exactMatch":"true"}]}],"service":{"name":"canary","port":{"number":80}}}]'
+	RouteRulesKey = "networking.gke.io/route-rules"
+
+	// PathActionsKey is the annotation key used to attach route actions, such
+	// as weighted traffic splitting, URL rewrites, request mirroring, retries or
+	// fault injection, to the paths or hosts of an Ingress. The value must be a
+	// JSON list of PathAction. Retry and fault injection policies are not
+	// supported by the classic external load balancer of the "gce" class.
+	// Examples:
+	// - annotations:
+	//     networking.gke.io/path-actions: '[{"host":"foo.com","path":"/app","action":{"weightedBackends":[{"service":{"name":"app","port":{"number":80}},"weight":90},{"service":{"name":"app-canary","port":{"number":80}},"weight":10}]}}]'
+	// - annotations:
+	//     networking.gke.io/path-actions: '[{"host":"foo.com","path":"/legacy/","action":{"urlRewrite":{"pathPrefixRewrite":"/","hostRewrite":"legacy.internal"}}}]'
+	// - annotations:
+	//     networking.gke.io/path-actions: '[{"host":"foo.com","action":{"requestMirrorPolicy":{"service":{"name":"app-v2","port":{"number":80}}}}}]'
+	// - annotations:
+	//     networking.gke.io/path-actions: '[{"host":"foo.com","path":"/api","action":{"retryPolicy":{"retryConditions":["5xx"],"numRetries":3,"perTryTimeout":"2s"},"faultInjectionPolicy":{"abort":{"httpStatus":503,"percentage":5}}}}]'
+	PathActionsKey = "networking.gke.io/path-actions"
+
+	// CustomErrorResponsesKey is the annotation key used to replace the error
+	// responses of the backends with custom error pages served by a Service or
+	// a backend bucket. The value must be a JSON CustomErrorResponsePolicy.
+	// The policy applies to all hosts of the Ingress.
+	// Examples:
+	// - annotations:
+	//     networking.gke.io/custom-error-responses: '{"service":{"name":"errors","port":{"number":80}},"rules":[{"matchResponseCodes":["4xx"],"path":"/404.html","overrideResponseCode":404},{"matchResponseCodes":["5xx"],"path":"/500.html"}]}'
+	// - annotations:
+	//     networking.gke.io/custom-error-responses: '{"backendBucket":"error-pages","rules":[{"matchResponseCodes":["5xx"],"path":"/500.html"}]}'
+	CustomErrorResponsesKey = "networking.gke.io/custom-error-responses"
+
+	// DriftPolicyKey is the annotation key used to choose how the controller
+	// handles out-of-band changes to the GCE resources of an Ingress when drift
+	// detection is enabled. With "auto-heal", the default, the changes are
+	// reported and reverted. With "report-only" the changes to the frontend,
+	// and to the backend services only used by report-only Ingresses, are
+	// reported and left in place until the desired state of the Ingress
+	// changes. Deleted backend services are always recreated.
+	// Examples:
+	// - annotations:
+	//     networking.gke.io/drift-policy: report-only
+	DriftPolicyKey = "networking.gke.io/drift-policy"
+	// DriftPolicyAutoHeal reverts out-of-band changes on the next sync.
+	DriftPolicyAutoHeal = "auto-heal"
+	// DriftPolicyReportOnly only reports out-of-band changes.
+	DriftPolicyReportOnly = "report-only"
+
+	// IngressGroupKey is the annotation key used to share one load balancer
+	// between several Ingresses, possibly in different namespaces, when
+	// ingress groups are enabled. The rules of all Ingresses with the same
+	// group name are merged into one URL map. When two Ingresses of a group
+	// define the same rule, the rule of the oldest Ingress is used.
+	// Examples:
+	// - annotations:
+	//     networking.gke.io/ingress-group: shop
+	IngressGroupKey = "networking.gke.io/ingress-group"
+
+	// PauseReconciliationKey is the annotation key used to pause the
+	// reconciliation of the load balancer of an Ingress or of a Service of
+	// type LoadBalancer, e.g. while it is patched manually. The controllers
+	// do not change or garbage collect the GCE resources of a paused object,
+	// and do not remove its finalizers, until the annotation is removed.
+	// The NEG syncers of a paused Service keep updating the endpoints of its
+	// existing NEGs, so that the load balancer keeps serving the pods.
+	// Examples:
+	// - annotations:
+	//     networking.gke.io/pause-reconciliation: "true"
+	PauseReconciliationKey = "networking.gke.io/pause-reconciliation"
+
+	// DeletionProtectionKey is the annotation key used to protect the load
+	// balancer of an Ingress or of a Service of type LoadBalancer from
+	// deletion. While it is set, the forwarding rules and addresses of the
+	// object are kept and its finalizer is not removed, even if the object is
+	// deleted or changes of class or scope.
+	// Examples:
+	// - annotations:
+	//     networking.gke.io/deletion-protection: "true"
+	DeletionProtectionKey = "networking.gke.io/deletion-protection"
+
+	// AdoptResourcesKey is the annotation key used to adopt existing GCE load
+	// balancer resources, e.g. built with Terraform, into an Ingress using the
+	// v2 naming scheme. The listed resources are used instead of creating new
+	// ones, and are managed and deleted with the Ingress from then on. The
+	// other resources of the load balancer are created as usual. Names
+	// following the naming schemes of the controllers cannot be adopted.
+	// Once adopted, a resource must stay listed until the Ingress is deleted.
+	// Examples:
+	// - annotations:
+	//     networking.gke.io/adopt-resources: '{"urlMap":"web","targetHttpsProxy":"web-https","httpsForwardingRule":"web-https"}'
+	AdoptResourcesKey = "networking.gke.io/adopt-resources"
+
 	// UrlMapKey is the annotation key used by controller to record GCP URL map.
 	UrlMapKey = StatusPrefix + "/url-map"
 	// UrlMapKey is the annotation key used by controller to record GCP URL map used for Https Redirects only.
@@ -107,6 +205,9 @@ const (
 	SSLCertKey = StatusPrefix + "/ssl-cert"
 	// StaticIPKey is the annotation key used by controller to record GCP static ip.
 	StaticIPKey = StatusPrefix + "/static-ip"
+	// AdoptedResourcesKey is the annotation key used by controller to record
+	// the GCE resources adopted with the AdoptResourcesKey annotation.
+	AdoptedResourcesKey = StatusPrefix + "/adopted-resources"
 )
 
 // Ingress represents ingress annotations.
@@ -199,6 +300,83 @@ func (ing *Ingress) SuppressFirewallXPNError() bool {
 	return v
 }
 
+// DriftPolicy returns the drift policy of the Ingress, DriftPolicyAutoHeal if
+// not set.
+func (ing *Ingress) DriftPolicy() (string, error) {
+	val, ok := ing.v[DriftPolicyKey]
+	if !ok {
+		return DriftPolicyAutoHeal, nil
+	}
+	switch val {
+	case DriftPolicyAutoHeal, DriftPolicyReportOnly:
+		return val, nil
+	}
+	return DriftPolicyAutoHeal, fmt.Errorf("invalid %s annotation %q, must be %q or %q", DriftPolicyKey, val, DriftPolicyAutoHeal, DriftPolicyReportOnly)
+}
+
+// IngressGroup returns the name of the ingress group of the Ingress, and
+// false if the Ingress does not belong to a group.
+func (ing *Ingress) IngressGroup() (string, bool) {
+	val, ok := ing.v[IngressGroupKey]
+	if !ok || val == "" {
+		return "", false
+	}
+	return val, true
+}
+
+// ReconciliationPaused returns true if the reconciliation of the Ingress is
+// paused.
+func (ing *Ingress) ReconciliationPaused() bool {
+	v, err := strconv.ParseBool(ing.v[PauseReconciliationKey])
+	return err == nil && v
+}
+
+// AdoptedResources are the names of the existing GCE resources adopted by an
+// Ingress, set with the AdoptResourcesKey annotation.
+type AdoptedResources struct {
+	URLMap              string `json:"urlMap,omitempty"`
+	TargetHTTPProxy     string `json:"targetHttpProxy,omitempty"`
+	TargetHTTPSProxy    string `json:"targetHttpsProxy,omitempty"`
+	HTTPForwardingRule  string `json:"httpForwardingRule,omitempty"`
+	HTTPSForwardingRule string `json:"httpsForwardingRule,omitempty"`
+}
+
+// AdoptedResources returns the GCE resources adopted by the Ingress, and
+// false if the Ingress does not adopt any.
+func (ing *Ingress) AdoptedResources() (*AdoptedResources, bool, error) {
+	return ing.adoptedResources(AdoptResourcesKey)
+}
+
+// AdoptedResourcesStatus returns the GCE resources adopted by the last sync
+// of the Ingress, and false if it did not adopt any.
+func (ing *Ingress) AdoptedResourcesStatus() (*AdoptedResources, bool, error) {
+	return ing.adoptedResources(AdoptedResourcesKey)
+}
+
+func (ing *Ingress) adoptedResources(key string) (*AdoptedResources, bool, error) {
+	val, ok := ing.v[key]
+	if !ok {
+		return nil, false, nil
+	}
+	var res AdoptedResources
+	decoder := json.NewDecoder(strings.NewReader(val))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&res); err != nil {
+		return nil, true, fmt.Errorf("invalid %s annotation: %w", key, err)
+	}
+	if res == (AdoptedResources{}) {
+		return nil, true, fmt.Errorf("invalid %s annotation: no resources listed", key)
+	}
+	return &res, true, nil
+}
+
+// DeletionProtected returns true if the load balancer of the Ingress is
+// protected from deletion.
+func (ing *Ingress) DeletionProtected() bool {
+	v, err := strconv.ParseBool(ing.v[DeletionProtectionKey])
+	return err == nil && v
+}
+
 func (ing *Ingress) FrontendConfig() string {
 	val, ok := ing.v[FrontendConfigKey]
 	if !ok {
@@ -17,8 +17,10 @@ limitations under the License.
 package annotations
 
 import (
+	"reflect"
 	"testing"
 
+	apiv1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -92,3 +94,140 @@ func TestIngress(t *testing.T) {
 		}
 	}
 }
+
+func TestDriftPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		desc        string
+		annotations map[string]string
+		want        string
+		wantErr     bool
+	}{
+		{
+			desc: "not set",
+			want: DriftPolicyAutoHeal,
+		},
+		{
+			desc:        "auto-heal",
+			annotations: map[string]string{DriftPolicyKey: "auto-heal"},
+			want:        DriftPolicyAutoHeal,
+		},
+		{
+			desc:        "report-only",
+			annotations: map[string]string{DriftPolicyKey: "report-only"},
+			want:        DriftPolicyReportOnly,
+		},
+		{
+			desc:        "invalid",
+			annotations: map[string]string{DriftPolicyKey: "ignore"},
+			want:        DriftPolicyAutoHeal,
+			wantErr:     true,
+		},
+	} {
+		ing := FromIngress(&v1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}})
+		got, err := ing.DriftPolicy()
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: DriftPolicy() err = %v, wantErr = %v", tc.desc, err, tc.wantErr)
+		}
+		if got != tc.want {
+			t.Errorf("%s: DriftPolicy() = %q, want %q", tc.desc, got, tc.want)
+		}
+	}
+}
+
+func TestReconciliationPaused(t *testing.T) {
+	for val, want := range map[string]bool{
+		"":      false,
+		"true":  true,
+		"false": false,
+		"yes":   false,
+	} {
+		ann := map[string]string{}
+		if val != "" {
+			ann[PauseReconciliationKey] = val
+		}
+		ing := FromIngress(&v1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: ann}})
+		if got := ing.ReconciliationPaused(); got != want {
+			t.Errorf("Ingress.ReconciliationPaused() with %q = %v, want %v", val, got, want)
+		}
+		svc := FromService(&apiv1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: ann}})
+		if got := svc.ReconciliationPaused(); got != want {
+			t.Errorf("Service.ReconciliationPaused() with %q = %v, want %v", val, got, want)
+		}
+	}
+}
+
+func TestDeletionProtected(t *testing.T) {
+	for val, want := range map[string]bool{
+		"":      false,
+		"true":  true,
+		"false": false,
+		"yes":   false,
+	} {
+		ann := map[string]string{}
+		if val != "" {
+			ann[DeletionProtectionKey] = val
+		}
+		ing := FromIngress(&v1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: ann}})
+		if got := ing.DeletionProtected(); got != want {
+			t.Errorf("Ingress.DeletionProtected() with %q = %v, want %v", val, got, want)
+		}
+		svc := FromService(&apiv1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: ann}})
+		if got := svc.DeletionProtected(); got != want {
+			t.Errorf("Service.DeletionProtected() with %q = %v, want %v", val, got, want)
+		}
+	}
+}
+
+func TestAdoptedResources(t *testing.T) {
+	for _, tc := range []struct {
+		desc      string
+		val       *string
+		want      *AdoptedResources
+		wantFound bool
+		wantErr   bool
+	}{
+		{
+			desc: "not set",
+		},
+		{
+			desc:      "url map and https frontend",
+			val:       strPtr(`{"urlMap":"web","targetHttpsProxy":"web-https","httpsForwardingRule":"web-https"}`),
+			want:      &AdoptedResources{URLMap: "web", TargetHTTPSProxy: "web-https", HTTPSForwardingRule: "web-https"},
+			wantFound: true,
+		},
+		{
+			desc:      "unknown resource",
+			val:       strPtr(`{"urlMap":"web","backendService":"web"}`),
+			wantFound: true,
+			wantErr:   true,
+		},
+		{
+			desc:      "no resources",
+			val:       strPtr(`{}`),
+			wantFound: true,
+			wantErr:   true,
+		},
+		{
+			desc:      "invalid json",
+			val:       strPtr(`web`),
+			wantFound: true,
+			wantErr:   true,
+		},
+	} {
+		ann := map[string]string{}
+		if tc.val != nil {
+			ann[AdoptResourcesKey] = *tc.val
+		}
+		got, found, err := FromIngress(&v1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: ann}}).AdoptedResources()
+		if (err != nil) != tc.wantErr || found != tc.wantFound {
+			t.Errorf("%s: AdoptedResources() = _, %v, %v, want found = %v, wantErr = %v", tc.desc, found, err, tc.wantFound, tc.wantErr)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: AdoptedResources() = %+v, want %+v", tc.desc, got, tc.want)
+		}
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
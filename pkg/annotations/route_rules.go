@@ -0,0 +1,200 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotations
+
+import (
+	"encoding/json"
+	"errors"
+
+	v1 "k8s.io/api/networking/v1"
+)
+
+var (
+	// ErrRouteRulesInvalidJSON is returned when the RouteRulesKey annotation
+	// cannot be parsed.
+	ErrRouteRulesInvalidJSON = errors.New("route rules annotation is invalid json")
+	// ErrPathActionsInvalidJSON is returned when the PathActionsKey annotation
+	// cannot be parsed.
+	ErrPathActionsInvalidJSON = errors.New("path actions annotation is invalid json")
+)
+
+// RouteRule is a single advanced routing rule for a host. A request that
+// satisfies any of the Matches is sent to Service, or handled by Action.
+// Rules are evaluated in ascending Priority order, before the plain path
+// rules of the host.
+type RouteRule struct {
+	// Host is the hostname the rule applies to. Empty means all hosts.
+	Host string `json:"host,omitempty"`
+	// Priority must be unique among the rules of a host. Lower values
+	// take precedence.
+	Priority int64 `json:"priority"`
+	// Matches is the list of match conditions for the rule.
+	Matches []RouteMatch `json:"matches,omitempty"`
+	// Service is the backend for requests matching the rule. Service must
+	// not be set if Action specifies WeightedBackends.
+	Service *v1.IngressServiceBackend `json:"service,omitempty"`
+	// Action is applied to requests matching the rule.
+	Action *RouteAction `json:"action,omitempty"`
+}
+
+// PathAction attaches a RouteAction to a path of the Ingress spec.
+type PathAction struct {
+	// Host is the host of the Ingress rule. Empty means the rules without a host.
+	Host string `json:"host,omitempty"`
+	// Path is the path as written in the Ingress rule. Empty means the action
+	// applies to all requests of the host, in which case only
+	// RequestMirrorPolicy can be set.
+	Path string `json:"path,omitempty"`
+	// Action is applied to requests matching the path.
+	Action RouteAction `json:"action"`
+}
+
+// RouteAction describes the actions taken on requests matching a route rule
+// or a path.
+type RouteAction struct {
+	// WeightedBackends splits traffic across several Services. When set,
+	// it replaces the Service of the route rule or the Ingress path.
+	WeightedBackends []WeightedBackend `json:"weightedBackends,omitempty"`
+	// UrlRewrite rewrites the request before it is forwarded to the backend.
+	UrlRewrite *UrlRewrite `json:"urlRewrite,omitempty"`
+	// RequestMirrorPolicy sends a copy of the requests to another Service.
+	// Responses from the mirror are ignored.
+	RequestMirrorPolicy *RequestMirrorPolicy `json:"requestMirrorPolicy,omitempty"`
+	// RetryPolicy retries requests that fail on the backend.
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// FaultInjectionPolicy delays or aborts a share of the requests, for
+	// resilience testing.
+	FaultInjectionPolicy *FaultInjectionPolicy `json:"faultInjectionPolicy,omitempty"`
+}
+
+// RetryPolicy describes when and how often requests are retried.
+type RetryPolicy struct {
+	// RetryConditions are the conditions for which a request is retried,
+	// such as "5xx" or "connect-failure".
+	RetryConditions []string `json:"retryConditions"`
+	// NumRetries is the number of allowed retries. Defaults to 1.
+	NumRetries int64 `json:"numRetries,omitempty"`
+	// PerTryTimeout is the timeout of each try, as a duration such as "1.5s".
+	PerTryTimeout string `json:"perTryTimeout,omitempty"`
+}
+
+// FaultInjectionPolicy describes the faults injected in the requests. At
+// least one of Delay and Abort must be set.
+type FaultInjectionPolicy struct {
+	Delay *FaultDelay `json:"delay,omitempty"`
+	Abort *FaultAbort `json:"abort,omitempty"`
+}
+
+// FaultDelay delays a percentage of the requests before they are forwarded.
+type FaultDelay struct {
+	// FixedDelay is the delay, as a duration such as "500ms".
+	FixedDelay string `json:"fixedDelay"`
+	// Percentage of requests that are delayed, between 0 and 100.
+	Percentage float64 `json:"percentage"`
+}
+
+// FaultAbort aborts a percentage of the requests with the given status.
+type FaultAbort struct {
+	// HttpStatus is the status code returned, between 200 and 599.
+	HttpStatus int64 `json:"httpStatus"`
+	// Percentage of requests that are aborted, between 0 and 100.
+	Percentage float64 `json:"percentage"`
+}
+
+// RequestMirrorPolicy describes the Service requests are mirrored to.
+type RequestMirrorPolicy struct {
+	Service v1.IngressServiceBackend `json:"service"`
+}
+
+// UrlRewrite describes how the request is modified before it is forwarded
+// to the backend. At least one of PathPrefixRewrite and HostRewrite must be set.
+type UrlRewrite struct {
+	// PathPrefixRewrite replaces the matching portion of the request path.
+	PathPrefixRewrite string `json:"pathPrefixRewrite,omitempty"`
+	// HostRewrite replaces the Host header of the request.
+	HostRewrite string `json:"hostRewrite,omitempty"`
+}
+
+// WeightedBackend is a Service receiving a share of the traffic proportional
+// to Weight divided by the sum of all weights.
+type WeightedBackend struct {
+	Service v1.IngressServiceBackend `json:"service"`
+	// Weight must be between 0 and 1000.
+	Weight int64 `json:"weight"`
+}
+
+// RouteMatch describes the criteria a request must meet to match a RouteRule.
+// At most one of PrefixMatch and FullPathMatch may be set. If neither is
+// set, the match applies to all paths.
+type RouteMatch struct {
+	PrefixMatch           string                `json:"prefixMatch,omitempty"`
+	FullPathMatch         string                `json:"fullPathMatch,omitempty"`
+	IgnoreCase            bool                  `json:"ignoreCase,omitempty"`
+	HeaderMatches         []HeaderMatch         `json:"headerMatches,omitempty"`
+	QueryParameterMatches []QueryParameterMatch `json:"queryParameterMatches,omitempty"`
+}
+
+// HeaderMatch matches a request header. Exactly one of ExactMatch,
+// PrefixMatch, SuffixMatch, RegexMatch and PresentMatch must be set.
+type HeaderMatch struct {
+	HeaderName   string `json:"headerName"`
+	ExactMatch   string `json:"exactMatch,omitempty"`
+	PrefixMatch  string `json:"prefixMatch,omitempty"`
+	SuffixMatch  string `json:"suffixMatch,omitempty"`
+	RegexMatch   string `json:"regexMatch,omitempty"`
+	PresentMatch bool   `json:"presentMatch,omitempty"`
+	InvertMatch  bool   `json:"invertMatch,omitempty"`
+}
+
+// QueryParameterMatch matches a query parameter. Exactly one of ExactMatch,
+// RegexMatch and PresentMatch must be set.
+type QueryParameterMatch struct {
+	Name         string `json:"name"`
+	ExactMatch   string `json:"exactMatch,omitempty"`
+	RegexMatch   string `json:"regexMatch,omitempty"`
+	PresentMatch bool   `json:"presentMatch,omitempty"`
+}
+
+// RouteRules returns the advanced route rules configured on the Ingress.
+// A nil slice is returned if the annotation is not set.
+func (ing *Ingress) RouteRules() ([]RouteRule, error) {
+	val, ok := ing.v[RouteRulesKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var rules []RouteRule
+	if err := json.Unmarshal([]byte(val), &rules); err != nil {
+		return nil, ErrRouteRulesInvalidJSON
+	}
+	return rules, nil
+}
+
+// PathActions returns the route actions attached to the paths of the Ingress.
+// A nil slice is returned if the annotation is not set.
+func (ing *Ingress) PathActions() ([]PathAction, error) {
+	val, ok := ing.v[PathActionsKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var actions []PathAction
+	if err := json.Unmarshal([]byte(val), &actions); err != nil {
+		return nil, ErrPathActionsInvalidJSON
+	}
+	return actions, nil
+}
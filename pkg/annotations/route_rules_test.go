@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotations
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRouteRules(t *testing.T) {
+	for _, tc := range []struct {
+		desc    string
+		ing     *v1.Ingress
+		want    []RouteRule
+		wantErr error
+	}{
+		{
+			desc: "No annotation",
+			ing:  &v1.Ingress{},
+		},
+		{
+			desc: "Invalid json",
+			ing: &v1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{RouteRulesKey: `[{"host":}]`},
+				},
+			},
+			wantErr: ErrRouteRulesInvalidJSON,
+		},
+		{
+			desc: "Header and query parameter matches",
+			ing: &v1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						RouteRulesKey: `[{"host":"foo.com","priority":1,"matches":[{"prefixMatch":"/","headerMatches":[{"headerName":"x-canary","exactMatch":"true"}],"queryParameterMatches":[{"name":"tenant","presentMatch":true}]}],"service":{"name":"canary","port":{"number":80}}}]`,
+					},
+				},
+			},
+			want: []RouteRule{
+				{
+					Host:     "foo.com",
+					Priority: 1,
+					Matches: []RouteMatch{
+						{
+							PrefixMatch:           "/",
+							HeaderMatches:         []HeaderMatch{{HeaderName: "x-canary", ExactMatch: "true"}},
+							QueryParameterMatches: []QueryParameterMatch{{Name: "tenant", PresentMatch: true}},
+						},
+					},
+					Service: &v1.IngressServiceBackend{Name: "canary", Port: v1.ServiceBackendPort{Number: 80}},
+				},
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := FromIngress(tc.ing).RouteRules()
+			if err != tc.wantErr {
+				t.Fatalf("RouteRules() = _, %v, want %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("RouteRules() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
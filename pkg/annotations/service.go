@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
@@ -382,6 +383,20 @@ func (svc *Service) IsThcAnnotated() (bool, error) {
 	return res.Enabled, nil
 }
 
+// ReconciliationPaused returns true if the reconciliation of the Service is
+// paused by the PauseReconciliationKey annotation.
+func (svc *Service) ReconciliationPaused() bool {
+	v, err := strconv.ParseBool(svc.v[PauseReconciliationKey])
+	return err == nil && v
+}
+
+// DeletionProtected returns true if the load balancer of the Service is
+// protected from deletion by the DeletionProtectionKey annotation.
+func (svc *Service) DeletionProtected() bool {
+	v, err := strconv.ParseBool(svc.v[DeletionProtectionKey])
+	return err == nil && v
+}
+
 func (svc *Service) NEGStatus() (*NegStatus, bool, error) {
 	var res NegStatus
 	var err error
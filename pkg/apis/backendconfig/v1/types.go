@@ -47,6 +47,26 @@ type BackendConfigSpec struct {
 	HealthCheck           *HealthCheckConfig           `json:"healthCheck,omitempty"`
 	// Logging specifies the configuration for access logs.
 	Logging *LogConfig `json:"logging,omitempty"`
+	// OutlierDetection ejects the endpoints returning errors from the load
+	// balancing pool. Only supported by the gce-internal and
+	// gce-regional-external Ingress classes.
+	OutlierDetection *OutlierDetectionConfig `json:"outlierDetection,omitempty"`
+	// CircuitBreakers limits the traffic sent to the backends. Only supported
+	// by the gce-internal and gce-regional-external Ingress classes.
+	CircuitBreakers *CircuitBreakersConfig `json:"circuitBreakers,omitempty"`
+	// LocalityLbPolicy is the algorithm used to pick a backend endpoint, one
+	// of ROUND_ROBIN, LEAST_REQUEST, RING_HASH, RANDOM, ORIGINAL_DESTINATION
+	// or MAGLEV. Only supported by the gce-internal and gce-regional-external
+	// Ingress classes.
+	LocalityLbPolicy string `json:"localityLbPolicy,omitempty"`
+	// ConsistentHash configures the hash of the RING_HASH and MAGLEV locality
+	// load balancing policies. Only supported by the gce-internal Ingress
+	// class.
+	ConsistentHash *ConsistentHashConfig `json:"consistentHash,omitempty"`
+	// Balancing configures the balancing mode and the capacity of the NEG
+	// backends. It takes precedence over the max-rate-per-endpoint and
+	// capacity-scaler Service annotations.
+	Balancing *BalancingConfig `json:"balancing,omitempty"`
 }
 
 // BackendConfigStatus is the status for a BackendConfig resource
@@ -245,3 +265,98 @@ type LogConfig struct {
 	// requests are reported. The default value is 1.0.
 	SampleRate *float64 `json:"sampleRate,omitempty"`
 }
+
+// OutlierDetectionConfig contains configuration for outlier detection, which
+// temporarily ejects the endpoints returning consecutive errors. Unset fields
+// keep the defaults of the load balancer.
+// +k8s:openapi-gen=true
+type OutlierDetectionConfig struct {
+	// Number of consecutive errors before an endpoint is ejected from the
+	// load balancing pool. A 5xx response counts as an error. Defaults to 5.
+	ConsecutiveErrors *int64 `json:"consecutiveErrors,omitempty"`
+	// Time interval between ejection analysis sweeps, in seconds. Defaults
+	// to 1.
+	IntervalSec *int64 `json:"intervalSec,omitempty"`
+	// Base time an endpoint is ejected for, in seconds. The ejection time is
+	// multiplied by the number of times the endpoint has been ejected.
+	// Defaults to 30.
+	BaseEjectionTimeSec *int64 `json:"baseEjectionTimeSec,omitempty"`
+	// Maximum percentage of the endpoints that can be ejected, in [0, 100].
+	// Defaults to 50.
+	MaxEjectionPercent *int64 `json:"maxEjectionPercent,omitempty"`
+}
+
+// CircuitBreakersConfig contains configuration for circuit breakers, which
+// limit the traffic sent to the backends. Unset fields are not limited,
+// except MaxRetries which defaults to 1.
+// +k8s:openapi-gen=true
+type CircuitBreakersConfig struct {
+	// Maximum number of connections to the backends.
+	MaxConnections *int64 `json:"maxConnections,omitempty"`
+	// Maximum number of parallel requests to the backends.
+	MaxRequests *int64 `json:"maxRequests,omitempty"`
+	// Maximum number of pending requests to the backends.
+	MaxPendingRequests *int64 `json:"maxPendingRequests,omitempty"`
+	// Maximum number of parallel retries to the backends.
+	MaxRetries *int64 `json:"maxRetries,omitempty"`
+}
+
+// ConsistentHashConfig contains configuration for consistent hashing, which
+// keeps the requests sharing a header or cookie on the same endpoint. Unset
+// fields keep the defaults of the load balancer.
+// +k8s:openapi-gen=true
+type ConsistentHashConfig struct {
+	// Name of the header hashed. Requires the HEADER_FIELD session affinity.
+	HttpHeaderName string `json:"httpHeaderName,omitempty"`
+	// Cookie hashed, generated by the load balancer if missing from the
+	// request. Requires the HTTP_COOKIE session affinity.
+	HttpCookie *HttpCookieConfig `json:"httpCookie,omitempty"`
+	// Minimum number of entries in the hash ring, in [1, 8388608]. Defaults
+	// to 1024.
+	MinimumRingSize *int64 `json:"minimumRingSize,omitempty"`
+}
+
+// HttpCookieConfig contains configuration for the cookie used for consistent
+// hashing.
+// +k8s:openapi-gen=true
+type HttpCookieConfig struct {
+	// Name of the cookie.
+	Name string `json:"name"`
+	// Path of the cookie.
+	Path string `json:"path,omitempty"`
+	// Lifetime of the cookie, in seconds.
+	TtlSec *int64 `json:"ttlSec,omitempty"`
+}
+
+// BalancingConfig contains configuration for how the load balancer spreads
+// the traffic across the NEG backends of the Service.
+// +k8s:openapi-gen=true
+type BalancingConfig struct {
+	// Balancing mode of the backends, one of RATE, UTILIZATION or CONNECTION.
+	// Defaults to RATE. The GCE_VM_IP_PORT NEGs of HTTP(S) load balancers only
+	// support RATE.
+	BalancingMode string `json:"balancingMode,omitempty"`
+	// Maximum number of requests per second per endpoint. Only used by the
+	// RATE balancing mode.
+	MaxRatePerEndpoint *float64 `json:"maxRatePerEndpoint,omitempty"`
+	// Maximum number of connections per endpoint. Required by the CONNECTION
+	// balancing mode.
+	MaxConnectionsPerEndpoint *int64 `json:"maxConnectionsPerEndpoint,omitempty"`
+	// Target utilization of the backends, in [0, 1]. Only used by the
+	// UTILIZATION balancing mode. Defaults to 0.8.
+	MaxUtilization *float64 `json:"maxUtilization,omitempty"`
+	// Fraction of the capacity of the backends used, in [0, 1]. Defaults to 1.
+	CapacityScaler *float64 `json:"capacityScaler,omitempty"`
+	// ZoneCapacityScalers override CapacityScaler for the backends of the
+	// given zones, for example to drain a zone with a CapacityScaler of 0.
+	ZoneCapacityScalers []ZoneCapacityScaler `json:"zoneCapacityScalers,omitempty"`
+}
+
+// ZoneCapacityScaler is the capacity scaler of the backends of a zone.
+// +k8s:openapi-gen=true
+type ZoneCapacityScaler struct {
+	// Zone of the backends, for example us-central1-a.
+	Zone string `json:"zone"`
+	// Fraction of the capacity of the backends used, in [0, 1].
+	CapacityScaler float64 `json:"capacityScaler"`
+}
@@ -139,6 +139,26 @@ func (in *BackendConfigSpec) DeepCopyInto(out *BackendConfigSpec) {
 		*out = new(LogConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OutlierDetection != nil {
+		in, out := &in.OutlierDetection, &out.OutlierDetection
+		*out = new(OutlierDetectionConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CircuitBreakers != nil {
+		in, out := &in.CircuitBreakers, &out.CircuitBreakers
+		*out = new(CircuitBreakersConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConsistentHash != nil {
+		in, out := &in.ConsistentHash, &out.ConsistentHash
+		*out = new(ConsistentHashConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Balancing != nil {
+		in, out := &in.Balancing, &out.Balancing
+		*out = new(BalancingConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -168,6 +188,47 @@ func (in *BackendConfigStatus) DeepCopy() *BackendConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BalancingConfig) DeepCopyInto(out *BalancingConfig) {
+	*out = *in
+	if in.MaxRatePerEndpoint != nil {
+		in, out := &in.MaxRatePerEndpoint, &out.MaxRatePerEndpoint
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MaxConnectionsPerEndpoint != nil {
+		in, out := &in.MaxConnectionsPerEndpoint, &out.MaxConnectionsPerEndpoint
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxUtilization != nil {
+		in, out := &in.MaxUtilization, &out.MaxUtilization
+		*out = new(float64)
+		**out = **in
+	}
+	if in.CapacityScaler != nil {
+		in, out := &in.CapacityScaler, &out.CapacityScaler
+		*out = new(float64)
+		**out = **in
+	}
+	if in.ZoneCapacityScalers != nil {
+		in, out := &in.ZoneCapacityScalers, &out.ZoneCapacityScalers
+		*out = make([]ZoneCapacityScaler, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BalancingConfig.
+func (in *BalancingConfig) DeepCopy() *BalancingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BalancingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BypassCacheOnRequestHeader) DeepCopyInto(out *BypassCacheOnRequestHeader) {
 	*out = *in
@@ -304,6 +365,42 @@ func (in *CacheKeyPolicy) DeepCopy() *CacheKeyPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CircuitBreakersConfig) DeepCopyInto(out *CircuitBreakersConfig) {
+	*out = *in
+	if in.MaxConnections != nil {
+		in, out := &in.MaxConnections, &out.MaxConnections
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxRequests != nil {
+		in, out := &in.MaxRequests, &out.MaxRequests
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxPendingRequests != nil {
+		in, out := &in.MaxPendingRequests, &out.MaxPendingRequests
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CircuitBreakersConfig.
+func (in *CircuitBreakersConfig) DeepCopy() *CircuitBreakersConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CircuitBreakersConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnectionDrainingConfig) DeepCopyInto(out *ConnectionDrainingConfig) {
 	*out = *in
@@ -320,6 +417,32 @@ func (in *ConnectionDrainingConfig) DeepCopy() *ConnectionDrainingConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsistentHashConfig) DeepCopyInto(out *ConsistentHashConfig) {
+	*out = *in
+	if in.HttpCookie != nil {
+		in, out := &in.HttpCookie, &out.HttpCookie
+		*out = new(HttpCookieConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MinimumRingSize != nil {
+		in, out := &in.MinimumRingSize, &out.MinimumRingSize
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsistentHashConfig.
+func (in *ConsistentHashConfig) DeepCopy() *ConsistentHashConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsistentHashConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CustomRequestHeadersConfig) DeepCopyInto(out *CustomRequestHeadersConfig) {
 	*out = *in
@@ -413,6 +536,27 @@ func (in *HealthCheckConfig) DeepCopy() *HealthCheckConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HttpCookieConfig) DeepCopyInto(out *HttpCookieConfig) {
+	*out = *in
+	if in.TtlSec != nil {
+		in, out := &in.TtlSec, &out.TtlSec
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HttpCookieConfig.
+func (in *HttpCookieConfig) DeepCopy() *HttpCookieConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HttpCookieConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IAPConfig) DeepCopyInto(out *IAPConfig) {
 	*out = *in
@@ -487,6 +631,42 @@ func (in *OAuthClientCredentials) DeepCopy() *OAuthClientCredentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutlierDetectionConfig) DeepCopyInto(out *OutlierDetectionConfig) {
+	*out = *in
+	if in.ConsecutiveErrors != nil {
+		in, out := &in.ConsecutiveErrors, &out.ConsecutiveErrors
+		*out = new(int64)
+		**out = **in
+	}
+	if in.IntervalSec != nil {
+		in, out := &in.IntervalSec, &out.IntervalSec
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BaseEjectionTimeSec != nil {
+		in, out := &in.BaseEjectionTimeSec, &out.BaseEjectionTimeSec
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxEjectionPercent != nil {
+		in, out := &in.MaxEjectionPercent, &out.MaxEjectionPercent
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutlierDetectionConfig.
+func (in *OutlierDetectionConfig) DeepCopy() *OutlierDetectionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OutlierDetectionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecurityPolicyConfig) DeepCopyInto(out *SecurityPolicyConfig) {
 	*out = *in
@@ -539,3 +719,19 @@ func (in *SignedUrlKey) DeepCopy() *SignedUrlKey {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneCapacityScaler) DeepCopyInto(out *ZoneCapacityScaler) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneCapacityScaler.
+func (in *ZoneCapacityScaler) DeepCopy() *ZoneCapacityScaler {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneCapacityScaler)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -32,20 +32,26 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 	return map[string]common.OpenAPIDefinition{
 		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.BackendConfig":               schema_pkg_apis_backendconfig_v1_BackendConfig(ref),
 		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.BackendConfigSpec":           schema_pkg_apis_backendconfig_v1_BackendConfigSpec(ref),
+		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.BalancingConfig":             schema_pkg_apis_backendconfig_v1_BalancingConfig(ref),
 		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.BypassCacheOnRequestHeader":  schema_pkg_apis_backendconfig_v1_BypassCacheOnRequestHeader(ref),
 		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.CDNConfig":                   schema_pkg_apis_backendconfig_v1_CDNConfig(ref),
 		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.CacheKeyPolicy":              schema_pkg_apis_backendconfig_v1_CacheKeyPolicy(ref),
+		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.CircuitBreakersConfig":       schema_pkg_apis_backendconfig_v1_CircuitBreakersConfig(ref),
 		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.ConnectionDrainingConfig":    schema_pkg_apis_backendconfig_v1_ConnectionDrainingConfig(ref),
+		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.ConsistentHashConfig":        schema_pkg_apis_backendconfig_v1_ConsistentHashConfig(ref),
 		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.CustomRequestHeadersConfig":  schema_pkg_apis_backendconfig_v1_CustomRequestHeadersConfig(ref),
 		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.CustomResponseHeadersConfig": schema_pkg_apis_backendconfig_v1_CustomResponseHeadersConfig(ref),
 		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.HealthCheckConfig":           schema_pkg_apis_backendconfig_v1_HealthCheckConfig(ref),
+		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.HttpCookieConfig":            schema_pkg_apis_backendconfig_v1_HttpCookieConfig(ref),
 		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.IAPConfig":                   schema_pkg_apis_backendconfig_v1_IAPConfig(ref),
 		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.LogConfig":                   schema_pkg_apis_backendconfig_v1_LogConfig(ref),
 		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.NegativeCachingPolicy":       schema_pkg_apis_backendconfig_v1_NegativeCachingPolicy(ref),
 		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.OAuthClientCredentials":      schema_pkg_apis_backendconfig_v1_OAuthClientCredentials(ref),
+		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.OutlierDetectionConfig":      schema_pkg_apis_backendconfig_v1_OutlierDetectionConfig(ref),
 		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.SecurityPolicyConfig":        schema_pkg_apis_backendconfig_v1_SecurityPolicyConfig(ref),
 		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.SessionAffinityConfig":       schema_pkg_apis_backendconfig_v1_SessionAffinityConfig(ref),
 		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.SignedUrlKey":                schema_pkg_apis_backendconfig_v1_SignedUrlKey(ref),
+		"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.ZoneCapacityScaler":          schema_pkg_apis_backendconfig_v1_ZoneCapacityScaler(ref),
 	}
 }
 
@@ -154,11 +160,106 @@ func schema_pkg_apis_backendconfig_v1_BackendConfigSpec(ref common.ReferenceCall
 							Ref:         ref("k8s.io/ingress-gce/pkg/apis/backendconfig/v1.LogConfig"),
 						},
 					},
+					"outlierDetection": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OutlierDetection ejects the endpoints returning errors from the load balancing pool. Only supported by the gce-internal and gce-regional-external Ingress classes.",
+							Ref:         ref("k8s.io/ingress-gce/pkg/apis/backendconfig/v1.OutlierDetectionConfig"),
+						},
+					},
+					"circuitBreakers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CircuitBreakers limits the traffic sent to the backends. Only supported by the gce-internal and gce-regional-external Ingress classes.",
+							Ref:         ref("k8s.io/ingress-gce/pkg/apis/backendconfig/v1.CircuitBreakersConfig"),
+						},
+					},
+					"localityLbPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LocalityLbPolicy is the algorithm used to pick a backend endpoint, one of ROUND_ROBIN, LEAST_REQUEST, RING_HASH, RANDOM, ORIGINAL_DESTINATION or MAGLEV. Only supported by the gce-internal and gce-regional-external Ingress classes.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"consistentHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConsistentHash configures the hash of the RING_HASH and MAGLEV locality load balancing policies. Only supported by the gce-internal Ingress class.",
+							Ref:         ref("k8s.io/ingress-gce/pkg/apis/backendconfig/v1.ConsistentHashConfig"),
+						},
+					},
+					"balancing": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Balancing configures the balancing mode and the capacity of the NEG backends. It takes precedence over the max-rate-per-endpoint and capacity-scaler Service annotations.",
+							Ref:         ref("k8s.io/ingress-gce/pkg/apis/backendconfig/v1.BalancingConfig"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.CDNConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.ConnectionDrainingConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.CustomRequestHeadersConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.CustomResponseHeadersConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.HealthCheckConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.IAPConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.LogConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.SecurityPolicyConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.SessionAffinityConfig"},
+			"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.BalancingConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.CDNConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.CircuitBreakersConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.ConnectionDrainingConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.ConsistentHashConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.CustomRequestHeadersConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.CustomResponseHeadersConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.HealthCheckConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.IAPConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.LogConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.OutlierDetectionConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.SecurityPolicyConfig", "k8s.io/ingress-gce/pkg/apis/backendconfig/v1.SessionAffinityConfig"},
+	}
+}
+
+func schema_pkg_apis_backendconfig_v1_BalancingConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "BalancingConfig contains configuration for how the load balancer spreads the traffic across the NEG backends of the Service.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"balancingMode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Balancing mode of the backends, one of RATE, UTILIZATION or CONNECTION. Defaults to RATE. The GCE_VM_IP_PORT NEGs of HTTP(S) load balancers only support RATE.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"maxRatePerEndpoint": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Maximum number of requests per second per endpoint. Only used by the RATE balancing mode.",
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+					"maxConnectionsPerEndpoint": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Maximum number of connections per endpoint. Required by the CONNECTION balancing mode.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"maxUtilization": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Target utilization of the backends, in [0, 1]. Only used by the UTILIZATION balancing mode. Defaults to 0.8.",
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+					"capacityScaler": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Fraction of the capacity of the backends used, in [0, 1]. Defaults to 1.",
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+					"zoneCapacityScalers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ZoneCapacityScalers override CapacityScaler for the backends of the given zones, for example to drain a zone with a CapacityScaler of 0.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/ingress-gce/pkg/apis/backendconfig/v1.ZoneCapacityScaler"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.ZoneCapacityScaler"},
 	}
 }
 
@@ -358,6 +459,47 @@ func schema_pkg_apis_backendconfig_v1_CacheKeyPolicy(ref common.ReferenceCallbac
 	}
 }
 
+func schema_pkg_apis_backendconfig_v1_CircuitBreakersConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CircuitBreakersConfig contains configuration for circuit breakers, which limit the traffic sent to the backends. Unset fields are not limited, except MaxRetries which defaults to 1.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"maxConnections": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Maximum number of connections to the backends.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"maxRequests": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Maximum number of parallel requests to the backends.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"maxPendingRequests": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Maximum number of pending requests to the backends.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"maxRetries": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Maximum number of parallel retries to the backends.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_backendconfig_v1_ConnectionDrainingConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -378,6 +520,41 @@ func schema_pkg_apis_backendconfig_v1_ConnectionDrainingConfig(ref common.Refere
 	}
 }
 
+func schema_pkg_apis_backendconfig_v1_ConsistentHashConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConsistentHashConfig contains configuration for consistent hashing, which keeps the requests sharing a header or cookie on the same endpoint. Unset fields keep the defaults of the load balancer.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"httpHeaderName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the header hashed. Requires the HEADER_FIELD session affinity.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"httpCookie": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Cookie hashed, generated by the load balancer if missing from the request. Requires the HTTP_COOKIE session affinity.",
+							Ref:         ref("k8s.io/ingress-gce/pkg/apis/backendconfig/v1.HttpCookieConfig"),
+						},
+					},
+					"minimumRingSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Minimum number of entries in the hash ring, in [1, 8388608]. Defaults to 1024.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/ingress-gce/pkg/apis/backendconfig/v1.HttpCookieConfig"},
+	}
+}
+
 func schema_pkg_apis_backendconfig_v1_CustomRequestHeadersConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -494,6 +671,42 @@ func schema_pkg_apis_backendconfig_v1_HealthCheckConfig(ref common.ReferenceCall
 	}
 }
 
+func schema_pkg_apis_backendconfig_v1_HttpCookieConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "HttpCookieConfig contains configuration for the cookie used for consistent hashing.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the cookie.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path of the cookie.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ttlSec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Lifetime of the cookie, in seconds.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_backendconfig_v1_IAPConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -612,6 +825,47 @@ func schema_pkg_apis_backendconfig_v1_OAuthClientCredentials(ref common.Referenc
 	}
 }
 
+func schema_pkg_apis_backendconfig_v1_OutlierDetectionConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "OutlierDetectionConfig contains configuration for outlier detection, which temporarily ejects the endpoints returning consecutive errors. Unset fields keep the defaults of the load balancer.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"consecutiveErrors": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of consecutive errors before an endpoint is ejected from the load balancing pool. A 5xx response counts as an error. Defaults to 5.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"intervalSec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time interval between ejection analysis sweeps, in seconds. Defaults to 1.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"baseEjectionTimeSec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Base time an endpoint is ejected for, in seconds. The ejection time is multiplied by the number of times the endpoint has been ejected. Defaults to 30.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"maxEjectionPercent": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Maximum percentage of the endpoints that can be ejected, in [0, 100]. Defaults to 50.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_backendconfig_v1_SecurityPolicyConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -692,3 +946,33 @@ func schema_pkg_apis_backendconfig_v1_SignedUrlKey(ref common.ReferenceCallback)
 		},
 	}
 }
+
+func schema_pkg_apis_backendconfig_v1_ZoneCapacityScaler(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ZoneCapacityScaler is the capacity scaler of the backends of a zone.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"zone": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Zone of the backends, for example us-central1-a.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"capacityScaler": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Fraction of the capacity of the backends used, in [0, 1].",
+							Default:     0,
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+				},
+				Required: []string{"zone", "capacityScaler"},
+			},
+		},
+	}
+}
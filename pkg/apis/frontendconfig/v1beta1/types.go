@@ -37,6 +37,40 @@ type FrontendConfig struct {
 type FrontendConfigSpec struct {
 	SslPolicy       *string              `json:"sslPolicy,omitempty"`
 	RedirectToHttps *HttpsRedirectConfig `json:"redirectToHttps,omitempty"`
+	CorsPolicy      *CorsPolicy          `json:"corsPolicy,omitempty"`
+	// ServerTlsPolicy is the name of a global network security ServerTlsPolicy.
+	// Its mTLS policy, that is the trust config and the client validation mode,
+	// is used to authenticate clients of the HTTPS frontend. An empty string
+	// removes the policy from the load balancer.
+	ServerTlsPolicy *string `json:"serverTlsPolicy,omitempty"`
+	// CertificateMap is the name of a global Certificate Manager certificate
+	// map, or its full resource name. When set, the HTTPS frontend serves the
+	// certificates of the map instead of the pre-shared and Secret based
+	// certificates of the Ingress, which are then removed from the load
+	// balancer.
+	CertificateMap string `json:"certificateMap,omitempty"`
+}
+
+// CorsPolicy representing the Cross-Origin Resource Sharing configuration
+// applied by the load balancer to all requests.
+// +k8s:openapi-gen=true
+type CorsPolicy struct {
+	// Origins allowed to make CORS requests. An origin is allowed if it
+	// matches either an item in AllowOrigins or in AllowOriginRegexes.
+	AllowOrigins []string `json:"allowOrigins,omitempty"`
+	// Regular expressions matching the origins allowed to make CORS requests.
+	AllowOriginRegexes []string `json:"allowOriginRegexes,omitempty"`
+	// Content for the Access-Control-Allow-Methods header.
+	AllowMethods []string `json:"allowMethods,omitempty"`
+	// Content for the Access-Control-Allow-Headers header.
+	AllowHeaders []string `json:"allowHeaders,omitempty"`
+	// Content for the Access-Control-Expose-Headers header.
+	ExposeHeaders []string `json:"exposeHeaders,omitempty"`
+	// How long in seconds the results of a preflight request can be cached.
+	MaxAge int64 `json:"maxAge,omitempty"`
+	// In response to a preflight request, setting this to true indicates that
+	// the actual request can include user credentials.
+	AllowCredentials bool `json:"allowCredentials,omitempty"`
 }
 
 // HttpsRedirectConfig representing the configuration of Https redirects
@@ -30,12 +30,115 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
+		"k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1.CorsPolicy":          schema_pkg_apis_frontendconfig_v1beta1_CorsPolicy(ref),
 		"k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1.FrontendConfig":      schema_pkg_apis_frontendconfig_v1beta1_FrontendConfig(ref),
 		"k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1.FrontendConfigSpec":  schema_pkg_apis_frontendconfig_v1beta1_FrontendConfigSpec(ref),
 		"k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1.HttpsRedirectConfig": schema_pkg_apis_frontendconfig_v1beta1_HttpsRedirectConfig(ref),
 	}
 }
 
+func schema_pkg_apis_frontendconfig_v1beta1_CorsPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CorsPolicy representing the Cross-Origin Resource Sharing configuration applied by the load balancer to all requests.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"allowOrigins": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Origins allowed to make CORS requests. An origin is allowed if it matches either an item in AllowOrigins or in AllowOriginRegexes.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"allowOriginRegexes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Regular expressions matching the origins allowed to make CORS requests.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"allowMethods": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Content for the Access-Control-Allow-Methods header.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"allowHeaders": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Content for the Access-Control-Allow-Headers header.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"exposeHeaders": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Content for the Access-Control-Expose-Headers header.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"maxAge": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How long in seconds the results of a preflight request can be cached.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"allowCredentials": {
+						SchemaProps: spec.SchemaProps{
+							Description: "In response to a preflight request, setting this to true indicates that the actual request can include user credentials.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_frontendconfig_v1beta1_FrontendConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -100,11 +203,30 @@ func schema_pkg_apis_frontendconfig_v1beta1_FrontendConfigSpec(ref common.Refere
 							Ref: ref("k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1.HttpsRedirectConfig"),
 						},
 					},
+					"corsPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1.CorsPolicy"),
+						},
+					},
+					"serverTlsPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ServerTlsPolicy is the name of a global network security ServerTlsPolicy. Its mTLS policy, that is the trust config and the client validation mode, is used to authenticate clients of the HTTPS frontend. An empty string removes the policy from the load balancer.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"certificateMap": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CertificateMap is the name of a global Certificate Manager certificate map, or its full resource name. When set, the HTTPS frontend serves the certificates of the map instead of the pre-shared and Secret based certificates of the Ingress, which are then removed from the load balancer.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1.HttpsRedirectConfig"},
+			"k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1.CorsPolicy", "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1.HttpsRedirectConfig"},
 	}
 }
 
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//
+// GCSBackend is a Cloud Storage bucket served by the load balancers of the
+// Ingresses referencing it in a resource backend of their paths.
+//
+// +k8s:openapi-gen=true
+type GCSBackend struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              GCSBackendSpec   `json:"spec,omitempty"`
+	Status            GCSBackendStatus `json:"status,omitempty"`
+}
+
+// GCSBackendSpec is the spec for a GCSBackend resource
+// +k8s:openapi-gen=true
+type GCSBackendSpec struct {
+	// BucketName is the name of the Cloud Storage bucket.
+	BucketName string `json:"bucketName"`
+	// Cdn is the Cloud CDN configuration of the backend bucket.
+	Cdn *CDNConfig `json:"cdn,omitempty"`
+}
+
+// CDNConfig contains configuration for CDN-enabled backend buckets. It
+// mirrors the CDNConfig of BackendConfigs, without the fields that do not
+// apply to backend buckets.
+// +k8s:openapi-gen=true
+type CDNConfig struct {
+	Enabled                     bool                          `json:"enabled"`
+	BypassCacheOnRequestHeaders []*BypassCacheOnRequestHeader `json:"bypassCacheOnRequestHeaders,omitempty"`
+	CachePolicy                 *CacheKeyPolicy               `json:"cachePolicy,omitempty"`
+	CacheMode                   *string                       `json:"cacheMode,omitempty"`
+	ClientTtl                   *int64                        `json:"clientTtl,omitempty"`
+	DefaultTtl                  *int64                        `json:"defaultTtl,omitempty"`
+	MaxTtl                      *int64                        `json:"maxTtl,omitempty"`
+	NegativeCaching             *bool                         `json:"negativeCaching,omitempty"`
+	NegativeCachingPolicy       []*NegativeCachingPolicy      `json:"negativeCachingPolicy,omitempty"`
+	RequestCoalescing           *bool                         `json:"requestCoalescing,omitempty"`
+	ServeWhileStale             *int64                        `json:"serveWhileStale,omitempty"`
+	SignedUrlCacheMaxAgeSec     *int64                        `json:"signedUrlCacheMaxAgeSec,omitempty"`
+}
+
+// BypassCacheOnRequestHeader contains configuration for how requests containing specific request
+// headers bypass the cache, even if the content was previously cached.
+// +k8s:openapi-gen=true
+type BypassCacheOnRequestHeader struct {
+	// The header field name to match on when bypassing cache. Values are
+	// case-insensitive.
+	HeaderName string `json:"headerName,omitempty"`
+}
+
+// NegativeCachingPolicy contains configuration for how negative caching is applied.
+// +k8s:openapi-gen=true
+type NegativeCachingPolicy struct {
+	// The HTTP status code to define a TTL against. Only HTTP status codes
+	// 300, 301, 308, 404, 405, 410, 421, 451 and 501 are can be specified
+	// as values, and you cannot specify a status code more than once.
+	Code int64 `json:"code,omitempty"`
+	// The TTL (in seconds) for which to cache responses with the
+	// corresponding status code. The maximum allowed value is 1800s (30
+	// minutes), noting that infrequently accessed objects may be evicted
+	// from the cache before the defined TTL.
+	Ttl int64 `json:"ttl,omitempty"`
+}
+
+// CacheKeyPolicy contains configuration for how requests to a CDN-enabled
+// backend bucket are cached.
+// +k8s:openapi-gen=true
+type CacheKeyPolicy struct {
+	// Names of the request headers included in cache keys.
+	IncludeHttpHeaders []string `json:"includeHttpHeaders,omitempty"`
+	// Names of query string parameters to include in cache keys. All other
+	// parameters are excluded.
+	QueryStringWhitelist []string `json:"queryStringWhitelist,omitempty"`
+}
+
+// GCSBackendStatus is the status for a GCSBackend resource
+type GCSBackendStatus struct{}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GCSBackendList is a list of GCSBackend resources
+type GCSBackendList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []GCSBackend `json:"items"`
+}
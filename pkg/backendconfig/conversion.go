@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backendconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
+	backendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1beta1"
+)
+
+// V1FieldsAnnotationKey is the annotation of a v1beta1 BackendConfig holding
+// the fields of its v1 spec that v1beta1 cannot represent, so that they
+// survive being written back by a v1beta1 client. The annotation is reserved
+// for the conversion and removed from v1 BackendConfigs.
+const V1FieldsAnnotationKey = "cloud.google.com/backendconfig-v1-fields"
+
+// v1Fields are the fields of a v1 BackendConfigSpec missing from v1beta1.
+type v1Fields struct {
+	CustomResponseHeaders *backendconfigv1.CustomResponseHeadersConfig `json:"customResponseHeaders,omitempty"`
+	Logging               *backendconfigv1.LogConfig                   `json:"logging,omitempty"`
+	OutlierDetection      *backendconfigv1.OutlierDetectionConfig      `json:"outlierDetection,omitempty"`
+	CircuitBreakers       *backendconfigv1.CircuitBreakersConfig       `json:"circuitBreakers,omitempty"`
+	LocalityLbPolicy      string                                       `json:"localityLbPolicy,omitempty"`
+	ConsistentHash        *backendconfigv1.ConsistentHashConfig        `json:"consistentHash,omitempty"`
+	Balancing             *backendconfigv1.BalancingConfig             `json:"balancing,omitempty"`
+	// Cdn only holds the CDN fields missing from v1beta1. Enabled and
+	// CachePolicy are always taken from the v1beta1 spec.
+	Cdn *backendconfigv1.CDNConfig `json:"cdn,omitempty"`
+}
+
+// ConvertToV1beta1 converts a v1 BackendConfig to v1beta1. The fields only
+// found in v1 are stored in the V1FieldsAnnotationKey annotation.
+func ConvertToV1beta1(in *backendconfigv1.BackendConfig) (*backendconfigv1beta1.BackendConfig, error) {
+	out := &backendconfigv1beta1.BackendConfig{
+		TypeMeta:   metav1.TypeMeta{APIVersion: backendconfigv1beta1.SchemeGroupVersion.String(), Kind: "BackendConfig"},
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+	}
+	// The fields of v1beta1 are a subset of v1 with the same JSON names, the
+	// fields unknown to v1beta1 are dropped when decoding.
+	if err := convertSpec(&in.Spec, &out.Spec); err != nil {
+		return nil, err
+	}
+
+	fields := v1Fields{
+		CustomResponseHeaders: in.Spec.CustomResponseHeaders,
+		Logging:               in.Spec.Logging,
+		OutlierDetection:      in.Spec.OutlierDetection,
+		CircuitBreakers:       in.Spec.CircuitBreakers,
+		LocalityLbPolicy:      in.Spec.LocalityLbPolicy,
+		ConsistentHash:        in.Spec.ConsistentHash,
+		Balancing:             in.Spec.Balancing,
+	}
+	if in.Spec.Cdn != nil {
+		cdn := *in.Spec.Cdn
+		cdn.Enabled = false
+		cdn.CachePolicy = nil
+		if !reflect.DeepEqual(cdn, backendconfigv1.CDNConfig{}) {
+			fields.Cdn = &cdn
+		}
+	}
+	delete(out.Annotations, V1FieldsAnnotationKey)
+	if fields != (v1Fields{}) {
+		raw, err := json.Marshal(fields)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode the v1 fields of BackendConfig %s/%s: %w", in.Namespace, in.Name, err)
+		}
+		if out.Annotations == nil {
+			out.Annotations = map[string]string{}
+		}
+		out.Annotations[V1FieldsAnnotationKey] = string(raw)
+	}
+	return out, nil
+}
+
+// ConvertToV1 converts a v1beta1 BackendConfig to v1, restoring the fields
+// stored in the V1FieldsAnnotationKey annotation. The v1 only CDN fields are
+// dropped if CDN was removed from the v1beta1 spec.
+func ConvertToV1(in *backendconfigv1beta1.BackendConfig) (*backendconfigv1.BackendConfig, error) {
+	out := &backendconfigv1.BackendConfig{
+		TypeMeta:   metav1.TypeMeta{APIVersion: backendconfigv1.SchemeGroupVersion.String(), Kind: "BackendConfig"},
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+	}
+	if err := convertSpec(&in.Spec, &out.Spec); err != nil {
+		return nil, err
+	}
+
+	raw, ok := out.Annotations[V1FieldsAnnotationKey]
+	if !ok {
+		return out, nil
+	}
+	delete(out.Annotations, V1FieldsAnnotationKey)
+	if len(out.Annotations) == 0 {
+		out.Annotations = nil
+	}
+
+	var fields v1Fields
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation on BackendConfig %s/%s: %w", V1FieldsAnnotationKey, in.Namespace, in.Name, err)
+	}
+	out.Spec.CustomResponseHeaders = fields.CustomResponseHeaders
+	out.Spec.Logging = fields.Logging
+	out.Spec.OutlierDetection = fields.OutlierDetection
+	out.Spec.CircuitBreakers = fields.CircuitBreakers
+	out.Spec.LocalityLbPolicy = fields.LocalityLbPolicy
+	out.Spec.ConsistentHash = fields.ConsistentHash
+	out.Spec.Balancing = fields.Balancing
+	if out.Spec.Cdn != nil && fields.Cdn != nil {
+		cdn := fields.Cdn
+		cdn.Enabled = out.Spec.Cdn.Enabled
+		cdn.CachePolicy = out.Spec.Cdn.CachePolicy
+		out.Spec.Cdn = cdn
+	}
+	return out, nil
+}
+
+// convertSpec copies the fields of the spec in to the spec out of the other
+// version.
+func convertSpec(in, out interface{}) error {
+	raw, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to encode BackendConfig spec: %w", err)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("failed to decode BackendConfig spec: %w", err)
+	}
+	return nil
+}
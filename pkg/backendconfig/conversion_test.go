@@ -0,0 +1,238 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backendconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	fuzz "github.com/google/gofuzz"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
+	backendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1beta1"
+	"k8s.io/klog/v2"
+)
+
+const fuzzIterations = 1000
+
+func TestConversionRoundTripV1(t *testing.T) {
+	f := fuzz.New().NilChance(0.3).NumElements(0, 3)
+	for i := 0; i < fuzzIterations; i++ {
+		in := &backendconfigv1.BackendConfig{}
+		f.Fuzz(in)
+		in.TypeMeta = meta_v1.TypeMeta{APIVersion: backendconfigv1.SchemeGroupVersion.String(), Kind: "BackendConfig"}
+
+		beta, err := ConvertToV1beta1(in)
+		if err != nil {
+			t.Fatalf("ConvertToV1beta1(%+v) = %v", in, err)
+		}
+		out, err := ConvertToV1(beta)
+		if err != nil {
+			t.Fatalf("ConvertToV1(%+v) = %v", beta, err)
+		}
+		if !apiequality.Semantic.DeepEqual(in, out) {
+			t.Fatalf("v1 -> v1beta1 -> v1 round trip mismatch (-want +got):\n%s", cmp.Diff(in, out))
+		}
+	}
+}
+
+func TestConversionRoundTripV1beta1(t *testing.T) {
+	f := fuzz.New().NilChance(0.3).NumElements(0, 3)
+	for i := 0; i < fuzzIterations; i++ {
+		in := &backendconfigv1beta1.BackendConfig{}
+		f.Fuzz(in)
+		in.TypeMeta = meta_v1.TypeMeta{APIVersion: backendconfigv1beta1.SchemeGroupVersion.String(), Kind: "BackendConfig"}
+
+		v1, err := ConvertToV1(in)
+		if err != nil {
+			t.Fatalf("ConvertToV1(%+v) = %v", in, err)
+		}
+		out, err := ConvertToV1beta1(v1)
+		if err != nil {
+			t.Fatalf("ConvertToV1beta1(%+v) = %v", v1, err)
+		}
+		if !apiequality.Semantic.DeepEqual(in, out) {
+			t.Fatalf("v1beta1 -> v1 -> v1beta1 round trip mismatch (-want +got):\n%s", cmp.Diff(in, out))
+		}
+	}
+}
+
+func TestConversionPreservesV1Fields(t *testing.T) {
+	sampleRate := 0.5
+	cacheMode := "CACHE_ALL_STATIC"
+	timeout := int64(42)
+	v1Config := func() *backendconfigv1.BackendConfig {
+		return &backendconfigv1.BackendConfig{
+			ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "config"},
+			Spec: backendconfigv1.BackendConfigSpec{
+				Cdn:                   &backendconfigv1.CDNConfig{Enabled: true, CacheMode: &cacheMode},
+				CustomResponseHeaders: &backendconfigv1.CustomResponseHeadersConfig{Headers: []string{"X-Foo: bar"}},
+				Logging:               &backendconfigv1.LogConfig{Enable: true, SampleRate: &sampleRate},
+			},
+		}
+	}
+
+	testCases := []struct {
+		desc   string
+		update func(*backendconfigv1beta1.BackendConfig)
+		want   func(*backendconfigv1.BackendConfig)
+	}{
+		{
+			desc:   "no update",
+			update: func(*backendconfigv1beta1.BackendConfig) {},
+			want:   func(*backendconfigv1.BackendConfig) {},
+		},
+		{
+			desc:   "v1beta1 client sets a timeout",
+			update: func(beta *backendconfigv1beta1.BackendConfig) { beta.Spec.TimeoutSec = &timeout },
+			want:   func(v1 *backendconfigv1.BackendConfig) { v1.Spec.TimeoutSec = &timeout },
+		},
+		{
+			desc:   "v1beta1 client disables cdn",
+			update: func(beta *backendconfigv1beta1.BackendConfig) { beta.Spec.Cdn.Enabled = false },
+			want:   func(v1 *backendconfigv1.BackendConfig) { v1.Spec.Cdn.Enabled = false },
+		},
+		{
+			desc:   "v1beta1 client removes cdn",
+			update: func(beta *backendconfigv1beta1.BackendConfig) { beta.Spec.Cdn = nil },
+			want:   func(v1 *backendconfigv1.BackendConfig) { v1.Spec.Cdn = nil },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			beta, err := ConvertToV1beta1(v1Config())
+			if err != nil {
+				t.Fatalf("ConvertToV1beta1() = %v", err)
+			}
+			if _, ok := beta.Annotations[V1FieldsAnnotationKey]; !ok {
+				t.Fatalf("ConvertToV1beta1() returned annotations %v, want the %s annotation", beta.Annotations, V1FieldsAnnotationKey)
+			}
+			tc.update(beta)
+
+			got, err := ConvertToV1(beta)
+			if err != nil {
+				t.Fatalf("ConvertToV1() = %v", err)
+			}
+			want := v1Config()
+			want.TypeMeta = meta_v1.TypeMeta{APIVersion: backendconfigv1.SchemeGroupVersion.String(), Kind: "BackendConfig"}
+			tc.want(want)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("ConvertToV1() returned diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConversionHandler(t *testing.T) {
+	sampleRate := 0.5
+	v1Config := &backendconfigv1.BackendConfig{
+		TypeMeta:   meta_v1.TypeMeta{APIVersion: backendconfigv1.SchemeGroupVersion.String(), Kind: "BackendConfig"},
+		ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "config"},
+		Spec: backendconfigv1.BackendConfigSpec{
+			Logging: &backendconfigv1.LogConfig{Enable: true, SampleRate: &sampleRate},
+		},
+	}
+	raw, err := json.Marshal(v1Config)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+
+	testCases := []struct {
+		desc              string
+		desiredAPIVersion string
+		objects           [][]byte
+		wantSuccess       bool
+	}{
+		{
+			desc:              "convert to v1beta1",
+			desiredAPIVersion: backendconfigv1beta1.SchemeGroupVersion.String(),
+			objects:           [][]byte{raw},
+			wantSuccess:       true,
+		},
+		{
+			desc:              "convert to the same version",
+			desiredAPIVersion: backendconfigv1.SchemeGroupVersion.String(),
+			objects:           [][]byte{raw},
+			wantSuccess:       true,
+		},
+		{
+			desc:              "unsupported version",
+			desiredAPIVersion: "cloud.google.com/v2",
+			objects:           [][]byte{raw},
+			wantSuccess:       false,
+		},
+		{
+			desc:              "invalid object",
+			desiredAPIVersion: backendconfigv1beta1.SchemeGroupVersion.String(),
+			objects:           [][]byte{raw, []byte(`{"apiVersion": "v1", "kind": "Service"}`)},
+			wantSuccess:       false,
+		},
+	}
+
+	handler := NewConversionHandler(klog.TODO())
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			review := &apiextensionsv1.ConversionReview{
+				Request: &apiextensionsv1.ConversionRequest{UID: "uid", DesiredAPIVersion: tc.desiredAPIVersion},
+			}
+			for _, obj := range tc.objects {
+				review.Request.Objects = append(review.Request.Objects, runtime.RawExtension{Raw: obj})
+			}
+			body, err := json.Marshal(review)
+			if err != nil {
+				t.Fatalf("json.Marshal() = %v", err)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, ConversionPath, bytes.NewReader(body)))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("ServeHTTP() returned status %d: %s", rec.Code, rec.Body.String())
+			}
+			got := &apiextensionsv1.ConversionReview{}
+			if err := json.Unmarshal(rec.Body.Bytes(), got); err != nil {
+				t.Fatalf("json.Unmarshal() = %v", err)
+			}
+			if got.Response == nil || got.Response.UID != "uid" {
+				t.Fatalf("Response = %+v, want a response for request uid", got.Response)
+			}
+			if success := got.Response.Result.Status == meta_v1.StatusSuccess; success != tc.wantSuccess {
+				t.Fatalf("Response.Result = %+v, want success = %t", got.Response.Result, tc.wantSuccess)
+			}
+			if !tc.wantSuccess {
+				return
+			}
+			if len(got.Response.ConvertedObjects) != len(tc.objects) {
+				t.Fatalf("len(ConvertedObjects) = %d, want %d", len(got.Response.ConvertedObjects), len(tc.objects))
+			}
+			typeMeta := &meta_v1.TypeMeta{}
+			if err := json.Unmarshal(got.Response.ConvertedObjects[0].Raw, typeMeta); err != nil {
+				t.Fatalf("json.Unmarshal() = %v", err)
+			}
+			if typeMeta.APIVersion != tc.desiredAPIVersion {
+				t.Errorf("converted apiVersion = %q, want %q", typeMeta.APIVersion, tc.desiredAPIVersion)
+			}
+		})
+	}
+}
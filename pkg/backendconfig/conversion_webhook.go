@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backendconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
+	backendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1beta1"
+	"k8s.io/ingress-gce/pkg/crd"
+	"k8s.io/klog/v2"
+)
+
+// ConversionPath is the path of the BackendConfig conversion webhook.
+const ConversionPath = "/convert/backendconfig"
+
+// ConversionHandler serves the ConversionReviews of the BackendConfig CRD.
+type ConversionHandler struct {
+	logger klog.Logger
+}
+
+// NewConversionHandler returns a ConversionHandler.
+func NewConversionHandler(logger klog.Logger) *ConversionHandler {
+	return &ConversionHandler{logger: logger}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *ConversionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	review := &apiextensionsv1.ConversionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+		http.Error(w, fmt.Sprintf("invalid ConversionReview: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "ConversionReview has no request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = h.convert(review.Request)
+	review.Request = nil
+	out, err := json.Marshal(review)
+	if err != nil {
+		h.logger.Error(err, "Failed to encode ConversionReview")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(out); err != nil {
+		h.logger.Error(err, "Failed to write conversion response")
+	}
+}
+
+// convert returns the response to a conversion request. The request fails
+// as a whole if any of its objects cannot be converted.
+func (h *ConversionHandler) convert(req *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	resp := &apiextensionsv1.ConversionResponse{UID: req.UID}
+	for _, obj := range req.Objects {
+		converted, err := ConvertRaw(obj.Raw, req.DesiredAPIVersion)
+		if err != nil {
+			h.logger.Error(err, "Failed to convert BackendConfig", "desiredAPIVersion", req.DesiredAPIVersion)
+			resp.ConvertedObjects = nil
+			resp.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+			return resp
+		}
+		resp.ConvertedObjects = append(resp.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+	resp.Result = metav1.Status{Status: metav1.StatusSuccess}
+	return resp
+}
+
+// ConvertRaw converts the JSON encoded BackendConfig to the given API
+// version.
+func ConvertRaw(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	typeMeta := &metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, typeMeta); err != nil {
+		return nil, fmt.Errorf("failed to decode object: %w", err)
+	}
+	if typeMeta.Kind != "BackendConfig" {
+		return nil, fmt.Errorf("unexpected kind %q, want BackendConfig", typeMeta.Kind)
+	}
+
+	v1 := backendconfigv1.SchemeGroupVersion.String()
+	v1beta1 := backendconfigv1beta1.SchemeGroupVersion.String()
+	if desiredAPIVersion != v1 && desiredAPIVersion != v1beta1 {
+		return nil, fmt.Errorf("unsupported desired API version %q", desiredAPIVersion)
+	}
+
+	var out interface{}
+	switch {
+	case typeMeta.APIVersion == desiredAPIVersion:
+		return raw, nil
+	case typeMeta.APIVersion == v1 && desiredAPIVersion == v1beta1:
+		in := &backendconfigv1.BackendConfig{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, fmt.Errorf("failed to decode %s BackendConfig: %w", v1, err)
+		}
+		converted, err := ConvertToV1beta1(in)
+		if err != nil {
+			return nil, err
+		}
+		out = converted
+	case typeMeta.APIVersion == v1beta1 && desiredAPIVersion == v1:
+		in := &backendconfigv1beta1.BackendConfig{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, fmt.Errorf("failed to decode %s BackendConfig: %w", v1beta1, err)
+		}
+		converted, err := ConvertToV1(in)
+		if err != nil {
+			return nil, err
+		}
+		out = converted
+	default:
+		return nil, fmt.Errorf("unsupported API version %q", typeMeta.APIVersion)
+	}
+	return json.Marshal(out)
+}
+
+// SetConversionWebhook makes the BackendConfig CRD use the conversion webhook
+// of the given <namespace>/<name> Service, verified with the CA bundle read
+// from caFile.
+func SetConversionWebhook(meta *crd.CRDMeta, service, caFile string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(service)
+	if err != nil || namespace == "" || name == "" {
+		return fmt.Errorf("invalid conversion webhook Service %q, want <namespace>/<name>", service)
+	}
+	if caFile == "" {
+		return fmt.Errorf("no CA bundle file for the conversion webhook Service %q", service)
+	}
+	caBundle, err := os.ReadFile(caFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the conversion webhook CA bundle: %w", err)
+	}
+	path := ConversionPath
+	meta.SetConversionWebhook(&apiextensionsv1.ServiceReference{Namespace: namespace, Name: name, Path: &path}, caBundle)
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
 	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
 	"k8s.io/ingress-gce/pkg/utils"
@@ -38,6 +39,31 @@ var supportedAffinities = map[string]bool{
 	"GENERATED_COOKIE": true,
 }
 
+// consistentHashAffinities are the session affinities that are only supported
+// along with the matching ConsistentHash setting.
+var consistentHashAffinities = map[string]bool{
+	"HEADER_FIELD": true,
+	"HTTP_COOKIE":  true,
+}
+
+var supportedLocalityLbPolicies = map[string]bool{
+	"ROUND_ROBIN":          true,
+	"LEAST_REQUEST":        true,
+	"RING_HASH":            true,
+	"RANDOM":               true,
+	"ORIGINAL_DESTINATION": true,
+	"MAGLEV":               true,
+}
+
+var supportedBalancingModes = map[string]bool{
+	"RATE":        true,
+	"UTILIZATION": true,
+	"CONNECTION":  true,
+}
+
+// maxMinimumRingSize is the largest MinimumRingSize of a consistent hash.
+const maxMinimumRingSize = 8388608
+
 func Validate(kubeClient kubernetes.Interface, beConfig *backendconfigv1.BackendConfig, servicePort *utils.ServicePort) error {
 	if beConfig == nil {
 		return nil
@@ -59,6 +85,26 @@ func Validate(kubeClient kubernetes.Interface, beConfig *backendconfigv1.Backend
 		return err
 	}
 
+	if err := validateOutlierDetection(beConfig, servicePort); err != nil {
+		return err
+	}
+
+	if err := validateCircuitBreakers(beConfig, servicePort); err != nil {
+		return err
+	}
+
+	if err := validateLocalityLbPolicy(beConfig, servicePort); err != nil {
+		return err
+	}
+
+	if err := validateConsistentHash(beConfig, servicePort); err != nil {
+		return err
+	}
+
+	if err := validateBalancing(beConfig, servicePort); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -104,9 +150,14 @@ func validateSessionAffinity(kubeClient kubernetes.Interface, beConfig *backendc
 	}
 
 	if beConfig.Spec.SessionAffinity.AffinityType != "" {
-		if _, ok := supportedAffinities[beConfig.Spec.SessionAffinity.AffinityType]; !ok {
-			return fmt.Errorf("unsupported AffinityType: %s, should be one of NONE, CLIENT_IP, or GENERATED_COOKIE",
-				beConfig.Spec.SessionAffinity.AffinityType)
+		affinityType := beConfig.Spec.SessionAffinity.AffinityType
+		if consistentHashAffinities[affinityType] {
+			if beConfig.Spec.ConsistentHash == nil {
+				return fmt.Errorf("AffinityType %s requires ConsistentHash", affinityType)
+			}
+		} else if _, ok := supportedAffinities[affinityType]; !ok {
+			return fmt.Errorf("unsupported AffinityType: %s, should be one of NONE, CLIENT_IP, GENERATED_COOKIE, HEADER_FIELD or HTTP_COOKIE",
+				affinityType)
 		}
 	}
 
@@ -133,6 +184,194 @@ func validateLogging(beConfig *backendconfigv1.BackendConfig) error {
 	return nil
 }
 
+func validateOutlierDetection(beConfig *backendconfigv1.BackendConfig, servicePort *utils.ServicePort) error {
+	od := beConfig.Spec.OutlierDetection
+	if od == nil {
+		return nil
+	}
+
+	if !supportsTrafficPolicies(servicePort) {
+		return fmt.Errorf("OutlierDetection is only supported by the gce-internal and gce-regional-external Ingress classes")
+	}
+	for _, field := range []struct {
+		name  string
+		value *int64
+	}{
+		{"ConsecutiveErrors", od.ConsecutiveErrors},
+		{"IntervalSec", od.IntervalSec},
+		{"BaseEjectionTimeSec", od.BaseEjectionTimeSec},
+	} {
+		if field.value != nil && *field.value < 1 {
+			return fmt.Errorf("unsupported OutlierDetection %s: %d, should be greater than 0", field.name, *field.value)
+		}
+	}
+	if od.MaxEjectionPercent != nil && (*od.MaxEjectionPercent < 0 || *od.MaxEjectionPercent > 100) {
+		return fmt.Errorf("unsupported OutlierDetection MaxEjectionPercent: %d, should be between 0 and 100", *od.MaxEjectionPercent)
+	}
+	return nil
+}
+
+func validateCircuitBreakers(beConfig *backendconfigv1.BackendConfig, servicePort *utils.ServicePort) error {
+	cb := beConfig.Spec.CircuitBreakers
+	if cb == nil {
+		return nil
+	}
+
+	if !supportsTrafficPolicies(servicePort) {
+		return fmt.Errorf("CircuitBreakers is only supported by the gce-internal and gce-regional-external Ingress classes")
+	}
+	for _, field := range []struct {
+		name  string
+		value *int64
+	}{
+		{"MaxConnections", cb.MaxConnections},
+		{"MaxRequests", cb.MaxRequests},
+		{"MaxPendingRequests", cb.MaxPendingRequests},
+		{"MaxRetries", cb.MaxRetries},
+	} {
+		if field.value != nil && *field.value < 1 {
+			return fmt.Errorf("unsupported CircuitBreakers %s: %d, should be greater than 0", field.name, *field.value)
+		}
+	}
+	return nil
+}
+
+func validateLocalityLbPolicy(beConfig *backendconfigv1.BackendConfig, servicePort *utils.ServicePort) error {
+	policy := beConfig.Spec.LocalityLbPolicy
+	if policy == "" {
+		return nil
+	}
+
+	if !supportsTrafficPolicies(servicePort) {
+		return fmt.Errorf("LocalityLbPolicy is only supported by the gce-internal and gce-regional-external Ingress classes")
+	}
+	if !supportedLocalityLbPolicies[policy] {
+		return fmt.Errorf("unsupported LocalityLbPolicy: %s, should be one of ROUND_ROBIN, LEAST_REQUEST, RING_HASH, RANDOM, ORIGINAL_DESTINATION or MAGLEV", policy)
+	}
+	return nil
+}
+
+func validateConsistentHash(beConfig *backendconfigv1.BackendConfig, servicePort *utils.ServicePort) error {
+	hash := beConfig.Spec.ConsistentHash
+	if hash == nil {
+		return nil
+	}
+
+	// Unlike the locality load balancing policy, GCE only supports consistent
+	// hashing for the internal managed load balancing scheme.
+	if servicePort != nil && !servicePort.L7ILBEnabled {
+		return fmt.Errorf("ConsistentHash is only supported by the gce-internal Ingress class")
+	}
+	if policy := beConfig.Spec.LocalityLbPolicy; policy != "RING_HASH" && policy != "MAGLEV" {
+		return fmt.Errorf("ConsistentHash requires the RING_HASH or MAGLEV LocalityLbPolicy, got %q", policy)
+	}
+
+	var affinityType string
+	if beConfig.Spec.SessionAffinity != nil {
+		affinityType = beConfig.Spec.SessionAffinity.AffinityType
+	}
+	if hash.HttpHeaderName != "" && affinityType != "HEADER_FIELD" {
+		return fmt.Errorf("ConsistentHash HttpHeaderName requires the HEADER_FIELD AffinityType, got %q", affinityType)
+	}
+	if affinityType == "HEADER_FIELD" && hash.HttpHeaderName == "" {
+		return fmt.Errorf("AffinityType HEADER_FIELD requires ConsistentHash HttpHeaderName")
+	}
+	if hash.HttpCookie != nil {
+		if affinityType != "HTTP_COOKIE" {
+			return fmt.Errorf("ConsistentHash HttpCookie requires the HTTP_COOKIE AffinityType, got %q", affinityType)
+		}
+		if hash.HttpCookie.Name == "" {
+			return fmt.Errorf("ConsistentHash HttpCookie requires a Name")
+		}
+		if hash.HttpCookie.TtlSec != nil && *hash.HttpCookie.TtlSec < 0 {
+			return fmt.Errorf("unsupported ConsistentHash HttpCookie TtlSec: %d, should not be negative", *hash.HttpCookie.TtlSec)
+		}
+	}
+	if affinityType == "HTTP_COOKIE" && hash.HttpCookie == nil {
+		return fmt.Errorf("AffinityType HTTP_COOKIE requires ConsistentHash HttpCookie")
+	}
+	if hash.MinimumRingSize != nil && (*hash.MinimumRingSize < 1 || *hash.MinimumRingSize > maxMinimumRingSize) {
+		return fmt.Errorf("unsupported ConsistentHash MinimumRingSize: %d, should be between 1 and %d", *hash.MinimumRingSize, maxMinimumRingSize)
+	}
+	return nil
+}
+
+func validateBalancing(beConfig *backendconfigv1.BackendConfig, servicePort *utils.ServicePort) error {
+	balancing := beConfig.Spec.Balancing
+	if balancing == nil {
+		return nil
+	}
+
+	if servicePort != nil && !servicePort.NEGEnabled {
+		return fmt.Errorf("Balancing is only supported for Services using NEGs")
+	}
+	mode := balancing.BalancingMode
+	if mode == "" {
+		mode = "RATE"
+	}
+	if !supportedBalancingModes[mode] {
+		return fmt.Errorf("unsupported BalancingMode: %s, should be one of RATE, UTILIZATION or CONNECTION", balancing.BalancingMode)
+	}
+	// GCE rejects the other balancing modes for the GCE_VM_IP_PORT NEGs of
+	// the external and internal HTTP(S) load balancers.
+	if mode != "RATE" && servicePort != nil && !servicePort.VMIPNEGEnabled {
+		return fmt.Errorf("BalancingMode %s is not supported by GCE_VM_IP_PORT NEGs, should be RATE", mode)
+	}
+
+	if balancing.MaxRatePerEndpoint != nil {
+		if mode != "RATE" {
+			return fmt.Errorf("MaxRatePerEndpoint is only supported by the RATE BalancingMode, got %s", mode)
+		}
+		if *balancing.MaxRatePerEndpoint <= 0 {
+			return fmt.Errorf("unsupported MaxRatePerEndpoint: %v, should be greater than 0", *balancing.MaxRatePerEndpoint)
+		}
+	}
+	if balancing.MaxConnectionsPerEndpoint != nil {
+		if mode != "CONNECTION" {
+			return fmt.Errorf("MaxConnectionsPerEndpoint is only supported by the CONNECTION BalancingMode, got %s", mode)
+		}
+		if *balancing.MaxConnectionsPerEndpoint < 1 {
+			return fmt.Errorf("unsupported MaxConnectionsPerEndpoint: %d, should be greater than 0", *balancing.MaxConnectionsPerEndpoint)
+		}
+	} else if mode == "CONNECTION" {
+		return fmt.Errorf("BalancingMode CONNECTION requires MaxConnectionsPerEndpoint")
+	}
+	if balancing.MaxUtilization != nil {
+		if mode != "UTILIZATION" {
+			return fmt.Errorf("MaxUtilization is only supported by the UTILIZATION BalancingMode, got %s", mode)
+		}
+		if *balancing.MaxUtilization < 0 || *balancing.MaxUtilization > 1 {
+			return fmt.Errorf("unsupported MaxUtilization: %v, should be between 0 and 1", *balancing.MaxUtilization)
+		}
+	}
+
+	if balancing.CapacityScaler != nil && (*balancing.CapacityScaler < 0 || *balancing.CapacityScaler > 1) {
+		return fmt.Errorf("unsupported CapacityScaler: %v, should be between 0 and 1", *balancing.CapacityScaler)
+	}
+	zones := sets.NewString()
+	for _, zone := range balancing.ZoneCapacityScalers {
+		if zone.Zone == "" {
+			return fmt.Errorf("ZoneCapacityScalers require a Zone")
+		}
+		if zones.Has(zone.Zone) {
+			return fmt.Errorf("duplicate ZoneCapacityScalers for zone %s", zone.Zone)
+		}
+		zones.Insert(zone.Zone)
+		if zone.CapacityScaler < 0 || zone.CapacityScaler > 1 {
+			return fmt.Errorf("unsupported CapacityScaler for zone %s: %v, should be between 0 and 1", zone.Zone, zone.CapacityScaler)
+		}
+	}
+	return nil
+}
+
+// supportsTrafficPolicies returns true if the backend services of the
+// ServicePort support the traffic policies, such as outlier detection, that
+// GCE rejects for the classic external load balancing scheme. A nil ServicePort,
+// as in admission, is not checked.
+func supportsTrafficPolicies(servicePort *utils.ServicePort) bool {
+	return servicePort == nil || servicePort.L7ILBEnabled || servicePort.L7XLBRegionalEnabled
+}
+
 func validateCDN(kubeClient kubernetes.Interface, beConfig *backendconfigv1.BackendConfig, servicePort *utils.ServicePort) error {
 	if beConfig.Spec.Cdn == nil || beConfig.Spec.Cdn.Enabled == false {
 		return nil
@@ -479,3 +479,320 @@ func TestValidateCDN(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateOutlierDetectionAndCircuitBreakers(t *testing.T) {
+	zero := int64(0)
+	ten := int64(10)
+	overPercent := int64(101)
+	ilbPort := &utils.ServicePort{L7ILBEnabled: true}
+
+	testCases := []struct {
+		desc        string
+		spec        backendconfigv1.BackendConfigSpec
+		servicePort *utils.ServicePort
+		expectError bool
+	}{
+		{
+			desc: "valid outlier detection for internal ingress",
+			spec: backendconfigv1.BackendConfigSpec{
+				OutlierDetection: &backendconfigv1.OutlierDetectionConfig{ConsecutiveErrors: &ten, MaxEjectionPercent: &ten},
+			},
+			servicePort: ilbPort,
+			expectError: false,
+		},
+		{
+			desc: "valid circuit breakers for regional external ingress",
+			spec: backendconfigv1.BackendConfigSpec{
+				CircuitBreakers: &backendconfigv1.CircuitBreakersConfig{MaxRequests: &ten},
+			},
+			servicePort: &utils.ServicePort{L7XLBRegionalEnabled: true},
+			expectError: false,
+		},
+		{
+			desc: "outlier detection without service port",
+			spec: backendconfigv1.BackendConfigSpec{
+				OutlierDetection: &backendconfigv1.OutlierDetectionConfig{IntervalSec: &ten},
+			},
+			servicePort: nil,
+			expectError: false,
+		},
+		{
+			desc: "outlier detection for external ingress",
+			spec: backendconfigv1.BackendConfigSpec{
+				OutlierDetection: &backendconfigv1.OutlierDetectionConfig{IntervalSec: &ten},
+			},
+			servicePort: &utils.ServicePort{},
+			expectError: true,
+		},
+		{
+			desc: "circuit breakers for external ingress",
+			spec: backendconfigv1.BackendConfigSpec{
+				CircuitBreakers: &backendconfigv1.CircuitBreakersConfig{MaxConnections: &ten},
+			},
+			servicePort: &utils.ServicePort{},
+			expectError: true,
+		},
+		{
+			desc: "zero outlier detection interval",
+			spec: backendconfigv1.BackendConfigSpec{
+				OutlierDetection: &backendconfigv1.OutlierDetectionConfig{IntervalSec: &zero},
+			},
+			servicePort: ilbPort,
+			expectError: true,
+		},
+		{
+			desc: "max ejection percent over 100",
+			spec: backendconfigv1.BackendConfigSpec{
+				OutlierDetection: &backendconfigv1.OutlierDetectionConfig{MaxEjectionPercent: &overPercent},
+			},
+			servicePort: ilbPort,
+			expectError: true,
+		},
+		{
+			desc: "zero circuit breakers max retries",
+			spec: backendconfigv1.BackendConfigSpec{
+				CircuitBreakers: &backendconfigv1.CircuitBreakersConfig{MaxRetries: &zero},
+			},
+			servicePort: ilbPort,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			err := Validate(kubeClient, &backendconfigv1.BackendConfig{Spec: tc.spec}, tc.servicePort)
+			if tc.expectError && err == nil {
+				t.Errorf("%v: Expected error but got nil", tc.desc)
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("%v: Did not expect error but got: %v", tc.desc, err)
+			}
+		})
+	}
+}
+
+func TestValidateLocalityLbPolicyAndConsistentHash(t *testing.T) {
+	ringSize := int64(1024)
+	badRingSize := int64(0)
+	ilbPort := &utils.ServicePort{L7ILBEnabled: true}
+	headerAffinity := &backendconfigv1.SessionAffinityConfig{AffinityType: "HEADER_FIELD"}
+	cookieAffinity := &backendconfigv1.SessionAffinityConfig{AffinityType: "HTTP_COOKIE"}
+
+	testCases := []struct {
+		desc        string
+		spec        backendconfigv1.BackendConfigSpec
+		servicePort *utils.ServicePort
+		expectError bool
+	}{
+		{
+			desc:        "least request for regional external ingress",
+			spec:        backendconfigv1.BackendConfigSpec{LocalityLbPolicy: "LEAST_REQUEST"},
+			servicePort: &utils.ServicePort{L7XLBRegionalEnabled: true},
+			expectError: false,
+		},
+		{
+			desc:        "locality policy for external ingress",
+			spec:        backendconfigv1.BackendConfigSpec{LocalityLbPolicy: "LEAST_REQUEST"},
+			servicePort: &utils.ServicePort{},
+			expectError: true,
+		},
+		{
+			desc:        "unsupported locality policy",
+			spec:        backendconfigv1.BackendConfigSpec{LocalityLbPolicy: "WEIGHTED_MAGLEV"},
+			servicePort: ilbPort,
+			expectError: true,
+		},
+		{
+			desc: "ring hash on a header for internal ingress",
+			spec: backendconfigv1.BackendConfigSpec{
+				LocalityLbPolicy: "RING_HASH",
+				SessionAffinity:  headerAffinity,
+				ConsistentHash:   &backendconfigv1.ConsistentHashConfig{HttpHeaderName: "X-User", MinimumRingSize: &ringSize},
+			},
+			servicePort: ilbPort,
+			expectError: false,
+		},
+		{
+			desc: "maglev on a cookie without service port",
+			spec: backendconfigv1.BackendConfigSpec{
+				LocalityLbPolicy: "MAGLEV",
+				SessionAffinity:  cookieAffinity,
+				ConsistentHash:   &backendconfigv1.ConsistentHashConfig{HttpCookie: &backendconfigv1.HttpCookieConfig{Name: "session"}},
+			},
+			servicePort: nil,
+			expectError: false,
+		},
+		{
+			desc: "consistent hash for regional external ingress",
+			spec: backendconfigv1.BackendConfigSpec{
+				LocalityLbPolicy: "RING_HASH",
+				SessionAffinity:  headerAffinity,
+				ConsistentHash:   &backendconfigv1.ConsistentHashConfig{HttpHeaderName: "X-User"},
+			},
+			servicePort: &utils.ServicePort{L7XLBRegionalEnabled: true},
+			expectError: true,
+		},
+		{
+			desc: "consistent hash without hashing policy",
+			spec: backendconfigv1.BackendConfigSpec{
+				LocalityLbPolicy: "ROUND_ROBIN",
+				SessionAffinity:  headerAffinity,
+				ConsistentHash:   &backendconfigv1.ConsistentHashConfig{HttpHeaderName: "X-User"},
+			},
+			servicePort: ilbPort,
+			expectError: true,
+		},
+		{
+			desc: "header hash with cookie affinity",
+			spec: backendconfigv1.BackendConfigSpec{
+				LocalityLbPolicy: "RING_HASH",
+				SessionAffinity:  cookieAffinity,
+				ConsistentHash:   &backendconfigv1.ConsistentHashConfig{HttpHeaderName: "X-User"},
+			},
+			servicePort: ilbPort,
+			expectError: true,
+		},
+		{
+			desc: "cookie hash without cookie name",
+			spec: backendconfigv1.BackendConfigSpec{
+				LocalityLbPolicy: "RING_HASH",
+				SessionAffinity:  cookieAffinity,
+				ConsistentHash:   &backendconfigv1.ConsistentHashConfig{HttpCookie: &backendconfigv1.HttpCookieConfig{}},
+			},
+			servicePort: ilbPort,
+			expectError: true,
+		},
+		{
+			desc: "invalid minimum ring size",
+			spec: backendconfigv1.BackendConfigSpec{
+				LocalityLbPolicy: "RING_HASH",
+				SessionAffinity:  headerAffinity,
+				ConsistentHash:   &backendconfigv1.ConsistentHashConfig{HttpHeaderName: "X-User", MinimumRingSize: &badRingSize},
+			},
+			servicePort: ilbPort,
+			expectError: true,
+		},
+		{
+			desc: "header affinity without consistent hash",
+			spec: backendconfigv1.BackendConfigSpec{
+				LocalityLbPolicy: "RING_HASH",
+				SessionAffinity:  headerAffinity,
+			},
+			servicePort: ilbPort,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			err := Validate(kubeClient, &backendconfigv1.BackendConfig{Spec: tc.spec}, tc.servicePort)
+			if tc.expectError && err == nil {
+				t.Errorf("%v: Expected error but got nil", tc.desc)
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("%v: Did not expect error but got: %v", tc.desc, err)
+			}
+		})
+	}
+}
+
+func TestValidateBalancing(t *testing.T) {
+	rate := float64(100)
+	connections := int64(10)
+	utilization := float64(0.5)
+	badScaler := float64(1.5)
+	negPort := &utils.ServicePort{NEGEnabled: true}
+
+	testCases := []struct {
+		desc        string
+		balancing   *backendconfigv1.BalancingConfig
+		servicePort *utils.ServicePort
+		expectError bool
+	}{
+		{
+			desc:        "rate with zone override",
+			balancing:   &backendconfigv1.BalancingConfig{MaxRatePerEndpoint: &rate, ZoneCapacityScalers: []backendconfigv1.ZoneCapacityScaler{{Zone: "us-central1-a"}}},
+			servicePort: negPort,
+			expectError: false,
+		},
+		{
+			desc:        "connection mode without service port",
+			balancing:   &backendconfigv1.BalancingConfig{BalancingMode: "CONNECTION", MaxConnectionsPerEndpoint: &connections},
+			servicePort: nil,
+			expectError: false,
+		},
+		{
+			desc:        "connection mode with GCE_VM_IP_PORT NEGs",
+			balancing:   &backendconfigv1.BalancingConfig{BalancingMode: "CONNECTION", MaxConnectionsPerEndpoint: &connections},
+			servicePort: negPort,
+			expectError: true,
+		},
+		{
+			desc:        "utilization mode with GCE_VM_IP_PORT NEGs",
+			balancing:   &backendconfigv1.BalancingConfig{BalancingMode: "UTILIZATION", MaxUtilization: &utilization},
+			servicePort: negPort,
+			expectError: true,
+		},
+		{
+			desc:        "utilization mode without service port",
+			balancing:   &backendconfigv1.BalancingConfig{BalancingMode: "UTILIZATION", MaxUtilization: &utilization},
+			servicePort: nil,
+			expectError: false,
+		},
+		{
+			desc:        "service without NEGs",
+			balancing:   &backendconfigv1.BalancingConfig{MaxRatePerEndpoint: &rate},
+			servicePort: &utils.ServicePort{},
+			expectError: true,
+		},
+		{
+			desc:        "unsupported balancing mode",
+			balancing:   &backendconfigv1.BalancingConfig{BalancingMode: "CUSTOM_METRICS"},
+			servicePort: negPort,
+			expectError: true,
+		},
+		{
+			desc:        "connection mode without max connections",
+			balancing:   &backendconfigv1.BalancingConfig{BalancingMode: "CONNECTION"},
+			servicePort: negPort,
+			expectError: true,
+		},
+		{
+			desc:        "max rate with utilization mode",
+			balancing:   &backendconfigv1.BalancingConfig{BalancingMode: "UTILIZATION", MaxRatePerEndpoint: &rate},
+			servicePort: negPort,
+			expectError: true,
+		},
+		{
+			desc:        "invalid capacity scaler",
+			balancing:   &backendconfigv1.BalancingConfig{CapacityScaler: &badScaler},
+			servicePort: negPort,
+			expectError: true,
+		},
+		{
+			desc: "duplicate zone override",
+			balancing: &backendconfigv1.BalancingConfig{ZoneCapacityScalers: []backendconfigv1.ZoneCapacityScaler{
+				{Zone: "us-central1-a"},
+				{Zone: "us-central1-a", CapacityScaler: 1},
+			}},
+			servicePort: negPort,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			beConfig := &backendconfigv1.BackendConfig{Spec: backendconfigv1.BackendConfigSpec{Balancing: tc.balancing}}
+			err := Validate(kubeClient, beConfig, tc.servicePort)
+			if tc.expectError && err == nil {
+				t.Errorf("%v: Expected error but got nil", tc.desc)
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("%v: Did not expect error but got: %v", tc.desc, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backends
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/compute/v1"
+	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/ingress-gce/pkg/backends/features"
+	"k8s.io/ingress-gce/pkg/composite/metrics"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// backendBucketService makes the compute calls of the backend buckets. The
+// compute stubs have no BackendBuckets, so the calls are hidden behind this
+// interface for the tests to fake them.
+type backendBucketService interface {
+	Get(name string) (*compute.BackendBucket, error)
+	Insert(bb *compute.BackendBucket) error
+	Update(bb *compute.BackendBucket) error
+	Delete(name string) error
+	List() ([]*compute.BackendBucket, error)
+}
+
+// gceBackendBuckets calls the GA compute API directly, as the compute stubs
+// have no BackendBuckets.
+type gceBackendBuckets struct {
+	cloud *gce.Cloud
+}
+
+// gceBackendBuckets is a backendBucketService
+var _ backendBucketService = (*gceBackendBuckets)(nil)
+
+// Get implements backendBucketService.
+func (g *gceBackendBuckets) Get(name string) (*compute.BackendBucket, error) {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("BackendBucket", "get", "", "", string(meta.VersionGA))
+
+	bb, err := g.cloud.ComputeServices().GA.BackendBuckets.Get(g.cloud.ProjectID(), name).Context(ctx).Do()
+	return bb, mc.Observe(err)
+}
+
+// Insert implements backendBucketService.
+func (g *gceBackendBuckets) Insert(bb *compute.BackendBucket) error {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("BackendBucket", "create", "", "", string(meta.VersionGA))
+
+	op, err := g.cloud.ComputeServices().GA.BackendBuckets.Insert(g.cloud.ProjectID(), bb).Context(ctx).Do()
+	return mc.Observe(g.waitForGlobalOp(ctx, op, err))
+}
+
+// Update implements backendBucketService.
+func (g *gceBackendBuckets) Update(bb *compute.BackendBucket) error {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("BackendBucket", "update", "", "", string(meta.VersionGA))
+
+	op, err := g.cloud.ComputeServices().GA.BackendBuckets.Update(g.cloud.ProjectID(), bb.Name, bb).Context(ctx).Do()
+	return mc.Observe(g.waitForGlobalOp(ctx, op, err))
+}
+
+// Delete implements backendBucketService.
+func (g *gceBackendBuckets) Delete(name string) error {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("BackendBucket", "delete", "", "", string(meta.VersionGA))
+
+	op, err := g.cloud.ComputeServices().GA.BackendBuckets.Delete(g.cloud.ProjectID(), name).Context(ctx).Do()
+	return mc.Observe(g.waitForGlobalOp(ctx, op, err))
+}
+
+// List implements backendBucketService.
+func (g *gceBackendBuckets) List() ([]*compute.BackendBucket, error) {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("BackendBucket", "list", "", "", string(meta.VersionGA))
+
+	var buckets []*compute.BackendBucket
+	err := g.cloud.ComputeServices().GA.BackendBuckets.List(g.cloud.ProjectID()).Pages(ctx, func(page *compute.BackendBucketList) error {
+		buckets = append(buckets, page.Items...)
+		return nil
+	})
+	if err := mc.Observe(err); err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// waitForGlobalOp waits for the global operation returned with err by a
+// compute call to complete.
+func (g *gceBackendBuckets) waitForGlobalOp(ctx context.Context, op *compute.Operation, err error) error {
+	service := g.cloud.ComputeServices().GA
+	for err == nil && op.Status != "DONE" {
+		op, err = service.GlobalOperations.Wait(g.cloud.ProjectID(), op.Name).Context(ctx).Do()
+	}
+	if err == nil && op.Error != nil && len(op.Error.Errors) > 0 {
+		err = fmt.Errorf("operation %q failed: %s", op.Name, op.Error.Errors[0].Message)
+	}
+	return err
+}
+
+// GetBackendBucket implements Pool.
+func (b *Backends) GetBackendBucket(name string, bbLogger klog.Logger) (*compute.BackendBucket, error) {
+	bbLogger.V(3).Info("Getting backend bucket", "backendBucketName", name)
+	return b.backendBuckets.Get(name)
+}
+
+// EnsureBackendBucket implements Pool.
+func (b *Backends) EnsureBackendBucket(name string, bucket utils.BackendBucket, bbLogger klog.Logger) error {
+	bbLogger = bbLogger.WithValues("backendBucketName", name, "bucketName", bucket.BucketName)
+	bb, err := b.GetBackendBucket(name, bbLogger)
+	if err != nil {
+		if !utils.IsHTTPErrorCode(err, http.StatusNotFound) {
+			return err
+		}
+		bb = &compute.BackendBucket{
+			Name:        name,
+			BucketName:  bucket.BucketName,
+			Description: bucket.Description(),
+		}
+		features.EnsureBackendBucketCDN(bucket, bb, bbLogger)
+		bbLogger.Info("Creating backend bucket")
+		return b.backendBuckets.Insert(bb)
+	}
+
+	needsUpdate := features.EnsureBackendBucketCDN(bucket, bb, bbLogger)
+	if bb.BucketName != bucket.BucketName {
+		bb.BucketName = bucket.BucketName
+		needsUpdate = true
+	}
+	if !needsUpdate {
+		return nil
+	}
+	bbLogger.Info("Updating backend bucket")
+	return b.backendBuckets.Update(bb)
+}
+
+// DeleteBackendBucket implements Pool.
+func (b *Backends) DeleteBackendBucket(name string, bbLogger klog.Logger) error {
+	bbLogger = bbLogger.WithValues("backendBucketName", name)
+	bbLogger.Info("Deleting backend bucket")
+
+	if err := b.backendBuckets.Delete(name); err != nil {
+		if utils.IsHTTPErrorCode(err, http.StatusNotFound) || utils.IsInUsedByError(err) {
+			bbLogger.Info("DeleteBackendBucket(): ignorable error", "err", err)
+			return nil
+		}
+		bbLogger.Error(err, "DeleteBackendBucket()")
+		return err
+	}
+	bbLogger.Info("DeleteBackendBucket() ok")
+	return nil
+}
+
+// ListBackendBuckets implements Pool. The backend buckets of the project are
+// returned unfiltered, as their names are given by the frontend namers.
+func (b *Backends) ListBackendBuckets(bbLogger klog.Logger) ([]*compute.BackendBucket, error) {
+	bbLogger.V(3).Info("Listing backend buckets")
+	return b.backendBuckets.List()
+}
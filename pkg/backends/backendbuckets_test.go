@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backends
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cloud-provider-gcp/providers/gce"
+	gcsbackendv1beta1 "k8s.io/ingress-gce/pkg/apis/gcsbackend/v1beta1"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/klog/v2"
+)
+
+// fakeBackendBuckets keeps the backend buckets in memory and records the
+// calls made. The buckets in inUse cannot be deleted.
+type fakeBackendBuckets struct {
+	buckets map[string]*compute.BackendBucket
+	inUse   map[string]bool
+	calls   []string
+}
+
+// fakeBackendBuckets is a backendBucketService
+var _ backendBucketService = (*fakeBackendBuckets)(nil)
+
+func (f *fakeBackendBuckets) Get(name string) (*compute.BackendBucket, error) {
+	f.calls = append(f.calls, "get "+name)
+	bb, ok := f.buckets[name]
+	if !ok {
+		return nil, &googleapi.Error{Code: http.StatusNotFound, Message: "not found"}
+	}
+	bbCopy := *bb
+	return &bbCopy, nil
+}
+
+func (f *fakeBackendBuckets) Insert(bb *compute.BackendBucket) error {
+	f.calls = append(f.calls, "insert "+bb.Name)
+	f.buckets[bb.Name] = bb
+	return nil
+}
+
+func (f *fakeBackendBuckets) Update(bb *compute.BackendBucket) error {
+	f.calls = append(f.calls, "update "+bb.Name)
+	f.buckets[bb.Name] = bb
+	return nil
+}
+
+func (f *fakeBackendBuckets) Delete(name string) error {
+	f.calls = append(f.calls, "delete "+name)
+	if _, ok := f.buckets[name]; !ok {
+		return &googleapi.Error{Code: http.StatusNotFound, Message: "not found"}
+	}
+	if f.inUse[name] {
+		return &googleapi.Error{Code: http.StatusBadRequest, Message: fmt.Sprintf("The backend bucket resource '%s' is already being used by 'url-map'", name)}
+	}
+	delete(f.buckets, name)
+	return nil
+}
+
+func (f *fakeBackendBuckets) List() ([]*compute.BackendBucket, error) {
+	f.calls = append(f.calls, "list")
+	var buckets []*compute.BackendBucket
+	for _, bb := range f.buckets {
+		buckets = append(buckets, bb)
+	}
+	return buckets, nil
+}
+
+func TestBackendBuckets(t *testing.T) {
+	fakeGCE := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
+	fake := &fakeBackendBuckets{buckets: map[string]*compute.BackendBucket{}, inUse: map[string]bool{}}
+	pool := NewPool(fakeGCE, namer.NewNamer("uid1", "", klog.TODO()))
+	pool.backendBuckets = fake
+
+	bucket := utils.BackendBucket{
+		ID:         types.NamespacedName{Namespace: "ns", Name: "assets"},
+		BucketName: "assets-bucket",
+		Cdn:        &gcsbackendv1beta1.CDNConfig{Enabled: true},
+	}
+	const name = "k8s-bb-ns-assets--uid1"
+
+	// Create the backend bucket.
+	if err := pool.EnsureBackendBucket(name, bucket, klog.TODO()); err != nil {
+		t.Fatalf("EnsureBackendBucket() = %v, want nil", err)
+	}
+	bb, err := pool.GetBackendBucket(name, klog.TODO())
+	if err != nil {
+		t.Fatalf("GetBackendBucket() = %v, want nil", err)
+	}
+	if bb.BucketName != bucket.BucketName || !bb.EnableCdn || bb.CdnPolicy == nil || bb.Description != bucket.Description() {
+		t.Errorf("GetBackendBucket() = %+v, want bucket %q with CDN enabled and description %q", bb, bucket.BucketName, bucket.Description())
+	}
+
+	// Nothing to update.
+	fake.calls = nil
+	if err := pool.EnsureBackendBucket(name, bucket, klog.TODO()); err != nil {
+		t.Fatalf("EnsureBackendBucket() = %v, want nil", err)
+	}
+	if want := []string{"get " + name}; fmt.Sprint(fake.calls) != fmt.Sprint(want) {
+		t.Errorf("EnsureBackendBucket() made calls %v, want %v", fake.calls, want)
+	}
+
+	// Update the bucket name and disable CDN.
+	bucket.BucketName = "other-bucket"
+	bucket.Cdn = nil
+	if err := pool.EnsureBackendBucket(name, bucket, klog.TODO()); err != nil {
+		t.Fatalf("EnsureBackendBucket() = %v, want nil", err)
+	}
+	bb, err = pool.GetBackendBucket(name, klog.TODO())
+	if err != nil {
+		t.Fatalf("GetBackendBucket() = %v, want nil", err)
+	}
+	if bb.BucketName != "other-bucket" || bb.EnableCdn || bb.CdnPolicy != nil {
+		t.Errorf("GetBackendBucket() = %+v, want bucket %q with CDN disabled", bb, "other-bucket")
+	}
+
+	buckets, err := pool.ListBackendBuckets(klog.TODO())
+	if err != nil || len(buckets) != 1 || buckets[0].Name != name {
+		t.Errorf("ListBackendBuckets() = %v, %v, want [%s], nil", buckets, err, name)
+	}
+
+	// A backend bucket still in use is kept without error.
+	fake.inUse[name] = true
+	if err := pool.DeleteBackendBucket(name, klog.TODO()); err != nil {
+		t.Errorf("DeleteBackendBucket() = %v, want nil", err)
+	}
+	if _, ok := fake.buckets[name]; !ok {
+		t.Errorf("DeleteBackendBucket() deleted backend bucket %s in use", name)
+	}
+	fake.inUse[name] = false
+	if err := pool.DeleteBackendBucket(name, klog.TODO()); err != nil {
+		t.Errorf("DeleteBackendBucket() = %v, want nil", err)
+	}
+	if _, ok := fake.buckets[name]; ok {
+		t.Errorf("DeleteBackendBucket() did not delete backend bucket %s", name)
+	}
+	// Deleting a missing backend bucket is not an error.
+	if err := pool.DeleteBackendBucket(name, klog.TODO()); err != nil {
+		t.Errorf("DeleteBackendBucket() = %v, want nil", err)
+	}
+}
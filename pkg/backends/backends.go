@@ -43,6 +43,7 @@ type Backends struct {
 	cloud                       *gce.Cloud
 	namer                       namer.BackendNamer
 	useConnectionTrackingPolicy bool
+	backendBuckets              backendBucketService
 }
 
 // Backends is a Pool.
@@ -53,8 +54,9 @@ var _ Pool = (*Backends)(nil)
 // - namer: produces names for backends.
 func NewPool(cloud *gce.Cloud, namer namer.BackendNamer) *Backends {
 	return &Backends{
-		cloud: cloud,
-		namer: namer,
+		cloud:          cloud,
+		namer:          namer,
+		backendBuckets: &gceBackendBuckets{cloud: cloud},
 	}
 }
 
@@ -68,6 +70,7 @@ func NewPoolWithConnectionTrackingPolicy(cloud *gce.Cloud, namer namer.BackendNa
 		cloud:                       cloud,
 		namer:                       namer,
 		useConnectionTrackingPolicy: useConnectionTrackingPolicy,
+		backendBuckets:              &gceBackendBuckets{cloud: cloud},
 	}
 }
 
@@ -106,6 +109,10 @@ func (b *Backends) Create(sp utils.ServicePort, hcLink string, beLogger klog.Log
 			SampleRate: 1.0,
 		},
 	}
+	// Backend services of Internet NEGs have no health check.
+	if hcLink == "" {
+		be.HealthChecks = nil
+	}
 
 	if sp.L7ILBEnabled {
 		// This enables l7-ILB and advanced traffic management features
@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"google.golang.org/api/compute/v1"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// EnsureBackendBucketCDN reads the CDN configuration of the GCSBackend and
+// applies it to the BackendBucket. CDN is disabled if the GCSBackend has no
+// CDN configuration. It returns true if the BackendBucket was changed.
+func EnsureBackendBucketCDN(bucket utils.BackendBucket, bb *compute.BackendBucket, logger klog.Logger) bool {
+	logger.V(3).Info(fmt.Sprintf("GCSBackend.Spec.Cdn before merge = %s", prettyPrint(bucket.Cdn)))
+	logger.V(3).Info(fmt.Sprintf("CdnPolicy before merge = %+v", prettyPrint(bb.CdnPolicy)))
+	newConfig := renderBackendBucketConfig(bucket)
+	if backendBucketHasDiff(newConfig, bb) {
+		logger.V(2).Info("Updated CDN settings for backend bucket", "gcsBackendKey", klog.KRef(bucket.ID.Namespace, bucket.ID.Name))
+		bb.EnableCdn = newConfig.EnableCdn
+		bb.CdnPolicy = newConfig.CdnPolicy
+		return true
+	}
+	return false
+}
+
+// renderBackendBucketConfig returns a BackendBucket with the CDN settings of
+// the GCSBackend. The defaults and constraints are the same as for backend
+// services, see renderConfig.
+func renderBackendBucketConfig(bucket utils.BackendBucket) *compute.BackendBucket {
+	cdnConfig := bucket.Cdn
+	bb := &compute.BackendBucket{}
+	if cdnConfig == nil || !cdnConfig.Enabled {
+		return bb
+	}
+	bb.EnableCdn = true
+	bb.CdnPolicy = &compute.BackendBucketCdnPolicy{}
+
+	if cdnConfig.CachePolicy != nil && (len(cdnConfig.CachePolicy.IncludeHttpHeaders) > 0 || len(cdnConfig.CachePolicy.QueryStringWhitelist) > 0) {
+		bb.CdnPolicy.CacheKeyPolicy = &compute.BackendBucketCdnPolicyCacheKeyPolicy{
+			IncludeHttpHeaders:   cdnConfig.CachePolicy.IncludeHttpHeaders,
+			QueryStringWhitelist: cdnConfig.CachePolicy.QueryStringWhitelist,
+		}
+	}
+
+	if cdnConfig.CacheMode != nil {
+		bb.CdnPolicy.CacheMode = *cdnConfig.CacheMode
+	} else {
+		bb.CdnPolicy.CacheMode = defaultCdnPolicy.CacheMode
+	}
+
+	if cdnConfig.RequestCoalescing != nil {
+		bb.CdnPolicy.RequestCoalescing = *cdnConfig.RequestCoalescing
+	} else {
+		bb.CdnPolicy.RequestCoalescing = defaultCdnPolicy.RequestCoalescing
+	}
+
+	if cdnConfig.ServeWhileStale != nil {
+		bb.CdnPolicy.ServeWhileStale = *cdnConfig.ServeWhileStale
+	} else {
+		bb.CdnPolicy.ServeWhileStale = defaultCdnPolicy.ServeWhileStale
+	}
+
+	// MaxTtl must be specified with CACHE_ALL_STATIC cache_mode only
+	if bb.CdnPolicy.CacheMode != "CACHE_ALL_STATIC" {
+		bb.CdnPolicy.MaxTtl = 0
+	} else if cdnConfig.MaxTtl != nil {
+		bb.CdnPolicy.MaxTtl = *cdnConfig.MaxTtl
+		if bb.CdnPolicy.MaxTtl == 0 {
+			bb.CdnPolicy.ForceSendFields = append(bb.CdnPolicy.ForceSendFields, "MaxTtl")
+		}
+	} else {
+		bb.CdnPolicy.MaxTtl = defaultCdnPolicy.MaxTtl
+	}
+
+	// if USE_ORIGIN_HEADERS ClientTtl and DefaultTtl must be ignored
+	if bb.CdnPolicy.CacheMode == "USE_ORIGIN_HEADERS" {
+		bb.CdnPolicy.ClientTtl = 0
+	} else if cdnConfig.ClientTtl != nil {
+		bb.CdnPolicy.ClientTtl = *cdnConfig.ClientTtl
+		if bb.CdnPolicy.ClientTtl == 0 {
+			bb.CdnPolicy.ForceSendFields = append(bb.CdnPolicy.ForceSendFields, "ClientTtl")
+		}
+	} else {
+		bb.CdnPolicy.ClientTtl = defaultCdnPolicy.ClientTtl
+	}
+
+	if bb.CdnPolicy.CacheMode == "USE_ORIGIN_HEADERS" {
+		bb.CdnPolicy.DefaultTtl = 0
+	} else if cdnConfig.DefaultTtl != nil {
+		bb.CdnPolicy.DefaultTtl = *cdnConfig.DefaultTtl
+		if bb.CdnPolicy.DefaultTtl == 0 {
+			bb.CdnPolicy.ForceSendFields = append(bb.CdnPolicy.ForceSendFields, "DefaultTtl")
+		}
+	} else {
+		bb.CdnPolicy.DefaultTtl = defaultCdnPolicy.DefaultTtl
+	}
+
+	if cdnConfig.NegativeCaching != nil {
+		bb.CdnPolicy.NegativeCaching = *cdnConfig.NegativeCaching
+	} else {
+		bb.CdnPolicy.NegativeCaching = defaultCdnPolicy.NegativeCaching
+	}
+	if bb.CdnPolicy.NegativeCaching {
+		for _, policyRef := range cdnConfig.NegativeCachingPolicy {
+			bb.CdnPolicy.NegativeCachingPolicy = append(bb.CdnPolicy.NegativeCachingPolicy, &compute.BackendBucketCdnPolicyNegativeCachingPolicy{
+				Code: policyRef.Code,
+				Ttl:  policyRef.Ttl,
+			})
+		}
+	}
+
+	if cdnConfig.SignedUrlCacheMaxAgeSec != nil {
+		bb.CdnPolicy.SignedUrlCacheMaxAgeSec = *cdnConfig.SignedUrlCacheMaxAgeSec
+	} else {
+		bb.CdnPolicy.SignedUrlCacheMaxAgeSec = defaultCdnPolicy.SignedUrlCacheMaxAgeSec
+	}
+
+	for _, policyRef := range cdnConfig.BypassCacheOnRequestHeaders {
+		bb.CdnPolicy.BypassCacheOnRequestHeaders = append(bb.CdnPolicy.BypassCacheOnRequestHeaders, &compute.BackendBucketCdnPolicyBypassCacheOnRequestHeader{
+			HeaderName: policyRef.HeaderName,
+		})
+	}
+	return bb
+}
+
+// backendBucketHasDiff returns true if the CDN settings of the current
+// BackendBucket differ from the new ones. As for backend services, the
+// SignedUrlKeyNames are preserved and the order of the negative caching
+// policies does not matter.
+func backendBucketHasDiff(new, current *compute.BackendBucket) bool {
+	if new.EnableCdn != current.EnableCdn {
+		return true
+	}
+	if !new.EnableCdn {
+		return false
+	}
+	if current.CdnPolicy == nil {
+		return true
+	}
+
+	if current.CdnPolicy.SignedUrlKeyNames != nil {
+		new.CdnPolicy.SignedUrlKeyNames = current.CdnPolicy.SignedUrlKeyNames
+	}
+	if backendBucketNegativeCachingPolicyEqual(new.CdnPolicy.NegativeCachingPolicy, current.CdnPolicy.NegativeCachingPolicy) {
+		new.CdnPolicy.NegativeCachingPolicy = current.CdnPolicy.NegativeCachingPolicy
+	}
+	return !reflect.DeepEqual(new.CdnPolicy, current.CdnPolicy)
+}
+
+func backendBucketNegativeCachingPolicyEqual(x, y []*compute.BackendBucketCdnPolicyNegativeCachingPolicy) bool {
+	xSorted := append([]*compute.BackendBucketCdnPolicyNegativeCachingPolicy{}, x...)
+	ySorted := append([]*compute.BackendBucketCdnPolicyNegativeCachingPolicy{}, y...)
+	sort.Slice(xSorted, func(i, j int) bool { return xSorted[i].Code < xSorted[j].Code })
+	sort.Slice(ySorted, func(i, j int) bool { return ySorted[i].Code < ySorted[j].Code })
+	return reflect.DeepEqual(xSorted, ySorted)
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+	gcsbackendv1beta1 "k8s.io/ingress-gce/pkg/apis/gcsbackend/v1beta1"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+func defaultBackendBucketCdnPolicy() *compute.BackendBucketCdnPolicy {
+	return &compute.BackendBucketCdnPolicy{
+		CacheMode:         defaultCdnPolicy.CacheMode,
+		ClientTtl:         defaultCdnPolicy.ClientTtl,
+		DefaultTtl:        defaultCdnPolicy.DefaultTtl,
+		MaxTtl:            defaultCdnPolicy.MaxTtl,
+		NegativeCaching:   defaultCdnPolicy.NegativeCaching,
+		RequestCoalescing: defaultCdnPolicy.RequestCoalescing,
+		ServeWhileStale:   defaultCdnPolicy.ServeWhileStale,
+	}
+}
+
+func TestEnsureBackendBucketCDN(t *testing.T) {
+	for _, tc := range []struct {
+		desc          string
+		cdn           *gcsbackendv1beta1.CDNConfig
+		current       *compute.BackendBucket
+		wantUpdate    bool
+		wantEnableCdn bool
+		wantCdnPolicy *compute.BackendBucketCdnPolicy
+	}{
+		{
+			desc:    "no cdn config",
+			current: &compute.BackendBucket{},
+		},
+		{
+			desc:       "cdn removed",
+			current:    &compute.BackendBucket{EnableCdn: true, CdnPolicy: defaultBackendBucketCdnPolicy()},
+			wantUpdate: true,
+		},
+		{
+			desc:          "cdn enabled with defaults",
+			cdn:           &gcsbackendv1beta1.CDNConfig{Enabled: true},
+			current:       &compute.BackendBucket{},
+			wantUpdate:    true,
+			wantEnableCdn: true,
+			wantCdnPolicy: defaultBackendBucketCdnPolicy(),
+		},
+		{
+			desc:          "defaults unchanged",
+			cdn:           &gcsbackendv1beta1.CDNConfig{Enabled: true},
+			current:       &compute.BackendBucket{EnableCdn: true, CdnPolicy: defaultBackendBucketCdnPolicy()},
+			wantEnableCdn: true,
+			wantCdnPolicy: defaultBackendBucketCdnPolicy(),
+		},
+		{
+			desc: "use origin headers",
+			cdn: &gcsbackendv1beta1.CDNConfig{
+				Enabled:    true,
+				CacheMode:  &useOriginHeaders,
+				ClientTtl:  createInt64(10),
+				DefaultTtl: createInt64(10),
+				MaxTtl:     createInt64(10),
+			},
+			current:       &compute.BackendBucket{EnableCdn: true, CdnPolicy: defaultBackendBucketCdnPolicy()},
+			wantUpdate:    true,
+			wantEnableCdn: true,
+			wantCdnPolicy: &compute.BackendBucketCdnPolicy{
+				CacheMode:         useOriginHeaders,
+				NegativeCaching:   true,
+				RequestCoalescing: true,
+				ServeWhileStale:   86400,
+			},
+		},
+		{
+			desc: "zero ttls and cache key policy",
+			cdn: &gcsbackendv1beta1.CDNConfig{
+				Enabled:     true,
+				ClientTtl:   createInt64(0),
+				DefaultTtl:  createInt64(0),
+				MaxTtl:      createInt64(0),
+				CachePolicy: &gcsbackendv1beta1.CacheKeyPolicy{QueryStringWhitelist: []string{"version"}},
+			},
+			current:       &compute.BackendBucket{},
+			wantUpdate:    true,
+			wantEnableCdn: true,
+			wantCdnPolicy: &compute.BackendBucketCdnPolicy{
+				CacheKeyPolicy:    &compute.BackendBucketCdnPolicyCacheKeyPolicy{QueryStringWhitelist: []string{"version"}},
+				CacheMode:         cacheAllStatic,
+				NegativeCaching:   true,
+				RequestCoalescing: true,
+				ServeWhileStale:   86400,
+				ForceSendFields:   []string{"MaxTtl", "ClientTtl", "DefaultTtl"},
+			},
+		},
+		{
+			desc: "negative caching policies in another order",
+			cdn: &gcsbackendv1beta1.CDNConfig{
+				Enabled: true,
+				NegativeCachingPolicy: []*gcsbackendv1beta1.NegativeCachingPolicy{
+					{Code: 404, Ttl: 60},
+					{Code: 301, Ttl: 30},
+				},
+			},
+			current: &compute.BackendBucket{EnableCdn: true, CdnPolicy: func() *compute.BackendBucketCdnPolicy {
+				policy := defaultBackendBucketCdnPolicy()
+				policy.NegativeCachingPolicy = []*compute.BackendBucketCdnPolicyNegativeCachingPolicy{{Code: 301, Ttl: 30}, {Code: 404, Ttl: 60}}
+				return policy
+			}()},
+			wantEnableCdn: true,
+			wantCdnPolicy: func() *compute.BackendBucketCdnPolicy {
+				policy := defaultBackendBucketCdnPolicy()
+				policy.NegativeCachingPolicy = []*compute.BackendBucketCdnPolicyNegativeCachingPolicy{{Code: 301, Ttl: 30}, {Code: 404, Ttl: 60}}
+				return policy
+			}(),
+		},
+		{
+			desc: "signed url keys are preserved",
+			cdn:  &gcsbackendv1beta1.CDNConfig{Enabled: true, RequestCoalescing: createBool(false)},
+			current: &compute.BackendBucket{EnableCdn: true, CdnPolicy: func() *compute.BackendBucketCdnPolicy {
+				policy := defaultBackendBucketCdnPolicy()
+				policy.SignedUrlKeyNames = []string{"key"}
+				return policy
+			}()},
+			wantUpdate:    true,
+			wantEnableCdn: true,
+			wantCdnPolicy: func() *compute.BackendBucketCdnPolicy {
+				policy := defaultBackendBucketCdnPolicy()
+				policy.RequestCoalescing = false
+				policy.SignedUrlKeyNames = []string{"key"}
+				return policy
+			}(),
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			bucket := utils.BackendBucket{BucketName: "bucket", Cdn: tc.cdn}
+			bb := tc.current
+			if gotUpdate := EnsureBackendBucketCDN(bucket, bb, klog.TODO()); gotUpdate != tc.wantUpdate {
+				t.Errorf("EnsureBackendBucketCDN() = %t, want %t", gotUpdate, tc.wantUpdate)
+			}
+			if bb.EnableCdn != tc.wantEnableCdn {
+				t.Errorf("EnableCdn = %t, want %t", bb.EnableCdn, tc.wantEnableCdn)
+			}
+			if diff := cmp.Diff(tc.wantCdnPolicy, bb.CdnPolicy); diff != "" {
+				t.Errorf("CdnPolicy mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
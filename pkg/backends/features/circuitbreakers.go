@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"reflect"
+
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// EnsureCircuitBreakers reads the CircuitBreakers configuration specified in
+// the ServicePort.BackendConfig and applies it to the BackendService. It
+// returns true if there were existing settings on the BackendService that
+// were overwritten.
+func EnsureCircuitBreakers(sp utils.ServicePort, be *composite.BackendService, logger klog.Logger) bool {
+	if sp.BackendConfig.Spec.CircuitBreakers == nil {
+		return false
+	}
+	beTemp := &composite.BackendService{}
+	if be.CircuitBreakers != nil {
+		existing := *be.CircuitBreakers
+		beTemp.CircuitBreakers = &existing
+	}
+	applyCircuitBreakersSettings(sp, beTemp)
+	if !reflect.DeepEqual(beTemp.CircuitBreakers, be.CircuitBreakers) {
+		be.CircuitBreakers = beTemp.CircuitBreakers
+		logger.V(2).Info("Updated CircuitBreakers settings for service", "serviceKey", klog.KRef(sp.ID.Service.Namespace, sp.ID.Service.Name))
+		return true
+	}
+	return false
+}
+
+// applyCircuitBreakersSettings applies the CircuitBreakers settings specified
+// in the BackendConfig to the passed in composite.BackendService. Settings not
+// specified in the BackendConfig are left untouched. A GCE API call still
+// needs to be made to actually persist the changes.
+func applyCircuitBreakersSettings(sp utils.ServicePort, be *composite.BackendService) {
+	config := sp.BackendConfig.Spec.CircuitBreakers
+	if be.CircuitBreakers == nil {
+		be.CircuitBreakers = &composite.CircuitBreakers{}
+	}
+	if config.MaxConnections != nil {
+		be.CircuitBreakers.MaxConnections = *config.MaxConnections
+	}
+	if config.MaxRequests != nil {
+		be.CircuitBreakers.MaxRequests = *config.MaxRequests
+	}
+	if config.MaxPendingRequests != nil {
+		be.CircuitBreakers.MaxPendingRequests = *config.MaxPendingRequests
+	}
+	if config.MaxRetries != nil {
+		be.CircuitBreakers.MaxRetries = *config.MaxRetries
+	}
+}
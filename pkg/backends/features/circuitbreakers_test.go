@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"testing"
+
+	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+func TestEnsureCircuitBreakers(t *testing.T) {
+	maxRequests := int64(100)
+
+	testCases := []struct {
+		desc           string
+		sp             utils.ServicePort
+		be             *composite.BackendService
+		updateExpected bool
+	}{
+		{
+			desc:           "circuit breakers missing from both ends, no update needed",
+			sp:             utils.ServicePort{BackendConfig: &backendconfigv1.BackendConfig{}},
+			be:             &composite.BackendService{},
+			updateExpected: false,
+		},
+		{
+			desc: "settings are identical, no update needed",
+			sp: utils.ServicePort{
+				BackendConfig: &backendconfigv1.BackendConfig{
+					Spec: backendconfigv1.BackendConfigSpec{
+						CircuitBreakers: &backendconfigv1.CircuitBreakersConfig{MaxRequests: &maxRequests},
+					},
+				},
+			},
+			be: &composite.BackendService{
+				CircuitBreakers: &composite.CircuitBreakers{MaxRequests: 100, MaxRetries: 3},
+			},
+			updateExpected: false,
+		},
+		{
+			desc: "settings are different, update needed",
+			sp: utils.ServicePort{
+				BackendConfig: &backendconfigv1.BackendConfig{
+					Spec: backendconfigv1.BackendConfigSpec{
+						CircuitBreakers: &backendconfigv1.CircuitBreakersConfig{MaxRequests: &maxRequests},
+					},
+				},
+			},
+			be: &composite.BackendService{
+				CircuitBreakers: &composite.CircuitBreakers{MaxRequests: 200},
+			},
+			updateExpected: true,
+		},
+		{
+			desc: "circuit breakers missing from backend service, update needed",
+			sp: utils.ServicePort{
+				BackendConfig: &backendconfigv1.BackendConfig{
+					Spec: backendconfigv1.BackendConfigSpec{
+						CircuitBreakers: &backendconfigv1.CircuitBreakersConfig{MaxRequests: &maxRequests},
+					},
+				},
+			},
+			be:             &composite.BackendService{},
+			updateExpected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			result := EnsureCircuitBreakers(tc.sp, tc.be, klog.TODO())
+			if result != tc.updateExpected {
+				t.Errorf("%v: expected %v but got %v", tc.desc, tc.updateExpected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"reflect"
+
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// EnsureLocalityLbPolicy reads the LocalityLbPolicy configuration specified in
+// the ServicePort.BackendConfig and applies it to the BackendService. It
+// returns true if there were existing settings on the BackendService that
+// were overwritten.
+func EnsureLocalityLbPolicy(sp utils.ServicePort, be *composite.BackendService, logger klog.Logger) bool {
+	if sp.BackendConfig.Spec.LocalityLbPolicy == "" {
+		return false
+	}
+	if be.LocalityLbPolicy != sp.BackendConfig.Spec.LocalityLbPolicy {
+		be.LocalityLbPolicy = sp.BackendConfig.Spec.LocalityLbPolicy
+		logger.V(2).Info("Updated LocalityLbPolicy settings for service", "serviceKey", klog.KRef(sp.ID.Service.Namespace, sp.ID.Service.Name))
+		return true
+	}
+	return false
+}
+
+// EnsureConsistentHash reads the ConsistentHash configuration specified in the
+// ServicePort.BackendConfig and applies it to the BackendService. It returns
+// true if there were existing settings on the BackendService that were
+// overwritten.
+func EnsureConsistentHash(sp utils.ServicePort, be *composite.BackendService, logger klog.Logger) bool {
+	if sp.BackendConfig.Spec.ConsistentHash == nil {
+		return false
+	}
+	beTemp := &composite.BackendService{}
+	if be.ConsistentHash != nil {
+		existing := *be.ConsistentHash
+		if existing.HttpCookie != nil {
+			cookie := *existing.HttpCookie
+			existing.HttpCookie = &cookie
+		}
+		beTemp.ConsistentHash = &existing
+	}
+	applyConsistentHashSettings(sp, beTemp)
+	if !reflect.DeepEqual(beTemp.ConsistentHash, be.ConsistentHash) {
+		be.ConsistentHash = beTemp.ConsistentHash
+		logger.V(2).Info("Updated ConsistentHash settings for service", "serviceKey", klog.KRef(sp.ID.Service.Namespace, sp.ID.Service.Name))
+		return true
+	}
+	return false
+}
+
+// applyConsistentHashSettings applies the ConsistentHash settings specified in
+// the BackendConfig to the passed in composite.BackendService. The hashed
+// header and cookie are replaced, while an unset MinimumRingSize keeps the
+// default filled in by GCE. A GCE API call still needs to be made to actually
+// persist the changes.
+func applyConsistentHashSettings(sp utils.ServicePort, be *composite.BackendService) {
+	config := sp.BackendConfig.Spec.ConsistentHash
+	if be.ConsistentHash == nil {
+		be.ConsistentHash = &composite.ConsistentHashLoadBalancerSettings{}
+	}
+	be.ConsistentHash.HttpHeaderName = config.HttpHeaderName
+	be.ConsistentHash.HttpCookie = nil
+	if config.HttpCookie != nil {
+		be.ConsistentHash.HttpCookie = &composite.ConsistentHashLoadBalancerSettingsHttpCookie{
+			Name: config.HttpCookie.Name,
+			Path: config.HttpCookie.Path,
+		}
+		if config.HttpCookie.TtlSec != nil {
+			be.ConsistentHash.HttpCookie.Ttl = &composite.Duration{Seconds: *config.HttpCookie.TtlSec}
+		}
+	}
+	if config.MinimumRingSize != nil {
+		be.ConsistentHash.MinimumRingSize = *config.MinimumRingSize
+	}
+}
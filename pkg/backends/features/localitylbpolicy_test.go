@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+func TestEnsureLocalityLbPolicy(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		policy         string
+		be             *composite.BackendService
+		updateExpected bool
+	}{
+		{
+			desc:           "policy missing from both ends, no update needed",
+			be:             &composite.BackendService{},
+			updateExpected: false,
+		},
+		{
+			desc:           "policy not in backend config, backend service untouched",
+			be:             &composite.BackendService{LocalityLbPolicy: "RANDOM"},
+			updateExpected: false,
+		},
+		{
+			desc:           "settings are identical, no update needed",
+			policy:         "LEAST_REQUEST",
+			be:             &composite.BackendService{LocalityLbPolicy: "LEAST_REQUEST"},
+			updateExpected: false,
+		},
+		{
+			desc:           "settings are different, update needed",
+			policy:         "LEAST_REQUEST",
+			be:             &composite.BackendService{LocalityLbPolicy: "ROUND_ROBIN"},
+			updateExpected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sp := utils.ServicePort{
+				BackendConfig: &backendconfigv1.BackendConfig{
+					Spec: backendconfigv1.BackendConfigSpec{LocalityLbPolicy: tc.policy},
+				},
+			}
+			result := EnsureLocalityLbPolicy(sp, tc.be, klog.TODO())
+			if result != tc.updateExpected {
+				t.Errorf("%v: expected %v but got %v", tc.desc, tc.updateExpected, result)
+			}
+			if tc.policy != "" && tc.be.LocalityLbPolicy != tc.policy {
+				t.Errorf("%v: LocalityLbPolicy = %q, want %q", tc.desc, tc.be.LocalityLbPolicy, tc.policy)
+			}
+		})
+	}
+}
+
+func TestEnsureConsistentHash(t *testing.T) {
+	ringSize := int64(2048)
+	ttl := int64(60)
+
+	testCases := []struct {
+		desc           string
+		config         *backendconfigv1.ConsistentHashConfig
+		be             *composite.BackendService
+		updateExpected bool
+		want           *composite.ConsistentHashLoadBalancerSettings
+	}{
+		{
+			desc:           "consistent hash missing from both ends, no update needed",
+			be:             &composite.BackendService{},
+			updateExpected: false,
+		},
+		{
+			desc:   "settings are identical, GCE default ring size is kept",
+			config: &backendconfigv1.ConsistentHashConfig{HttpHeaderName: "X-User"},
+			be: &composite.BackendService{
+				ConsistentHash: &composite.ConsistentHashLoadBalancerSettings{HttpHeaderName: "X-User", MinimumRingSize: 1024},
+			},
+			updateExpected: false,
+			want:           &composite.ConsistentHashLoadBalancerSettings{HttpHeaderName: "X-User", MinimumRingSize: 1024},
+		},
+		{
+			desc: "header replaced by cookie, update needed",
+			config: &backendconfigv1.ConsistentHashConfig{
+				HttpCookie:      &backendconfigv1.HttpCookieConfig{Name: "session", Path: "/", TtlSec: &ttl},
+				MinimumRingSize: &ringSize,
+			},
+			be: &composite.BackendService{
+				ConsistentHash: &composite.ConsistentHashLoadBalancerSettings{HttpHeaderName: "X-User", MinimumRingSize: 1024},
+			},
+			updateExpected: true,
+			want: &composite.ConsistentHashLoadBalancerSettings{
+				HttpCookie: &composite.ConsistentHashLoadBalancerSettingsHttpCookie{
+					Name: "session",
+					Path: "/",
+					Ttl:  &composite.Duration{Seconds: 60},
+				},
+				MinimumRingSize: 2048,
+			},
+		},
+		{
+			desc:   "cookie ttl changed, update needed",
+			config: &backendconfigv1.ConsistentHashConfig{HttpCookie: &backendconfigv1.HttpCookieConfig{Name: "session", TtlSec: &ttl}},
+			be: &composite.BackendService{
+				ConsistentHash: &composite.ConsistentHashLoadBalancerSettings{
+					HttpCookie: &composite.ConsistentHashLoadBalancerSettingsHttpCookie{Name: "session", Ttl: &composite.Duration{Seconds: 30}},
+				},
+			},
+			updateExpected: true,
+			want: &composite.ConsistentHashLoadBalancerSettings{
+				HttpCookie: &composite.ConsistentHashLoadBalancerSettingsHttpCookie{Name: "session", Ttl: &composite.Duration{Seconds: 60}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sp := utils.ServicePort{
+				BackendConfig: &backendconfigv1.BackendConfig{
+					Spec: backendconfigv1.BackendConfigSpec{ConsistentHash: tc.config},
+				},
+			}
+			result := EnsureConsistentHash(sp, tc.be, klog.TODO())
+			if result != tc.updateExpected {
+				t.Errorf("%v: expected %v but got %v", tc.desc, tc.updateExpected, result)
+			}
+			if diff := cmp.Diff(tc.want, tc.be.ConsistentHash); diff != "" {
+				t.Errorf("%v: ConsistentHash diff (-want +got):\n%s", tc.desc, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"reflect"
+
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// EnsureOutlierDetection reads the OutlierDetection configuration specified in
+// the ServicePort.BackendConfig and applies it to the BackendService. It
+// returns true if there were existing settings on the BackendService that
+// were overwritten.
+func EnsureOutlierDetection(sp utils.ServicePort, be *composite.BackendService, logger klog.Logger) bool {
+	if sp.BackendConfig.Spec.OutlierDetection == nil {
+		return false
+	}
+	beTemp := &composite.BackendService{}
+	if be.OutlierDetection != nil {
+		existing := *be.OutlierDetection
+		beTemp.OutlierDetection = &existing
+	}
+	applyOutlierDetectionSettings(sp, beTemp)
+	if !reflect.DeepEqual(beTemp.OutlierDetection, be.OutlierDetection) {
+		be.OutlierDetection = beTemp.OutlierDetection
+		logger.V(2).Info("Updated OutlierDetection settings for service", "serviceKey", klog.KRef(sp.ID.Service.Namespace, sp.ID.Service.Name))
+		return true
+	}
+	return false
+}
+
+// applyOutlierDetectionSettings applies the OutlierDetection settings specified
+// in the BackendConfig to the passed in composite.BackendService. Settings not
+// specified in the BackendConfig are left untouched, since GCE fills in their
+// defaults. A GCE API call still needs to be made to actually persist the
+// changes.
+func applyOutlierDetectionSettings(sp utils.ServicePort, be *composite.BackendService) {
+	config := sp.BackendConfig.Spec.OutlierDetection
+	if be.OutlierDetection == nil {
+		be.OutlierDetection = &composite.OutlierDetection{}
+	}
+	if config.ConsecutiveErrors != nil {
+		be.OutlierDetection.ConsecutiveErrors = *config.ConsecutiveErrors
+	}
+	if config.IntervalSec != nil {
+		be.OutlierDetection.Interval = &composite.Duration{Seconds: *config.IntervalSec}
+	}
+	if config.BaseEjectionTimeSec != nil {
+		be.OutlierDetection.BaseEjectionTime = &composite.Duration{Seconds: *config.BaseEjectionTimeSec}
+	}
+	if config.MaxEjectionPercent != nil {
+		be.OutlierDetection.MaxEjectionPercent = *config.MaxEjectionPercent
+	}
+}
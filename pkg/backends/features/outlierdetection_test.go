@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+func TestEnsureOutlierDetection(t *testing.T) {
+	consecutiveErrors := int64(3)
+	interval := int64(10)
+
+	testCases := []struct {
+		desc           string
+		sp             utils.ServicePort
+		be             *composite.BackendService
+		updateExpected bool
+		want           *composite.OutlierDetection
+	}{
+		{
+			desc:           "outlier detection missing from both ends, no update needed",
+			sp:             utils.ServicePort{BackendConfig: &backendconfigv1.BackendConfig{}},
+			be:             &composite.BackendService{},
+			updateExpected: false,
+		},
+		{
+			desc: "outlier detection not in backend config, backend service untouched",
+			sp:   utils.ServicePort{BackendConfig: &backendconfigv1.BackendConfig{}},
+			be: &composite.BackendService{
+				OutlierDetection: &composite.OutlierDetection{ConsecutiveErrors: 5},
+			},
+			updateExpected: false,
+			want:           &composite.OutlierDetection{ConsecutiveErrors: 5},
+		},
+		{
+			desc: "settings are identical, GCE defaults are kept",
+			sp: utils.ServicePort{
+				BackendConfig: &backendconfigv1.BackendConfig{
+					Spec: backendconfigv1.BackendConfigSpec{
+						OutlierDetection: &backendconfigv1.OutlierDetectionConfig{ConsecutiveErrors: &consecutiveErrors},
+					},
+				},
+			},
+			be: &composite.BackendService{
+				OutlierDetection: &composite.OutlierDetection{ConsecutiveErrors: 3, MaxEjectionPercent: 50},
+			},
+			updateExpected: false,
+			want:           &composite.OutlierDetection{ConsecutiveErrors: 3, MaxEjectionPercent: 50},
+		},
+		{
+			desc: "settings are different, update needed",
+			sp: utils.ServicePort{
+				BackendConfig: &backendconfigv1.BackendConfig{
+					Spec: backendconfigv1.BackendConfigSpec{
+						OutlierDetection: &backendconfigv1.OutlierDetectionConfig{
+							ConsecutiveErrors: &consecutiveErrors,
+							IntervalSec:       &interval,
+						},
+					},
+				},
+			},
+			be: &composite.BackendService{
+				OutlierDetection: &composite.OutlierDetection{ConsecutiveErrors: 5, MaxEjectionPercent: 50},
+			},
+			updateExpected: true,
+			want: &composite.OutlierDetection{
+				ConsecutiveErrors:  3,
+				Interval:           &composite.Duration{Seconds: 10},
+				MaxEjectionPercent: 50,
+			},
+		},
+		{
+			desc: "outlier detection missing from backend service, update needed",
+			sp: utils.ServicePort{
+				BackendConfig: &backendconfigv1.BackendConfig{
+					Spec: backendconfigv1.BackendConfigSpec{
+						OutlierDetection: &backendconfigv1.OutlierDetectionConfig{ConsecutiveErrors: &consecutiveErrors},
+					},
+				},
+			},
+			be:             &composite.BackendService{},
+			updateExpected: true,
+			want:           &composite.OutlierDetection{ConsecutiveErrors: 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			result := EnsureOutlierDetection(tc.sp, tc.be, klog.TODO())
+			if result != tc.updateExpected {
+				t.Errorf("%v: expected %v but got %v", tc.desc, tc.updateExpected, result)
+			}
+			if diff := cmp.Diff(tc.want, tc.be.OutlierDetection); diff != "" {
+				t.Errorf("%v: OutlierDetection diff (-want +got):\n%s", tc.desc, diff)
+			}
+		})
+	}
+}
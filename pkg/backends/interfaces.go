@@ -18,8 +18,10 @@ package backends
 
 import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/compute/v1"
 	api_v1 "k8s.io/api/core/v1"
 	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/dryrun"
 	"k8s.io/ingress-gce/pkg/utils"
 	"k8s.io/klog/v2"
 )
@@ -50,6 +52,14 @@ type Pool interface {
 	AddSignedUrlKey(be *composite.BackendService, signedurlkey *composite.SignedUrlKey, logger klog.Logger) error
 	// Deletes a SignedUrlKey from BackendService
 	DeleteSignedUrlKey(be *composite.BackendService, keyName string, logger klog.Logger) error
+	// Get a BackendBucket given its name.
+	GetBackendBucket(name string, logger klog.Logger) (*compute.BackendBucket, error)
+	// Create or update the BackendBucket with the given name to serve a GCSBackend.
+	EnsureBackendBucket(name string, bucket utils.BackendBucket, logger klog.Logger) error
+	// Delete a BackendBucket given its name.
+	DeleteBackendBucket(name string, logger klog.Logger) error
+	// Get a list of all BackendBuckets of the project.
+	ListBackendBuckets(logger klog.Logger) ([]*compute.BackendBucket, error)
 }
 
 // Syncer is an interface to sync Kubernetes services to GCE BackendServices.
@@ -59,8 +69,13 @@ type Syncer interface {
 	// Sync a BackendService. Implementations should only create the BackendService
 	// but not its groups.
 	Sync(svcPorts []utils.ServicePort, logger klog.Logger) error
+	// Plan returns the changes Sync would make to the BackendServices and
+	// their health checks.
+	Plan(svcPorts []utils.ServicePort, logger klog.Logger) (*dryrun.Plan, error)
 	// GC garbage collects unused BackendService's
 	GC(svcPorts []utils.ServicePort, logger klog.Logger) error
+	// PlanGC returns the BackendServices and health checks GC would delete.
+	PlanGC(svcPorts []utils.ServicePort, logger klog.Logger) (*dryrun.Plan, error)
 	// Status returns the status of a BackendService given its name.
 	Status(name string, version meta.Version, scope meta.KeyType, logger klog.Logger) (string, error)
 	// Shutdown cleans up all BackendService's previously synced.
@@ -73,6 +88,14 @@ type Linker interface {
 	Link(sp utils.ServicePort, groups []GroupKey) error
 }
 
+// InternetNEGLinker is a Linker for ServicePorts with Internet NEGs, which
+// also manages the lifecycle of the NEGs.
+type InternetNEGLinker interface {
+	Linker
+	// GC deletes the Internet NEGs which are not used by the given ServicePorts.
+	GC(svcPorts []utils.ServicePort, logger klog.Logger) error
+}
+
 // NEGGetter is an interface to retrieve NEG object
 type NEGGetter interface {
 	GetNetworkEndpointGroup(name string, zone string, version meta.Version, logger klog.Logger) (*composite.NetworkEndpointGroup, error)
@@ -0,0 +1,255 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backends
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/compute/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cloud-provider-gcp/providers/gce"
+	befeatures "k8s.io/ingress-gce/pkg/backends/features"
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/composite/metrics"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/klog/v2"
+)
+
+// internetFQDNPortNEGType is the type of the global NEGs whose endpoint is
+// the external name of a Service.
+const internetFQDNPortNEGType = "INTERNET_FQDN_PORT"
+
+// internetNEGLinker handles linking backends to global Internet NEGs. Unlike
+// the NEGs of the NEG controller, Internet NEGs are created and deleted by
+// the linker itself.
+type internetNEGLinker struct {
+	cloud *gce.Cloud
+	namer *namer.Namer
+
+	logger klog.Logger
+}
+
+// internetNEGLinker is an InternetNEGLinker
+var _ InternetNEGLinker = (*internetNEGLinker)(nil)
+
+func NewInternetNEGLinker(cloud *gce.Cloud, namer *namer.Namer, logger klog.Logger) InternetNEGLinker {
+	return &internetNEGLinker{
+		cloud:  cloud,
+		namer:  namer,
+		logger: logger.WithName("InternetNEGLinker"),
+	}
+}
+
+// Link implements Link. Internet NEGs are global, so the groups are ignored.
+func (l *internetNEGLinker) Link(sp utils.ServicePort, _ []GroupKey) error {
+	negName := sp.NEGName()
+	negLogger := l.logger.WithValues("negName", negName, "servicePort", sp.ID)
+	negUrl, err := l.ensureInternetNEG(negName, &sp, negLogger)
+	if err != nil {
+		return err
+	}
+
+	beName := sp.BackendName()
+	key, err := composite.CreateKey(l.cloud, beName, meta.Global)
+	if err != nil {
+		return err
+	}
+	backendService, err := composite.GetBackendService(l.cloud, key, befeatures.VersionFromServicePort(&sp), negLogger)
+	if err != nil {
+		return err
+	}
+
+	// An Internet NEG is the only backend of its backend service, and takes
+	// no balancing mode or capacity.
+	newBackends := []*composite.Backend{{Group: negUrl}}
+	diff := diffBackends(backendService.Backends, newBackends, false, negLogger)
+	if diff.isEqual() {
+		negLogger.V(2).Info("No changes in backends for service port")
+		return nil
+	}
+	negLogger.V(2).Info("Backends changed for service port", "removing", diff.toRemove(), "adding", diff.toAdd())
+
+	backendService.Backends = newBackends
+	return composite.UpdateBackendService(l.cloud, key, backendService, negLogger)
+}
+
+// GC implements InternetNEGLinker.
+func (l *internetNEGLinker) GC(svcPorts []utils.ServicePort, logger klog.Logger) error {
+	negsToKeep := sets.New[string]()
+	for _, sp := range svcPorts {
+		if sp.InternetNEGEnabled {
+			negsToKeep.Insert(sp.NEGName())
+		}
+	}
+
+	negs, err := l.listGlobalNEGs(logger)
+	if err != nil {
+		return fmt.Errorf("error listing global NEGs: %w", err)
+	}
+	for _, neg := range negs {
+		if neg.NetworkEndpointType != internetFQDNPortNEGType || !l.namer.IsNEG(neg.Name) || negsToKeep.Has(neg.Name) {
+			continue
+		}
+		if err := l.deleteGlobalNEG(neg.Name, logger.WithValues("negName", neg.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureInternetNEG creates the Internet NEG of the ServicePort if needed,
+// makes ExternalName:Port its only endpoint and returns its self link.
+func (l *internetNEGLinker) ensureInternetNEG(name string, sp *utils.ServicePort, negLogger klog.Logger) (string, error) {
+	key := meta.GlobalKey(name)
+	neg, err := l.getGlobalNEG(key, negLogger)
+	if err != nil {
+		if !utils.IsNotFoundError(err) {
+			return "", err
+		}
+		negLogger.Info("Creating Internet NEG")
+		neg = &compute.NetworkEndpointGroup{
+			Name:                name,
+			NetworkEndpointType: internetFQDNPortNEGType,
+			Description:         sp.GetDescription().String(),
+		}
+		if err := l.insertGlobalNEG(key, neg); err != nil {
+			return "", err
+		}
+		if neg, err = l.getGlobalNEG(key, negLogger); err != nil {
+			return "", err
+		}
+	}
+	if neg.NetworkEndpointType != internetFQDNPortNEGType {
+		return "", fmt.Errorf("NEG %s has type %s, expected %s", name, neg.NetworkEndpointType, internetFQDNPortNEGType)
+	}
+
+	endpoints, err := l.listGlobalNEGEndpoints(key)
+	if err != nil {
+		return "", err
+	}
+	want := &compute.NetworkEndpoint{Fqdn: sp.ExternalName, Port: int64(sp.Port)}
+	found := false
+	var toDetach []*compute.NetworkEndpoint
+	for _, ep := range endpoints {
+		if ep.NetworkEndpoint == nil {
+			continue
+		}
+		if ep.NetworkEndpoint.Fqdn == want.Fqdn && ep.NetworkEndpoint.Port == want.Port {
+			found = true
+			continue
+		}
+		toDetach = append(toDetach, ep.NetworkEndpoint)
+	}
+	// An Internet NEG has at most one endpoint, so the old endpoint must be
+	// detached before the new one is attached.
+	if len(toDetach) > 0 {
+		negLogger.Info("Detaching endpoints from Internet NEG", "count", len(toDetach))
+		if err := l.detachGlobalNEGEndpoints(key, toDetach); err != nil {
+			return "", err
+		}
+	}
+	if !found {
+		negLogger.Info("Attaching endpoint to Internet NEG", "fqdn", want.Fqdn, "port", want.Port)
+		if err := l.attachGlobalNEGEndpoints(key, []*compute.NetworkEndpoint{want}); err != nil {
+			return "", err
+		}
+	}
+	return neg.SelfLink, nil
+}
+
+// The composite types only support zonal NEGs, so the methods below call the
+// global NEGs of the GA compute API directly.
+
+func (l *internetNEGLinker) getGlobalNEG(key *meta.Key, negLogger klog.Logger) (*compute.NetworkEndpointGroup, error) {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("NetworkEndpointGroup", "get", "", "", string(meta.VersionGA))
+	negLogger.V(3).Info("Getting global NEG")
+
+	neg, err := l.cloud.Compute().GlobalNetworkEndpointGroups().Get(ctx, key)
+	return neg, mc.Observe(err)
+}
+
+func (l *internetNEGLinker) insertGlobalNEG(key *meta.Key, neg *compute.NetworkEndpointGroup) error {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("NetworkEndpointGroup", "create", "", "", string(meta.VersionGA))
+
+	return mc.Observe(l.cloud.Compute().GlobalNetworkEndpointGroups().Insert(ctx, key, neg))
+}
+
+func (l *internetNEGLinker) listGlobalNEGs(logger klog.Logger) ([]*compute.NetworkEndpointGroup, error) {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("NetworkEndpointGroup", "list", "", "", string(meta.VersionGA))
+	logger.V(3).Info("Listing global NEGs")
+
+	negs, err := l.cloud.Compute().GlobalNetworkEndpointGroups().List(ctx, filter.None)
+	return negs, mc.Observe(err)
+}
+
+func (l *internetNEGLinker) deleteGlobalNEG(name string, negLogger klog.Logger) error {
+	negLogger.Info("Deleting Internet NEG")
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("NetworkEndpointGroup", "delete", "", "", string(meta.VersionGA))
+
+	err := mc.Observe(l.cloud.Compute().GlobalNetworkEndpointGroups().Delete(ctx, meta.GlobalKey(name)))
+	if err != nil {
+		// Internet NEGs still used by a backend service are deleted by a
+		// later GC.
+		if utils.IsHTTPErrorCode(err, http.StatusNotFound) || utils.IsInUsedByError(err) {
+			negLogger.Info("DeleteInternetNEG(): ignorable error", "err", err)
+			return nil
+		}
+		negLogger.Error(err, "DeleteInternetNEG()")
+		return err
+	}
+	return nil
+}
+
+func (l *internetNEGLinker) listGlobalNEGEndpoints(key *meta.Key) ([]*compute.NetworkEndpointWithHealthStatus, error) {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("NetworkEndpointGroup", "list", "", "", string(meta.VersionGA))
+
+	endpoints, err := l.cloud.Compute().GlobalNetworkEndpointGroups().ListNetworkEndpoints(ctx, key, filter.None)
+	return endpoints, mc.Observe(err)
+}
+
+func (l *internetNEGLinker) attachGlobalNEGEndpoints(key *meta.Key, endpoints []*compute.NetworkEndpoint) error {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("NetworkEndpointGroup", "attach", "", "", string(meta.VersionGA))
+
+	req := &compute.GlobalNetworkEndpointGroupsAttachEndpointsRequest{NetworkEndpoints: endpoints}
+	return mc.Observe(l.cloud.Compute().GlobalNetworkEndpointGroups().AttachNetworkEndpoints(ctx, key, req))
+}
+
+func (l *internetNEGLinker) detachGlobalNEGEndpoints(key *meta.Key, endpoints []*compute.NetworkEndpoint) error {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("NetworkEndpointGroup", "detach", "", "", string(meta.VersionGA))
+
+	req := &compute.GlobalNetworkEndpointGroupsDetachEndpointsRequest{NetworkEndpoints: endpoints}
+	return mc.Observe(l.cloud.Compute().GlobalNetworkEndpointGroups().DetachNetworkEndpoints(ctx, key, req))
+}
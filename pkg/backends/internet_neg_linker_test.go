@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backends
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/backends/features"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/klog/v2"
+)
+
+// addGlobalNEGEndpointHooks makes the mock global NEGs keep their endpoints
+// in the given map.
+func addGlobalNEGEndpointHooks(mockGCE *cloud.MockGCE, endpoints map[string][]*compute.NetworkEndpoint) {
+	mockGCE.MockGlobalNetworkEndpointGroups.AttachNetworkEndpointsHook = func(_ context.Context, key *meta.Key, req *compute.GlobalNetworkEndpointGroupsAttachEndpointsRequest, _ *cloud.MockGlobalNetworkEndpointGroups, _ ...cloud.Option) error {
+		endpoints[key.Name] = append(endpoints[key.Name], req.NetworkEndpoints...)
+		return nil
+	}
+	mockGCE.MockGlobalNetworkEndpointGroups.DetachNetworkEndpointsHook = func(_ context.Context, key *meta.Key, req *compute.GlobalNetworkEndpointGroupsDetachEndpointsRequest, _ *cloud.MockGlobalNetworkEndpointGroups, _ ...cloud.Option) error {
+		var kept []*compute.NetworkEndpoint
+		for _, ep := range endpoints[key.Name] {
+			detached := false
+			for _, toDetach := range req.NetworkEndpoints {
+				if ep.Fqdn == toDetach.Fqdn && ep.Port == toDetach.Port {
+					detached = true
+				}
+			}
+			if !detached {
+				kept = append(kept, ep)
+			}
+		}
+		endpoints[key.Name] = kept
+		return nil
+	}
+	mockGCE.MockGlobalNetworkEndpointGroups.ListNetworkEndpointsHook = func(_ context.Context, key *meta.Key, _ *filter.F, _ *cloud.MockGlobalNetworkEndpointGroups, _ ...cloud.Option) ([]*compute.NetworkEndpointWithHealthStatus, error) {
+		var ret []*compute.NetworkEndpointWithHealthStatus
+		for _, ep := range endpoints[key.Name] {
+			ret = append(ret, &compute.NetworkEndpointWithHealthStatus{NetworkEndpoint: ep})
+		}
+		return ret, nil
+	}
+}
+
+func TestInternetNEGLinker(t *testing.T) {
+	fakeGCE := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
+	mockGCE := fakeGCE.Compute().(*cloud.MockGCE)
+	endpoints := map[string][]*compute.NetworkEndpoint{}
+	addGlobalNEGEndpointHooks(mockGCE, endpoints)
+	syncer := newTestSyncer(fakeGCE)
+	linker := NewInternetNEGLinker(fakeGCE, defaultNamer, klog.TODO())
+
+	sp := utils.ServicePort{
+		ID:                 utils.ServicePortID{Service: types.NamespacedName{Namespace: "ns", Name: "saas"}},
+		Port:               443,
+		Protocol:           annotations.ProtocolHTTPS,
+		InternetNEGEnabled: true,
+		ExternalName:       "api.example.com",
+		BackendNamer:       defaultNamer,
+	}
+	negName := sp.NEGName()
+
+	link := func() {
+		t.Helper()
+		if err := syncer.Sync([]utils.ServicePort{sp}, klog.TODO()); err != nil {
+			t.Fatalf("Sync() = %v, want nil", err)
+		}
+		if err := linker.Link(sp, nil); err != nil {
+			t.Fatalf("Link() = %v, want nil", err)
+		}
+		neg, err := mockGCE.GlobalNetworkEndpointGroups().Get(context.TODO(), meta.GlobalKey(negName))
+		if err != nil {
+			t.Fatalf("Failed to get Internet NEG %s: %v", negName, err)
+		}
+		if neg.NetworkEndpointType != internetFQDNPortNEGType {
+			t.Errorf("NEG %s has type %q, want %q", negName, neg.NetworkEndpointType, internetFQDNPortNEGType)
+		}
+		want := []*compute.NetworkEndpoint{{Fqdn: sp.ExternalName, Port: int64(sp.Port)}}
+		if diff := cmp.Diff(want, endpoints[negName]); diff != "" {
+			t.Errorf("Endpoints of NEG %s mismatch (-want +got):\n%s", negName, diff)
+		}
+		be, err := syncer.backendPool.Get(sp.BackendName(), features.VersionFromServicePort(&sp), features.ScopeFromServicePort(&sp), klog.TODO())
+		if err != nil {
+			t.Fatalf("Failed to get backend service %s: %v", sp.BackendName(), err)
+		}
+		if len(be.Backends) != 1 || !utils.EqualResourceIDs(be.Backends[0].Group, neg.SelfLink) {
+			t.Errorf("Backend service %s has backends %+v, want only NEG %s", be.Name, be.Backends, neg.SelfLink)
+		}
+	}
+
+	// The NEG is created and linked.
+	link()
+	// Linking again does not change anything.
+	link()
+	// A new external name replaces the endpoint.
+	sp.ExternalName = "api2.example.com"
+	link()
+
+	// Global NEGs of other types or clusters are not garbage collected.
+	otherNEGs := []*compute.NetworkEndpointGroup{
+		{Name: defaultNamer.NEG("ns", "ip", 80), NetworkEndpointType: "INTERNET_IP_PORT"},
+		{Name: namer.NewNamer("uid2", "fw2", klog.TODO()).NEG("ns", "saas", 443), NetworkEndpointType: internetFQDNPortNEGType},
+	}
+	for _, neg := range otherNEGs {
+		if err := mockGCE.GlobalNetworkEndpointGroups().Insert(context.TODO(), meta.GlobalKey(neg.Name), neg); err != nil {
+			t.Fatalf("Failed to insert NEG %s: %v", neg.Name, err)
+		}
+	}
+
+	if err := linker.GC([]utils.ServicePort{sp}, klog.TODO()); err != nil {
+		t.Fatalf("GC() = %v, want nil", err)
+	}
+	if _, err := mockGCE.GlobalNetworkEndpointGroups().Get(context.TODO(), meta.GlobalKey(negName)); err != nil {
+		t.Errorf("GC() deleted Internet NEG %s in use: %v", negName, err)
+	}
+
+	if err := linker.GC(nil, klog.TODO()); err != nil {
+		t.Fatalf("GC() = %v, want nil", err)
+	}
+	if _, err := mockGCE.GlobalNetworkEndpointGroups().Get(context.TODO(), meta.GlobalKey(negName)); !utils.IsNotFoundError(err) {
+		t.Errorf("GC() did not delete unused Internet NEG %s, err = %v", negName, err)
+	}
+	for _, neg := range otherNEGs {
+		if _, err := mockGCE.GlobalNetworkEndpointGroups().Get(context.TODO(), meta.GlobalKey(neg.Name)); err != nil {
+			t.Errorf("GC() deleted NEG %s: %v", neg.Name, err)
+		}
+	}
+}
@@ -15,12 +15,14 @@ package backends
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/cloud-provider-gcp/providers/gce"
+	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
 	negv1beta1 "k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1"
 	befeatures "k8s.io/ingress-gce/pkg/backends/features"
 	"k8s.io/ingress-gce/pkg/composite"
@@ -30,6 +32,10 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// defaultMaxUtilization is the MaxUtilization of the backends with the
+// UTILIZATION balancing mode, the default of GCE.
+const defaultMaxUtilization = 0.8
+
 // negLinker handles linking backends to NEG's.
 type negLinker struct {
 	backendPool  Pool
@@ -37,6 +43,15 @@ type negLinker struct {
 	cloud        *gce.Cloud
 	svcNegLister cache.Indexer
 
+	// balanced holds the names of the backend services last linked with a
+	// Balancing configuration. Their balancing mode and capacity are reverted
+	// once the configuration is removed, while the capacity set out of band
+	// on other backend services is left alone. The names are only kept in
+	// memory, so a configuration removed while the controller restarts is
+	// not reverted.
+	balancedLock sync.Mutex
+	balanced     sets.String
+
 	logger klog.Logger
 }
 
@@ -55,6 +70,7 @@ func NewNEGLinker(
 		negGetter:    negGetter,
 		cloud:        cloud,
 		svcNegLister: svcNegLister,
+		balanced:     sets.NewString(),
 		logger:       logger.WithName("NEGLinker"),
 	}
 }
@@ -107,15 +123,42 @@ func (nl *negLinker) Link(sp utils.ServicePort, groups []GroupKey) error {
 		mergedBackend = newBackends
 	}
 
-	diff := diffBackends(backendService.Backends, mergedBackend, nl.logger)
+	balanced := balancingConfig(&sp) != nil
+	revert := !balanced && nl.wasBalanced(beName)
+	diff := diffBackends(backendService.Backends, mergedBackend, compareCapacity(&sp) || revert, nl.logger)
 	if diff.isEqual() {
 		nl.logger.V(2).Info("No changes in backends for service port", "servicePort", sp.ID)
+		nl.setBalanced(beName, balanced)
 		return nil
 	}
 	nl.logger.V(2).Info("Backends changed for service port", "servicePort", sp.ID, "removing", diff.toRemove(), "adding", diff.toAdd(), "changed", diff.changed)
 
 	backendService.Backends = mergedBackend
-	return composite.UpdateBackendService(nl.cloud, key, backendService, nl.logger)
+	if err := composite.UpdateBackendService(nl.cloud, key, backendService, nl.logger); err != nil {
+		return err
+	}
+	nl.setBalanced(beName, balanced)
+	return nil
+}
+
+// wasBalanced returns true if the backend service was last linked with a
+// Balancing configuration.
+func (nl *negLinker) wasBalanced(beName string) bool {
+	nl.balancedLock.Lock()
+	defer nl.balancedLock.Unlock()
+	return nl.balanced.Has(beName)
+}
+
+// setBalanced records whether the backend service was linked with a
+// Balancing configuration.
+func (nl *negLinker) setBalanced(beName string, balanced bool) {
+	nl.balancedLock.Lock()
+	defer nl.balancedLock.Unlock()
+	if balanced {
+		nl.balanced.Insert(beName)
+	} else {
+		nl.balanced.Delete(beName)
+	}
 }
 
 type backendDiff struct {
@@ -162,7 +205,14 @@ func mergeBackends(old, new []*composite.Backend) ([]*composite.Backend, error)
 	return ret, nil
 }
 
-func diffBackends(old, new []*composite.Backend, logger klog.Logger) *backendDiff {
+// compareCapacity returns true if the balancing mode and capacity of the
+// backends of the ServicePort are managed by the controller and must be
+// compared when computing diffs.
+func compareCapacity(sp *utils.ServicePort) bool {
+	return flags.F.EnableTrafficScaling || balancingConfig(sp) != nil
+}
+
+func diffBackends(old, new []*composite.Backend, compareCapacity bool, logger klog.Logger) *backendDiff {
 	d := &backendDiff{
 		old:     sets.NewString(),
 		new:     sets.NewString(),
@@ -184,9 +234,12 @@ func diffBackends(old, new []*composite.Backend, logger klog.Logger) *backendDif
 			// value (e.g. CapacityScaler is 1.0), you will need to set that
 			// value when creating a new Backend to avoid a false positive when
 			// computing diffs.
-			if flags.F.EnableTrafficScaling {
+			if compareCapacity {
 				var changed bool
+				changed = changed || oldBe.BalancingMode != be.BalancingMode
 				changed = changed || oldBe.MaxRatePerEndpoint != be.MaxRatePerEndpoint
+				changed = changed || oldBe.MaxConnectionsPerEndpoint != be.MaxConnectionsPerEndpoint
+				changed = changed || oldBe.MaxUtilization != be.MaxUtilization
 				changed = changed || oldBe.CapacityScaler != be.CapacityScaler
 				if changed {
 					d.changed.Insert(beGroup)
@@ -231,6 +284,14 @@ func backendsForNEGs(negSelfLinks []string, sp *utils.ServicePort) []*composite.
 					newBackend.CapacityScaler = *sp.CapacityScaler
 				}
 			}
+			if config := balancingConfig(sp); config != nil {
+				applyBalancing(newBackend, neg, config)
+			}
+			// CapacityScaler is omitted when empty, and a scaler of 0 drains
+			// the backend rather than falling back to the default of GCE.
+			if newBackend.CapacityScaler == 0 {
+				newBackend.ForceSendFields = append(newBackend.ForceSendFields, "CapacityScaler")
+			}
 		}
 
 		backends = append(backends, newBackend)
@@ -238,6 +299,60 @@ func backendsForNEGs(negSelfLinks []string, sp *utils.ServicePort) []*composite.
 	return backends
 }
 
+// balancingConfig returns the Balancing configuration of the BackendConfig of
+// the ServicePort, if any.
+func balancingConfig(sp *utils.ServicePort) *backendconfigv1.BalancingConfig {
+	if sp.BackendConfig == nil {
+		return nil
+	}
+	return sp.BackendConfig.Spec.Balancing
+}
+
+// applyBalancing applies the Balancing configuration to the backend of the
+// given NEG, overriding the max-rate-per-endpoint and capacity-scaler Service
+// annotations. The capacity scaler of the zone of the NEG takes precedence
+// over the one of the Service.
+func applyBalancing(backend *composite.Backend, negUrl string, config *backendconfigv1.BalancingConfig) {
+	switch BalancingMode(config.BalancingMode) {
+	case Utilization:
+		backend.BalancingMode = string(Utilization)
+		backend.MaxRatePerEndpoint = 0
+		// Set the default explicitly, GCE fills it in otherwise and the
+		// backend would always differ.
+		backend.MaxUtilization = defaultMaxUtilization
+		if config.MaxUtilization != nil {
+			backend.MaxUtilization = *config.MaxUtilization
+		}
+	case Connections:
+		backend.BalancingMode = string(Connections)
+		backend.MaxRatePerEndpoint = 0
+		if config.MaxConnectionsPerEndpoint != nil {
+			backend.MaxConnectionsPerEndpoint = *config.MaxConnectionsPerEndpoint
+		}
+	default:
+		backend.BalancingMode = string(Rate)
+		if config.MaxRatePerEndpoint != nil {
+			backend.MaxRatePerEndpoint = *config.MaxRatePerEndpoint
+		}
+	}
+
+	if config.CapacityScaler != nil {
+		backend.CapacityScaler = *config.CapacityScaler
+	}
+	if len(config.ZoneCapacityScalers) == 0 {
+		return
+	}
+	key, err := getNegMergeGroupKey(negUrl)
+	if err != nil {
+		return
+	}
+	for _, zone := range config.ZoneCapacityScalers {
+		if zone.Zone == key.Zone {
+			backend.CapacityScaler = zone.CapacityScaler
+		}
+	}
+}
+
 // getNegType returns NEG type based on service port config
 func getNegType(sp utils.ServicePort) types.NetworkEndpointType {
 	if sp.VMIPNEGEnabled {
@@ -14,6 +14,8 @@ limitations under the License.
 package backends
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -23,6 +25,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
 	"github.com/google/go-cmp/cmp"
 	"github.com/kr/pretty"
@@ -30,11 +33,14 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cloud-provider-gcp/providers/gce"
 	"k8s.io/ingress-gce/pkg/annotations"
+	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
 	befeatures "k8s.io/ingress-gce/pkg/backends/features"
 	"k8s.io/ingress-gce/pkg/composite"
 	"k8s.io/ingress-gce/pkg/flags"
 	negtypes "k8s.io/ingress-gce/pkg/neg/types"
 	"k8s.io/ingress-gce/pkg/utils"
+
+	compute "google.golang.org/api/compute/v1"
 )
 
 func newTestNEGLinker(fakeNEG negtypes.NetworkEndpointGroupCloud, fakeGCE *gce.Cloud) *negLinker {
@@ -48,7 +54,14 @@ func newTestNEGLinker(fakeNEG negtypes.NetworkEndpointGroupCloud, fakeGCE *gce.C
 	(fakeGCE.Compute().(*cloud.MockGCE)).MockAlphaRegionBackendServices.UpdateHook = mock.UpdateAlphaRegionBackendServiceHook
 	(fakeGCE.Compute().(*cloud.MockGCE)).MockBetaRegionBackendServices.UpdateHook = mock.UpdateBetaRegionBackendServiceHook
 	(fakeGCE.Compute().(*cloud.MockGCE)).MockRegionBackendServices.UpdateHook = mock.UpdateRegionBackendServiceHook
-	return &negLinker{fakeBackendPool, fakeNEG, fakeGCE, ctx.SvcNegInformer.GetIndexer(), klog.TODO()}
+	return &negLinker{
+		backendPool:  fakeBackendPool,
+		negGetter:    fakeNEG,
+		cloud:        fakeGCE,
+		svcNegLister: ctx.SvcNegInformer.GetIndexer(),
+		balanced:     sets.NewString(),
+		logger:       klog.TODO(),
+	}
 }
 
 func TestLinkBackendServiceToNEG(t *testing.T) {
@@ -177,6 +190,152 @@ func TestLinkBackendServiceToNEG(t *testing.T) {
 
 }
 
+// TestLinkDrainedZone checks that a capacity scaler of 0 is sent to GCE
+// rather than omitted, and that the drained backend is then up to date.
+func TestLinkDrainedZone(t *testing.T) {
+	fakeGCE := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
+	fakeNEG := negtypes.NewFakeNetworkEndpointGroupCloud("test-subnetwork", "test-network")
+	linker := newTestNEGLinker(fakeNEG, fakeGCE)
+
+	zones := []GroupKey{{Zone: "zone1"}, {Zone: "zone2"}}
+	svcPort := utils.ServicePort{
+		ID:           utils.ServicePortID{Service: types.NamespacedName{Namespace: "ns", Name: "name"}},
+		Port:         80,
+		NodePort:     30001,
+		Protocol:     annotations.ProtocolHTTP,
+		TargetPort:   intstr.FromString("port"),
+		NEGEnabled:   true,
+		BackendNamer: defaultNamer,
+		BackendConfig: &backendconfigv1.BackendConfig{
+			Spec: backendconfigv1.BackendConfigSpec{
+				Balancing: &backendconfigv1.BalancingConfig{
+					ZoneCapacityScalers: []backendconfigv1.ZoneCapacityScaler{{Zone: "zone2", CapacityScaler: 0}},
+				},
+			},
+		},
+	}
+	if _, err := linker.backendPool.Create(svcPort, "fake-healthcheck-link", klog.TODO()); err != nil {
+		t.Fatalf("Failed to create backend service for svcPort %v: %v", svcPort, err)
+	}
+	for _, key := range zones {
+		neg := &composite.NetworkEndpointGroup{Name: svcPort.NEGName(), Version: meta.VersionGA}
+		if err := fakeNEG.CreateNetworkEndpointGroup(neg, key.Zone, klog.TODO()); err != nil {
+			t.Fatalf("Failed to create NEG for svcPort %v: %v", svcPort, err)
+		}
+	}
+
+	var requests [][]byte
+	(fakeGCE.Compute().(*cloud.MockGCE)).MockBackendServices.UpdateHook = func(ctx context.Context, key *meta.Key, obj *compute.BackendService, m *cloud.MockBackendServices, options ...cloud.Option) error {
+		raw, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		requests = append(requests, raw)
+		return mock.UpdateBackendServiceHook(ctx, key, obj, m, options...)
+	}
+	for i := 0; i < 2; i++ {
+		if err := linker.Link(svcPort, zones); err != nil {
+			t.Fatalf("Link(_, _) = %v", err)
+		}
+	}
+	if len(requests) != 1 {
+		t.Fatalf("Link(_, _) twice sent %d updates, want 1", len(requests))
+	}
+
+	var sent struct {
+		Backends []map[string]interface{} `json:"backends"`
+	}
+	if err := json.Unmarshal(requests[0], &sent); err != nil {
+		t.Fatalf("json.Unmarshal(%s) = %v", requests[0], err)
+	}
+	if len(sent.Backends) != len(zones) {
+		t.Fatalf("Update request has %d backends, want %d: %s", len(sent.Backends), len(zones), requests[0])
+	}
+	for _, be := range sent.Backends {
+		want := 1.0
+		if strings.Contains(be["group"].(string), "/zones/zone2/") {
+			want = 0
+		}
+		if got, ok := be["capacityScaler"]; !ok || got != want {
+			t.Errorf("Update request sent capacityScaler %v for backend %v, want %v", got, be["group"], want)
+		}
+	}
+}
+
+// TestLinkBalancingRemoved asserts that the balancing mode and capacity of
+// the backends are reverted when the Balancing configuration which set them is
+// removed, and that the capacity set out of band is left alone otherwise.
+func TestLinkBalancingRemoved(t *testing.T) {
+	fakeGCE := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
+	fakeNEG := negtypes.NewFakeNetworkEndpointGroupCloud("test-subnetwork", "test-network")
+	linker := newTestNEGLinker(fakeNEG, fakeGCE)
+
+	zones := []GroupKey{{Zone: "zone1"}}
+	rate := float64(5)
+	scaler := 0.5
+	svcPort := utils.ServicePort{
+		ID:           utils.ServicePortID{Service: types.NamespacedName{Namespace: "ns", Name: "name"}},
+		Port:         80,
+		NodePort:     30001,
+		Protocol:     annotations.ProtocolHTTP,
+		TargetPort:   intstr.FromString("port"),
+		NEGEnabled:   true,
+		BackendNamer: defaultNamer,
+		BackendConfig: &backendconfigv1.BackendConfig{
+			Spec: backendconfigv1.BackendConfigSpec{
+				Balancing: &backendconfigv1.BalancingConfig{MaxRatePerEndpoint: &rate, CapacityScaler: &scaler},
+			},
+		},
+	}
+	if _, err := linker.backendPool.Create(svcPort, "fake-healthcheck-link", klog.TODO()); err != nil {
+		t.Fatalf("Failed to create backend service for svcPort %v: %v", svcPort, err)
+	}
+	for _, key := range zones {
+		neg := &composite.NetworkEndpointGroup{Name: svcPort.NEGName(), Version: meta.VersionGA}
+		if err := fakeNEG.CreateNetworkEndpointGroup(neg, key.Zone, klog.TODO()); err != nil {
+			t.Fatalf("Failed to create NEG for svcPort %v: %v", svcPort, err)
+		}
+	}
+	backend := func() *composite.Backend {
+		t.Helper()
+		bs, err := composite.GetBackendService(fakeGCE, meta.GlobalKey(svcPort.BackendName()), meta.VersionGA, klog.TODO())
+		if err != nil || len(bs.Backends) != 1 {
+			t.Fatalf("GetBackendService() = %v, %v, want 1 backend", bs, err)
+		}
+		return bs.Backends[0]
+	}
+
+	if err := linker.Link(svcPort, zones); err != nil {
+		t.Fatalf("Link(_, _) = %v", err)
+	}
+	if be := backend(); be.MaxRatePerEndpoint != rate || be.CapacityScaler != scaler {
+		t.Errorf("Link(_, _) with balancing set max rate %v and capacity scaler %v, want %v and %v", be.MaxRatePerEndpoint, be.CapacityScaler, rate, scaler)
+	}
+
+	// Removing the Balancing configuration reverts the backends.
+	svcPort.BackendConfig = nil
+	if err := linker.Link(svcPort, zones); err != nil {
+		t.Fatalf("Link(_, _) = %v", err)
+	}
+	if be := backend(); be.BalancingMode != string(Rate) || be.MaxRatePerEndpoint != maxRPS || be.CapacityScaler != 1.0 {
+		t.Errorf("Link(_, _) after balancing removed set %s, max rate %v and capacity scaler %v, want RATE, %v and 1", be.BalancingMode, be.MaxRatePerEndpoint, be.CapacityScaler, maxRPS)
+	}
+
+	// The capacity set out of band is left alone.
+	mockBS := fakeGCE.Compute().(*cloud.MockGCE).MockBackendServices
+	for k, obj := range mockBS.Objects {
+		bs := obj.ToGA()
+		bs.Backends[0].CapacityScaler = 0.2
+		mockBS.Objects[k] = &cloud.MockBackendServicesObj{Obj: bs}
+	}
+	if err := linker.Link(svcPort, zones); err != nil {
+		t.Fatalf("Link(_, _) = %v", err)
+	}
+	if be := backend(); be.CapacityScaler != 0.2 {
+		t.Errorf("Link(_, _) changed the capacity scaler set out of band to %v, want 0.2", be.CapacityScaler)
+	}
+}
+
 func TestMergeBackends(t *testing.T) {
 	t.Parallel()
 
@@ -260,7 +419,7 @@ func TestMergeBackends(t *testing.T) {
 			}
 
 			if !tc.expectError {
-				diffBackend := diffBackends(tc.expect, ret, klog.TODO())
+				diffBackend := diffBackends(tc.expect, ret, true, klog.TODO())
 				if !diffBackend.isEqual() {
 					t.Errorf("Expect tc.expect == ret, however got, tc.expect = %v, ret = %v", tc.expect, ret)
 				}
@@ -270,16 +429,15 @@ func TestMergeBackends(t *testing.T) {
 }
 
 func TestDiffBackends(t *testing.T) {
-	// No t.Parallel().
-	oldFlag := flags.F.EnableTrafficScaling
-	flags.F.EnableTrafficScaling = true
-	defer func() { flags.F.EnableTrafficScaling = oldFlag }()
-
 	for _, tc := range []struct {
 		name string
 		old  []*composite.Backend
 		new  []*composite.Backend
 
+		// ignoreCapacity is true if the capacity of the backends is not
+		// managed by the controller.
+		ignoreCapacity bool
+
 		isEqual  bool
 		toRemove sets.String
 		toAdd    sets.String
@@ -344,15 +502,55 @@ func TestDiffBackends(t *testing.T) {
 			new:     []*composite.Backend{{Group: "a", CapacityScaler: 0.5}},
 			changed: sets.NewString("a"),
 		},
+		{
+			name:    "update balancing mode",
+			old:     []*composite.Backend{{Group: "a", BalancingMode: "RATE", MaxRatePerEndpoint: 1}},
+			new:     []*composite.Backend{{Group: "a", BalancingMode: "CONNECTION", MaxConnectionsPerEndpoint: 10}},
+			changed: sets.NewString("a"),
+		},
+		{
+			name:    "update max utilization",
+			old:     []*composite.Backend{{Group: "a", BalancingMode: "UTILIZATION", MaxUtilization: 0.8}},
+			new:     []*composite.Backend{{Group: "a", BalancingMode: "UTILIZATION", MaxUtilization: 0.5}},
+			changed: sets.NewString("a"),
+		},
 		{
 			name:    "no change",
 			old:     []*composite.Backend{{Group: "a", CapacityScaler: 1.0}},
 			new:     []*composite.Backend{{Group: "a", CapacityScaler: 1.0}},
 			isEqual: true,
 		},
+		{
+			name:           "unmanaged rate",
+			old:            []*composite.Backend{{Group: "a", BalancingMode: "RATE", MaxRatePerEndpoint: 1, CapacityScaler: 1.0}},
+			new:            []*composite.Backend{{Group: "a", BalancingMode: "RATE", MaxRatePerEndpoint: 3, CapacityScaler: 1.0}},
+			ignoreCapacity: true,
+			isEqual:        true,
+		},
+		{
+			name:           "unmanaged connection backends",
+			old:            []*composite.Backend{{Group: "a", BalancingMode: "CONNECTION", CapacityScaler: 1.0}},
+			new:            []*composite.Backend{{Group: "a", BalancingMode: "CONNECTION"}},
+			ignoreCapacity: true,
+			isEqual:        true,
+		},
+		{
+			name:           "unmanaged balancing mode set out of band",
+			old:            []*composite.Backend{{Group: "a", BalancingMode: "UTILIZATION", MaxUtilization: 0.8, CapacityScaler: 1.0}},
+			new:            []*composite.Backend{{Group: "a", BalancingMode: "RATE", MaxRatePerEndpoint: maxRPS, CapacityScaler: 1.0}},
+			ignoreCapacity: true,
+			isEqual:        true,
+		},
+		{
+			name:           "unmanaged capacity scaler set out of band",
+			old:            []*composite.Backend{{Group: "a", BalancingMode: "RATE", MaxRatePerEndpoint: maxRPS, CapacityScaler: 0}},
+			new:            []*composite.Backend{{Group: "a", BalancingMode: "RATE", MaxRatePerEndpoint: maxRPS, CapacityScaler: 1.0}},
+			ignoreCapacity: true,
+			isEqual:        true,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			diff := diffBackends(tc.old, tc.new, klog.TODO())
+			diff := diffBackends(tc.old, tc.new, !tc.ignoreCapacity, klog.TODO())
 			if got := diff.isEqual(); got != tc.isEqual {
 				t.Errorf("diff := diffBackends(%s, %s); diff.isEqual() = %t, want %t", pretty.Sprint(tc.old), pretty.Sprint(tc.new), got, tc.isEqual)
 			}
@@ -376,6 +574,13 @@ func TestBackendsForNEG(t *testing.T) {
 	defer func() { flags.F.EnableTrafficScaling = oldFlag }()
 
 	f64 := func(x float64) *float64 { return &x }
+	i64 := func(x int64) *int64 { return &x }
+	negUrlA := "https://www.googleapis.com/compute/v1/projects/project-name/zones/us-central1-a/networkEndpointGroups/k8s1-neg-name"
+	negUrlB := "https://www.googleapis.com/compute/v1/projects/project-name/zones/us-central1-b/networkEndpointGroups/k8s1-neg-name"
+	withBalancing := func(sp *utils.ServicePort, balancing *backendconfigv1.BalancingConfig) *utils.ServicePort {
+		sp.BackendConfig = &backendconfigv1.BackendConfig{Spec: backendconfigv1.BackendConfigSpec{Balancing: balancing}}
+		return sp
+	}
 
 	for _, tc := range []struct {
 		name string
@@ -537,6 +742,94 @@ func TestBackendsForNEG(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "neg endpoint (backend config overrides traffic policy)",
+			negs: []*composite.NetworkEndpointGroup{
+				{
+					NetworkEndpointType: string(negtypes.VmIpPortEndpointType),
+					SelfLink:            "/neg1",
+				},
+			},
+			sp: withBalancing(&utils.ServicePort{MaxRatePerEndpoint: f64(1234), CapacityScaler: f64(0.5)},
+				&backendconfigv1.BalancingConfig{MaxRatePerEndpoint: f64(100), CapacityScaler: f64(0.8)}),
+			want: []*composite.Backend{
+				{
+					BalancingMode:      "RATE",
+					MaxRatePerEndpoint: 100,
+					CapacityScaler:     0.8,
+					Group:              "/neg1",
+				},
+			},
+		},
+		{
+			name: "neg endpoint (backend config connection mode)",
+			negs: []*composite.NetworkEndpointGroup{
+				{
+					NetworkEndpointType: string(negtypes.VmIpPortEndpointType),
+					SelfLink:            "/neg1",
+				},
+			},
+			sp: withBalancing(&utils.ServicePort{},
+				&backendconfigv1.BalancingConfig{BalancingMode: "CONNECTION", MaxConnectionsPerEndpoint: i64(50)}),
+			want: []*composite.Backend{
+				{
+					BalancingMode:             "CONNECTION",
+					MaxConnectionsPerEndpoint: 50,
+					CapacityScaler:            1.0,
+					Group:                     "/neg1",
+				},
+			},
+		},
+		{
+			name: "neg endpoint (backend config utilization mode default)",
+			negs: []*composite.NetworkEndpointGroup{
+				{
+					NetworkEndpointType: string(negtypes.VmIpPortEndpointType),
+					SelfLink:            "/neg1",
+				},
+			},
+			sp: withBalancing(&utils.ServicePort{}, &backendconfigv1.BalancingConfig{BalancingMode: "UTILIZATION"}),
+			want: []*composite.Backend{
+				{
+					BalancingMode:  "UTILIZATION",
+					MaxUtilization: defaultMaxUtilization,
+					CapacityScaler: 1.0,
+					Group:          "/neg1",
+				},
+			},
+		},
+		{
+			name: "neg endpoint (backend config drains a zone)",
+			negs: []*composite.NetworkEndpointGroup{
+				{
+					NetworkEndpointType: string(negtypes.VmIpPortEndpointType),
+					SelfLink:            negUrlA,
+				},
+				{
+					NetworkEndpointType: string(negtypes.VmIpPortEndpointType),
+					SelfLink:            negUrlB,
+				},
+			},
+			sp: withBalancing(&utils.ServicePort{}, &backendconfigv1.BalancingConfig{
+				CapacityScaler:      f64(0.5),
+				ZoneCapacityScalers: []backendconfigv1.ZoneCapacityScaler{{Zone: "us-central1-b", CapacityScaler: 0}},
+			}),
+			want: []*composite.Backend{
+				{
+					BalancingMode:      "RATE",
+					MaxRatePerEndpoint: maxRPS,
+					CapacityScaler:     0.5,
+					Group:              negUrlA,
+				},
+				{
+					BalancingMode:      "RATE",
+					MaxRatePerEndpoint: maxRPS,
+					CapacityScaler:     0,
+					Group:              negUrlB,
+					ForceSendFields:    []string{"CapacityScaler"},
+				},
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			negUrls := []string{}
@@ -15,6 +15,7 @@ package backends
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
@@ -23,6 +24,7 @@ import (
 	"k8s.io/cloud-provider-gcp/providers/gce"
 	"k8s.io/ingress-gce/pkg/backends/features"
 	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/dryrun"
 	"k8s.io/ingress-gce/pkg/healthchecks"
 	lbfeatures "k8s.io/ingress-gce/pkg/loadbalancers/features"
 	"k8s.io/ingress-gce/pkg/utils"
@@ -67,6 +69,49 @@ func (s *backendSyncer) Sync(svcPorts []utils.ServicePort, ingLogger klog.Logger
 	return nil
 }
 
+// Plan implements Syncer.
+func (s *backendSyncer) Plan(svcPorts []utils.ServicePort, ingLogger klog.Logger) (*dryrun.Plan, error) {
+	plan := &dryrun.Plan{}
+	for _, sp := range svcPorts {
+		beName := sp.BackendName()
+		version := features.VersionFromServicePort(&sp)
+		scope := features.ScopeFromServicePort(&sp)
+		beLogger := ingLogger.WithValues("backendServiceName", beName, "backendVersion", version, "backendScope", scope)
+
+		// Health checks are named after their backend service. Only missing
+		// health checks are planned, updates depend on the probes of the pods.
+		var hcLink string
+		if !sp.InternetNEGEnabled {
+			hc, err := s.healthChecker.Get(beName, version, scope, beLogger)
+			if err != nil {
+				if !utils.IsNotFoundError(err) {
+					return nil, err
+				}
+				plan.Create("HealthCheck", beName)
+			} else {
+				hcLink = hc.SelfLink
+			}
+		}
+
+		be, err := s.backendPool.Get(beName, version, scope, beLogger)
+		if err != nil {
+			if !utils.IsNotFoundError(err) {
+				return nil, err
+			}
+			plan.Create("BackendService", beName)
+			continue
+		}
+		fields, err := applyServicePort(sp, be, hcLink, beLogger)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) > 0 {
+			plan.Update("BackendService", beName, fields...)
+		}
+	}
+	return plan, nil
+}
+
 // ensureBackendService will update or create a BackendService for the given port.
 func (s *backendSyncer) ensureBackendService(sp utils.ServicePort, ingLogger klog.Logger) error {
 	// We must track the ports even if creating the backends failed, because
@@ -84,10 +129,15 @@ func (s *backendSyncer) ensureBackendService(sp utils.ServicePort, ingLogger klo
 	)
 	be, getErr := s.backendPool.Get(beName, version, scope, beLogger)
 
-	// Ensure health check for backend service exists.
-	hcLink, err := s.ensureHealthCheck(sp, beLogger)
-	if err != nil {
-		return fmt.Errorf("error ensuring health check: %w", err)
+	// Ensure health check for backend service exists. Internet NEGs are not
+	// health checked.
+	var hcLink string
+	var err error
+	if !sp.InternetNEGEnabled {
+		hcLink, err = s.ensureHealthCheck(sp, beLogger)
+		if err != nil {
+			return fmt.Errorf("error ensuring health check: %w", err)
+		}
 	}
 
 	// Verify existence of a backend service for the proper port
@@ -104,27 +154,12 @@ func (s *backendSyncer) ensureBackendService(sp utils.ServicePort, ingLogger klo
 		}
 	}
 
-	needUpdate := ensureProtocol(be, sp)
-	needUpdate = ensureHealthCheckLink(be, hcLink) || needUpdate
-	needUpdate = ensureDescription(be, &sp) || needUpdate
-	if sp.BackendConfig != nil {
-		needUpdate = features.EnsureCDN(sp, be, beLogger) || needUpdate
-		needUpdate = features.EnsureTimeout(sp, be, beLogger) || needUpdate
-		needUpdate = features.EnsureDraining(sp, be, beLogger) || needUpdate
-		needUpdate = features.EnsureAffinity(sp, be, beLogger) || needUpdate
-		needUpdate = features.EnsureCustomRequestHeaders(sp, be, beLogger) || needUpdate
-		needUpdate = features.EnsureCustomResponseHeaders(sp, be, beLogger) || needUpdate
-		needUpdate = features.EnsureLogging(sp, be, beLogger) || needUpdate
-
-		updateIAP, err := features.EnsureIAP(sp, be, beLogger)
-		if err != nil {
-			beLogger.Error(err, "Errored ensuring IAP")
-			return err
-		}
-		needUpdate = updateIAP || needUpdate
+	changedFields, err := applyServicePort(sp, be, hcLink, beLogger)
+	if err != nil {
+		return err
 	}
 
-	if needUpdate {
+	if len(changedFields) > 0 {
 		if err := s.backendPool.Update(be, beLogger); err != nil {
 			return err
 		}
@@ -147,6 +182,41 @@ func (s *backendSyncer) ensureBackendService(sp utils.ServicePort, ingLogger klo
 	return nil
 }
 
+// applyServicePort updates the BackendService with the configuration of the
+// ServicePort and returns the names of the fields which were changed.
+func applyServicePort(sp utils.ServicePort, be *composite.BackendService, hcLink string, beLogger klog.Logger) ([]string, error) {
+	var fields []string
+	changed := func(needUpdate bool, field string) {
+		if needUpdate {
+			fields = append(fields, field)
+		}
+	}
+	changed(ensureProtocol(be, sp), "Protocol")
+	changed(ensureHealthCheckLink(be, hcLink), "HealthChecks")
+	changed(ensureDescription(be, &sp), "Description")
+	if sp.BackendConfig != nil {
+		changed(features.EnsureCDN(sp, be, beLogger), "CdnPolicy")
+		changed(features.EnsureTimeout(sp, be, beLogger), "TimeoutSec")
+		changed(features.EnsureDraining(sp, be, beLogger), "ConnectionDraining")
+		changed(features.EnsureAffinity(sp, be, beLogger), "SessionAffinity")
+		changed(features.EnsureCustomRequestHeaders(sp, be, beLogger), "CustomRequestHeaders")
+		changed(features.EnsureCustomResponseHeaders(sp, be, beLogger), "CustomResponseHeaders")
+		changed(features.EnsureLogging(sp, be, beLogger), "LogConfig")
+		changed(features.EnsureOutlierDetection(sp, be, beLogger), "OutlierDetection")
+		changed(features.EnsureCircuitBreakers(sp, be, beLogger), "CircuitBreakers")
+		changed(features.EnsureLocalityLbPolicy(sp, be, beLogger), "LocalityLbPolicy")
+		changed(features.EnsureConsistentHash(sp, be, beLogger), "ConsistentHash")
+
+		updateIAP, err := features.EnsureIAP(sp, be, beLogger)
+		if err != nil {
+			beLogger.Error(err, "Errored ensuring IAP")
+			return nil, err
+		}
+		changed(updateIAP, "Iap")
+	}
+	return fields, nil
+}
+
 func (s *backendSyncer) ensureBackendSignedUrlKeys(sp utils.ServicePort, be *composite.BackendService, beLogger klog.Logger) error {
 
 	existingKeyNames := map[string]bool{}
@@ -231,6 +301,58 @@ func (s *backendSyncer) GC(svcPorts []utils.ServicePort, ingLogger klog.Logger)
 	return nil
 }
 
+// PlanGC implements Syncer.
+func (s *backendSyncer) PlanGC(svcPorts []utils.ServicePort, ingLogger klog.Logger) (*dryrun.Plan, error) {
+	knownPorts, err := knownPortsFromServicePorts(s.cloud, svcPorts)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &dryrun.Plan{}
+	// GC lists the regional backends of L7 ILB, then the global ones.
+	for _, l := range []struct {
+		scope   meta.KeyType
+		version meta.Version
+	}{
+		{scope: meta.Regional, version: lbfeatures.L7ILBVersions().BackendService},
+		{scope: meta.Global, version: meta.VersionGA},
+	} {
+		key, err := composite.CreateKey(s.cloud, "", l.scope)
+		if err != nil {
+			return nil, err
+		}
+		backends, err := s.backendPool.List(key, l.version, ingLogger)
+		if err != nil {
+			return nil, fmt.Errorf("error listing backends: %w", err)
+		}
+		// Sort the backends so that the plan does not change between syncs.
+		sort.Slice(backends, func(i, j int) bool { return backends[i].Name < backends[j].Name })
+		for _, be := range backends {
+			if strings.Contains(be.Description, utils.L4ILBServiceDescKey) {
+				continue
+			}
+			scope, err := composite.ScopeFromSelfLink(be.SelfLink)
+			if err != nil {
+				return nil, err
+			}
+			if key, err = composite.CreateKey(s.cloud, be.Name, scope); err != nil {
+				return nil, err
+			}
+			if knownPorts.Has(key.String()) {
+				continue
+			}
+			plan.Delete("BackendService", be.Name)
+			// Health checks are named after their backend service.
+			if _, err := s.healthChecker.Get(be.Name, be.Version, scope, ingLogger); err == nil {
+				plan.Delete("HealthCheck", be.Name)
+			} else if !utils.IsNotFoundError(err) {
+				return nil, err
+			}
+		}
+	}
+	return plan, nil
+}
+
 // gc deletes the provided backends
 func (s *backendSyncer) gc(backends []*composite.BackendService, knownPorts sets.String, ingLogger klog.Logger) error {
 	for _, be := range backends {
@@ -339,6 +461,14 @@ func ensureProtocol(be *composite.BackendService, p utils.ServicePort) (needsUpd
 
 // ensureHealthCheckLink updates the BackendService HealthCheck with the expected value
 func ensureHealthCheckLink(be *composite.BackendService, hcLink string) (needsUpdate bool) {
+	if hcLink == "" {
+		// The backend service has no health check.
+		if len(be.HealthChecks) == 0 {
+			return false
+		}
+		be.HealthChecks = nil
+		return true
+	}
 	existingHCLink := getHealthCheckLink(be)
 
 	if utils.EqualResourceIDs(existingHCLink, hcLink) {
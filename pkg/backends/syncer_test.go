@@ -26,6 +26,7 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/google/go-cmp/cmp"
 	computebeta "google.golang.org/api/compute/v0.beta"
 	"google.golang.org/api/compute/v1"
 	"google.golang.org/api/googleapi"
@@ -37,6 +38,7 @@ import (
 	"k8s.io/ingress-gce/pkg/annotations"
 	"k8s.io/ingress-gce/pkg/backends/features"
 	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/dryrun"
 	"k8s.io/ingress-gce/pkg/healthchecks"
 	"k8s.io/ingress-gce/pkg/utils"
 	"k8s.io/ingress-gce/pkg/utils/namer"
@@ -340,6 +342,50 @@ func TestGC(t *testing.T) {
 	}
 }
 
+func TestPlanGC(t *testing.T) {
+	fakeGCE := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
+	syncer := newTestSyncer(fakeGCE)
+
+	svcNodePorts := []utils.ServicePort{
+		{NodePort: 81, Protocol: annotations.ProtocolHTTP, BackendNamer: defaultNamer},
+		{NodePort: 82, Protocol: annotations.ProtocolHTTPS, BackendNamer: defaultNamer},
+		{NodePort: 83, Protocol: annotations.ProtocolHTTP, BackendNamer: defaultNamer},
+	}
+	ps := newPortset(svcNodePorts)
+	if err := ps.add(svcNodePorts); err != nil {
+		t.Fatal(err)
+	}
+	if err := syncer.Sync(ps.existingPorts(), klog.TODO()); err != nil {
+		t.Fatalf("syncer.Sync(%+v) = %v, want nil ", ps.existingPorts(), err)
+	}
+
+	plan, err := syncer.PlanGC(ps.existingPorts(), klog.TODO())
+	if err != nil {
+		t.Fatalf("syncer.PlanGC(%+v) = %v, want nil", ps.existingPorts(), err)
+	}
+	if len(plan.Changes) != 0 {
+		t.Errorf("syncer.PlanGC(%+v) = %v, want no changes", ps.existingPorts(), plan)
+	}
+
+	plan, err = syncer.PlanGC(svcNodePorts[:1], klog.TODO())
+	if err != nil {
+		t.Fatalf("syncer.PlanGC(%+v) = %v, want nil", svcNodePorts[:1], err)
+	}
+	var want []dryrun.Change
+	for _, sp := range svcNodePorts[1:] {
+		want = append(want,
+			dryrun.Change{Operation: dryrun.OperationDelete, Resource: "BackendService", Name: sp.BackendName()},
+			dryrun.Change{Operation: dryrun.OperationDelete, Resource: "HealthCheck", Name: sp.BackendName()})
+	}
+	if diff := cmp.Diff(want, plan.Changes); diff != "" {
+		t.Errorf("syncer.PlanGC() returned unexpected diff (-want +got):\n%s", diff)
+	}
+	// Planning does not delete anything.
+	if err := ps.check(fakeGCE); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // Test GC with both ELB and ILBs. Add in an L4 ILB NEG which should not be deleted as part of GC.
 func TestGCMixed(t *testing.T) {
 	fakeGCE := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
@@ -560,6 +606,44 @@ func TestSyncNEG(t *testing.T) {
 	}
 }
 
+func TestSyncInternetNEG(t *testing.T) {
+	fakeGCE := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
+	syncer := newTestSyncer(fakeGCE)
+
+	svcPort := utils.ServicePort{
+		ID:                 utils.ServicePortID{Service: types.NamespacedName{Namespace: "ns", Name: "saas"}},
+		Port:               443,
+		Protocol:           annotations.ProtocolHTTPS,
+		InternetNEGEnabled: true,
+		ExternalName:       "api.example.com",
+		BackendNamer:       defaultNamer,
+	}
+	if err := syncer.Sync([]utils.ServicePort{svcPort}, klog.TODO()); err != nil {
+		t.Fatalf("Sync() = %v, want nil", err)
+	}
+
+	beName := svcPort.BackendName()
+	if beName != svcPort.NEGName() {
+		t.Errorf("BackendName() = %q, want the NEG name %q", beName, svcPort.NEGName())
+	}
+	be, err := syncer.backendPool.Get(beName, features.VersionFromServicePort(&svcPort), features.ScopeFromServicePort(&svcPort), klog.TODO())
+	if err != nil {
+		t.Fatalf("Failed to get backend service %v: %v", beName, err)
+	}
+	if len(be.HealthChecks) != 0 {
+		t.Errorf("Backend service %v has health checks %v, want none", beName, be.HealthChecks)
+	}
+	if hc, err := syncer.healthChecker.Get(beName, features.VersionFromServicePort(&svcPort), features.ScopeFromServicePort(&svcPort), klog.TODO()); err == nil {
+		t.Errorf("Expected no health check for Internet NEG, got %+v", hc)
+	}
+
+	// A backend service which had a health check loses it.
+	be.HealthChecks = []string{"hc-link"}
+	if needsUpdate := ensureHealthCheckLink(be, ""); !needsUpdate || len(be.HealthChecks) != 0 {
+		t.Errorf("ensureHealthCheckLink(_, \"\") = %t, health checks %v, want true and no health checks", needsUpdate, be.HealthChecks)
+	}
+}
+
 func TestShutdown(t *testing.T) {
 	fakeGCE := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
 	syncer := newTestSyncer(fakeGCE)
@@ -0,0 +1,32 @@
+package operator
+
+import (
+	v1 "k8s.io/api/networking/v1"
+	apisgcsbackend "k8s.io/ingress-gce/pkg/apis/gcsbackend"
+	gcsbackendv1beta1 "k8s.io/ingress-gce/pkg/apis/gcsbackend/v1beta1"
+	"k8s.io/ingress-gce/pkg/gcsbackend"
+)
+
+// doesIngressReferenceGCSBackend returns true if a path of the passed in
+// Ingress uses the passed in GCSBackend as its resource backend.
+func doesIngressReferenceGCSBackend(ing *v1.Ingress, gcsBackend *gcsbackendv1beta1.GCSBackend) bool {
+	if ing.Namespace != gcsBackend.Namespace {
+		return false
+	}
+
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, p := range rule.HTTP.Paths {
+			ref := p.Backend.Resource
+			if ref == nil || ref.APIGroup == nil {
+				continue
+			}
+			if *ref.APIGroup == apisgcsbackend.GroupName && ref.Kind == gcsbackend.Kind && ref.Name == gcsBackend.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package operator
+
+import (
+	"testing"
+
+	api_v1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/networking/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apisgcsbackend "k8s.io/ingress-gce/pkg/apis/gcsbackend"
+	gcsbackendv1beta1 "k8s.io/ingress-gce/pkg/apis/gcsbackend/v1beta1"
+)
+
+func TestDoesIngressReferenceGCSBackend(t *testing.T) {
+	t.Parallel()
+
+	gcsBackend := &gcsbackendv1beta1.GCSBackend{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "assets", Namespace: "test"},
+	}
+	group := apisgcsbackend.GroupName
+	otherGroup := "example.com"
+	ingress := func(namespace string, ref *api_v1.TypedLocalObjectReference) *v1.Ingress {
+		return &v1.Ingress{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "ing", Namespace: namespace},
+			Spec: v1.IngressSpec{
+				Rules: []v1.IngressRule{
+					{Host: "foo.com"},
+					{
+						IngressRuleValue: v1.IngressRuleValue{HTTP: &v1.HTTPIngressRuleValue{
+							Paths: []v1.HTTPIngressPath{
+								{Path: "/", Backend: v1.IngressBackend{Service: &v1.IngressServiceBackend{Name: "svc"}}},
+								{Path: "/assets", Backend: v1.IngressBackend{Resource: ref}},
+							},
+						}},
+					},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		desc     string
+		ing      *v1.Ingress
+		expected bool
+	}{
+		{
+			desc:     "ingress without resource backend",
+			ing:      ingress("test", nil),
+			expected: false,
+		},
+		{
+			desc:     "ingress with other gcs backend",
+			ing:      ingress("test", &api_v1.TypedLocalObjectReference{APIGroup: &group, Kind: "GCSBackend", Name: "other"}),
+			expected: false,
+		},
+		{
+			desc:     "ingress with resource backend of another group",
+			ing:      ingress("test", &api_v1.TypedLocalObjectReference{APIGroup: &otherGroup, Kind: "GCSBackend", Name: "assets"}),
+			expected: false,
+		},
+		{
+			desc:     "ingress in different namespace",
+			ing:      ingress("other", &api_v1.TypedLocalObjectReference{APIGroup: &group, Kind: "GCSBackend", Name: "assets"}),
+			expected: false,
+		},
+		{
+			desc:     "ingress with expected gcs backend",
+			ing:      ingress("test", &api_v1.TypedLocalObjectReference{APIGroup: &group, Kind: "GCSBackend", Name: "assets"}),
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			result := doesIngressReferenceGCSBackend(tc.ing, gcsBackend)
+			if result != tc.expected {
+				t.Fatalf("Expected result to be %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
 	frontendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1"
+	gcsbackendv1beta1 "k8s.io/ingress-gce/pkg/apis/gcsbackend/v1beta1"
 
 	api_v1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
@@ -100,3 +101,18 @@ func (op *IngressesOperator) ReferencesFrontendConfig(feConfig *frontendconfigv1
 	}
 	return Ingresses(i)
 }
+
+// ReferencesGCSBackend returns the Ingresses that reference the given GCSBackend.
+func (op *IngressesOperator) ReferencesGCSBackend(gcsBackend *gcsbackendv1beta1.GCSBackend) *IngressesOperator {
+	dupes := map[string]bool{}
+
+	var i []*v1.Ingress
+	for _, ing := range op.i {
+		key := fmt.Sprintf("%s/%s", ing.Namespace, ing.Name)
+		if doesIngressReferenceGCSBackend(ing, gcsBackend) && !dupes[key] {
+			i = append(i, ing)
+			dupes[key] = true
+		}
+	}
+	return Ingresses(i)
+}
@@ -192,6 +192,33 @@ func SetSslPolicyForTargetHttpsProxy(gceCloud *gce.Cloud, key *meta.Key, targetH
 	}
 }
 
+// SetCertificateMapForTargetHttpsProxy() sets the certificate map for a global
+// target https proxy. An empty certMapLink detaches the map.
+func SetCertificateMapForTargetHttpsProxy(gceCloud *gce.Cloud, key *meta.Key, targetHttpsProxy *TargetHttpsProxy, certMapLink string, logger klog.Logger) error {
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("TargetHttpsProxy", "set_certificate_map", key.Region, key.Zone, string(targetHttpsProxy.Version))
+
+	if key.Type() != meta.Global {
+		return fmt.Errorf("key type %v is not valid. SetCertificateMapForTargetHttpsProxy is only supported for global TargetHttpsProxies", key)
+	}
+	// Set name in case it is not present in the key
+	key.Name = targetHttpsProxy.Name
+	logger.V(3).Info("setting CertificateMap for TargetHttpsProxy", "key", key)
+
+	switch targetHttpsProxy.Version {
+	case meta.VersionAlpha:
+		req := &computealpha.TargetHttpsProxiesSetCertificateMapRequest{CertificateMap: certMapLink}
+		return mc.Observe(gceCloud.Compute().AlphaTargetHttpsProxies().SetCertificateMap(ctx, key, req))
+	case meta.VersionBeta:
+		req := &computebeta.TargetHttpsProxiesSetCertificateMapRequest{CertificateMap: certMapLink}
+		return mc.Observe(gceCloud.Compute().BetaTargetHttpsProxies().SetCertificateMap(ctx, key, req))
+	default:
+		req := &compute.TargetHttpsProxiesSetCertificateMapRequest{CertificateMap: certMapLink}
+		return mc.Observe(gceCloud.Compute().TargetHttpsProxies().SetCertificateMap(ctx, key, req))
+	}
+}
+
 // SetUrlMapForTargetHttpProxy() sets the url map for a target proxy
 func SetUrlMapForTargetHttpProxy(gceCloud *gce.Cloud, key *meta.Key, targetHttpProxy *TargetHttpProxy, urlMapLink string, logger klog.Logger) error {
 	ctx, cancel := cloud.ContextWithCallTimeout()
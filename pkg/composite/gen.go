@@ -4497,6 +4497,12 @@ func (backendService *BackendService) ToAlpha() (*computealpha.BackendService, e
 			alpha.LogConfig.ForceSendFields = []string{"Enable", "SampleRate"}
 		}
 	}
+	// ForceSendFields of the backends are not copied via JSON.
+	for i, backend := range backendService.Backends {
+		if backend != nil && alpha.Backends[i] != nil {
+			alpha.Backends[i].ForceSendFields = backend.ForceSendFields
+		}
+	}
 
 	return alpha, nil
 }
@@ -4525,6 +4531,12 @@ func (backendService *BackendService) ToBeta() (*computebeta.BackendService, err
 			beta.LogConfig.ForceSendFields = []string{"Enable", "SampleRate"}
 		}
 	}
+	// ForceSendFields of the backends are not copied via JSON.
+	for i, backend := range backendService.Backends {
+		if backend != nil && beta.Backends[i] != nil {
+			beta.Backends[i].ForceSendFields = backend.ForceSendFields
+		}
+	}
 
 	return beta, nil
 }
@@ -4553,6 +4565,12 @@ func (backendService *BackendService) ToGA() (*compute.BackendService, error) {
 			ga.LogConfig.ForceSendFields = []string{"Enable", "SampleRate"}
 		}
 	}
+	// ForceSendFields of the backends are not copied via JSON.
+	for i, backend := range backendService.Backends {
+		if backend != nil && ga.Backends[i] != nil {
+			ga.Backends[i].ForceSendFields = backend.ForceSendFields
+		}
+	}
 
 	return ga, nil
 }
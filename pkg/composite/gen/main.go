@@ -849,6 +849,12 @@ func ({{$type.VarName}} *{{$type.Name}}) To{{$version}}() (*compute{{$extension}
 			{{$lower}}.LogConfig.ForceSendFields = []string{"Enable", "SampleRate"}
 		}
 	}
+	// ForceSendFields of the backends are not copied via JSON.
+	for i, backend := range {{$type.VarName}}.Backends {
+		if backend != nil && {{$lower}}.Backends[i] != nil {
+			{{$lower}}.Backends[i].ForceSendFields = backend.ForceSendFields
+		}
+	}
 	{{- end}}
 
 	return {{$lower}}, nil
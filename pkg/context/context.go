@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	informers "k8s.io/client-go/informers"
 	informerv1 "k8s.io/client-go/informers/core/v1"
 	discoveryinformer "k8s.io/client-go/informers/discovery/v1"
@@ -48,6 +49,9 @@ import (
 	"k8s.io/ingress-gce/pkg/flags"
 	frontendconfigclient "k8s.io/ingress-gce/pkg/frontendconfig/client/clientset/versioned"
 	informerfrontendconfig "k8s.io/ingress-gce/pkg/frontendconfig/client/informers/externalversions/frontendconfig/v1beta1"
+	"k8s.io/ingress-gce/pkg/gateway"
+	gcsbackendclient "k8s.io/ingress-gce/pkg/gcsbackend/client/clientset/versioned"
+	informergcsbackend "k8s.io/ingress-gce/pkg/gcsbackend/client/informers/externalversions/gcsbackend/v1beta1"
 	ingparamsclient "k8s.io/ingress-gce/pkg/ingparams/client/clientset/versioned"
 	informeringparams "k8s.io/ingress-gce/pkg/ingparams/client/informers/externalversions/ingparams/v1beta1"
 	"k8s.io/ingress-gce/pkg/instancegroups"
@@ -78,6 +82,7 @@ type ControllerContext struct {
 	SvcNegClient   svcnegclient.Interface
 	SAClient       serviceattachmentclient.Interface
 	FirewallClient firewallclient.Interface
+	GatewayClient  dynamic.Interface
 
 	Cloud *gce.Cloud
 
@@ -103,6 +108,9 @@ type ControllerContext struct {
 	FirewallInformer         cache.SharedIndexInformer
 	NetworkInformer          cache.SharedIndexInformer
 	GKENetworkParamsInformer cache.SharedIndexInformer
+	GCSBackendInformer       cache.SharedIndexInformer
+	GatewayInformer          cache.SharedIndexInformer
+	HTTPRouteInformer        cache.SharedIndexInformer
 
 	ControllerMetrics *metrics.ControllerMetrics
 
@@ -156,6 +164,8 @@ func NewControllerContext(
 	ingParamsClient ingparamsclient.Interface,
 	saClient serviceattachmentclient.Interface,
 	networkClient networkclient.Interface,
+	gcsBackendClient gcsbackendclient.Interface,
+	gatewayClient dynamic.Interface,
 	cloud *gce.Cloud,
 	clusterNamer *namer.Namer,
 	kubeSystemUID types.UID,
@@ -202,8 +212,11 @@ func NewControllerContext(
 	if config.FrontendConfigEnabled {
 		context.FrontendConfigInformer = informerfrontendconfig.NewFrontendConfigInformer(frontendConfigClient, config.Namespace, config.ResyncPeriod, utils.NewNamespaceIndexer())
 	}
-	if ingParamsClient != nil {
+	// The IngressClasses also allow Ingresses to join ingress groups.
+	if ingParamsClient != nil || flags.F.EnableIngressGroups {
 		context.IngClassInformer = informernetworking.NewIngressClassInformer(kubeClient, config.ResyncPeriod, utils.NewNamespaceIndexer())
+	}
+	if ingParamsClient != nil {
 		context.IngParamsInformer = informeringparams.NewGCPIngressParamsInformer(ingParamsClient, config.ResyncPeriod, utils.NewNamespaceIndexer())
 	}
 
@@ -216,6 +229,18 @@ func NewControllerContext(
 		context.GKENetworkParamsInformer = informernetwork.NewGKENetworkParamSetInformer(networkClient, config.ResyncPeriod, utils.NewNamespaceIndexer())
 	}
 
+	if gcsBackendClient != nil {
+		context.GCSBackendInformer = informergcsbackend.NewGCSBackendInformer(gcsBackendClient, config.Namespace, config.ResyncPeriod, utils.NewNamespaceIndexer())
+	}
+
+	if gatewayClient != nil {
+		context.GatewayClient = gatewayClient
+		context.GatewayInformer = gateway.NewGatewayInformer(gatewayClient, config.Namespace, config.ResyncPeriod, utils.NewNamespaceIndexer())
+		routeIndexers := utils.NewNamespaceIndexer()
+		routeIndexers[gateway.ParentGatewayIndex] = gateway.ParentGatewayIndexFunc
+		context.HTTPRouteInformer = gateway.NewHTTPRouteInformer(gatewayClient, config.Namespace, config.ResyncPeriod, routeIndexers)
+	}
+
 	if flags.F.GKEClusterType == ClusterTypeRegional {
 		context.RegionalCluster = true
 	}
@@ -232,6 +257,7 @@ func NewControllerContext(
 		context.NodeInformer,
 		context.PodInformer,
 		context.EndpointSliceInformer,
+		context.GCSBackendInformer,
 		context.KubeClient,
 		context,
 		flags.F.EnableTransparentHealthChecks,
@@ -321,6 +347,12 @@ func (ctx *ControllerContext) HasSynced() bool {
 	if ctx.GKENetworkParamsInformer != nil {
 		funcs = append(funcs, ctx.GKENetworkParamsInformer.HasSynced)
 	}
+	if ctx.GCSBackendInformer != nil {
+		funcs = append(funcs, ctx.GCSBackendInformer.HasSynced)
+	}
+	if ctx.GatewayInformer != nil {
+		funcs = append(funcs, ctx.GatewayInformer.HasSynced, ctx.HTTPRouteInformer.HasSynced)
+	}
 
 	if ctx.FirewallInformer != nil {
 		funcs = append(funcs, ctx.FirewallInformer.HasSynced)
@@ -348,6 +380,9 @@ func (ctx *ControllerContext) Recorder(ns string) record.EventRecorder {
 		Interface: ctx.KubeClient.CoreV1().Events(ns),
 	})
 	rec := broadcaster.NewRecorder(ctx.generateScheme(), apiv1.EventSource{Component: "loadbalancer-controller"})
+	if ctx.GatewayClient != nil {
+		rec = gateway.NewEventRecorder(rec)
+	}
 	ctx.recorders[ns] = rec
 
 	return rec
@@ -415,6 +450,13 @@ func (ctx *ControllerContext) Start(stopCh <-chan struct{}) {
 	if ctx.GKENetworkParamsInformer != nil {
 		go ctx.GKENetworkParamsInformer.Run(stopCh)
 	}
+	if ctx.GCSBackendInformer != nil {
+		go ctx.GCSBackendInformer.Run(stopCh)
+	}
+	if ctx.GatewayInformer != nil {
+		go ctx.GatewayInformer.Run(stopCh)
+		go ctx.HTTPRouteInformer.Run(stopCh)
+	}
 	// Export ingress usage metrics.
 	go ctx.ControllerMetrics.Run(stopCh)
 }
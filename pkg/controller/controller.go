@@ -21,6 +21,7 @@ import (
 	"math/rand"
 	"net/http"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
@@ -28,6 +29,8 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
 	unversionedcore "k8s.io/client-go/kubernetes/typed/core/v1"
 	listers "k8s.io/client-go/listers/core/v1"
@@ -36,19 +39,25 @@ import (
 	"k8s.io/ingress-gce/pkg/annotations"
 	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
 	frontendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1"
+	gcsbackendv1beta1 "k8s.io/ingress-gce/pkg/apis/gcsbackend/v1beta1"
 	"k8s.io/ingress-gce/pkg/backends"
 	"k8s.io/ingress-gce/pkg/common/operator"
 	"k8s.io/ingress-gce/pkg/context"
 	legacytranslator "k8s.io/ingress-gce/pkg/controller/translator"
+	"k8s.io/ingress-gce/pkg/drift"
+	"k8s.io/ingress-gce/pkg/dryrun"
 	"k8s.io/ingress-gce/pkg/events"
 	"k8s.io/ingress-gce/pkg/flags"
 	"k8s.io/ingress-gce/pkg/frontendconfig"
+	"k8s.io/ingress-gce/pkg/gateway"
 	"k8s.io/ingress-gce/pkg/healthchecks"
+	"k8s.io/ingress-gce/pkg/ingressgroup"
 	"k8s.io/ingress-gce/pkg/instancegroups"
 	"k8s.io/ingress-gce/pkg/loadbalancers"
 	"k8s.io/ingress-gce/pkg/loadbalancers/features"
 	"k8s.io/ingress-gce/pkg/metrics"
 	negtypes "k8s.io/ingress-gce/pkg/neg/types"
+	"k8s.io/ingress-gce/pkg/pause"
 	ingsync "k8s.io/ingress-gce/pkg/sync"
 	"k8s.io/ingress-gce/pkg/translator"
 	"k8s.io/ingress-gce/pkg/utils"
@@ -58,6 +67,9 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// controllerName is the name of the Ingress controller in metrics.
+const controllerName = "ingress-controller"
+
 // LoadBalancerController watches the kubernetes api and adds/removes services
 // from the loadbalancer, via loadBalancerConfig.
 type LoadBalancerController struct {
@@ -84,6 +96,10 @@ type LoadBalancerController struct {
 
 	// syncer implementation for backends
 	backendSyncer backends.Syncer
+	// backendPool manages the backend buckets of GCSBackends.
+	backendPool backends.Pool
+	// namerFactory names the frontend resources of ingresses.
+	namerFactory namer.IngressFrontendNamerFactory
 	// backendLock locks the SyncBackend function to avoid conflicts between
 	// multiple ingress workers.
 	backendLock sync.Mutex
@@ -92,8 +108,9 @@ type LoadBalancerController struct {
 	gcLock sync.Mutex
 
 	// linker implementations for backends
-	negLinker backends.Linker
-	igLinker  backends.Linker
+	negLinker         backends.Linker
+	igLinker          backends.Linker
+	internetNEGLinker backends.InternetNEGLinker
 
 	// Ingress sync + GC implementation
 	ingSyncer ingsync.Syncer
@@ -101,9 +118,19 @@ type LoadBalancerController struct {
 	// Ingress usage metrics.
 	metrics metrics.IngressMetricsCollector
 
+	// driftTracker remembers a hash of the desired state of each Ingress at
+	// its last successful sync, for drift detection.
+	driftTracker *drift.Tracker
+
 	ingClassLister  cache.Indexer
 	ingParamsLister cache.Indexer
 
+	// gatewayQueue syncs Gateways. It is nil unless the Gateway API is enabled.
+	gatewayQueue utils.TaskQueue
+	// groupQueue syncs ingress groups by name. It is nil unless ingress
+	// groups are enabled.
+	groupQueue utils.TaskQueue
+
 	ZoneGetter *zonegetter.ZoneGetter
 
 	logger klog.Logger
@@ -116,6 +143,7 @@ func NewLoadBalancerController(
 	logger klog.Logger,
 ) *LoadBalancerController {
 	logger = logger.WithName("IngressController")
+	pause.RegisterMetrics()
 
 	broadcaster := record.NewBroadcaster()
 	broadcaster.StartLogging(klog.Infof)
@@ -129,31 +157,44 @@ func NewLoadBalancerController(
 		THCPort: int64(flags.F.THCPort),
 	})
 	backendPool := backends.NewPool(ctx.Cloud, ctx.ClusterNamer)
+	namerFactory := namer.NewFrontendNamerFactory(ctx.ClusterNamer, ctx.KubeSystemUID, logger)
 
 	lbc := LoadBalancerController{
-		ctx:           ctx,
-		nodeLister:    ctx.NodeInformer.GetIndexer(),
-		Translator:    ctx.Translator,
-		stopCh:        stopCh,
-		hasSynced:     ctx.HasSynced,
-		instancePool:  ctx.InstancePool,
-		l7Pool:        loadbalancers.NewLoadBalancerPool(ctx.Cloud, ctx.ClusterNamer, ctx, namer.NewFrontendNamerFactory(ctx.ClusterNamer, ctx.KubeSystemUID, logger), logger),
-		backendSyncer: backends.NewBackendSyncer(backendPool, healthChecker, ctx.Cloud),
-		negLinker:     backends.NewNEGLinker(backendPool, negtypes.NewAdapter(ctx.Cloud), ctx.Cloud, ctx.SvcNegInformer.GetIndexer(), logger),
-		igLinker:      backends.NewInstanceGroupLinker(ctx.InstancePool, backendPool, logger),
-		metrics:       ctx.ControllerMetrics,
-		ZoneGetter:    ctx.ZoneGetter,
-		logger:        logger,
+		ctx:               ctx,
+		nodeLister:        ctx.NodeInformer.GetIndexer(),
+		Translator:        ctx.Translator,
+		stopCh:            stopCh,
+		hasSynced:         ctx.HasSynced,
+		instancePool:      ctx.InstancePool,
+		l7Pool:            loadbalancers.NewLoadBalancerPool(ctx.Cloud, ctx.ClusterNamer, ctx, namerFactory, logger),
+		backendSyncer:     backends.NewBackendSyncer(backendPool, healthChecker, ctx.Cloud),
+		backendPool:       backendPool,
+		namerFactory:      namerFactory,
+		negLinker:         backends.NewNEGLinker(backendPool, negtypes.NewAdapter(ctx.Cloud), ctx.Cloud, ctx.SvcNegInformer.GetIndexer(), logger),
+		igLinker:          backends.NewInstanceGroupLinker(ctx.InstancePool, backendPool, logger),
+		internetNEGLinker: backends.NewInternetNEGLinker(ctx.Cloud, ctx.ClusterNamer, logger),
+		metrics:           ctx.ControllerMetrics,
+		driftTracker:      drift.NewTracker(),
+		ZoneGetter:        ctx.ZoneGetter,
+		logger:            logger,
 	}
 
 	if ctx.IngClassInformer != nil {
 		lbc.ingClassLister = ctx.IngClassInformer.GetIndexer()
+	}
+	if ctx.IngParamsInformer != nil {
 		lbc.ingParamsLister = ctx.IngParamsInformer.GetIndexer()
 	}
 
 	lbc.ingSyncer = ingsync.NewIngressSyncer(&lbc, logger)
 	lbc.ingQueue = utils.NewPeriodicTaskQueueWithMultipleWorkers("ingress", "ingresses", flags.F.NumIngressWorkers, lbc.sync, logger)
 	lbc.backendSyncer.Init(lbc.Translator)
+	if ctx.GatewayInformer != nil {
+		lbc.initGatewayController(logger)
+	}
+	if flags.F.EnableIngressGroups {
+		lbc.groupQueue = utils.NewPeriodicTaskQueueWithMultipleWorkers("ingress-group", "ingressgroups", flags.F.NumIngressWorkers, lbc.syncIngressGroup, logger.WithName("IngressGroup"))
+	}
 
 	// Ingress event handlers.
 	ctx.IngressInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -188,6 +229,9 @@ func NewLoadBalancerController(
 				ingLogger.Info("Ignoring delete for ingress based on annotation", "annotation", annotations.IngressClassKey)
 				return
 			}
+			if group, _ := lbc.ingressGroup(delIng); group != "" {
+				lbc.groupQueue.Enqueue(cache.ExplicitKey(group))
+			}
 
 			ingLogger.Info("Ingress deleted, enqueueing")
 			lbc.ingQueue.Enqueue(obj)
@@ -195,6 +239,12 @@ func NewLoadBalancerController(
 		UpdateFunc: func(old, cur interface{}) {
 			curIng := cur.(*v1.Ingress)
 			ingLogger := logger.WithValues("ingressKey", common.NamespacedName(curIng))
+			if oldGroup, _ := lbc.ingressGroup(old.(*v1.Ingress)); oldGroup != "" {
+				if curGroup, _ := lbc.ingressGroup(curIng); curGroup != oldGroup {
+					// The rules of the Ingress are removed from its former group.
+					lbc.groupQueue.Enqueue(cache.ExplicitKey(oldGroup))
+				}
+			}
 			if !utils.IsGLBCIngress(curIng) {
 				// Ingress needs to be enqueued if a ingress finalizer exists.
 				// An existing finalizer means that
@@ -321,6 +371,45 @@ func NewLoadBalancerController(
 		})
 	}
 
+	// GCSBackend event handlers.
+	if ctx.GCSBackendInformer != nil {
+		ctx.GCSBackendInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				gcsBackend := obj.(*gcsbackendv1beta1.GCSBackend)
+				ings := operator.Ingresses(ctx.Ingresses().List()).ReferencesGCSBackend(gcsBackend).AsList()
+				lbc.ingQueue.Enqueue(convert(ings)...)
+			},
+			UpdateFunc: func(old, cur interface{}) {
+				if !reflect.DeepEqual(old, cur) {
+					gcsBackend := cur.(*gcsbackendv1beta1.GCSBackend)
+					logger.Info("GCSBackend updated", "gcsBackendName", klog.KRef(gcsBackend.Namespace, gcsBackend.Name))
+					ings := operator.Ingresses(ctx.Ingresses().List()).ReferencesGCSBackend(gcsBackend).AsList()
+					lbc.ingQueue.Enqueue(convert(ings)...)
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				gcsBackend, ok := obj.(*gcsbackendv1beta1.GCSBackend)
+				if !ok {
+					// This can happen if the watch is closed and misses the delete event
+					state, stateOk := obj.(cache.DeletedFinalStateUnknown)
+					if !stateOk {
+						logger.Error(nil, "Wanted cache.DeleteFinalStateUnknown of gcsbackend obj", "got", fmt.Sprintf("%+v", obj), "gotType", fmt.Sprintf("%T", obj))
+						return
+					}
+
+					gcsBackend, ok = state.Obj.(*gcsbackendv1beta1.GCSBackend)
+					if !ok {
+						logger.Error(nil, "Wanted gcsbackend obj", "got", fmt.Sprintf("%+v", state.Obj), "gotType", fmt.Sprintf("%T", state.Obj))
+						return
+					}
+				}
+
+				ings := operator.Ingresses(ctx.Ingresses().List()).ReferencesGCSBackend(gcsBackend).AsList()
+				lbc.ingQueue.Enqueue(convert(ings)...)
+			},
+		})
+	}
+
 	// Register health check on controller context.
 	ctx.AddHealthCheck("ingress", func() error {
 		name := "k8s-ingress-svc-acct-permission-check-probe"
@@ -351,6 +440,12 @@ func (lbc *LoadBalancerController) Run() {
 	}()
 	lbc.logger.Info("Starting loadbalancer controller")
 	go lbc.ingQueue.Run()
+	if lbc.gatewayQueue != nil {
+		go lbc.gatewayQueue.Run()
+	}
+	if lbc.groupQueue != nil {
+		go lbc.groupQueue.Run()
+	}
 
 	<-lbc.stopCh
 	lbc.logger.Info("Shutting down Loadbalancer Controller")
@@ -367,6 +462,12 @@ func (lbc *LoadBalancerController) Stop() {
 	if !lbc.shutdown {
 		lbc.logger.Info("Shutting down controller queues.")
 		lbc.ingQueue.Shutdown()
+		if lbc.gatewayQueue != nil {
+			lbc.gatewayQueue.Shutdown()
+		}
+		if lbc.groupQueue != nil {
+			lbc.groupQueue.Shutdown()
+		}
 		lbc.shutdown = true
 	}
 }
@@ -401,11 +502,32 @@ func (lbc *LoadBalancerController) SyncBackends(state interface{}, ingLogger klo
 		ingLogger.Info("Skip syncing instance groups")
 	}
 
+	// The backend services left unchanged because of the report-only drift
+	// policy are neither synced nor linked.
+	svcPorts := ingSvcPorts
+	if len(syncState.skipBackends) > 0 {
+		svcPorts = nil
+		for _, sp := range ingSvcPorts {
+			if !syncState.skipBackends.Has(sp.BackendName()) {
+				svcPorts = append(svcPorts, sp)
+			}
+		}
+	}
+
 	// Sync the backends
-	if err := lbc.backendSyncer.Sync(ingSvcPorts, ingLogger); err != nil {
+	if err := lbc.backendSyncer.Sync(svcPorts, ingLogger); err != nil {
 		return err
 	}
 
+	// Sync the backend buckets of the GCSBackends.
+	frontendNamer := lbc.namerFactory.Namer(syncState.ing)
+	for _, bucket := range syncState.urlMap.AllBackendBuckets() {
+		name := frontendNamer.BackendBucket(bucket.ID.Namespace, bucket.ID.Name)
+		if err := lbc.backendPool.EnsureBackendBucket(name, bucket, ingLogger); err != nil {
+			return err
+		}
+	}
+
 	// Get the zones our groups live in.
 	zones, err := lbc.ZoneGetter.List(zonegetter.CandidateNodesFilter, lbc.logger)
 	if err != nil {
@@ -418,9 +540,12 @@ func (lbc *LoadBalancerController) SyncBackends(state interface{}, ingLogger klo
 	}
 
 	// Link backends to groups.
-	for _, sp := range ingSvcPorts {
+	for _, sp := range svcPorts {
 		var linkErr error
-		if sp.NEGEnabled {
+		if sp.InternetNEGEnabled {
+			// Link backend to its Internet NEG for ExternalName Services.
+			linkErr = lbc.internetNEGLinker.Link(sp, groupKeys)
+		} else if sp.NEGEnabled {
 			// Link backend to NEG's if the backend has NEG enabled.
 			linkErr = lbc.negLinker.Link(sp, groupKeys)
 		} else {
@@ -477,13 +602,27 @@ func (lbc *LoadBalancerController) syncInstanceGroup(ing *v1.Ingress, ingSvcPort
 func (lbc *LoadBalancerController) GCBackends(toKeep []*v1.Ingress, ingLogger klog.Logger) error {
 	// Only GCE ingress associated resources are managed by this controller.
 	GCEIngresses := operator.Ingresses(toKeep).Filter(utils.IsGCEIngress).AsList()
+	// The backends of paused and protected ingresses are kept even if they
+	// are deleted.
+	retained := lbc.retainedIngresses()
+	GCEIngresses = append(GCEIngresses, retained...)
 	svcPortsToKeep := lbc.ToSvcPorts(GCEIngresses)
 	if err := lbc.backendSyncer.GC(svcPortsToKeep, ingLogger); err != nil {
 		return err
 	}
+	if flags.F.EnableInternetNEGs {
+		if err := lbc.internetNEGLinker.GC(svcPortsToKeep, ingLogger); err != nil {
+			return err
+		}
+	}
+	if flags.F.EnableGCSBackends {
+		if err := lbc.gcBackendBuckets(GCEIngresses, ingLogger); err != nil {
+			return err
+		}
+	}
 	// TODO(ingress#120): Move this to the backend pool so it mirrors creation
 	// Do not delete instance group if there exists a GLBC ingress.
-	if len(toKeep) == 0 {
+	if len(toKeep) == 0 && len(retained) == 0 {
 		igName := lbc.ctx.ClusterNamer.InstanceGroup()
 		ingLogger.Info("Deleting instance group", "instanceGroup", igName)
 		if err := lbc.instancePool.DeleteInstanceGroup(igName); err != err {
@@ -493,6 +632,43 @@ func (lbc *LoadBalancerController) GCBackends(toKeep []*v1.Ingress, ingLogger kl
 	return nil
 }
 
+// gcBackendBuckets deletes the backend buckets of this cluster which are not
+// used by the given ingresses. Backend buckets still referenced by a URL map
+// are kept by GCE and deleted by a later GC. Nothing is deleted if any of the
+// ingresses fails to translate, as its backend buckets are then unknown.
+func (lbc *LoadBalancerController) gcBackendBuckets(toKeep []*v1.Ingress, ingLogger klog.Logger) error {
+	namesToKeep := sets.New[string]()
+	for _, ing := range toKeep {
+		frontendNamer := lbc.namerFactory.Namer(ing)
+		if group, ok := ingressgroup.GroupForIngress(ing); ok {
+			// The members of a group share the load balancer of the group.
+			frontendNamer = lbc.namerFactory.Namer(ingressgroup.ToIngress(group, []*v1.Ingress{ing}))
+		}
+		urlMap, errs, _ := lbc.Translator.TranslateIngress(ing, lbc.ctx.DefaultBackendSvcPort.ID, lbc.ctx.ClusterNamer)
+		if errs != nil {
+			ingLogger.Info("Skipping GC of backend buckets, failed to translate ingress", "ingressKey", common.NamespacedName(ing), "err", utils.JoinErrs(errs))
+			return nil
+		}
+		for _, bucket := range urlMap.AllBackendBuckets() {
+			namesToKeep.Insert(frontendNamer.BackendBucket(bucket.ID.Namespace, bucket.ID.Name))
+		}
+	}
+
+	buckets, err := lbc.backendPool.ListBackendBuckets(ingLogger)
+	if err != nil {
+		return err
+	}
+	for _, bb := range buckets {
+		if !lbc.namerFactory.IsBackendBucket(bb.Name) || namesToKeep.Has(bb.Name) {
+			continue
+		}
+		if err := lbc.backendPool.DeleteBackendBucket(bb.Name, ingLogger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // SyncLoadBalancer implements Controller.
 func (lbc *LoadBalancerController) SyncLoadBalancer(state interface{}, ingLogger klog.Logger) error {
 	ingLogger = ingLogger.WithName("SyncLoadBalancer")
@@ -502,9 +678,17 @@ func (lbc *LoadBalancerController) SyncLoadBalancer(state interface{}, ingLogger
 		return fmt.Errorf("expected state type to be syncState, type was %T", state)
 	}
 
-	lb, err := lbc.toRuntimeInfo(syncState.ing, syncState.urlMap, ingLogger)
-	if err != nil {
-		return err
+	if syncState.skipFrontend {
+		ingLogger.Info("Skipping sync of the frontend of ingress with report-only drift policy")
+		return nil
+	}
+
+	lb := syncState.lb
+	if lb == nil {
+		var err error
+		if lb, err = lbc.toRuntimeInfo(syncState.ing, syncState.urlMap, ingLogger); err != nil {
+			return err
+		}
 	}
 
 	// Create higher-level LB resources.
@@ -519,14 +703,38 @@ func (lbc *LoadBalancerController) SyncLoadBalancer(state interface{}, ingLogger
 
 // GCv1LoadBalancers implements Controller.
 func (lbc *LoadBalancerController) GCv1LoadBalancers(toKeep []*v1.Ingress) error {
+	toKeep = append(toKeep, lbc.retainedIngresses()...)
 	return lbc.l7Pool.GCv1(common.ToIngressKeys(toKeep, lbc.logger))
 }
 
+// deletionProtectedEvents limits the events of GCv2LoadBalancer and
+// gcRegionalIngressResources, which run on every sync of an Ingress being
+// deleted or whose class changed.
+var deletionProtectedEvents = events.NewLimiter(events.DeletionProtectedInterval)
+
 // GCv2LoadBalancer implements Controller.
 func (lbc *LoadBalancerController) GCv2LoadBalancer(ing *v1.Ingress, scope meta.KeyType) error {
+	// Returning an error also keeps the finalizer of a paused ingress.
+	if ing != nil && annotations.FromIngress(ing).ReconciliationPaused() {
+		return fmt.Errorf("reconciliation of ingress %s is paused", common.NamespacedName(ing))
+	}
+	if ing != nil && annotations.FromIngress(ing).DeletionProtected() {
+		deletionProtectedEvents.Eventf(lbc.ctx.Recorder(ing.Namespace), ing, apiv1.EventTypeWarning, events.DeletionProtected, "Keeping the load balancer of the ingress because of the %s annotation", annotations.DeletionProtectionKey)
+		return nil
+	}
 	return lbc.l7Pool.GCv2(ing, scope)
 }
 
+// retainedIngresses returns the ingresses whose GCE resources must not be
+// garbage collected, because their reconciliation is paused or they are
+// protected from deletion.
+func (lbc *LoadBalancerController) retainedIngresses() []*v1.Ingress {
+	return operator.Ingresses(lbc.ctx.Ingresses().List()).Filter(func(ing *v1.Ingress) bool {
+		ingAnnotations := annotations.FromIngress(ing)
+		return ingAnnotations.ReconciliationPaused() || ingAnnotations.DeletionProtected()
+	}).AsList()
+}
+
 // EnsureDeleteV1Finalizers implements Controller.
 func (lbc *LoadBalancerController) EnsureDeleteV1Finalizers(toCleanup []*v1.Ingress, ingLogger klog.Logger) error {
 	if !flags.F.FinalizerRemove {
@@ -534,6 +742,10 @@ func (lbc *LoadBalancerController) EnsureDeleteV1Finalizers(toCleanup []*v1.Ingr
 		return nil
 	}
 	for _, ing := range toCleanup {
+		if annotations.FromIngress(ing).DeletionProtected() {
+			ingLogger.Info("Keeping finalizer of deletion protected ingress", "ingress", common.NamespacedName(ing))
+			continue
+		}
 		ingClient := lbc.ctx.KubeClient.NetworkingV1().Ingresses(ing.Namespace)
 		if err := common.EnsureDeleteFinalizer(ing, ingClient, common.FinalizerKey, ingLogger); err != nil {
 			ingLogger.Error(err, "Failed to ensure delete finalizer", "finalizer", common.FinalizerKey)
@@ -545,6 +757,18 @@ func (lbc *LoadBalancerController) EnsureDeleteV1Finalizers(toCleanup []*v1.Ingr
 
 // EnsureDeleteV2Finalizer implements Controller.
 func (lbc *LoadBalancerController) EnsureDeleteV2Finalizer(ing *v1.Ingress, ingLogger klog.Logger) error {
+	if gwKey, ok := gateway.GatewayForIngress(ing); ok {
+		return lbc.ensureDeleteGatewayFinalizer(gwKey, ingLogger)
+	}
+	if _, ok := ingressgroup.GroupForIngress(ing); ok {
+		// The Ingress of a group is not persisted. The finalizers of the
+		// members are removed by gcIngressGroup.
+		return nil
+	}
+	if annotations.FromIngress(ing).DeletionProtected() {
+		ingLogger.Info("Keeping finalizer of deletion protected ingress", "finalizer", common.FinalizerKeyV2)
+		return nil
+	}
 	if !flags.F.FinalizerRemove {
 		ingLogger.Info("Removing finalizers not enabled")
 		return nil
@@ -566,6 +790,18 @@ func (lbc *LoadBalancerController) PostProcess(state interface{}, ingLogger klog
 		return fmt.Errorf("expected state type to be syncState, type was %T", state)
 	}
 
+	if _, ok := gateway.GatewayForIngress(syncState.ing); ok {
+		// The status of the Gateway is updated by syncGateway, which also
+		// reports the errors of the sync.
+		return nil
+	}
+	if group, ok := ingressgroup.GroupForIngress(syncState.ing); ok {
+		return lbc.updateIngressGroupStatus(syncState.l7, group, ingLogger)
+	}
+	if syncState.skipFrontend {
+		// The frontend, and so the status, was left unchanged.
+		return nil
+	}
 	// Update the ingress status.
 	return lbc.updateIngressStatus(syncState.l7, syncState.ing, ingLogger)
 }
@@ -580,7 +816,7 @@ func (lbc *LoadBalancerController) preSyncGC(key string, scope meta.KeyType, ing
 	ingLogger.Info("Running preSyncGC")
 	defer ingLogger.Info("Finish preSyncGC")
 
-	allIngresses := lbc.ctx.Ingresses().List()
+	allIngresses := lbc.allIngresses()
 	// Determine if the ingress needs to be GCed.
 	if !ingExists || utils.NeedsCleanup(ing) {
 		frontendGCAlgorithm := frontendGCAlgorithm(ingExists, false, ing, ingLogger)
@@ -608,7 +844,12 @@ func (lbc *LoadBalancerController) gcRegionalIngressResources(ing *v1.Ingress, i
 	ingLogger.Info("Running gcRegionalIngressResources")
 	defer ingLogger.Info("Finish gcRegionalIngressResources")
 
-	allIngresses := lbc.ctx.Ingresses().List()
+	if annotations.FromIngress(ing).DeletionProtected() {
+		deletionProtectedEvents.Eventf(lbc.ctx.Recorder(ing.Namespace), ing, apiv1.EventTypeWarning, events.DeletionProtected, "Keeping the load balancer of the previous ingress class because of the %s annotation", annotations.DeletionProtectionKey)
+		return nil
+	}
+
+	allIngresses := lbc.allIngresses()
 	// Keep all ingresses, besides current one, that needs to be cleaned up.
 	filteredIngresses := operator.Ingresses(allIngresses).Filter(func(curIng *v1.Ingress) bool {
 		return curIng.Namespace != ing.Namespace && curIng.Name != ing.Name
@@ -634,7 +875,7 @@ func (lbc *LoadBalancerController) postSyncGC(key string, syncErr error, oldScop
 	// Garbage collection will occur regardless of an error occurring. If an error occurred,
 	// it could have been caused by quota issues; therefore, garbage collecting now may
 	// free up enough quota for the next sync to pass.
-	allIngresses := lbc.ctx.Ingresses().List()
+	allIngresses := lbc.allIngresses()
 	frontendGCAlgorithm := frontendGCAlgorithm(ingExists, oldScope != nil, ing, ingLogger)
 	if gcErr := lbc.ingSyncer.GC(allIngresses, ing, frontendGCAlgorithm, newScope, ingLogger); gcErr != nil {
 		lbc.ctx.Recorder(ing.Namespace).Eventf(ing, apiv1.EventTypeWarning, events.GarbageCollection, "Error during garbage collection: %v", gcErr)
@@ -658,6 +899,39 @@ func (lbc *LoadBalancerController) sync(key string) error {
 		return fmt.Errorf("error getting Ingress for key %s: %v", key, err)
 	}
 
+	// Paused ingresses keep their GCE resources and finalizers as they are.
+	if ingExists && annotations.FromIngress(ing).ReconciliationPaused() {
+		ingLogger.Info("Reconciliation of ingress is paused, skipping sync")
+		pause.Report(lbc.ctx.Recorder(ing.Namespace), ing, pause.KindIngress, controllerName)
+		return nil
+	}
+	pause.Resume(pause.KindIngress, controllerName, key)
+
+	// The members of an ingress group are synced together.
+	if group, err := lbc.ingressGroup(ing); err != nil {
+		lbc.ctx.Recorder(ing.Namespace).Eventf(ing, apiv1.EventTypeWarning, events.SyncIngress, "Error: %v", err)
+		return err
+	} else if group != "" {
+		ingLogger.Info("Ingress is a member of an ingress group, enqueuing the group", "ingressGroup", group)
+		lbc.groupQueue.Enqueue(cache.ExplicitKey(group))
+		return nil
+	}
+
+	if ingExists && !utils.NeedsCleanup(ing) {
+		if err := lbc.loadBalancerNameConflict(ing); err != nil {
+			lbc.ctx.Recorder(ing.Namespace).Eventf(ing, apiv1.EventTypeWarning, events.SyncIngress, "Error: %v", err)
+			return err
+		}
+	}
+
+	if flags.F.DryRun {
+		return lbc.planIngress(ing, ingExists, ingLogger)
+	}
+
+	if !ingExists || utils.NeedsCleanup(ing) {
+		lbc.driftTracker.Delete(key)
+	}
+
 	// Capture GC state for ingress.
 	scope := features.ScopeFromIngress(ing)
 	needSync, err := lbc.preSyncGC(key, scope, ingExists, ing, ingLogger)
@@ -704,12 +978,23 @@ func (lbc *LoadBalancerController) sync(key string) error {
 		lbc.ctx.Recorder(ing.Namespace).Event(ing, apiv1.EventTypeWarning, "THCAnnotationWithoutFlag", msg)
 	}
 
+	syncState := &syncState{urlMap: urlMap, ing: ing}
+	var desiredHash string
+	if flags.F.EnableDriftDetection {
+		if desiredHash, err = lbc.detectDrift(key, syncState, ingLogger); err != nil {
+			lbc.ctx.Recorder(ing.Namespace).Eventf(ing, apiv1.EventTypeWarning, events.SyncIngress, "Error syncing to GCP: %v", err)
+			return err
+		}
+	}
+
 	// Sync GCP resources.
-	syncState := &syncState{urlMap, ing, nil}
 	syncErr := lbc.ingSyncer.Sync(syncState, ingLogger)
 	if syncErr != nil {
 		lbc.ctx.Recorder(ing.Namespace).Eventf(ing, apiv1.EventTypeWarning, events.SyncIngress, "Error syncing to GCP: %v", syncErr.Error())
 	} else {
+		if desiredHash != "" {
+			lbc.driftTracker.SetSynced(key, desiredHash)
+		}
 		// Insert/update the ingress state for metrics after successful sync.
 		var fc *frontendconfigv1beta1.FrontendConfig
 		if flags.F.EnableFrontendConfig {
@@ -734,6 +1019,224 @@ func (lbc *LoadBalancerController) sync(key string) error {
 	return lbc.postSyncGC(key, syncErr, oldScope, scope, ingExists, ing, ingLogger)
 }
 
+// loadBalancerNameConflict returns an error if an ingress group or a Gateway
+// older than the Ingress synthesizes an Ingress with the same namespace and
+// name, and so uses the same load balancer. The older of the two keeps the
+// load balancer, and the Ingress wins ties.
+func (lbc *LoadBalancerController) loadBalancerNameConflict(ing *v1.Ingress) error {
+	if group, ok := ingressgroup.GroupForIngressName(ing.Namespace, ing.Name); ok && flags.F.EnableIngressGroups {
+		if members, _, _ := lbc.ingressGroupMembers(group); len(members) > 0 && members[0].CreationTimestamp.Before(&ing.CreationTimestamp) {
+			return fmt.Errorf("ingress conflicts with ingress group %q which uses the same load balancer name", group)
+		}
+	}
+	if gwKey, ok := gateway.GatewayForIngressName(ing.Namespace, ing.Name); ok && lbc.ctx.GatewayInformer != nil {
+		obj, exists, err := lbc.ctx.GatewayInformer.GetIndexer().GetByKey(gwKey.String())
+		if err != nil || !exists {
+			return nil
+		}
+		gw, err := gateway.GatewayFromUnstructured(obj)
+		if err != nil {
+			return nil
+		}
+		if _, ok := gateway.IngressClass(gw); !ok && !common.HasGivenFinalizer(gw.ObjectMeta, gateway.FinalizerKey) {
+			return nil
+		}
+		if gw.CreationTimestamp.Before(&ing.CreationTimestamp) {
+			return fmt.Errorf("ingress conflicts with Gateway %s which uses the same load balancer name", gwKey)
+		}
+	}
+	return nil
+}
+
+// planIngress reports the changes a sync of the ingress would make to GCE
+// resources as an event on the ingress, without making them.
+func (lbc *LoadBalancerController) planIngress(ing *v1.Ingress, ingExists bool, ingLogger klog.Logger) error {
+	if !ingExists {
+		ingLogger.Info("Ingress does not exist, nothing to plan")
+		return nil
+	}
+	recorder := lbc.ctx.Recorder(ing.Namespace)
+	if utils.NeedsCleanup(ing) {
+		plan, err := lbc.planDelete(ing, ingLogger)
+		if err != nil {
+			recorder.Eventf(ing, apiv1.EventTypeWarning, dryrun.EventReason, "Error planning GCE changes: %v", err)
+			return err
+		}
+		dryrun.Report(recorder, ing, plan)
+		return nil
+	}
+
+	urlMap, errs, _ := lbc.Translator.TranslateIngress(ing, lbc.ctx.DefaultBackendSvcPort.ID, lbc.ctx.ClusterNamer)
+	if errs != nil {
+		msg := fmt.Errorf("invalid ingress spec: %v", utils.JoinErrs(errs))
+		recorder.Eventf(ing, apiv1.EventTypeWarning, events.TranslateIngress, "Translation failed: %v", msg)
+		return msg
+	}
+	plan, err := lbc.planSync(ing, urlMap, ingLogger)
+	if err != nil {
+		recorder.Eventf(ing, apiv1.EventTypeWarning, dryrun.EventReason, "Error planning GCE changes: %v", err)
+		return err
+	}
+	dryrun.Report(recorder, ing, plan)
+	return nil
+}
+
+// planDelete returns the changes the garbage collection of the deleted
+// ingress would make to GCE resources: the deletion of its frontend and of
+// the backend services no other ingress uses.
+func (lbc *LoadBalancerController) planDelete(ing *v1.Ingress, ingLogger klog.Logger) (*dryrun.Plan, error) {
+	plan, err := lbc.l7Pool.PlanDelete(ing)
+	if err != nil {
+		return nil, err
+	}
+	toKeep := operator.Ingresses(lbc.allIngresses()).Filter(func(ing *v1.Ingress) bool {
+		return !utils.NeedsCleanup(ing) && utils.IsGCEIngress(ing)
+	}).AsList()
+	toKeep = append(toKeep, lbc.retainedIngresses()...)
+	bePlan, err := lbc.backendSyncer.PlanGC(lbc.ToSvcPorts(toKeep), ingLogger)
+	if err != nil {
+		return nil, err
+	}
+	plan.Append(bePlan)
+	return plan, nil
+}
+
+// planSync returns the changes a sync of the translated ingress would make
+// to GCE resources.
+func (lbc *LoadBalancerController) planSync(ing *v1.Ingress, urlMap *utils.GCEURLMap, ingLogger klog.Logger) (*dryrun.Plan, error) {
+	plan, err := lbc.backendSyncer.Plan(urlMap.AllServicePorts(), ingLogger)
+	if err != nil {
+		return nil, err
+	}
+	lb, err := lbc.toRuntimeInfo(ing, urlMap, ingLogger)
+	if err != nil {
+		return nil, err
+	}
+	lbPlan, err := lbc.l7Pool.Plan(lb)
+	if err != nil {
+		return nil, err
+	}
+	plan.Append(lbPlan)
+	return plan, nil
+}
+
+// detectDrift computes the runtime info of the ingress for the sync and, if
+// the desired state of its GCE resources did not change since the last
+// successful sync, reports the out-of-band changes to them. The frontend of
+// an ingress with the report-only drift policy, and its backend services
+// which no other ingress reverts, are then left unchanged. It returns the hash of the desired state, or the empty string if it could not
+// be computed.
+func (lbc *LoadBalancerController) detectDrift(key string, state *syncState, ingLogger klog.Logger) (string, error) {
+	lb, err := lbc.toRuntimeInfo(state.ing, state.urlMap, ingLogger)
+	if err != nil {
+		return "", err
+	}
+	state.lb = lb
+	hash, err := desiredStateHash(lb)
+	if err != nil {
+		ingLogger.Error(err, "Failed to hash the desired state of the ingress, skipping drift detection")
+		return "", nil
+	}
+	if lbc.driftTracker.IsResync(key, hash) {
+		lbc.healDrift(state, ingLogger)
+	}
+	return hash, nil
+}
+
+// desiredStateHash returns a hash of the desired state of the GCE resources
+// of an ingress: its URL map, TLS certificates, FrontendConfig and the
+// annotations and spec of the ingress. The annotations the controller writes
+// on the ingress and its status are not part of it.
+func desiredStateHash(lb *loadbalancers.L7RuntimeInfo) (string, error) {
+	ing := &v1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   lb.Ingress.Namespace,
+			Name:        lb.Ingress.Name,
+			UID:         lb.Ingress.UID,
+			Annotations: map[string]string{},
+		},
+		Spec: lb.Ingress.Spec,
+	}
+	for k, v := range lb.Ingress.Annotations {
+		if !strings.HasPrefix(k, annotations.StatusPrefix+"/") {
+			ing.Annotations[k] = v
+		}
+	}
+	desired := *lb
+	desired.Ingress = ing
+	return drift.Hash(&desired)
+}
+
+// healDrift reports the out-of-band changes to the GCE resources of an
+// ingress whose desired state did not change since its last successful
+// sync. It marks the changes which must be left in place because of the
+// report-only drift policy of the ingress in the sync state.
+func (lbc *LoadBalancerController) healDrift(state *syncState, ingLogger klog.Logger) {
+	ing := state.ing
+	recorder := lbc.ctx.Recorder(ing.Namespace)
+	policy, err := annotations.FromIngress(ing).DriftPolicy()
+	if err != nil {
+		recorder.Eventf(ing, apiv1.EventTypeWarning, drift.EventReason, "%v, using %q", err, policy)
+	}
+	bePlan, err := lbc.backendSyncer.Plan(state.urlMap.AllServicePorts(), ingLogger)
+	if err != nil {
+		ingLogger.Error(err, "Failed to detect drift of GCE resources")
+		return
+	}
+	fePlan, err := lbc.l7Pool.Plan(state.lb)
+	if err != nil {
+		ingLogger.Error(err, "Failed to detect drift of GCE resources")
+		return
+	}
+	heal := policy == annotations.DriftPolicyAutoHeal
+	if changes := drift.Changes(bePlan); len(changes) > 0 {
+		if !heal {
+			state.skipBackends = lbc.reportOnlyBackends(changes)
+		}
+		var healed, unchanged []dryrun.Change
+		for _, c := range changes {
+			if c.Resource == "BackendService" && state.skipBackends.Has(c.Name) {
+				unchanged = append(unchanged, c)
+			} else {
+				healed = append(healed, c)
+			}
+		}
+		if len(healed) > 0 {
+			drift.Report(recorder, ing, healed, true)
+		}
+		if len(unchanged) > 0 {
+			drift.Report(recorder, ing, unchanged, false)
+		}
+	}
+	if changes := drift.Changes(fePlan); len(changes) > 0 {
+		drift.Report(recorder, ing, changes, heal)
+		state.skipFrontend = !heal
+	}
+}
+
+// reportOnlyBackends returns the names of the backend services modified
+// out-of-band which are only used by ingresses with the report-only drift
+// policy. The backend services shared with other ingresses are reverted by
+// their syncs, and the deleted ones are recreated since the backends of the
+// ingress are linked to them.
+func (lbc *LoadBalancerController) reportOnlyBackends(changes []dryrun.Change) sets.Set[string] {
+	names := sets.New[string]()
+	for _, c := range changes {
+		if c.Operation == dryrun.OperationUpdate && c.Resource == "BackendService" {
+			names.Insert(c.Name)
+		}
+	}
+	for _, ing := range operator.Ingresses(lbc.ctx.Ingresses().List()).Filter(utils.IsGCEIngress).AsList() {
+		if policy, _ := annotations.FromIngress(ing).DriftPolicy(); policy == annotations.DriftPolicyReportOnly {
+			continue
+		}
+		for _, sp := range lbc.ToSvcPorts([]*v1.Ingress{ing}) {
+			names.Delete(sp.BackendName())
+		}
+	}
+	return names
+}
+
 // updateIngressStatus updates the IP and annotations of a loadbalancer.
 // The annotations are parsed by kubectl describe.
 func (lbc *LoadBalancerController) updateIngressStatus(l7 *loadbalancers.L7, ing *v1.Ingress, ingLogger klog.Logger) error {
@@ -773,6 +1276,9 @@ func (lbc *LoadBalancerController) updateIngressStatus(l7 *loadbalancers.L7, ing
 
 // toRuntimeInfo returns L7RuntimeInfo for the given ingress.
 func (lbc *LoadBalancerController) toRuntimeInfo(ing *v1.Ingress, urlMap *utils.GCEURLMap, ingLogger klog.Logger) (*loadbalancers.L7RuntimeInfo, error) {
+	if group, ok := ingressgroup.GroupForIngress(ing); ok {
+		return lbc.groupRuntimeInfo(group, ing, urlMap, ingLogger)
+	}
 	annotations := annotations.FromIngress(ing)
 	env, err := translator.NewEnv(ing, lbc.ctx.KubeClient, "", "", "")
 	if err != nil {
@@ -30,16 +30,19 @@ import (
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/api/compute/v1"
 	api_v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/cloud-provider-gcp/providers/gce"
 	"k8s.io/ingress-gce/pkg/annotations"
 	backendconfigclient "k8s.io/ingress-gce/pkg/backendconfig/client/clientset/versioned/fake"
+	"k8s.io/ingress-gce/pkg/backends"
 	"k8s.io/ingress-gce/pkg/common/operator"
 	"k8s.io/ingress-gce/pkg/composite"
 	"k8s.io/ingress-gce/pkg/context"
@@ -81,7 +84,7 @@ func newLoadBalancerController() *LoadBalancerController {
 		HealthCheckPath:               "/",
 		EnableIngressRegionalExternal: true,
 	}
-	ctx := context.NewControllerContext(nil, kubeClient, backendConfigClient, nil, nil, nil, nil, nil, nil, fakeGCE, namer, "" /*kubeSystemUID*/, ctxConfig, klog.TODO())
+	ctx := context.NewControllerContext(nil, kubeClient, backendConfigClient, nil, nil, nil, nil, nil, nil, nil, nil, fakeGCE, namer, "" /*kubeSystemUID*/, ctxConfig, klog.TODO())
 	lbc := NewLoadBalancerController(ctx, stopCh, klog.TODO())
 	// TODO(rramkumar): Fix this so we don't have to override with our fake
 	lbc.instancePool = instancegroups.NewManager(&instancegroups.ManagerConfig{
@@ -1013,6 +1016,9 @@ func TestGCRegionalIngressResources(t *testing.T) {
 	for _, tc := range []struct {
 		desc             string
 		ingressClassName string
+		// protected sets the deletion protection annotation on the ingress
+		// whose resources are garbage collected.
+		protected bool
 	}{
 		{
 			desc:             "ILB",
@@ -1022,6 +1028,11 @@ func TestGCRegionalIngressResources(t *testing.T) {
 			desc:             "RXLB",
 			ingressClassName: annotations.GceL7XLBRegionalIngressClass,
 		},
+		{
+			desc:             "RXLB protected from deletion",
+			ingressClassName: annotations.GceL7XLBRegionalIngressClass,
+			protected:        true,
+		},
 	} {
 		t.Run(tc.desc, func(t *testing.T) {
 			flags.F.EnableIngressRegionalExternal = true
@@ -1066,10 +1077,19 @@ func TestGCRegionalIngressResources(t *testing.T) {
 				t.Fatalf("Expected to get backend service, got bs = %v, err = %v", bs, err)
 			}
 
+			if tc.protected {
+				ingressToDelete.Annotations[annotations.DeletionProtectionKey] = "true"
+			}
 			err = lbc.gcRegionalIngressResources(ingressToDelete, lbc.logger)
 			if err != nil {
 				t.Fatalf("lbc.gcRegionalIngressResources(%v, ...) returned error %v", ingressToDelete, err)
 			}
+			if tc.protected {
+				if _, err := composite.GetForwardingRule(lbc.ctx.Cloud, fwRulekey, meta.VersionGA, lbc.logger); err != nil {
+					t.Errorf("Expected the forwarding rule %v of a protected ingress to be kept, got %v", fwRulekey, err)
+				}
+				return
+			}
 
 			// Verify we didn't delete any ingress objects.
 			allIngresses := operator.Ingresses(lbc.ctx.Ingresses().List()).AsList()
@@ -1163,3 +1183,411 @@ func getUpdatedIngress(t *testing.T, lbc *LoadBalancerController, ing *networkin
 	}
 	return updatedIng
 }
+
+// TestIngressDryRun asserts that `sync` does not create GCE resources or
+// update the Ingress in dry run mode.
+func TestIngressDryRun(t *testing.T) {
+	flags.F.DryRun = true
+	defer func() { flags.F.DryRun = false }()
+	lbc := newLoadBalancerController()
+
+	svc := test.NewService(types.NamespacedName{Name: "my-service", Namespace: "default"}, api_v1.ServiceSpec{
+		Type:  api_v1.ServiceTypeNodePort,
+		Ports: []api_v1.ServicePort{{Port: 80}},
+	})
+	addService(lbc, svc)
+	defaultBackend := backend("my-service", networkingv1.ServiceBackendPort{Number: 80})
+	ing := test.NewIngress(types.NamespacedName{Name: "my-ingress", Namespace: "default"},
+		networkingv1.IngressSpec{
+			DefaultBackend: &defaultBackend,
+		})
+	addIngress(lbc, ing)
+
+	ingStoreKey := getKey(ing, t)
+	if err := lbc.sync(ingStoreKey); err != nil {
+		t.Fatalf("lbc.sync(%v) = %v, want nil", ingStoreKey, err)
+	}
+
+	mockGCE := lbc.ctx.Cloud.Compute().(*cloud.MockGCE)
+	if n := len(mockGCE.MockUrlMaps.Objects); n != 0 {
+		t.Errorf("lbc.sync(%v) created %d url maps, want none", ingStoreKey, n)
+	}
+	if n := len(mockGCE.MockBackendServices.Objects); n != 0 {
+		t.Errorf("lbc.sync(%v) created %d backend services, want none", ingStoreKey, n)
+	}
+	updatedIng := getUpdatedIngress(t, lbc, ing)
+	if len(updatedIng.Finalizers) != 0 || len(updatedIng.Status.LoadBalancer.Ingress) != 0 {
+		t.Errorf("lbc.sync(%v) updated the Ingress to %+v, want no changes", ingStoreKey, updatedIng)
+	}
+}
+
+// TestIngressDriftPolicy asserts that out-of-band changes to the frontend of
+// an Ingress are reverted by a resync unless its drift policy is report-only,
+// and that deleted backend services are recreated regardless of the policy.
+func TestIngressDriftPolicy(t *testing.T) {
+	flags.F.EnableDriftDetection = true
+	defer func() { flags.F.EnableDriftDetection = false }()
+
+	const manualService = "https://www.googleapis.com/compute/v1/projects/p/global/backendServices/manual"
+	for _, tc := range []struct {
+		desc   string
+		policy string
+		// deleteProxy deletes the target proxy instead of changing the url
+		// map.
+		deleteProxy bool
+		// changeAnnotation changes an annotation of the Ingress with the
+		// out-of-band change, so that the resync is not a resync of the same
+		// desired state.
+		changeAnnotation bool
+		wantHeal         bool
+	}{
+		{
+			desc:     "no policy",
+			wantHeal: true,
+		},
+		{
+			desc:     "auto-heal",
+			policy:   annotations.DriftPolicyAutoHeal,
+			wantHeal: true,
+		},
+		{
+			desc:   "report-only",
+			policy: annotations.DriftPolicyReportOnly,
+		},
+		{
+			desc:        "auto-heal deleted target proxy",
+			policy:      annotations.DriftPolicyAutoHeal,
+			deleteProxy: true,
+			wantHeal:    true,
+		},
+		{
+			desc:        "report-only deleted target proxy",
+			policy:      annotations.DriftPolicyReportOnly,
+			deleteProxy: true,
+		},
+		{
+			desc:             "report-only with changed annotation",
+			policy:           annotations.DriftPolicyReportOnly,
+			changeAnnotation: true,
+			wantHeal:         true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			lbc := newLoadBalancerController()
+			mockGCE := lbc.ctx.Cloud.Compute().(*cloud.MockGCE)
+			mockGCE.MockUrlMaps.UpdateHook = mock.UpdateURLMapHook
+
+			ing := ensureIngress(t, lbc, "default", "my-ingress", namer_util.V1NamingScheme)
+			ingStoreKey := getKey(ing, t)
+			if tc.policy != "" {
+				ing.Annotations[annotations.DriftPolicyKey] = tc.policy
+				updateIngress(lbc, ing)
+				if err := lbc.sync(ingStoreKey); err != nil {
+					t.Fatalf("lbc.sync(%v) = %v, want nil", ingStoreKey, err)
+				}
+				ing = getUpdatedIngress(t, lbc, ing)
+			}
+			if len(mockGCE.MockUrlMaps.Objects) != 1 || len(mockGCE.MockTargetHttpProxies.Objects) != 1 {
+				t.Fatalf("got %d url maps and %d target proxies, want 1", len(mockGCE.MockUrlMaps.Objects), len(mockGCE.MockTargetHttpProxies.Objects))
+			}
+			var key meta.Key
+			var wantDefaultService string
+			for k, obj := range mockGCE.MockUrlMaps.Objects {
+				key, wantDefaultService = k, obj.ToGA().DefaultService
+			}
+			if tc.deleteProxy {
+				for k := range mockGCE.MockTargetHttpProxies.Objects {
+					delete(mockGCE.MockTargetHttpProxies.Objects, k)
+				}
+			} else {
+				um := mockGCE.MockUrlMaps.Objects[key].ToGA()
+				um.DefaultService = manualService
+				mockGCE.MockUrlMaps.Objects[key] = &cloud.MockUrlMapsObj{Obj: um}
+			}
+			if tc.changeAnnotation {
+				ing.Annotations["example.com/owner"] = "team-a"
+				updateIngress(lbc, ing)
+			}
+			// Deleted backend services are recreated regardless of the drift
+			// policy.
+			for k := range mockGCE.MockBackendServices.Objects {
+				delete(mockGCE.MockBackendServices.Objects, k)
+			}
+
+			if err := lbc.sync(ingStoreKey); err != nil {
+				t.Fatalf("lbc.sync(%v) = %v, want nil", ingStoreKey, err)
+			}
+			if tc.deleteProxy {
+				if healed := len(mockGCE.MockTargetHttpProxies.Objects) == 1; healed != tc.wantHeal {
+					t.Errorf("got %d target proxies after resync, healed = %v, want %v", len(mockGCE.MockTargetHttpProxies.Objects), healed, tc.wantHeal)
+				}
+			} else {
+				um := mockGCE.MockUrlMaps.Objects[key].ToGA()
+				if healed := um.DefaultService == wantDefaultService; healed != tc.wantHeal {
+					t.Errorf("url map default service = %q after resync, healed = %v, want %v", um.DefaultService, healed, tc.wantHeal)
+				}
+			}
+			if len(mockGCE.MockBackendServices.Objects) == 0 {
+				t.Errorf("got no backend services after resync, want the deleted backend services recreated")
+			}
+		})
+	}
+}
+
+// TestIngressDriftPolicyBackendService asserts that out-of-band changes to a
+// backend service are left in place by a resync only if every Ingress using
+// it has the report-only drift policy.
+func TestIngressDriftPolicyBackendService(t *testing.T) {
+	flags.F.EnableDriftDetection = true
+	defer func() { flags.F.EnableDriftDetection = false }()
+
+	const manualDescription = "manual"
+	for _, tc := range []struct {
+		desc   string
+		policy string
+		// shared adds an Ingress with the default drift policy using the
+		// same backend service.
+		shared   bool
+		wantHeal bool
+	}{
+		{
+			desc:     "auto-heal",
+			policy:   annotations.DriftPolicyAutoHeal,
+			wantHeal: true,
+		},
+		{
+			desc:   "report-only",
+			policy: annotations.DriftPolicyReportOnly,
+		},
+		{
+			desc:     "report-only shared with auto-heal",
+			policy:   annotations.DriftPolicyReportOnly,
+			shared:   true,
+			wantHeal: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			lbc := newLoadBalancerController()
+			mockGCE := lbc.ctx.Cloud.Compute().(*cloud.MockGCE)
+			mockGCE.MockBackendServices.UpdateHook = mock.UpdateBackendServiceHook
+
+			ing := ensureIngress(t, lbc, "default", "my-ingress", namer_util.V1NamingScheme)
+			ingStoreKey := getKey(ing, t)
+			ing.Annotations[annotations.DriftPolicyKey] = tc.policy
+			updateIngress(lbc, ing)
+			if err := lbc.sync(ingStoreKey); err != nil {
+				t.Fatalf("lbc.sync(%v) = %v, want nil", ingStoreKey, err)
+			}
+			if tc.shared {
+				other := test.NewIngress(types.NamespacedName{Name: "other", Namespace: "default"}, ing.Spec)
+				other.ObjectMeta.Finalizers = []string{common.FinalizerKey}
+				addIngress(lbc, other)
+				if err := lbc.sync(getKey(other, t)); err != nil {
+					t.Fatalf("lbc.sync(%v) = %v, want nil", getKey(other, t), err)
+				}
+			}
+			if len(mockGCE.MockBackendServices.Objects) == 0 {
+				t.Fatalf("got no backend services, want the backend service of the Ingress")
+			}
+			var wantDescriptions []string
+			for k, obj := range mockGCE.MockBackendServices.Objects {
+				bs := obj.ToGA()
+				wantDescriptions = append(wantDescriptions, bs.Description)
+				bs.Description = manualDescription
+				mockGCE.MockBackendServices.Objects[k] = &cloud.MockBackendServicesObj{Obj: bs}
+			}
+
+			if err := lbc.sync(ingStoreKey); err != nil {
+				t.Fatalf("lbc.sync(%v) = %v, want nil", ingStoreKey, err)
+			}
+			for _, obj := range mockGCE.MockBackendServices.Objects {
+				bs := obj.ToGA()
+				if healed := bs.Description != manualDescription; healed != tc.wantHeal {
+					t.Errorf("backend service %q has description %q after resync, healed = %v, want %v (descriptions before the change: %q)", bs.Name, bs.Description, healed, tc.wantHeal, wantDescriptions)
+				}
+			}
+		})
+	}
+}
+
+// TestIngressPauseReconciliation asserts that `sync` leaves the GCE resources
+// of a paused Ingress as they are, even when the Ingress or other Ingresses
+// are deleted.
+func TestIngressPauseReconciliation(t *testing.T) {
+	flagSaver := test.NewFlagSaver()
+	flagSaver.Save(test.FinalizerAddFlag, &flags.F.FinalizerAdd)
+	defer flagSaver.Reset(test.FinalizerAddFlag, &flags.F.FinalizerAdd)
+	flagSaver.Save(test.FinalizerRemoveFlag, &flags.F.FinalizerRemove)
+	defer flagSaver.Reset(test.FinalizerRemoveFlag, &flags.F.FinalizerRemove)
+	flags.F.FinalizerAdd = true
+	flags.F.FinalizerRemove = true
+	lbc := newLoadBalancerController()
+	mockGCE := lbc.ctx.Cloud.Compute().(*cloud.MockGCE)
+
+	ing := ensureIngress(t, lbc, "default", "paused", namer_util.V2NamingScheme)
+	otherIng := ensureIngress(t, lbc, "default", "other", namer_util.V2NamingScheme)
+	if n := len(mockGCE.MockUrlMaps.Objects); n != 2 {
+		t.Fatalf("got %d url maps, want 2", n)
+	}
+	pausedBackend := lbc.ctx.ClusterNamer.IGBackend(int64(nodePortCounter - 2))
+
+	// Deleting the paused Ingress keeps its load balancer and finalizer.
+	ing.Annotations = map[string]string{annotations.PauseReconciliationKey: "true"}
+	setDeletionTimestamp(lbc, ing)
+	ingStoreKey := getKey(ing, t)
+	if err := lbc.sync(ingStoreKey); err != nil {
+		t.Fatalf("lbc.sync(%v) = %v, want nil", ingStoreKey, err)
+	}
+	if n := len(mockGCE.MockUrlMaps.Objects); n != 2 {
+		t.Errorf("got %d url maps after deleting the paused Ingress, want 2", n)
+	}
+	if finalizers := getUpdatedIngress(t, lbc, ing).Finalizers; len(finalizers) != 1 {
+		t.Errorf("finalizers of the paused Ingress = %v, want %v", finalizers, []string{common.FinalizerKeyV2})
+	}
+
+	// Deleting another Ingress does not garbage collect the paused one.
+	setDeletionTimestamp(lbc, otherIng)
+	otherIngStoreKey := getKey(otherIng, t)
+	if err := lbc.sync(otherIngStoreKey); err != nil {
+		t.Fatalf("lbc.sync(%v) = %v, want nil", otherIngStoreKey, err)
+	}
+	if n := len(mockGCE.MockUrlMaps.Objects); n != 1 {
+		t.Errorf("got %d url maps after deleting the other Ingress, want 1", n)
+	}
+	if _, ok := mockGCE.MockBackendServices.Objects[*meta.GlobalKey(pausedBackend)]; !ok {
+		t.Errorf("backend service %s of the paused Ingress was deleted", pausedBackend)
+	}
+
+	// Resuming the reconciliation completes the deletion.
+	ing = getUpdatedIngress(t, lbc, ing)
+	delete(ing.Annotations, annotations.PauseReconciliationKey)
+	updateIngress(lbc, ing)
+	if err := lbc.sync(ingStoreKey); err != nil {
+		t.Fatalf("lbc.sync(%v) = %v, want nil", ingStoreKey, err)
+	}
+	if n := len(mockGCE.MockUrlMaps.Objects); n != 0 {
+		t.Errorf("got %d url maps after resuming the reconciliation, want none", n)
+	}
+	if finalizers := getUpdatedIngress(t, lbc, ing).Finalizers; len(finalizers) != 0 {
+		t.Errorf("finalizers of the resumed Ingress = %v, want none", finalizers)
+	}
+}
+
+// TestIngressDeletionProtection asserts that deleting a protected Ingress
+// keeps its load balancer and finalizer until the protection is removed.
+func TestIngressDeletionProtection(t *testing.T) {
+	flagSaver := test.NewFlagSaver()
+	flagSaver.Save(test.FinalizerAddFlag, &flags.F.FinalizerAdd)
+	defer flagSaver.Reset(test.FinalizerAddFlag, &flags.F.FinalizerAdd)
+	flagSaver.Save(test.FinalizerRemoveFlag, &flags.F.FinalizerRemove)
+	defer flagSaver.Reset(test.FinalizerRemoveFlag, &flags.F.FinalizerRemove)
+	flags.F.FinalizerAdd = true
+	flags.F.FinalizerRemove = true
+	lbc := newLoadBalancerController()
+	mockGCE := lbc.ctx.Cloud.Compute().(*cloud.MockGCE)
+
+	ing := ensureIngress(t, lbc, "default", "protected", namer_util.V2NamingScheme)
+	ing.Annotations = map[string]string{annotations.DeletionProtectionKey: "true"}
+	setDeletionTimestamp(lbc, ing)
+	ingStoreKey := getKey(ing, t)
+	if err := lbc.sync(ingStoreKey); err != nil {
+		t.Fatalf("lbc.sync(%v) = %v, want nil", ingStoreKey, err)
+	}
+	if n := len(mockGCE.MockUrlMaps.Objects); n != 1 {
+		t.Errorf("got %d url maps after deleting the protected Ingress, want 1", n)
+	}
+	if n := len(mockGCE.MockGlobalForwardingRules.Objects); n != 1 {
+		t.Errorf("got %d forwarding rules after deleting the protected Ingress, want 1", n)
+	}
+	if finalizers := getUpdatedIngress(t, lbc, ing).Finalizers; len(finalizers) != 1 {
+		t.Errorf("finalizers of the protected Ingress = %v, want %v", finalizers, []string{common.FinalizerKeyV2})
+	}
+
+	// Removing the protection completes the deletion.
+	ing = getUpdatedIngress(t, lbc, ing)
+	delete(ing.Annotations, annotations.DeletionProtectionKey)
+	updateIngress(lbc, ing)
+	if err := lbc.sync(ingStoreKey); err != nil {
+		t.Fatalf("lbc.sync(%v) = %v, want nil", ingStoreKey, err)
+	}
+	if n := len(mockGCE.MockUrlMaps.Objects); n != 0 {
+		t.Errorf("got %d url maps after removing the protection, want none", n)
+	}
+	if finalizers := getUpdatedIngress(t, lbc, ing).Finalizers; len(finalizers) != 0 {
+		t.Errorf("finalizers of the unprotected Ingress = %v, want none", finalizers)
+	}
+}
+
+// fakeBackendBucketPool serves the backend buckets of a backends.Pool from
+// memory.
+type fakeBackendBucketPool struct {
+	backends.Pool
+	buckets sets.Set[string]
+}
+
+func (p *fakeBackendBucketPool) ListBackendBuckets(klog.Logger) ([]*compute.BackendBucket, error) {
+	var buckets []*compute.BackendBucket
+	for _, name := range sets.List(p.buckets) {
+		buckets = append(buckets, &compute.BackendBucket{Name: name})
+	}
+	return buckets, nil
+}
+
+func (p *fakeBackendBucketPool) DeleteBackendBucket(name string, _ klog.Logger) error {
+	p.buckets.Delete(name)
+	return nil
+}
+
+func TestGCBackendBuckets(t *testing.T) {
+	lbc := newLoadBalancerController()
+	serviceBackend := backend(test.DefaultBeSvcPort.ID.Service.Name, test.BackendPort)
+	pathType := networkingv1.PathTypePrefix
+	ing := test.NewIngress(types.NamespacedName{Namespace: test.DefaultBeSvcPort.ID.Service.Namespace, Name: "assets"}, networkingv1.IngressSpec{
+		DefaultBackend: &serviceBackend,
+		Rules: []networkingv1.IngressRule{{
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/assets",
+						PathType: &pathType,
+						Backend: networkingv1.IngressBackend{
+							Resource: &api_v1.TypedLocalObjectReference{Kind: "GCSBackend", Name: "assets"},
+						},
+					}},
+				},
+			},
+		}},
+	})
+	bucketName := lbc.namerFactory.Namer(ing).BackendBucket(ing.Namespace, "assets")
+
+	for _, tc := range []struct {
+		desc        string
+		ing         *networkingv1.Ingress
+		wantBuckets []string
+	}{
+		{
+			// GCSBackends are disabled in the translator, so the backend
+			// bucket of the ingress cannot be translated.
+			desc:        "translation failed",
+			ing:         ing,
+			wantBuckets: []string{bucketName},
+		},
+		{
+			desc: "backend bucket not used",
+			ing: test.NewIngress(types.NamespacedName{Namespace: test.DefaultBeSvcPort.ID.Service.Namespace, Name: "service"}, networkingv1.IngressSpec{
+				DefaultBackend: &serviceBackend,
+			}),
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			pool := &fakeBackendBucketPool{Pool: lbc.backendPool, buckets: sets.New(bucketName)}
+			lbc.backendPool = pool
+			if err := lbc.gcBackendBuckets([]*networkingv1.Ingress{tc.ing}, klog.TODO()); err != nil {
+				t.Fatalf("gcBackendBuckets() = %v, want nil", err)
+			}
+			if got := sets.List(pool.buckets); len(got)+len(tc.wantBuckets) > 0 && !reflect.DeepEqual(got, tc.wantBuckets) {
+				t.Errorf("backend buckets after GC = %v, want %v", got, tc.wantBuckets)
+			}
+		})
+	}
+}
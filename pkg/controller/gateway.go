@@ -0,0 +1,437 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	context2 "context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	apiv1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
+	"k8s.io/ingress-gce/pkg/common/operator"
+	"k8s.io/ingress-gce/pkg/context"
+	"k8s.io/ingress-gce/pkg/events"
+	"k8s.io/ingress-gce/pkg/flags"
+	"k8s.io/ingress-gce/pkg/gateway"
+	"k8s.io/ingress-gce/pkg/loadbalancers/features"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/common"
+	"k8s.io/klog/v2"
+)
+
+// initGatewayController sets up the queue and the event handlers for Gateways
+// and HTTPRoutes. Each Gateway is synthesized into an Ingress which is synced
+// through the same backend syncers, linkers and load balancer pool as regular
+// Ingresses.
+func (lbc *LoadBalancerController) initGatewayController(logger klog.Logger) {
+	ctx := lbc.ctx
+	gwLogger := logger.WithName("Gateway")
+	lbc.gatewayQueue = utils.NewPeriodicTaskQueueWithMultipleWorkers("gateway", "gateways", flags.F.NumIngressWorkers, lbc.syncGateway, gwLogger)
+
+	ctx.GatewayInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			lbc.gatewayQueue.Enqueue(obj)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			// Status updates are written by the sync itself.
+			if !reflect.DeepEqual(withoutStatus(old), withoutStatus(cur)) {
+				lbc.gatewayQueue.Enqueue(cur)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			lbc.gatewayQueue.Enqueue(obj)
+		},
+	})
+
+	enqueueParents := func(obj interface{}) {
+		if state, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = state.Obj
+		}
+		route, err := gateway.HTTPRouteFromUnstructured(obj)
+		if err != nil {
+			gwLogger.Error(err, "Failed to decode HTTPRoute")
+			return
+		}
+		for _, key := range gateway.ParentGateways(route) {
+			lbc.gatewayQueue.Enqueue(cache.ExplicitKey(key))
+		}
+	}
+	ctx.HTTPRouteInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueueParents,
+		UpdateFunc: func(old, cur interface{}) {
+			if !reflect.DeepEqual(withoutStatus(old), withoutStatus(cur)) {
+				// Routes detached from a Gateway must be removed from its URL map.
+				enqueueParents(old)
+				enqueueParents(cur)
+			}
+		},
+		DeleteFunc: enqueueParents,
+	})
+
+	enqueueForService := func(obj interface{}) {
+		if state, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = state.Obj
+		}
+		svc, ok := obj.(*apiv1.Service)
+		if !ok {
+			gwLogger.Error(nil, "Wanted service obj", "got", fmt.Sprintf("%+v", obj))
+			return
+		}
+		for _, route := range lbc.gatewayRoutes(svc.Namespace) {
+			if gateway.ReferencesService(route, svc) {
+				for _, key := range gateway.ParentGateways(route) {
+					lbc.gatewayQueue.Enqueue(cache.ExplicitKey(key))
+				}
+			}
+		}
+	}
+	ctx.ServiceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueueForService,
+		UpdateFunc: func(old, cur interface{}) {
+			if !reflect.DeepEqual(old, cur) {
+				enqueueForService(cur)
+			}
+		},
+		// The routes of a deleted Service lose their backend.
+		DeleteFunc: enqueueForService,
+	})
+
+	enqueueForBackendConfig := func(obj interface{}) {
+		if state, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = state.Obj
+		}
+		beConfig, ok := obj.(*backendconfigv1.BackendConfig)
+		if !ok {
+			gwLogger.Error(nil, "Wanted backendconfig obj", "got", fmt.Sprintf("%+v", obj))
+			return
+		}
+		for _, svc := range operator.Services(ctx.Services().List(), gwLogger).ReferencesBackendConfig(beConfig).AsList() {
+			enqueueForService(svc)
+		}
+	}
+	ctx.BackendConfigInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueueForBackendConfig,
+		UpdateFunc: func(old, cur interface{}) {
+			if !reflect.DeepEqual(old, cur) {
+				enqueueForBackendConfig(cur)
+			}
+		},
+		DeleteFunc: enqueueForBackendConfig,
+	})
+}
+
+// withoutStatus returns the Gateway API object without its status.
+func withoutStatus(obj interface{}) interface{} {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return obj
+	}
+	u = u.DeepCopy()
+	unstructured.RemoveNestedField(u.Object, "status")
+	return u
+}
+
+// gatewayRoutes returns the HTTPRoutes in the given namespace.
+func (lbc *LoadBalancerController) gatewayRoutes(namespace string) []*gateway.HTTPRoute {
+	objs, err := lbc.ctx.HTTPRouteInformer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+	if err != nil {
+		lbc.logger.Error(err, "Failed to list HTTPRoutes", "namespace", namespace)
+		return nil
+	}
+	var routes []*gateway.HTTPRoute
+	for _, obj := range objs {
+		route, err := gateway.HTTPRouteFromUnstructured(obj)
+		if err != nil {
+			lbc.logger.Error(err, "Failed to decode HTTPRoute")
+			continue
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// statusRoutes returns the HTTPRoutes whose status may refer to the Gateway:
+// the routes in its namespace, and the routes of other namespaces attached
+// to it or reporting a status for it.
+func (lbc *LoadBalancerController) statusRoutes(gw *gateway.Gateway) []*gateway.HTTPRoute {
+	routes := lbc.gatewayRoutes(gw.Namespace)
+	objs, err := lbc.ctx.HTTPRouteInformer.GetIndexer().ByIndex(gateway.ParentGatewayIndex, gw.Namespace+"/"+gw.Name)
+	if err != nil {
+		lbc.logger.Error(err, "Failed to list HTTPRoutes", "gateway", klog.KObj(gw))
+		return routes
+	}
+	for _, obj := range objs {
+		route, err := gateway.HTTPRouteFromUnstructured(obj)
+		if err != nil {
+			lbc.logger.Error(err, "Failed to decode HTTPRoute")
+			continue
+		}
+		// The routes in the namespace of the Gateway are already listed.
+		if route.Namespace != gw.Namespace {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// gatewayIngresses returns the Ingresses synthesized for the Gateways managed
+// by this controller.
+func (lbc *LoadBalancerController) gatewayIngresses() []*v1.Ingress {
+	if lbc.ctx.GatewayInformer == nil {
+		return nil
+	}
+	var ings []*v1.Ingress
+	for _, obj := range lbc.ctx.GatewayInformer.GetStore().List() {
+		gw, err := gateway.GatewayFromUnstructured(obj)
+		if err != nil {
+			lbc.logger.Error(err, "Failed to decode Gateway")
+			continue
+		}
+		if _, ok := gateway.IngressClass(gw); !ok && !common.HasGivenFinalizer(gw.ObjectMeta, gateway.FinalizerKey) {
+			continue
+		}
+		// Translation errors are reported when the Gateway is synced.
+		ing, _ := gateway.ToIngress(gw, lbc.gatewayRoutes(gw.Namespace))
+		ings = append(ings, ing)
+	}
+	return ings
+}
+
+// allIngresses returns the Ingresses and the Ingresses synthesized for
+// Gateways. Garbage collection uses it to keep the GCE resources of both.
+func (lbc *LoadBalancerController) allIngresses() []*v1.Ingress {
+	return append(lbc.ctx.Ingresses().List(), lbc.gatewayIngresses()...)
+}
+
+// syncGateway manages Gateway create/updates/deletes events from queue.
+func (lbc *LoadBalancerController) syncGateway(key string) error {
+	syncTrackingId := rand.Int31()
+	gwLogger := lbc.logger.WithValues("gatewayKey", key, "syncId", syncTrackingId)
+	if !lbc.hasSynced() {
+		time.Sleep(context.StoreSyncPollPeriod)
+		return fmt.Errorf("waiting for stores to sync")
+	}
+	if flags.F.DryRun {
+		gwLogger.Info("Skipping gateway sync in dry run mode")
+		return nil
+	}
+	gwLogger.Info("Syncing gateway")
+
+	obj, exists, err := lbc.ctx.GatewayInformer.GetIndexer().GetByKey(key)
+	if err != nil {
+		return fmt.Errorf("error getting Gateway for key %s: %v", key, err)
+	}
+	if !exists {
+		// The finalizer ensures resources are deleted before the Gateway is gone.
+		gwLogger.Info("Gateway does not exist, skipping sync")
+		return nil
+	}
+	u := obj.(*unstructured.Unstructured)
+	gw, err := gateway.GatewayFromUnstructured(u)
+	if err != nil {
+		return err
+	}
+
+	hasFinalizer := common.HasGivenFinalizer(gw.ObjectMeta, gateway.FinalizerKey)
+	if _, ok := gateway.IngressClass(gw); !hasFinalizer && (!ok || gw.DeletionTimestamp != nil) {
+		gwLogger.Info("Gateway is not managed by this controller, skipping sync")
+		return nil
+	}
+
+	routes := lbc.gatewayRoutes(gw.Namespace)
+	ing, errs := gateway.ToIngress(gw, routes)
+	scope := features.ScopeFromIngress(ing)
+	if utils.NeedsCleanup(ing) {
+		return lbc.gcGateway(u, ing, utils.CleanupV2FrontendResources, scope, nil, gwLogger)
+	}
+
+	// The older of the Gateway and of an Ingress with the same name keeps the
+	// load balancer, see loadBalancerNameConflict.
+	if other, exists, err := lbc.ctx.Ingresses().GetByKey(ing.Namespace + "/" + ing.Name); err == nil && exists && !ing.CreationTimestamp.Before(&other.CreationTimestamp) {
+		msg := fmt.Errorf("gateway conflicts with Ingress %s/%s which uses the same load balancer name", ing.Namespace, ing.Name)
+		lbc.ctx.Recorder(gw.Namespace).Eventf(u, apiv1.EventTypeWarning, events.SyncIngress, "Error: %v", msg)
+		lbc.updateGatewayStatus(u, gw, routes, msg, "", gwLogger)
+		return msg
+	}
+	if errs != nil {
+		msg := fmt.Errorf("invalid gateway spec: %v", utils.JoinErrs(errs))
+		lbc.ctx.Recorder(gw.Namespace).Eventf(u, apiv1.EventTypeWarning, events.TranslateIngress, "Translation failed: %v", msg)
+		lbc.updateGatewayStatus(u, gw, routes, msg, "", gwLogger)
+		return msg
+	}
+
+	if !hasFinalizer {
+		if u, err = lbc.ensureGatewayFinalizer(u, gwLogger); err != nil {
+			return err
+		}
+		ing.Finalizers = []string{common.FinalizerKeyV2}
+	}
+
+	urlMap, errs, _ := lbc.Translator.TranslateIngress(ing, lbc.ctx.DefaultBackendSvcPort.ID, lbc.ctx.ClusterNamer)
+	if errs != nil {
+		msg := fmt.Errorf("invalid gateway spec: %v", utils.JoinErrs(errs))
+		lbc.ctx.Recorder(gw.Namespace).Eventf(u, apiv1.EventTypeWarning, events.TranslateIngress, "Translation failed: %v", msg)
+		lbc.updateGatewayStatus(u, gw, routes, msg, "", gwLogger)
+		return msg
+	}
+
+	syncState := &syncState{urlMap: urlMap, ing: ing}
+	syncErr := lbc.ingSyncer.Sync(syncState, gwLogger)
+	if syncErr != nil {
+		lbc.ctx.Recorder(gw.Namespace).Eventf(u, apiv1.EventTypeWarning, events.SyncIngress, "Error syncing to GCP: %v", syncErr.Error())
+	}
+	ip := ""
+	if syncState.l7 != nil {
+		ip = syncState.l7.GetIP()
+	}
+	if err := lbc.updateGatewayStatus(u, gw, routes, syncErr, ip, gwLogger); err != nil && syncErr == nil {
+		syncErr = err
+	}
+
+	oldScope, err := lbc.l7Pool.FrontendScopeChangeGC(ing, gwLogger)
+	if err != nil {
+		return err
+	}
+	frontendGCAlgorithm := utils.NoCleanUpNeeded
+	if oldScope != nil {
+		scope = *oldScope
+		frontendGCAlgorithm = utils.CleanupV2FrontendResourcesScopeChange
+	}
+	return lbc.gcGateway(u, ing, frontendGCAlgorithm, scope, syncErr, gwLogger)
+}
+
+// gcGateway runs garbage collection for the Ingress synthesized for a Gateway.
+func (lbc *LoadBalancerController) gcGateway(u *unstructured.Unstructured, ing *v1.Ingress, frontendGCAlgorithm utils.FrontendGCAlgorithm, scope meta.KeyType, syncErr error, gwLogger klog.Logger) error {
+	lbc.gcLock.Lock()
+	defer lbc.gcLock.Unlock()
+
+	if gcErr := lbc.ingSyncer.GC(lbc.allIngresses(), ing, frontendGCAlgorithm, scope, gwLogger); gcErr != nil {
+		lbc.ctx.Recorder(u.GetNamespace()).Eventf(u, apiv1.EventTypeWarning, events.GarbageCollection, "Error during garbage collection: %v", gcErr)
+		if syncErr == nil {
+			return gcErr
+		}
+		return fmt.Errorf("error during sync %v, error during GC %v", syncErr, gcErr)
+	}
+	return syncErr
+}
+
+// ensureGatewayFinalizer adds the Gateway finalizer, which also selects the
+// v2 frontend naming scheme for the resources of the Gateway.
+func (lbc *LoadBalancerController) ensureGatewayFinalizer(u *unstructured.Unstructured, gwLogger klog.Logger) (*unstructured.Unstructured, error) {
+	gwLogger.Info("Adding finalizer", "finalizer", gateway.FinalizerKey)
+	return lbc.patchGateway(u.GetNamespace(), u.GetName(), map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers":      append(u.GetFinalizers(), gateway.FinalizerKey),
+			"resourceVersion": u.GetResourceVersion(),
+		},
+	})
+}
+
+// patchGateway applies a JSON merge patch to the Gateway.
+func (lbc *LoadBalancerController) patchGateway(namespace, name string, patch map[string]interface{}) (*unstructured.Unstructured, error) {
+	return lbc.patchGatewayAPIObject(gateway.GatewayGVR, namespace, name, patch)
+}
+
+// patchGatewayAPIObject applies a JSON merge patch to a Gateway API object.
+// The subresources are patched instead of the object if given.
+func (lbc *LoadBalancerController) patchGatewayAPIObject(gvr schema.GroupVersionResource, namespace, name string, patch map[string]interface{}, subresources ...string) (*unstructured.Unstructured, error) {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	return lbc.ctx.GatewayClient.Resource(gvr).Namespace(namespace).Patch(context2.TODO(), name, types.MergePatchType, data, metav1.PatchOptions{}, subresources...)
+}
+
+// ensureDeleteGatewayFinalizer removes the Gateway finalizer once the
+// resources of the Gateway have been deleted.
+func (lbc *LoadBalancerController) ensureDeleteGatewayFinalizer(gwKey types.NamespacedName, gwLogger klog.Logger) error {
+	obj, exists, err := lbc.ctx.GatewayInformer.GetIndexer().GetByKey(gwKey.String())
+	if err != nil || !exists {
+		return err
+	}
+	u := obj.(*unstructured.Unstructured)
+	var finalizers []string
+	for _, f := range u.GetFinalizers() {
+		if f != gateway.FinalizerKey {
+			finalizers = append(finalizers, f)
+		}
+	}
+	if len(finalizers) == len(u.GetFinalizers()) {
+		return nil
+	}
+	gwLogger.Info("Removing finalizer", "finalizer", gateway.FinalizerKey)
+	_, err = lbc.patchGateway(gwKey.Namespace, gwKey.Name, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers":      finalizers,
+			"resourceVersion": u.GetResourceVersion(),
+		},
+	})
+	return err
+}
+
+// updateGatewayStatus records the IP of the load balancer and the Accepted
+// and Programmed conditions in the status of the Gateway, its listeners and
+// the routes referencing it.
+func (lbc *LoadBalancerController) updateGatewayStatus(u *unstructured.Unstructured, gw *gateway.Gateway, routes []*gateway.HTTPRoute, syncErr error, ip string, gwLogger klog.Logger) error {
+	status := gateway.GatewayStatusFor(gw, routes, syncErr, ip)
+	if !reflect.DeepEqual(*status, gw.Status) {
+		gwLogger.Info("Updating gateway status", "IP", ip)
+		if _, err := lbc.patchGatewayAPIObject(gateway.GatewayGVR, gw.Namespace, gw.Name, map[string]interface{}{
+			"status": status,
+		}, "status"); err != nil {
+			gwLogger.Error(err, "Failed to update gateway status")
+			return err
+		}
+		if addrs := gw.Status.Addresses; ip != "" && (len(addrs) != 1 || addrs[0].Value != ip) {
+			lbc.ctx.Recorder(gw.Namespace).Eventf(u, apiv1.EventTypeNormal, events.IPChanged, "IP is now %v", ip)
+		}
+	}
+
+	// Routes in other namespaces are not attached, but are reported as such
+	// in their status.
+	for _, route := range lbc.statusRoutes(gw) {
+		parents := gateway.RouteParentStatusesFor(route, gw, status)
+		if reflect.DeepEqual(parents, route.Status.Parents) {
+			continue
+		}
+		routeLogger := gwLogger.WithValues("httpRoute", klog.KObj(route))
+		routeLogger.Info("Updating HTTPRoute status")
+		if _, err := lbc.patchGatewayAPIObject(gateway.HTTPRouteGVR, route.Namespace, route.Name, map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"resourceVersion": route.ResourceVersion,
+			},
+			"status": map[string]interface{}{
+				"parents": parents,
+			},
+		}, "status"); err != nil {
+			routeLogger.Error(err, "Failed to update HTTPRoute status")
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	context2 "context"
+	"encoding/json"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	api_v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/cloud-provider-gcp/providers/gce"
+	backendconfigclient "k8s.io/ingress-gce/pkg/backendconfig/client/clientset/versioned/fake"
+	"k8s.io/ingress-gce/pkg/context"
+	"k8s.io/ingress-gce/pkg/events"
+	"k8s.io/ingress-gce/pkg/flags"
+	"k8s.io/ingress-gce/pkg/gateway"
+	"k8s.io/ingress-gce/pkg/instancegroups"
+	"k8s.io/ingress-gce/pkg/loadbalancers"
+	"k8s.io/ingress-gce/pkg/test"
+	"k8s.io/ingress-gce/pkg/utils"
+	namer_util "k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/ingress-gce/pkg/utils/zonegetter"
+	"k8s.io/klog/v2"
+)
+
+// newGatewayController returns a LoadBalancerController with the Gateway API
+// controller enabled.
+func newGatewayController() *LoadBalancerController {
+	kubeClient := fake.NewSimpleClientset()
+	backendConfigClient := backendconfigclient.NewSimpleClientset()
+	gatewayClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		gateway.GatewayGVR:   "GatewayList",
+		gateway.HTTPRouteGVR: "HTTPRouteList",
+	})
+	fakeGCE := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
+	nodeInformer := zonegetter.FakeNodeInformer()
+	fakeZoneGetter := zonegetter.NewZoneGetter(nodeInformer)
+	zonegetter.AddFakeNodes(fakeZoneGetter, fakeZone, "test-node")
+
+	(fakeGCE.Compute().(*cloud.MockGCE)).MockGlobalForwardingRules.InsertHook = loadbalancers.InsertGlobalForwardingRuleHook
+	namer := namer_util.NewNamer(clusterUID, "", klog.TODO())
+
+	stopCh := make(chan struct{})
+	ctxConfig := context.ControllerContextConfig{
+		Namespace:             api_v1.NamespaceAll,
+		ResyncPeriod:          0,
+		DefaultBackendSvcPort: test.DefaultBeSvcPort,
+		HealthCheckPath:       "/",
+	}
+	ctx := context.NewControllerContext(nil, kubeClient, backendConfigClient, nil, nil, nil, nil, nil, nil, nil, gatewayClient, fakeGCE, namer, "" /*kubeSystemUID*/, ctxConfig, klog.TODO())
+	lbc := NewLoadBalancerController(ctx, stopCh, klog.TODO())
+	lbc.instancePool = instancegroups.NewManager(&instancegroups.ManagerConfig{
+		Cloud:      instancegroups.NewEmptyFakeInstanceGroups(),
+		Namer:      namer,
+		Recorders:  &test.FakeRecorderSource{},
+		BasePath:   utils.GetBasePath(fakeGCE),
+		ZoneGetter: fakeZoneGetter,
+		MaxIGSize:  1000,
+	}, klog.TODO())
+	lbc.l7Pool = loadbalancers.NewLoadBalancerPool(fakeGCE, namer, events.RecorderProducerMock{}, namer_util.NewFrontendNamerFactory(namer, "", klog.TODO()), klog.TODO())
+	lbc.hasSynced = func() bool { return true }
+
+	defaultSvc := test.NewService(test.DefaultBeSvcPort.ID.Service, api_v1.ServiceSpec{
+		Type:  api_v1.ServiceTypeNodePort,
+		Ports: []api_v1.ServicePort{{Name: "http", Port: 80}},
+	})
+	addService(lbc, defaultSvc)
+	return lbc
+}
+
+func toUnstructured(t *testing.T, obj interface{}) *unstructured.Unstructured {
+	t.Helper()
+	data, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &u.Object); err != nil {
+		t.Fatalf("json.Unmarshal() = %v", err)
+	}
+	return u
+}
+
+// applyGatewayObject writes the object to the dynamic client and the informer store.
+func applyGatewayObject(t *testing.T, lbc *LoadBalancerController, u *unstructured.Unstructured) {
+	t.Helper()
+	gvr, store := gateway.GatewayGVR, lbc.ctx.GatewayInformer.GetIndexer()
+	if u.GetKind() == "HTTPRoute" {
+		gvr, store = gateway.HTTPRouteGVR, lbc.ctx.HTTPRouteInformer.GetIndexer()
+	}
+	client := lbc.ctx.GatewayClient.Resource(gvr).Namespace(u.GetNamespace())
+	got, err := client.Update(context2.TODO(), u, meta_v1.UpdateOptions{})
+	if err != nil {
+		if got, err = client.Create(context2.TODO(), u, meta_v1.CreateOptions{}); err != nil {
+			t.Fatalf("Create(%s) = %v", u.GetName(), err)
+		}
+	}
+	if err := store.Update(got); err != nil {
+		t.Fatalf("store.Update(%s) = %v", u.GetName(), err)
+	}
+}
+
+// syncGatewayFromClient refreshes the informer store from the dynamic client
+// and syncs the Gateway.
+func syncGatewayFromClient(t *testing.T, lbc *LoadBalancerController, key types.NamespacedName) (*gateway.Gateway, error) {
+	t.Helper()
+	u, err := lbc.ctx.GatewayClient.Resource(gateway.GatewayGVR).Namespace(key.Namespace).Get(context2.TODO(), key.Name, meta_v1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(%v) = %v", key, err)
+	}
+	lbc.ctx.GatewayInformer.GetIndexer().Update(u)
+	syncErr := lbc.syncGateway(key.String())
+
+	u, err = lbc.ctx.GatewayClient.Resource(gateway.GatewayGVR).Namespace(key.Namespace).Get(context2.TODO(), key.Name, meta_v1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(%v) = %v", key, err)
+	}
+	lbc.ctx.GatewayInformer.GetIndexer().Update(u)
+	gw, err := gateway.GatewayFromUnstructured(u)
+	if err != nil {
+		t.Fatalf("GatewayFromUnstructured() = %v", err)
+	}
+	return gw, syncErr
+}
+
+func TestGatewayCreateDelete(t *testing.T) {
+	defer func(old bool) { flags.F.EnableIngressGlobalExternal = old }(flags.F.EnableIngressGlobalExternal)
+	flags.F.EnableIngressGlobalExternal = true
+	lbc := newGatewayController()
+	svc := test.NewService(types.NamespacedName{Name: "my-service", Namespace: "default"}, api_v1.ServiceSpec{
+		Type:  api_v1.ServiceTypeNodePort,
+		Ports: []api_v1.ServicePort{{Port: 80}},
+	})
+	addService(lbc, svc)
+
+	port := int32(80)
+	path := "/foo"
+	gw := &gateway.Gateway{
+		TypeMeta:   meta_v1.TypeMeta{APIVersion: gateway.GroupName + "/v1", Kind: gateway.KindGateway},
+		ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "my-gateway"},
+		Spec: gateway.GatewaySpec{
+			GatewayClassName: "gke-l7-gxlb",
+			Listeners:        []gateway.Listener{{Name: "http", Port: 80, Protocol: gateway.HTTPProtocolType}},
+		},
+	}
+	route := &gateway.HTTPRoute{
+		TypeMeta:   meta_v1.TypeMeta{APIVersion: gateway.GroupName + "/v1", Kind: "HTTPRoute"},
+		ObjectMeta: meta_v1.ObjectMeta{Namespace: "default", Name: "my-route"},
+		Spec: gateway.HTTPRouteSpec{
+			ParentRefs: []gateway.ParentReference{{Name: "my-gateway"}},
+			Rules: []gateway.HTTPRouteRule{{
+				Matches:     []gateway.HTTPRouteMatch{{Path: &gateway.HTTPPathMatch{Value: &path}}},
+				BackendRefs: []gateway.HTTPBackendRef{{Name: "my-service", Port: &port}},
+			}},
+		},
+	}
+	gwNamespace := "default"
+	otherRoute := &gateway.HTTPRoute{
+		TypeMeta:   route.TypeMeta,
+		ObjectMeta: meta_v1.ObjectMeta{Namespace: "other", Name: "other-route"},
+		Spec: gateway.HTTPRouteSpec{
+			ParentRefs: []gateway.ParentReference{{Namespace: &gwNamespace, Name: "my-gateway"}},
+			Rules:      route.Spec.Rules,
+		},
+	}
+	applyGatewayObject(t, lbc, toUnstructured(t, gw))
+	applyGatewayObject(t, lbc, toUnstructured(t, route))
+	applyGatewayObject(t, lbc, toUnstructured(t, otherRoute))
+	key := types.NamespacedName{Namespace: "default", Name: "my-gateway"}
+
+	got, err := syncGatewayFromClient(t, lbc, key)
+	if err != nil {
+		t.Fatalf("lbc.syncGateway(%v) = %v, want nil", key, err)
+	}
+	if len(got.Finalizers) != 1 || got.Finalizers[0] != gateway.FinalizerKey {
+		t.Errorf("Finalizers = %v, want [%s]", got.Finalizers, gateway.FinalizerKey)
+	}
+	// The first sync adds the finalizer, which selects the v2 naming scheme.
+	if got, err = syncGatewayFromClient(t, lbc, key); err != nil {
+		t.Fatalf("lbc.syncGateway(%v) = %v, want nil", key, err)
+	}
+	if len(got.Status.Addresses) != 1 || got.Status.Addresses[0].Value == "" {
+		t.Errorf("Status.Addresses = %v, want one address", got.Status.Addresses)
+	}
+	for _, condType := range []string{gateway.ConditionAccepted, gateway.ConditionProgrammed} {
+		if !meta.IsStatusConditionTrue(got.Status.Conditions, condType) {
+			t.Errorf("Status.Conditions = %+v, want %s", got.Status.Conditions, condType)
+		}
+	}
+	if len(got.Status.Listeners) != 1 || got.Status.Listeners[0].AttachedRoutes != 1 || !meta.IsStatusConditionTrue(got.Status.Listeners[0].Conditions, gateway.ConditionProgrammed) {
+		t.Errorf("Status.Listeners = %+v, want one programmed listener with one route", got.Status.Listeners)
+	}
+	u, err := lbc.ctx.GatewayClient.Resource(gateway.HTTPRouteGVR).Namespace("default").Get(context2.TODO(), "my-route", meta_v1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(my-route) = %v", err)
+	}
+	gotRoute, err := gateway.HTTPRouteFromUnstructured(u)
+	if err != nil {
+		t.Fatalf("HTTPRouteFromUnstructured() = %v", err)
+	}
+	if parents := gotRoute.Status.Parents; len(parents) != 1 || parents[0].ControllerName != gateway.ControllerName || !meta.IsStatusConditionTrue(parents[0].Conditions, gateway.ConditionAccepted) {
+		t.Errorf("HTTPRoute Status.Parents = %+v, want the route accepted by the gateway", parents)
+	}
+	// Routes of other namespaces are found through the parent Gateway index.
+	u, err = lbc.ctx.GatewayClient.Resource(gateway.HTTPRouteGVR).Namespace("other").Get(context2.TODO(), "other-route", meta_v1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(other-route) = %v", err)
+	}
+	if gotRoute, err = gateway.HTTPRouteFromUnstructured(u); err != nil {
+		t.Fatalf("HTTPRouteFromUnstructured() = %v", err)
+	}
+	if parents := gotRoute.Status.Parents; len(parents) != 1 || parents[0].ControllerName != gateway.ControllerName || meta.IsStatusConditionTrue(parents[0].Conditions, gateway.ConditionAccepted) {
+		t.Errorf("HTTPRoute other/other-route Status.Parents = %+v, want the route not accepted by the gateway", parents)
+	}
+	urlMaps, err := lbc.ctx.Cloud.ListURLMaps()
+	if err != nil || len(urlMaps) != 1 {
+		t.Fatalf("ListURLMaps() = %v, %v, want 1 URL map", urlMaps, err)
+	}
+
+	// A real Ingress with the name of the synthesized Ingress conflicts with the Gateway.
+	conflicting := test.NewIngress(types.NamespacedName{Namespace: "default", Name: gateway.IngressName(gw)}, networkingv1.IngressSpec{})
+	lbc.ctx.IngressInformer.GetIndexer().Add(conflicting)
+	if _, err := syncGatewayFromClient(t, lbc, key); err == nil {
+		t.Errorf("lbc.syncGateway(%v) = nil, want conflict error", key)
+	}
+	lbc.ctx.IngressInformer.GetIndexer().Delete(conflicting)
+
+	u, _ = lbc.ctx.GatewayClient.Resource(gateway.GatewayGVR).Namespace("default").Get(context2.TODO(), "my-gateway", meta_v1.GetOptions{})
+	now := meta_v1.Now()
+	u.SetDeletionTimestamp(&now)
+	lbc.ctx.GatewayInformer.GetIndexer().Update(u)
+	if err := lbc.syncGateway(key.String()); err != nil {
+		t.Fatalf("lbc.syncGateway(%v) = %v, want nil", key, err)
+	}
+	u, _ = lbc.ctx.GatewayClient.Resource(gateway.GatewayGVR).Namespace("default").Get(context2.TODO(), "my-gateway", meta_v1.GetOptions{})
+	if finalizers := u.GetFinalizers(); len(finalizers) != 0 {
+		t.Errorf("Finalizers = %v, want none", finalizers)
+	}
+	if urlMaps, err := lbc.ctx.Cloud.ListURLMaps(); err != nil || len(urlMaps) != 0 {
+		t.Errorf("ListURLMaps() = %v, %v, want no URL maps", urlMaps, err)
+	}
+}
@@ -0,0 +1,324 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	apiv1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/networking/v1"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/common/operator"
+	"k8s.io/ingress-gce/pkg/context"
+	"k8s.io/ingress-gce/pkg/dryrun"
+	"k8s.io/ingress-gce/pkg/events"
+	"k8s.io/ingress-gce/pkg/flags"
+	"k8s.io/ingress-gce/pkg/ingressgroup"
+	"k8s.io/ingress-gce/pkg/loadbalancers"
+	"k8s.io/ingress-gce/pkg/loadbalancers/features"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/common"
+	"k8s.io/klog/v2"
+)
+
+// ingressGroup returns the group of an Ingress, or "" if ingress groups are
+// disabled or the Ingress is not a member of a group. An error is returned if
+// the name of the group is invalid, or if the IngressClass of the Ingress does
+// not allow it to join the group. Ingresses being deleted can always leave
+// their group.
+func (lbc *LoadBalancerController) ingressGroup(ing *v1.Ingress) (string, error) {
+	if !flags.F.EnableIngressGroups || ing == nil || utils.IsGCEMultiClusterIngress(ing) {
+		return "", nil
+	}
+	group, ok := annotations.FromIngress(ing).IngressGroup()
+	if !ok {
+		return "", nil
+	}
+	if err := ingressgroup.Validate(group); err != nil {
+		return "", err
+	}
+	if !utils.NeedsCleanup(ing) && !ingressgroup.Allowed(lbc.ingressClass(ingressgroup.ClassName(ing)), group) {
+		return "", fmt.Errorf("ingress group %q is not allowed by the %s annotation of IngressClass %q", group, ingressgroup.AllowedGroupsKey, ingressgroup.ClassName(ing))
+	}
+	return group, nil
+}
+
+// ingressClass returns the IngressClass with the given name, or nil if it
+// does not exist.
+func (lbc *LoadBalancerController) ingressClass(name string) *v1.IngressClass {
+	if lbc.ingClassLister == nil {
+		return nil
+	}
+	obj, exists, err := lbc.ingClassLister.GetByKey(name)
+	if err != nil || !exists {
+		return nil
+	}
+	return obj.(*v1.IngressClass)
+}
+
+// ingressGroupMembers returns the members of the group, sorted by
+// ingressgroup.Members, the conflicts of the Ingresses which cannot join the
+// group, and the former members whose rules must be removed from the load
+// balancer of the group before their finalizer is removed.
+func (lbc *LoadBalancerController) ingressGroupMembers(group string) ([]*v1.Ingress, []ingressgroup.Conflict, []*v1.Ingress) {
+	var ings []*v1.Ingress
+	for _, ing := range lbc.ctx.Ingresses().List() {
+		if g, _ := lbc.ingressGroup(ing); g != group {
+			continue
+		}
+		if utils.IsGCEIngress(ing) || common.HasFinalizer(ing.ObjectMeta) {
+			ings = append(ings, ing)
+		}
+	}
+	leaving, active := operator.Ingresses(ings).Partition(utils.NeedsCleanup)
+	members, conflicts := ingressgroup.Members(active.AsList())
+	return members, conflicts, leaving.AsList()
+}
+
+// syncIngressGroup syncs the load balancer shared by the members of an
+// ingress group. Members are enqueued by name of their group when synced.
+func (lbc *LoadBalancerController) syncIngressGroup(group string) error {
+	syncTrackingId := rand.Int31()
+	groupLogger := lbc.logger.WithValues("ingressGroup", group, "syncId", syncTrackingId)
+	if !lbc.hasSynced() {
+		time.Sleep(context.StoreSyncPollPeriod)
+		return fmt.Errorf("waiting for stores to sync")
+	}
+	groupLogger.Info("Syncing ingress group")
+
+	members, conflicts, leaving := lbc.ingressGroupMembers(group)
+	if len(members) == 0 && len(leaving) == 0 {
+		groupLogger.Info("Ingress group has no members, skipping sync")
+		return nil
+	}
+	// The load balancer of a group is paused with any of its members, which
+	// report it when synced.
+	for _, member := range append(append([]*v1.Ingress(nil), members...), leaving...) {
+		if annotations.FromIngress(member).ReconciliationPaused() {
+			groupLogger.Info("Reconciliation of a member is paused, skipping sync", "ingress", common.NamespacedName(member))
+			return nil
+		}
+	}
+	if flags.F.DryRun {
+		return lbc.planIngressGroup(group, members, leaving, groupLogger)
+	}
+	if len(members) == 0 {
+		// The load balancer is deleted with the last members.
+		leaving, _ = ingressgroup.Members(leaving)
+		ing := ingressgroup.ToIngress(group, leaving)
+		return lbc.gcIngressGroup(ing, nil, leaving, utils.CleanupV2FrontendResources, features.ScopeFromIngress(ing), nil, groupLogger)
+	}
+
+	ing := ingressgroup.ToIngress(group, members)
+	// The older of the group and of an Ingress with the same name keeps the
+	// load balancer, see loadBalancerNameConflict.
+	if other, exists, err := lbc.ctx.Ingresses().GetByKey(ingressgroup.IngressKey(group)); err == nil && exists && !ing.CreationTimestamp.Before(&other.CreationTimestamp) {
+		msg := fmt.Errorf("ingress group %q conflicts with Ingress %s which uses the same load balancer name", group, ingressgroup.IngressKey(group))
+		for _, member := range members {
+			lbc.ctx.Recorder(member.Namespace).Eventf(member, apiv1.EventTypeWarning, events.SyncIngress, "Error: %v", msg)
+		}
+		return msg
+	}
+
+	if flags.F.FinalizerAdd {
+		for i := range members {
+			var err error
+			if members[i], err = lbc.ensureFinalizer(members[i], groupLogger); err != nil {
+				return err
+			}
+		}
+	}
+
+	urlMap, err := lbc.translateIngressGroup(group, members, conflicts, groupLogger)
+	if err != nil {
+		return err
+	}
+
+	syncState := &syncState{urlMap: urlMap, ing: ing}
+	syncErr := lbc.ingSyncer.Sync(syncState, groupLogger)
+	if syncErr != nil {
+		for _, member := range members {
+			lbc.ctx.Recorder(member.Namespace).Eventf(member, apiv1.EventTypeWarning, events.SyncIngress, "Error syncing to GCP: %v", syncErr.Error())
+		}
+	}
+
+	// Members which had their own load balancer before joining the group
+	// still record it in their status annotations.
+	var joined []*v1.Ingress
+	if syncErr == nil && syncState.l7 != nil {
+		for _, member := range members {
+			if name, ok := member.Annotations[annotations.UrlMapKey]; ok && name != syncState.l7.UrlMap().Name {
+				joined = append(joined, member)
+			}
+		}
+	}
+
+	scope := features.ScopeFromIngress(ing)
+	oldScope, err := lbc.l7Pool.FrontendScopeChangeGC(ing, groupLogger)
+	if err != nil {
+		return err
+	}
+	frontendGCAlgorithm := utils.NoCleanUpNeeded
+	if oldScope != nil {
+		scope = *oldScope
+		frontendGCAlgorithm = utils.CleanupV2FrontendResourcesScopeChange
+	}
+	return lbc.gcIngressGroup(ing, joined, leaving, frontendGCAlgorithm, scope, syncErr, groupLogger)
+}
+
+// translateIngressGroup returns the URL map of the group merged from the URL
+// maps of its members. The members which fail translation are left out of
+// the URL map, and the failure is reported as an event on them. The conflicts
+// between members are reported as events on the Ingresses whose rules are
+// ignored. An error is returned if no member can be translated.
+func (lbc *LoadBalancerController) translateIngressGroup(group string, members []*v1.Ingress, conflicts []ingressgroup.Conflict, groupLogger klog.Logger) (*utils.GCEURLMap, error) {
+	var translated []ingressgroup.Member
+	var errs []error
+	for _, member := range members {
+		urlMap, translateErrs, _ := lbc.Translator.TranslateIngress(member, lbc.ctx.DefaultBackendSvcPort.ID, lbc.ctx.ClusterNamer)
+		if translateErrs != nil {
+			msg := fmt.Errorf("invalid ingress spec: %v", utils.JoinErrs(translateErrs))
+			groupLogger.Info("Leaving the rules of ingress group member out of the load balancer", "ingressKey", common.NamespacedName(member), "err", msg)
+			lbc.ctx.Recorder(member.Namespace).Eventf(member, apiv1.EventTypeWarning, events.TranslateIngress, "Translation failed, rules left out of ingress group %q: %v", group, msg)
+			errs = append(errs, fmt.Errorf("%s: %w", common.NamespacedName(member), msg))
+			continue
+		}
+		translated = append(translated, ingressgroup.Member{Ingress: member, URLMap: urlMap})
+	}
+	if len(translated) == 0 {
+		return nil, utils.JoinErrs(errs)
+	}
+
+	urlMap, mergeConflicts := ingressgroup.MergeURLMaps(translated, groupLogger)
+	for _, c := range append(conflicts, mergeConflicts...) {
+		groupLogger.Info("Ignoring rule of ingress group member", "ingressKey", common.NamespacedName(c.Ingress), "conflict", c.String())
+		lbc.ctx.Recorder(c.Ingress.Namespace).Eventf(c.Ingress, apiv1.EventTypeWarning, ingressgroup.EventReason, "Rule ignored by ingress group %q: %v", group, c)
+	}
+	return urlMap, nil
+}
+
+// gcIngressGroup runs garbage collection for the Ingress synthesized for a
+// group. Once the load balancer of the group no longer uses their rules, the
+// load balancers the joined members had before joining the group are deleted,
+// and the finalizers of the leaving members are removed.
+func (lbc *LoadBalancerController) gcIngressGroup(ing *v1.Ingress, joined, leaving []*v1.Ingress, groupGCAlgorithm utils.FrontendGCAlgorithm, scope meta.KeyType, syncErr error, groupLogger klog.Logger) error {
+	lbc.gcLock.Lock()
+	defer lbc.gcLock.Unlock()
+
+	allIngresses := lbc.allIngresses()
+	var errs []error
+	if gcErr := lbc.ingSyncer.GC(allIngresses, ing, groupGCAlgorithm, scope, groupLogger); gcErr != nil {
+		errs = append(errs, gcErr)
+	} else if syncErr == nil {
+		for _, member := range joined {
+			groupLogger.Info("Deleting load balancer of ingress group member", "ingressKey", common.NamespacedName(member))
+			if err := lbc.ingSyncer.GC(allIngresses, member, utils.CleanupV2FrontendResourcesScopeChange, features.ScopeFromIngress(member), groupLogger); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		for _, member := range leaving {
+			memberLogger := groupLogger.WithValues("ingressKey", common.NamespacedName(member))
+			if err := lbc.ingSyncer.GC(allIngresses, member, frontendGCAlgorithm(true, false, member, memberLogger), features.ScopeFromIngress(member), memberLogger); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if errs == nil {
+		return syncErr
+	}
+
+	gcErr := utils.JoinErrs(errs)
+	for _, member := range append(append([]*v1.Ingress(nil), leaving...), joined...) {
+		lbc.ctx.Recorder(member.Namespace).Eventf(member, apiv1.EventTypeWarning, events.GarbageCollection, "Error during garbage collection: %v", gcErr)
+	}
+	if syncErr == nil {
+		return gcErr
+	}
+	return fmt.Errorf("error during sync %v, error during GC %v", syncErr, gcErr)
+}
+
+// groupRuntimeInfo returns the L7RuntimeInfo of the load balancer of a group,
+// merged from the L7RuntimeInfo of its members. The members whose runtime
+// info cannot be computed are left out, and the failure is reported as an
+// event on them. The settings of the load balancer are those of the oldest
+// member, so an error is returned if its runtime info cannot be computed.
+func (lbc *LoadBalancerController) groupRuntimeInfo(group string, ing *v1.Ingress, urlMap *utils.GCEURLMap, groupLogger klog.Logger) (*loadbalancers.L7RuntimeInfo, error) {
+	members, _, _ := lbc.ingressGroupMembers(group)
+	if len(members) == 0 {
+		return nil, fmt.Errorf("ingress group %q has no members", group)
+	}
+	var infos []*loadbalancers.L7RuntimeInfo
+	for i, member := range members {
+		info, err := lbc.toRuntimeInfo(member, urlMap, groupLogger)
+		if err != nil && i == 0 {
+			return nil, err
+		}
+		if err != nil {
+			groupLogger.Info("Leaving the settings of ingress group member out of the load balancer", "ingressKey", common.NamespacedName(member), "err", err)
+			lbc.ctx.Recorder(member.Namespace).Eventf(member, apiv1.EventTypeWarning, events.SyncIngress, "Error: %v, TLS certificates left out of ingress group %q", err, group)
+			continue
+		}
+		infos = append(infos, info)
+	}
+	return ingressgroup.MergeRuntimeInfo(ing, urlMap, infos), nil
+}
+
+// updateIngressGroupStatus records the load balancer of a group in the
+// status of its members.
+func (lbc *LoadBalancerController) updateIngressGroupStatus(l7 *loadbalancers.L7, group string, groupLogger klog.Logger) error {
+	members, _, _ := lbc.ingressGroupMembers(group)
+	var errs []error
+	for _, member := range members {
+		if err := lbc.updateIngressStatus(l7, member, groupLogger.WithValues("ingressKey", common.NamespacedName(member))); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if errs != nil {
+		return utils.JoinErrs(errs)
+	}
+	return nil
+}
+
+// planIngressGroup reports the changes a sync of the group would make to GCE
+// resources as events on its members, without making them.
+func (lbc *LoadBalancerController) planIngressGroup(group string, members, leaving []*v1.Ingress, groupLogger klog.Logger) error {
+	var plan *dryrun.Plan
+	var err error
+	if len(members) == 0 {
+		leaving, _ = ingressgroup.Members(leaving)
+		members = leaving
+		plan, err = lbc.l7Pool.PlanDelete(ingressgroup.ToIngress(group, leaving))
+	} else {
+		var urlMap *utils.GCEURLMap
+		if urlMap, err = lbc.translateIngressGroup(group, members, nil, groupLogger); err != nil {
+			return err
+		}
+		plan, err = lbc.planSync(ingressgroup.ToIngress(group, members), urlMap, groupLogger)
+	}
+	for _, member := range members {
+		recorder := lbc.ctx.Recorder(member.Namespace)
+		if err != nil {
+			recorder.Eventf(member, apiv1.EventTypeWarning, dryrun.EventReason, "Error planning GCE changes: %v", err)
+		} else {
+			dryrun.Report(recorder, member, plan)
+		}
+	}
+	return err
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	api_v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/flags"
+	"k8s.io/ingress-gce/pkg/ingressgroup"
+	"k8s.io/ingress-gce/pkg/test"
+)
+
+// addGroupMember creates an Ingress of the "shop" group forwarding the paths
+// of shop.com to a Service of its namespace.
+func addGroupMember(lbc *LoadBalancerController, namespace string, age time.Duration, paths ...string) *networkingv1.Ingress {
+	svc := test.NewService(types.NamespacedName{Name: "web", Namespace: namespace}, api_v1.ServiceSpec{
+		Type:  api_v1.ServiceTypeNodePort,
+		Ports: []api_v1.ServicePort{{Port: 80}},
+	})
+	addService(lbc, svc)
+
+	var httpPaths []networkingv1.HTTPIngressPath
+	for _, path := range paths {
+		httpPaths = append(httpPaths, networkingv1.HTTPIngressPath{
+			Path:    path,
+			Backend: backend("web", networkingv1.ServiceBackendPort{Number: 80}),
+		})
+	}
+	ing := test.NewIngress(types.NamespacedName{Name: "shop", Namespace: namespace}, networkingv1.IngressSpec{
+		Rules: []networkingv1.IngressRule{{
+			Host: "shop.com",
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{Paths: httpPaths},
+			},
+		}},
+	})
+	ing.Annotations = map[string]string{annotations.IngressGroupKey: "shop"}
+	ing.CreationTimestamp = meta_v1.NewTime(time.Now().Add(-age))
+	addIngress(lbc, ing)
+	return ing
+}
+
+// groupPaths returns the backend service of each path of the url maps.
+func groupPaths(t *testing.T, mockGCE *cloud.MockGCE) map[string]string {
+	t.Helper()
+	paths := map[string]string{}
+	for _, obj := range mockGCE.MockUrlMaps.Objects {
+		for _, pm := range obj.ToGA().PathMatchers {
+			for _, rule := range pm.PathRules {
+				for _, path := range rule.Paths {
+					paths[path] = rule.Service
+				}
+			}
+		}
+	}
+	return paths
+}
+
+// pathsMatch returns true if the paths forward to the backend services with
+// the wanted names.
+func pathsMatch(got, want map[string]string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for path, name := range want {
+		if !strings.HasSuffix(got[path], "/"+name) {
+			return false
+		}
+	}
+	return true
+}
+
+// TestIngressGroup asserts that the Ingresses of a group share one load
+// balancer, and that deleting a member only removes its rules.
+func TestIngressGroup(t *testing.T) {
+	flagSaver := test.NewFlagSaver()
+	flagSaver.Save(test.FinalizerAddFlag, &flags.F.FinalizerAdd)
+	defer flagSaver.Reset(test.FinalizerAddFlag, &flags.F.FinalizerAdd)
+	flagSaver.Save(test.FinalizerRemoveFlag, &flags.F.FinalizerRemove)
+	defer flagSaver.Reset(test.FinalizerRemoveFlag, &flags.F.FinalizerRemove)
+	flags.F.FinalizerAdd = true
+	flags.F.FinalizerRemove = true
+	flags.F.EnableIngressGroups = true
+	defer func() { flags.F.EnableIngressGroups = false }()
+	// The group queue needs a worker to accept the groups enqueued by syncs
+	// of their members.
+	numWorkers := flags.F.NumIngressWorkers
+	flags.F.NumIngressWorkers = 1
+	defer func() { flags.F.NumIngressWorkers = numWorkers }()
+
+	lbc := newLoadBalancerController()
+	mockGCE := lbc.ctx.Cloud.Compute().(*cloud.MockGCE)
+	mockGCE.MockUrlMaps.UpdateHook = mock.UpdateURLMapHook
+	class := &networkingv1.IngressClass{ObjectMeta: meta_v1.ObjectMeta{Name: "gce"}}
+	lbc.ctx.IngClassInformer.GetIndexer().Add(class)
+
+	teamA := addGroupMember(lbc, "team-a", time.Hour, "/cart")
+	teamB := addGroupMember(lbc, "team-b", time.Minute, "/cart", "/search")
+	// The group is not allowed by the IngressClass of its members yet.
+	if err := lbc.sync(getKey(teamA, t)); err == nil {
+		t.Errorf("lbc.sync(%v) = nil, want error for a group not allowed by the IngressClass", getKey(teamA, t))
+	}
+	class.Annotations = map[string]string{ingressgroup.AllowedGroupsKey: "other,shop"}
+	lbc.ctx.IngClassInformer.GetIndexer().Update(class)
+	for _, ing := range []*networkingv1.Ingress{teamA, teamB} {
+		if err := lbc.sync(getKey(ing, t)); err != nil {
+			t.Fatalf("lbc.sync(%v) = %v, want nil", getKey(ing, t), err)
+		}
+	}
+	teamABackend := lbc.ctx.ClusterNamer.IGBackend(int64(nodePortCounter - 2))
+	teamBBackend := lbc.ctx.ClusterNamer.IGBackend(int64(nodePortCounter - 1))
+	// A member whose spec does not translate is left out of the group.
+	teamC := addGroupMember(lbc, "team-c", 0, "/checkout")
+	teamC.Spec.Rules[0].HTTP.Paths[0].Backend = backend("missing", networkingv1.ServiceBackendPort{Number: 80})
+	lbc.ctx.IngressInformer.GetIndexer().Update(teamC)
+	if n := len(mockGCE.MockUrlMaps.Objects); n != 0 {
+		t.Fatalf("lbc.sync() of group members created %d url maps, want none", n)
+	}
+	if err := lbc.syncIngressGroup("shop"); err != nil {
+		t.Fatalf("lbc.syncIngressGroup(%q) = %v, want nil", "shop", err)
+	}
+
+	if n := len(mockGCE.MockUrlMaps.Objects); n != 1 {
+		t.Fatalf("got %d url maps, want 1", n)
+	}
+	// The older Ingress of team-a wins the conflict on /cart.
+	if got, want := groupPaths(t, mockGCE), map[string]string{"/cart": teamABackend, "/search": teamBBackend}; !pathsMatch(got, want) {
+		t.Errorf("url map paths = %v, want %v", got, want)
+	}
+	teamA, teamB = getUpdatedIngress(t, lbc, teamA), getUpdatedIngress(t, lbc, teamB)
+	// A plain Ingress created after the group cannot take the name of the
+	// Ingress synthesized for the group.
+	plain := test.NewIngress(types.NamespacedName{Namespace: meta_v1.NamespaceSystem, Name: "ingress-group-shop"}, networkingv1.IngressSpec{})
+	plain.CreationTimestamp = meta_v1.Now()
+	if err := lbc.loadBalancerNameConflict(plain); err == nil {
+		t.Errorf("lbc.loadBalancerNameConflict(%v) = nil, want conflict with the older group", getKey(plain, t))
+	}
+	plain.CreationTimestamp = meta_v1.NewTime(time.Now().Add(-2 * time.Hour))
+	if err := lbc.loadBalancerNameConflict(plain); err != nil {
+		t.Errorf("lbc.loadBalancerNameConflict(%v) = %v, want nil for an Ingress older than the group", getKey(plain, t), err)
+	}
+	lbc.ctx.IngressInformer.GetIndexer().Delete(teamC)
+	ipA, ipB := teamA.Status.LoadBalancer.Ingress, teamB.Status.LoadBalancer.Ingress
+	if len(ipA) != 1 || ipA[0].IP == "" || !reflect.DeepEqual(ipA, ipB) {
+		t.Errorf("load balancer status = %v and %v, want the same IP", ipA, ipB)
+	}
+
+	// Deleting team-b removes its rules and backend only.
+	setDeletionTimestamp(lbc, teamB)
+	if err := lbc.sync(getKey(teamB, t)); err != nil {
+		t.Fatalf("lbc.sync(%v) = %v, want nil", getKey(teamB, t), err)
+	}
+	if err := lbc.syncIngressGroup("shop"); err != nil {
+		t.Fatalf("lbc.syncIngressGroup(%q) = %v, want nil", "shop", err)
+	}
+	if got, want := groupPaths(t, mockGCE), map[string]string{"/cart": teamABackend}; !pathsMatch(got, want) {
+		t.Errorf("url map paths = %v, want %v", got, want)
+	}
+	if _, ok := mockGCE.MockBackendServices.Objects[*meta.GlobalKey(teamBBackend)]; ok {
+		t.Errorf("backend service %s of the deleted member was not deleted", teamBBackend)
+	}
+	if _, ok := mockGCE.MockBackendServices.Objects[*meta.GlobalKey(teamABackend)]; !ok {
+		t.Errorf("backend service %s of the remaining member was deleted", teamABackend)
+	}
+	if finalizers := getUpdatedIngress(t, lbc, teamB).Finalizers; len(finalizers) != 0 {
+		t.Errorf("finalizers of the deleted member = %v, want none", finalizers)
+	}
+
+	// Deleting the last member deletes the load balancer.
+	setDeletionTimestamp(lbc, teamA)
+	if err := lbc.syncIngressGroup("shop"); err != nil {
+		t.Fatalf("lbc.syncIngressGroup(%q) = %v, want nil", "shop", err)
+	}
+	if n := len(mockGCE.MockUrlMaps.Objects); n != 0 {
+		t.Errorf("got %d url maps after deleting all members, want none", n)
+	}
+	if finalizers := getUpdatedIngress(t, lbc, teamA).Finalizers; len(finalizers) != 0 {
+		t.Errorf("finalizers of the last member = %v, want none", finalizers)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package translator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/networking/v1"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/utils"
+	namer_util "k8s.io/ingress-gce/pkg/utils/namer"
+)
+
+const (
+	// minErrorResponseCode and maxErrorResponseCode bound the response codes
+	// matched by an error response rule.
+	minErrorResponseCode = 400
+	maxErrorResponseCode = 599
+	// minOverrideResponseCode and maxOverrideResponseCode bound the response
+	// code returned with the custom error content.
+	minOverrideResponseCode = 200
+	maxOverrideResponseCode = 599
+	// maxErrorResponsePathLength is the maximum length of the path of an error
+	// response rule.
+	maxErrorResponsePathLength = 1024
+)
+
+// translateErrorResponsePolicy adds the custom error response policy
+// configured on the Ingress to the urlMap. The policy is dropped if it is invalid.
+func (t *Translator) translateErrorResponsePolicy(ing *v1.Ingress, urlMap *utils.GCEURLMap, params *getServicePortParams, namer namer_util.BackendNamer) ([]error, bool) {
+	policy, err := annotations.FromIngress(ing).CustomErrorResponses()
+	if err != nil {
+		return []error{err}, false
+	}
+	if policy == nil {
+		return nil, false
+	}
+	if err := validateErrorResponsePolicy(*policy, params); err != nil {
+		return []error{err}, false
+	}
+
+	ret := &utils.ErrorResponsePolicy{BackendBucket: policy.BackendBucket}
+	for _, rule := range policy.Rules {
+		ret.Rules = append(ret.Rules, utils.ErrorResponseRule(rule))
+	}
+	var warning bool
+	if policy.Service != nil {
+		var errs []error
+		ret.Backend, errs, warning = t.servicePortForBackend(*policy.Service, ing.Namespace, params, namer)
+		if len(errs) > 0 {
+			return errs, warning
+		}
+	}
+	urlMap.ErrorResponsePolicy = ret
+	return nil, warning
+}
+
+// validateErrorResponsePolicy validates the fields of a custom error response
+// policy, and that they are supported by the load balancer of the Ingress.
+func validateErrorResponsePolicy(policy annotations.CustomErrorResponsePolicy, params *getServicePortParams) error {
+	if (policy.Service == nil) == (policy.BackendBucket == "") {
+		return fmt.Errorf("custom error responses must specify exactly one of service and backendBucket")
+	}
+	if policy.Service != nil && policy.Service.Name == "" {
+		return fmt.Errorf("custom error responses must specify the name of the service")
+	}
+	if policy.BackendBucket != "" && (params.isL7ILB || params.isL7XLBRegional) {
+		return fmt.Errorf("custom error responses from a backend bucket are only supported by the %q Ingress class", annotations.GceIngressClass)
+	}
+	if len(policy.Rules) == 0 {
+		return fmt.Errorf("custom error responses must specify at least one rule")
+	}
+
+	seen := map[string]bool{}
+	for _, rule := range policy.Rules {
+		if len(rule.MatchResponseCodes) == 0 {
+			return fmt.Errorf("custom error response rule for path %q must specify at least one response code", rule.Path)
+		}
+		for _, code := range rule.MatchResponseCodes {
+			if err := validateErrorResponseCode(code); err != nil {
+				return err
+			}
+			if seen[code] {
+				return fmt.Errorf("response code %q is matched by more than one custom error response rule", code)
+			}
+			seen[code] = true
+		}
+		if !strings.HasPrefix(rule.Path, "/") || len(rule.Path) > maxErrorResponsePathLength {
+			return fmt.Errorf("path %q of custom error response rule must start with a '/' and be at most %d characters", rule.Path, maxErrorResponsePathLength)
+		}
+		if len(rule.Path) > 1 && strings.HasSuffix(rule.Path, "/") {
+			return fmt.Errorf("path %q of custom error response rule must not end with a '/'", rule.Path)
+		}
+		if rule.OverrideResponseCode != 0 && (rule.OverrideResponseCode < minOverrideResponseCode || rule.OverrideResponseCode > maxOverrideResponseCode) {
+			return fmt.Errorf("overrideResponseCode of custom error response rule for path %q must be between %d and %d", rule.Path, minOverrideResponseCode, maxOverrideResponseCode)
+		}
+	}
+	return nil
+}
+
+// validateErrorResponseCode validates a response code matched by an error
+// response rule. Codes are either "4xx", "5xx" or a single error code.
+func validateErrorResponseCode(code string) error {
+	if code == "4xx" || code == "5xx" {
+		return nil
+	}
+	value, err := strconv.Atoi(code)
+	if err != nil || value < minErrorResponseCode || value > maxErrorResponseCode {
+		return fmt.Errorf("response code %q must be \"4xx\", \"5xx\" or between %d and %d", code, minErrorResponseCode, maxErrorResponseCode)
+	}
+	return nil
+}
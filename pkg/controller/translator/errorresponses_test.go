@@ -0,0 +1,222 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package translator
+
+import (
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/test"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+func TestTranslateIngressWithCustomErrorResponses(t *testing.T) {
+	translator := fakeTranslator()
+	svcLister := translator.ServiceInformer.GetIndexer()
+	for _, name := range []string{"first-service", "error-service"} {
+		svc := test.NewService(types.NamespacedName{Name: name, Namespace: "default"}, apiv1.ServiceSpec{
+			Type:  apiv1.ServiceTypeNodePort,
+			Ports: []apiv1.ServicePort{{Port: 80}},
+		})
+		svcLister.Add(svc)
+	}
+
+	firstSvcPort := utils.ServicePort{ID: utils.ServicePortID{Service: types.NamespacedName{Name: "first-service", Namespace: "default"}, Port: port80}}
+	errorSvcPort := utils.ServicePort{ID: utils.ServicePortID{Service: types.NamespacedName{Name: "error-service", Namespace: "default"}, Port: port80}}
+	rules := []utils.ErrorResponseRule{{MatchResponseCodes: []string{"5xx"}, Path: "/500.html"}}
+
+	for _, tc := range []struct {
+		desc                    string
+		ingressClass            string
+		errorResponses          string
+		wantErrCount            int
+		wantErrorResponsePolicy *utils.ErrorResponsePolicy
+	}{
+		{
+			desc:                    "service",
+			errorResponses:          `{"service":{"name":"error-service","port":{"number":80}},"rules":[{"matchResponseCodes":["5xx"],"path":"/500.html"}]}`,
+			wantErrorResponsePolicy: &utils.ErrorResponsePolicy{Backend: &errorSvcPort, Rules: rules},
+		},
+		{
+			desc:                    "backend bucket",
+			errorResponses:          `{"backendBucket":"error-pages","rules":[{"matchResponseCodes":["5xx"],"path":"/500.html"}]}`,
+			wantErrorResponsePolicy: &utils.ErrorResponsePolicy{BackendBucket: "error-pages", Rules: rules},
+		},
+		{
+			desc:           "backend bucket on internal class",
+			ingressClass:   annotations.GceL7ILBIngressClass,
+			errorResponses: `{"backendBucket":"error-pages","rules":[{"matchResponseCodes":["5xx"],"path":"/500.html"}]}`,
+			wantErrCount:   1,
+		},
+		{
+			desc:           "missing service",
+			errorResponses: `{"service":{"name":"missing-service","port":{"number":80}},"rules":[{"matchResponseCodes":["5xx"],"path":"/500.html"}]}`,
+			wantErrCount:   1,
+		},
+		{
+			desc:           "invalid json",
+			errorResponses: `{"rules":`,
+			wantErrCount:   1,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ing := test.NewIngress(types.NamespacedName{Name: "my-ingress", Namespace: "default"}, v1.IngressSpec{
+				DefaultBackend: test.Backend("first-service", port80),
+			})
+			ing.Annotations = map[string]string{annotations.CustomErrorResponsesKey: tc.errorResponses}
+			if tc.ingressClass != "" {
+				ing.Annotations[annotations.IngressClassKey] = tc.ingressClass
+			}
+
+			gotGCEURLMap, gotErrs, _ := translator.TranslateIngress(ing, defaultBackend.ID, defaultNamer)
+			if len(gotErrs) != tc.wantErrCount {
+				t.Errorf("TranslateIngress() = _, %+v, want %v errs", gotErrs, tc.wantErrCount)
+			}
+			wantGCEURLMap := utils.NewGCEURLMap(klog.TODO())
+			wantGCEURLMap.DefaultBackend = &firstSvcPort
+			wantGCEURLMap.ErrorResponsePolicy = tc.wantErrorResponsePolicy
+			if !utils.EqualMapping(gotGCEURLMap, wantGCEURLMap) {
+				t.Errorf("TranslateIngress() = %+v, want %+v", gotGCEURLMap.ErrorResponsePolicy, wantGCEURLMap.ErrorResponsePolicy)
+			}
+		})
+	}
+}
+
+func TestValidateErrorResponsePolicy(t *testing.T) {
+	errorService := &v1.IngressServiceBackend{Name: "errors", Port: port80}
+	rules := []annotations.ErrorResponseRule{
+		{MatchResponseCodes: []string{"4xx"}, Path: "/404.html", OverrideResponseCode: 404},
+		{MatchResponseCodes: []string{"500", "503"}, Path: "/500.html"},
+	}
+	regional := &getServicePortParams{isL7XLBRegional: true}
+	classic := &getServicePortParams{}
+
+	for _, tc := range []struct {
+		desc    string
+		policy  annotations.CustomErrorResponsePolicy
+		params  *getServicePortParams
+		wantErr bool
+	}{
+		{
+			desc:   "service on classic external class",
+			policy: annotations.CustomErrorResponsePolicy{Service: errorService, Rules: rules},
+			params: classic,
+		},
+		{
+			desc:   "service on regional external class",
+			policy: annotations.CustomErrorResponsePolicy{Service: errorService, Rules: rules},
+			params: regional,
+		},
+		{
+			desc:   "backend bucket on classic external class",
+			policy: annotations.CustomErrorResponsePolicy{BackendBucket: "error-pages", Rules: rules},
+			params: classic,
+		},
+		{
+			desc:    "backend bucket on regional external class",
+			policy:  annotations.CustomErrorResponsePolicy{BackendBucket: "error-pages", Rules: rules},
+			params:  regional,
+			wantErr: true,
+		},
+		{
+			desc:    "service and backend bucket",
+			policy:  annotations.CustomErrorResponsePolicy{Service: errorService, BackendBucket: "error-pages", Rules: rules},
+			params:  classic,
+			wantErr: true,
+		},
+		{
+			desc:    "no service or backend bucket",
+			policy:  annotations.CustomErrorResponsePolicy{Rules: rules},
+			params:  classic,
+			wantErr: true,
+		},
+		{
+			desc:    "no rules",
+			policy:  annotations.CustomErrorResponsePolicy{Service: errorService},
+			params:  classic,
+			wantErr: true,
+		},
+		{
+			desc: "rule without response codes",
+			policy: annotations.CustomErrorResponsePolicy{Service: errorService, Rules: []annotations.ErrorResponseRule{
+				{Path: "/error.html"},
+			}},
+			params:  classic,
+			wantErr: true,
+		},
+		{
+			desc: "invalid response code",
+			policy: annotations.CustomErrorResponsePolicy{Service: errorService, Rules: []annotations.ErrorResponseRule{
+				{MatchResponseCodes: []string{"3xx"}, Path: "/error.html"},
+			}},
+			params:  classic,
+			wantErr: true,
+		},
+		{
+			desc: "response code out of range",
+			policy: annotations.CustomErrorResponsePolicy{Service: errorService, Rules: []annotations.ErrorResponseRule{
+				{MatchResponseCodes: []string{"302"}, Path: "/error.html"},
+			}},
+			params:  classic,
+			wantErr: true,
+		},
+		{
+			desc: "duplicate response code",
+			policy: annotations.CustomErrorResponsePolicy{Service: errorService, Rules: []annotations.ErrorResponseRule{
+				{MatchResponseCodes: []string{"5xx"}, Path: "/a.html"},
+				{MatchResponseCodes: []string{"5xx"}, Path: "/b.html"},
+			}},
+			params:  classic,
+			wantErr: true,
+		},
+		{
+			desc: "relative path",
+			policy: annotations.CustomErrorResponsePolicy{Service: errorService, Rules: []annotations.ErrorResponseRule{
+				{MatchResponseCodes: []string{"5xx"}, Path: "error.html"},
+			}},
+			params:  classic,
+			wantErr: true,
+		},
+		{
+			desc: "path with trailing slash",
+			policy: annotations.CustomErrorResponsePolicy{Service: errorService, Rules: []annotations.ErrorResponseRule{
+				{MatchResponseCodes: []string{"5xx"}, Path: "/errors/"},
+			}},
+			params:  classic,
+			wantErr: true,
+		},
+		{
+			desc: "override response code out of range",
+			policy: annotations.CustomErrorResponsePolicy{Service: errorService, Rules: []annotations.ErrorResponseRule{
+				{MatchResponseCodes: []string{"5xx"}, Path: "/500.html", OverrideResponseCode: 600},
+			}},
+			params:  classic,
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := validateErrorResponsePolicy(tc.policy, tc.params)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("validateErrorResponsePolicy(%+v) = %v, wantErr = %v", tc.policy, err, tc.wantErr)
+			}
+		})
+	}
+}
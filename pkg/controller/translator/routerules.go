@@ -0,0 +1,496 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package translator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/networking/v1"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/utils"
+	namer_util "k8s.io/ingress-gce/pkg/utils/namer"
+)
+
+const (
+	// maxBackendWeight is the maximum weight of a weighted backend service.
+	maxBackendWeight = 1000
+	// maxPathPrefixRewriteLength is the maximum length of urlRewrite.pathPrefixRewrite.
+	maxPathPrefixRewriteLength = 1024
+	// maxHostRewriteLength is the maximum length of urlRewrite.hostRewrite.
+	maxHostRewriteLength = 255
+	// minFaultAbortStatus and maxFaultAbortStatus bound the HTTP status of an
+	// aborted request.
+	minFaultAbortStatus = 200
+	maxFaultAbortStatus = 599
+)
+
+// retryConditions are the retry conditions supported by retryPolicy.
+var retryConditions = map[string]bool{
+	"5xx":                true,
+	"gateway-error":      true,
+	"connect-failure":    true,
+	"retriable-4xx":      true,
+	"refused-stream":     true,
+	"cancelled":          true,
+	"deadline-exceeded":  true,
+	"internal":           true,
+	"resource-exhausted": true,
+	"unavailable":        true,
+}
+
+// translateRouteRules adds the route rules configured on the Ingress to the urlMap.
+// Invalid rules are skipped and reported through the returned errors.
+func (t *Translator) translateRouteRules(ing *v1.Ingress, urlMap *utils.GCEURLMap, params *getServicePortParams, namer namer_util.BackendNamer) ([]error, bool) {
+	var errs []error
+	var warnings bool
+
+	rules, err := annotations.FromIngress(ing).RouteRules()
+	if err != nil {
+		return []error{err}, false
+	}
+
+	var hosts []string
+	hostRouteRules := map[string][]utils.RouteRule{}
+	for _, rule := range rules {
+		host := rule.Host
+		if host == "" {
+			host = DefaultHost
+		}
+		if err := validateRouteRule(rule, hostRouteRules[host]); err != nil {
+			errs = append(errs, fmt.Errorf("invalid route rule for host %q with priority %d: %w", host, rule.Priority, err))
+			continue
+		}
+
+		routeRule := utils.RouteRule{
+			Priority: rule.Priority,
+			Matches:  toRouteMatches(rule.Matches),
+		}
+		if rule.Service != nil {
+			svcPort, svcErrs, warning := t.servicePortForBackend(*rule.Service, ing.Namespace, params, namer)
+			warnings = warnings || warning
+			if len(svcErrs) > 0 {
+				errs = append(errs, svcErrs...)
+				continue
+			}
+			routeRule.Backend = *svcPort
+		}
+		if rule.Action != nil {
+			action, actionErrs, warning := t.translateRouteAction(*rule.Action, ing.Namespace, params, namer)
+			warnings = warnings || warning
+			if len(actionErrs) > 0 {
+				errs = append(errs, actionErrs...)
+				continue
+			}
+			routeRule.Action = action
+		}
+
+		if _, ok := hostRouteRules[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		hostRouteRules[host] = append(hostRouteRules[host], routeRule)
+	}
+
+	for _, host := range hosts {
+		if err := validateRouteRulePriorities(hostRouteRules[host], hostPathCount(urlMap, host)); err != nil {
+			errs = append(errs, fmt.Errorf("invalid route rules for host %q: %w", host, err))
+			continue
+		}
+		urlMap.PutRouteRulesForHost(host, hostRouteRules[host])
+	}
+	return errs, warnings
+}
+
+// hostPathCount returns the number of path rules of the host in the urlMap.
+func hostPathCount(urlMap *utils.GCEURLMap, host string) int {
+	for _, hostRule := range urlMap.HostRules {
+		if hostRule.Hostname == host {
+			return len(hostRule.Paths)
+		}
+	}
+	return 0
+}
+
+// validateRouteRulePriorities returns an error if the path rules of a host
+// cannot follow its route rules. Path rules are turned into route rules with
+// the priorities following the highest one of the route rules, which must
+// not exceed the maximum priority of GCE.
+func validateRouteRulePriorities(rules []utils.RouteRule, pathCount int) error {
+	var highest int64
+	for _, rule := range rules {
+		if rule.Priority > highest {
+			highest = rule.Priority
+		}
+	}
+	if maxPriority := int64(math.MaxInt32 - pathCount); highest > maxPriority {
+		return fmt.Errorf("priority %d leaves no room for the %d paths of the host, priorities must be at most %d", highest, pathCount, maxPriority)
+	}
+	return nil
+}
+
+// pathActions returns the path actions configured on the Ingress, keyed by
+// pathActionKey.
+func pathActions(ing *v1.Ingress) (map[string]annotations.PathAction, error) {
+	actions, err := annotations.FromIngress(ing).PathActions()
+	if err != nil {
+		return nil, err
+	}
+	ret := map[string]annotations.PathAction{}
+	for _, action := range actions {
+		key := pathActionKey(action.Host, action.Path)
+		if _, ok := ret[key]; ok {
+			return nil, fmt.Errorf("path action for host %q and path %q is specified more than once", action.Host, action.Path)
+		}
+		ret[key] = action
+	}
+	return ret, nil
+}
+
+// translateHostActions adds the path actions without a path to the hosts of
+// the urlMap. Such actions apply to all requests of the host, so only request
+// mirroring is supported.
+func (t *Translator) translateHostActions(actions map[string]annotations.PathAction, namespace string, urlMap *utils.GCEURLMap, params *getServicePortParams, namer namer_util.BackendNamer) ([]error, bool) {
+	var errs []error
+	var warnings bool
+
+	var keys []string
+	for key, action := range actions {
+		if action.Path == "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		hostAction := actions[key]
+		host := hostAction.Host
+		if host == "" {
+			host = DefaultHost
+		}
+		if a := hostAction.Action; len(a.WeightedBackends) > 0 || a.UrlRewrite != nil || a.RetryPolicy != nil || a.FaultInjectionPolicy != nil {
+			errs = append(errs, fmt.Errorf("path action for host %q without a path only supports requestMirrorPolicy", hostAction.Host))
+			continue
+		}
+		if !urlMap.HostExists(host) {
+			errs = append(errs, fmt.Errorf("path action for host %q does not match any host of the Ingress", hostAction.Host))
+			continue
+		}
+		action, actionErrs, warning := t.translateRouteAction(hostAction.Action, namespace, params, namer)
+		warnings = warnings || warning
+		if len(actionErrs) > 0 {
+			errs = append(errs, actionErrs...)
+			continue
+		}
+		urlMap.PutActionForHost(host, action)
+	}
+	return errs, warnings
+}
+
+// pathActionKey returns the key identifying a path of an Ingress rule.
+func pathActionKey(host, path string) string {
+	return host + path
+}
+
+// servicePortForBackend resolves a Service backend referenced from an annotation.
+func (t *Translator) servicePortForBackend(be v1.IngressServiceBackend, namespace string, params *getServicePortParams, namer namer_util.BackendNamer) (*utils.ServicePort, []error, bool) {
+	svcPortID, err := utils.BackendToServicePortID(v1.IngressBackend{Service: &be}, namespace)
+	if err != nil {
+		return nil, []error{err}, false
+	}
+	svcPort, err, warning := t.getServicePort(svcPortID, params, namer)
+	if err != nil {
+		return nil, []error{err}, warning
+	}
+	return svcPort, nil, warning
+}
+
+// translateRouteAction validates the given route action and resolves the
+// Services it references.
+func (t *Translator) translateRouteAction(action annotations.RouteAction, namespace string, params *getServicePortParams, namer namer_util.BackendNamer) (*utils.RouteAction, []error, bool) {
+	if err := validateRouteAction(action, params); err != nil {
+		return nil, []error{err}, false
+	}
+
+	var errs []error
+	var warnings bool
+	ret := &utils.RouteAction{
+		UrlRewrite:           (*utils.UrlRewrite)(action.UrlRewrite),
+		RetryPolicy:          (*utils.RetryPolicy)(action.RetryPolicy),
+		FaultInjectionPolicy: toFaultInjectionPolicy(action.FaultInjectionPolicy),
+	}
+	for _, wb := range action.WeightedBackends {
+		svcPort, svcErrs, warning := t.servicePortForBackend(wb.Service, namespace, params, namer)
+		warnings = warnings || warning
+		if len(svcErrs) > 0 {
+			errs = append(errs, svcErrs...)
+			continue
+		}
+		ret.WeightedBackends = append(ret.WeightedBackends, utils.WeightedBackend{Backend: *svcPort, Weight: wb.Weight})
+	}
+	if action.RequestMirrorPolicy != nil {
+		svcPort, svcErrs, warning := t.servicePortForBackend(action.RequestMirrorPolicy.Service, namespace, params, namer)
+		warnings = warnings || warning
+		if len(svcErrs) > 0 {
+			errs = append(errs, svcErrs...)
+		} else {
+			ret.MirrorBackend = svcPort
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs, warnings
+	}
+	return ret, nil, warnings
+}
+
+// toRouteMatches converts the matches of a route rule annotation to the
+// matches of the urlMap.
+func toRouteMatches(matches []annotations.RouteMatch) []utils.RouteMatch {
+	var ret []utils.RouteMatch
+	for _, m := range matches {
+		match := utils.RouteMatch{
+			PrefixMatch:   m.PrefixMatch,
+			FullPathMatch: m.FullPathMatch,
+			IgnoreCase:    m.IgnoreCase,
+		}
+		for _, hm := range m.HeaderMatches {
+			match.HeaderMatches = append(match.HeaderMatches, utils.HeaderMatch(hm))
+		}
+		for _, qm := range m.QueryParameterMatches {
+			match.QueryParameterMatches = append(match.QueryParameterMatches, utils.QueryParameterMatch(qm))
+		}
+		ret = append(ret, match)
+	}
+	return ret
+}
+
+// toFaultInjectionPolicy converts the fault injection policy of a route
+// action annotation to the policy of the urlMap.
+func toFaultInjectionPolicy(policy *annotations.FaultInjectionPolicy) *utils.FaultInjectionPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &utils.FaultInjectionPolicy{
+		Delay: (*utils.FaultDelay)(policy.Delay),
+		Abort: (*utils.FaultAbort)(policy.Abort),
+	}
+}
+
+// validateRouteAction validates the fields of a route action, and that they
+// are supported by the load balancer of the Ingress.
+func validateRouteAction(action annotations.RouteAction, params *getServicePortParams) error {
+	var totalWeight int64
+	for _, wb := range action.WeightedBackends {
+		if wb.Weight < 0 || wb.Weight > maxBackendWeight {
+			return fmt.Errorf("weight of service %q must be between 0 and %d", wb.Service.Name, maxBackendWeight)
+		}
+		totalWeight += wb.Weight
+	}
+	if len(action.WeightedBackends) > 0 && totalWeight == 0 {
+		return fmt.Errorf("at least one weighted backend must have a weight greater than 0")
+	}
+	if action.UrlRewrite != nil {
+		if err := validateUrlRewrite(*action.UrlRewrite); err != nil {
+			return err
+		}
+	}
+	if action.RequestMirrorPolicy != nil && action.RequestMirrorPolicy.Service.Name == "" {
+		return fmt.Errorf("requestMirrorPolicy must specify a service")
+	}
+	// The classic external load balancer does not support retry and fault
+	// injection policies.
+	isClassicXLB := !params.isL7ILB && !params.isL7XLBRegional
+	if action.RetryPolicy != nil {
+		if isClassicXLB {
+			return fmt.Errorf("retryPolicy is not supported by the %q ingress class", annotations.GceIngressClass)
+		}
+		if err := validateRetryPolicy(*action.RetryPolicy); err != nil {
+			return err
+		}
+	}
+	if action.FaultInjectionPolicy != nil {
+		if isClassicXLB {
+			return fmt.Errorf("faultInjectionPolicy is not supported by the %q ingress class", annotations.GceIngressClass)
+		}
+		if err := validateFaultInjectionPolicy(*action.FaultInjectionPolicy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRetryPolicy validates the fields of a retry policy.
+func validateRetryPolicy(policy annotations.RetryPolicy) error {
+	if len(policy.RetryConditions) == 0 {
+		return fmt.Errorf("retryPolicy must specify at least one retry condition")
+	}
+	for _, cond := range policy.RetryConditions {
+		if !retryConditions[cond] {
+			return fmt.Errorf("retry condition %q is not supported", cond)
+		}
+	}
+	// A numRetries of 0 is unset, and GCE retries once.
+	if policy.NumRetries < 0 {
+		return fmt.Errorf("numRetries must not be negative")
+	}
+	if policy.PerTryTimeout != "" {
+		if err := validatePositiveDuration("perTryTimeout", policy.PerTryTimeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateFaultInjectionPolicy validates the fields of a fault injection policy.
+func validateFaultInjectionPolicy(policy annotations.FaultInjectionPolicy) error {
+	if policy.Delay == nil && policy.Abort == nil {
+		return fmt.Errorf("faultInjectionPolicy must specify at least one of delay and abort")
+	}
+	if delay := policy.Delay; delay != nil {
+		if err := validatePositiveDuration("fixedDelay", delay.FixedDelay); err != nil {
+			return err
+		}
+		if delay.Percentage < 0 || delay.Percentage > 100 {
+			return fmt.Errorf("delay percentage must be between 0 and 100")
+		}
+	}
+	if abort := policy.Abort; abort != nil {
+		if abort.HttpStatus < minFaultAbortStatus || abort.HttpStatus > maxFaultAbortStatus {
+			return fmt.Errorf("abort httpStatus must be between %d and %d", minFaultAbortStatus, maxFaultAbortStatus)
+		}
+		if abort.Percentage < 0 || abort.Percentage > 100 {
+			return fmt.Errorf("abort percentage must be between 0 and 100")
+		}
+	}
+	return nil
+}
+
+// validatePositiveDuration validates that the value of the named field is a
+// duration greater than 0.
+func validatePositiveDuration(field, value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%s %q is not a valid duration: %w", field, value, err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("%s must be greater than 0", field)
+	}
+	return nil
+}
+
+// validateUrlRewrite validates the fields of a url rewrite.
+func validateUrlRewrite(rewrite annotations.UrlRewrite) error {
+	if rewrite.PathPrefixRewrite == "" && rewrite.HostRewrite == "" {
+		return fmt.Errorf("urlRewrite must specify at least one of pathPrefixRewrite and hostRewrite")
+	}
+	if rewrite.PathPrefixRewrite != "" {
+		if !strings.HasPrefix(rewrite.PathPrefixRewrite, "/") {
+			return fmt.Errorf("pathPrefixRewrite %q must begin with '/'", rewrite.PathPrefixRewrite)
+		}
+		if len(rewrite.PathPrefixRewrite) > maxPathPrefixRewriteLength {
+			return fmt.Errorf("pathPrefixRewrite must be at most %d characters", maxPathPrefixRewriteLength)
+		}
+	}
+	if len(rewrite.HostRewrite) > maxHostRewriteLength {
+		return fmt.Errorf("hostRewrite must be at most %d characters", maxHostRewriteLength)
+	}
+	if strings.ContainsAny(rewrite.HostRewrite, "/ ") {
+		return fmt.Errorf("hostRewrite %q must be a valid host", rewrite.HostRewrite)
+	}
+	return nil
+}
+
+// validateRouteRule validates a single route rule against the rules already
+// accepted for the same host.
+func validateRouteRule(rule annotations.RouteRule, existing []utils.RouteRule) error {
+	hasWeightedBackends := rule.Action != nil && len(rule.Action.WeightedBackends) > 0
+	if rule.Service == nil && !hasWeightedBackends {
+		return fmt.Errorf("one of service and action.weightedBackends must be specified")
+	}
+	if rule.Service != nil && hasWeightedBackends {
+		return fmt.Errorf("service and action.weightedBackends cannot both be specified")
+	}
+	if rule.Priority < 0 || rule.Priority >= math.MaxInt32 {
+		return fmt.Errorf("priority must be between 0 and %d", math.MaxInt32-1)
+	}
+	for _, r := range existing {
+		if r.Priority == rule.Priority {
+			return fmt.Errorf("priority is used by another route rule")
+		}
+	}
+	if len(rule.Matches) == 0 {
+		return fmt.Errorf("at least one match must be specified")
+	}
+	for _, match := range rule.Matches {
+		if err := validateRouteMatch(match); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRouteMatch validates that the path, header and query parameter
+// matches of a route match are well formed.
+func validateRouteMatch(match annotations.RouteMatch) error {
+	if match.PrefixMatch != "" && match.FullPathMatch != "" {
+		return fmt.Errorf("only one of prefixMatch and fullPathMatch can be specified")
+	}
+	for _, path := range []string{match.PrefixMatch, match.FullPathMatch} {
+		if path != "" && !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("path %q must begin with '/'", path)
+		}
+	}
+
+	for _, hm := range match.HeaderMatches {
+		if hm.HeaderName == "" {
+			return fmt.Errorf("headerName must be specified for header matches")
+		}
+		count := countNonEmpty(hm.ExactMatch, hm.PrefixMatch, hm.SuffixMatch, hm.RegexMatch)
+		if hm.PresentMatch {
+			count++
+		}
+		if count != 1 {
+			return fmt.Errorf("header match on %q must specify exactly one of exactMatch, prefixMatch, suffixMatch, regexMatch and presentMatch", hm.HeaderName)
+		}
+	}
+
+	for _, qm := range match.QueryParameterMatches {
+		if qm.Name == "" {
+			return fmt.Errorf("name must be specified for query parameter matches")
+		}
+		count := countNonEmpty(qm.ExactMatch, qm.RegexMatch)
+		if qm.PresentMatch {
+			count++
+		}
+		if count != 1 {
+			return fmt.Errorf("query parameter match on %q must specify exactly one of exactMatch, regexMatch and presentMatch", qm.Name)
+		}
+	}
+	return nil
+}
+
+// countNonEmpty returns the number of non empty strings.
+func countNonEmpty(values ...string) int {
+	count := 0
+	for _, v := range values {
+		if v != "" {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,631 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package translator
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/test"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+func TestTranslateIngressWithRouteRules(t *testing.T) {
+	translator := fakeTranslator()
+	svcLister := translator.ServiceInformer.GetIndexer()
+	for _, name := range []string{"first-service", "canary-service"} {
+		svc := test.NewService(types.NamespacedName{Name: name, Namespace: "default"}, apiv1.ServiceSpec{
+			Type:  apiv1.ServiceTypeNodePort,
+			Ports: []apiv1.ServicePort{{Port: 80}},
+		})
+		svcLister.Add(svc)
+	}
+
+	firstSvcPort := utils.ServicePort{ID: utils.ServicePortID{Service: types.NamespacedName{Name: "first-service", Namespace: "default"}, Port: port80}}
+	canarySvcPort := utils.ServicePort{ID: utils.ServicePortID{Service: types.NamespacedName{Name: "canary-service", Namespace: "default"}, Port: port80}}
+
+	spec := v1.IngressSpec{
+		DefaultBackend: test.Backend("first-service", port80),
+		Rules: []v1.IngressRule{
+			{
+				Host: "foo.com",
+				IngressRuleValue: v1.IngressRuleValue{
+					HTTP: &v1.HTTPIngressRuleValue{
+						Paths: []v1.HTTPIngressPath{{Path: "/*", Backend: *test.Backend("first-service", port80)}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		desc          string
+		routeRules    string
+		wantErrCount  int
+		wantGCEURLMap func() *utils.GCEURLMap
+	}{
+		{
+			desc:       "header match on existing host",
+			routeRules: `[{"host":"foo.com","priority":1,"matches":[{"headerMatches":[{"headerName":"x-canary","exactMatch":"true"}]}],"service":{"name":"canary-service","port":{"number":80}}}]`,
+			wantGCEURLMap: func() *utils.GCEURLMap {
+				m := utils.NewGCEURLMap(klog.TODO())
+				m.DefaultBackend = &firstSvcPort
+				m.PutPathRulesForHost("foo.com", []utils.PathRule{{Path: "/*", Backend: firstSvcPort}})
+				m.PutRouteRulesForHost("foo.com", []utils.RouteRule{{
+					Priority: 1,
+					Matches:  []utils.RouteMatch{{HeaderMatches: []utils.HeaderMatch{{HeaderName: "x-canary", ExactMatch: "true"}}}},
+					Backend:  canarySvcPort,
+				}})
+				return m
+			},
+		},
+		{
+			desc:       "query parameter match on new host",
+			routeRules: `[{"host":"bar.com","priority":1,"matches":[{"queryParameterMatches":[{"name":"tenant","exactMatch":"a"}]}],"service":{"name":"canary-service","port":{"number":80}}}]`,
+			wantGCEURLMap: func() *utils.GCEURLMap {
+				m := utils.NewGCEURLMap(klog.TODO())
+				m.DefaultBackend = &firstSvcPort
+				m.PutPathRulesForHost("foo.com", []utils.PathRule{{Path: "/*", Backend: firstSvcPort}})
+				m.PutRouteRulesForHost("bar.com", []utils.RouteRule{{
+					Priority: 1,
+					Matches:  []utils.RouteMatch{{QueryParameterMatches: []utils.QueryParameterMatch{{Name: "tenant", ExactMatch: "a"}}}},
+					Backend:  canarySvcPort,
+				}})
+				return m
+			},
+		},
+		{
+			desc:         "duplicate priority and missing service",
+			routeRules:   `[{"host":"foo.com","priority":1,"matches":[{"prefixMatch":"/a"}],"service":{"name":"canary-service","port":{"number":80}}},{"host":"foo.com","priority":1,"matches":[{"prefixMatch":"/b"}],"service":{"name":"canary-service","port":{"number":80}}},{"host":"foo.com","priority":2,"matches":[{"prefixMatch":"/c"}],"service":{"name":"missing-service","port":{"number":80}}}]`,
+			wantErrCount: 2,
+			wantGCEURLMap: func() *utils.GCEURLMap {
+				m := utils.NewGCEURLMap(klog.TODO())
+				m.DefaultBackend = &firstSvcPort
+				m.PutPathRulesForHost("foo.com", []utils.PathRule{{Path: "/*", Backend: firstSvcPort}})
+				m.PutRouteRulesForHost("foo.com", []utils.RouteRule{{Priority: 1, Matches: []utils.RouteMatch{{PrefixMatch: "/a"}}, Backend: canarySvcPort}})
+				return m
+			},
+		},
+		{
+			desc:       "highest priority leaving room for the paths of the host",
+			routeRules: `[{"host":"foo.com","priority":2147483646,"matches":[{"prefixMatch":"/a"}],"service":{"name":"canary-service","port":{"number":80}}}]`,
+			wantGCEURLMap: func() *utils.GCEURLMap {
+				m := utils.NewGCEURLMap(klog.TODO())
+				m.DefaultBackend = &firstSvcPort
+				m.PutPathRulesForHost("foo.com", []utils.PathRule{{Path: "/*", Backend: firstSvcPort}})
+				m.PutRouteRulesForHost("foo.com", []utils.RouteRule{{Priority: 2147483646, Matches: []utils.RouteMatch{{PrefixMatch: "/a"}}, Backend: canarySvcPort}})
+				return m
+			},
+		},
+		{
+			desc:         "invalid json",
+			routeRules:   `[{`,
+			wantErrCount: 1,
+			wantGCEURLMap: func() *utils.GCEURLMap {
+				m := utils.NewGCEURLMap(klog.TODO())
+				m.DefaultBackend = &firstSvcPort
+				m.PutPathRulesForHost("foo.com", []utils.PathRule{{Path: "/*", Backend: firstSvcPort}})
+				return m
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ing := test.NewIngress(types.NamespacedName{Name: "my-ingress", Namespace: "default"}, spec)
+			ing.Annotations = map[string]string{annotations.RouteRulesKey: tc.routeRules}
+
+			gotGCEURLMap, gotErrs, _ := translator.TranslateIngress(ing, defaultBackend.ID, defaultNamer)
+			if len(gotErrs) != tc.wantErrCount {
+				t.Errorf("TranslateIngress() = _, %+v, want %v errs", gotErrs, tc.wantErrCount)
+			}
+			wantGCEURLMap := tc.wantGCEURLMap()
+			if !utils.EqualMapping(gotGCEURLMap, wantGCEURLMap) {
+				t.Errorf("TranslateIngress() = %+v\nwant\n%+v", gotGCEURLMap.String(), wantGCEURLMap.String())
+			}
+		})
+	}
+}
+
+func TestTranslateIngressWithPathActions(t *testing.T) {
+	translator := fakeTranslator()
+	svcLister := translator.ServiceInformer.GetIndexer()
+	for _, name := range []string{"first-service", "canary-service"} {
+		svc := test.NewService(types.NamespacedName{Name: name, Namespace: "default"}, apiv1.ServiceSpec{
+			Type:  apiv1.ServiceTypeNodePort,
+			Ports: []apiv1.ServicePort{{Port: 80}},
+		})
+		svcLister.Add(svc)
+	}
+
+	firstSvcPort := utils.ServicePort{ID: utils.ServicePortID{Service: types.NamespacedName{Name: "first-service", Namespace: "default"}, Port: port80}}
+	canarySvcPort := utils.ServicePort{ID: utils.ServicePortID{Service: types.NamespacedName{Name: "canary-service", Namespace: "default"}, Port: port80}}
+	weighted := &utils.RouteAction{
+		WeightedBackends: []utils.WeightedBackend{{Backend: firstSvcPort, Weight: 90}, {Backend: canarySvcPort, Weight: 10}},
+	}
+
+	prefix := v1.PathTypePrefix
+	spec := v1.IngressSpec{
+		DefaultBackend: test.Backend("first-service", port80),
+		Rules: []v1.IngressRule{
+			{
+				Host: "foo.com",
+				IngressRuleValue: v1.IngressRuleValue{
+					HTTP: &v1.HTTPIngressRuleValue{
+						Paths: []v1.HTTPIngressPath{
+							{Path: "/app", PathType: &prefix, Backend: *test.Backend("first-service", port80)},
+							{Path: "/other", PathType: &prefix, Backend: *test.Backend("first-service", port80)},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		desc          string
+		pathActions   string
+		wantErrCount  int
+		wantGCEURLMap func() *utils.GCEURLMap
+	}{
+		{
+			desc:        "weighted backends on prefix path",
+			pathActions: `[{"host":"foo.com","path":"/app","action":{"weightedBackends":[{"service":{"name":"first-service","port":{"number":80}},"weight":90},{"service":{"name":"canary-service","port":{"number":80}},"weight":10}]}}]`,
+			wantGCEURLMap: func() *utils.GCEURLMap {
+				m := utils.NewGCEURLMap(klog.TODO())
+				m.DefaultBackend = &firstSvcPort
+				m.PutPathRulesForHost("foo.com", []utils.PathRule{
+					{Path: "/app", Backend: firstSvcPort, Action: weighted},
+					{Path: "/app/*", Backend: firstSvcPort, Action: weighted},
+					{Path: "/other", Backend: firstSvcPort},
+					{Path: "/other/*", Backend: firstSvcPort},
+				})
+				return m
+			},
+		},
+		{
+			desc:        "url rewrite on prefix path",
+			pathActions: `[{"host":"foo.com","path":"/app","action":{"urlRewrite":{"pathPrefixRewrite":"/","hostRewrite":"app.internal"}}}]`,
+			wantGCEURLMap: func() *utils.GCEURLMap {
+				rewrite := &utils.RouteAction{
+					UrlRewrite: &utils.UrlRewrite{PathPrefixRewrite: "/", HostRewrite: "app.internal"},
+				}
+				m := utils.NewGCEURLMap(klog.TODO())
+				m.DefaultBackend = &firstSvcPort
+				m.PutPathRulesForHost("foo.com", []utils.PathRule{
+					{Path: "/app", Backend: firstSvcPort, Action: rewrite},
+					{Path: "/app/*", Backend: firstSvcPort, Action: rewrite},
+					{Path: "/other", Backend: firstSvcPort},
+					{Path: "/other/*", Backend: firstSvcPort},
+				})
+				return m
+			},
+		},
+		{
+			desc:         "invalid url rewrite",
+			pathActions:  `[{"host":"foo.com","path":"/app","action":{"urlRewrite":{"pathPrefixRewrite":"app"}}}]`,
+			wantErrCount: 1,
+			wantGCEURLMap: func() *utils.GCEURLMap {
+				m := utils.NewGCEURLMap(klog.TODO())
+				m.DefaultBackend = &firstSvcPort
+				m.PutPathRulesForHost("foo.com", []utils.PathRule{
+					{Path: "/other", Backend: firstSvcPort},
+					{Path: "/other/*", Backend: firstSvcPort},
+				})
+				return m
+			},
+		},
+		{
+			desc:        "request mirror on path",
+			pathActions: `[{"host":"foo.com","path":"/app","action":{"requestMirrorPolicy":{"service":{"name":"canary-service","port":{"number":80}}}}}]`,
+			wantGCEURLMap: func() *utils.GCEURLMap {
+				mirror := &utils.RouteAction{MirrorBackend: &canarySvcPort}
+				m := utils.NewGCEURLMap(klog.TODO())
+				m.DefaultBackend = &firstSvcPort
+				m.PutPathRulesForHost("foo.com", []utils.PathRule{
+					{Path: "/app", Backend: firstSvcPort, Action: mirror},
+					{Path: "/app/*", Backend: firstSvcPort, Action: mirror},
+					{Path: "/other", Backend: firstSvcPort},
+					{Path: "/other/*", Backend: firstSvcPort},
+				})
+				return m
+			},
+		},
+		{
+			desc:        "request mirror on host",
+			pathActions: `[{"host":"foo.com","action":{"requestMirrorPolicy":{"service":{"name":"canary-service","port":{"number":80}}}}}]`,
+			wantGCEURLMap: func() *utils.GCEURLMap {
+				m := utils.NewGCEURLMap(klog.TODO())
+				m.DefaultBackend = &firstSvcPort
+				m.PutPathRulesForHost("foo.com", []utils.PathRule{
+					{Path: "/app", Backend: firstSvcPort},
+					{Path: "/app/*", Backend: firstSvcPort},
+					{Path: "/other", Backend: firstSvcPort},
+					{Path: "/other/*", Backend: firstSvcPort},
+				})
+				m.PutActionForHost("foo.com", &utils.RouteAction{MirrorBackend: &canarySvcPort})
+				return m
+			},
+		},
+		{
+			desc:         "unknown host and unsupported host action",
+			pathActions:  `[{"host":"bar.com","action":{"requestMirrorPolicy":{"service":{"name":"canary-service","port":{"number":80}}}}},{"host":"foo.com","action":{"urlRewrite":{"pathPrefixRewrite":"/"}}}]`,
+			wantErrCount: 2,
+			wantGCEURLMap: func() *utils.GCEURLMap {
+				m := utils.NewGCEURLMap(klog.TODO())
+				m.DefaultBackend = &firstSvcPort
+				m.PutPathRulesForHost("foo.com", []utils.PathRule{
+					{Path: "/app", Backend: firstSvcPort},
+					{Path: "/app/*", Backend: firstSvcPort},
+					{Path: "/other", Backend: firstSvcPort},
+					{Path: "/other/*", Backend: firstSvcPort},
+				})
+				return m
+			},
+		},
+		{
+			desc:         "retry policy on classic external class",
+			pathActions:  `[{"host":"foo.com","path":"/app","action":{"retryPolicy":{"retryConditions":["5xx"],"numRetries":2}}}]`,
+			wantErrCount: 1,
+			wantGCEURLMap: func() *utils.GCEURLMap {
+				m := utils.NewGCEURLMap(klog.TODO())
+				m.DefaultBackend = &firstSvcPort
+				m.PutPathRulesForHost("foo.com", []utils.PathRule{
+					{Path: "/other", Backend: firstSvcPort},
+					{Path: "/other/*", Backend: firstSvcPort},
+				})
+				return m
+			},
+		},
+		{
+			desc:         "unknown path and invalid weight",
+			pathActions:  `[{"host":"foo.com","path":"/missing","action":{}},{"host":"foo.com","path":"/app","action":{"weightedBackends":[{"service":{"name":"canary-service","port":{"number":80}},"weight":1001}]}}]`,
+			wantErrCount: 2,
+			wantGCEURLMap: func() *utils.GCEURLMap {
+				m := utils.NewGCEURLMap(klog.TODO())
+				m.DefaultBackend = &firstSvcPort
+				m.PutPathRulesForHost("foo.com", []utils.PathRule{
+					{Path: "/other", Backend: firstSvcPort},
+					{Path: "/other/*", Backend: firstSvcPort},
+				})
+				return m
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ing := test.NewIngress(types.NamespacedName{Name: "my-ingress", Namespace: "default"}, spec)
+			ing.Annotations = map[string]string{annotations.PathActionsKey: tc.pathActions}
+
+			gotGCEURLMap, gotErrs, _ := translator.TranslateIngress(ing, defaultBackend.ID, defaultNamer)
+			if len(gotErrs) != tc.wantErrCount {
+				t.Errorf("TranslateIngress() = _, %+v, want %v errs", gotErrs, tc.wantErrCount)
+			}
+			wantGCEURLMap := tc.wantGCEURLMap()
+			if !utils.EqualMapping(gotGCEURLMap, wantGCEURLMap) {
+				t.Errorf("TranslateIngress() = %+v\nwant\n%+v", gotGCEURLMap.String(), wantGCEURLMap.String())
+			}
+		})
+	}
+}
+
+func TestValidateRouteRule(t *testing.T) {
+	canary := &v1.IngressServiceBackend{Name: "canary", Port: port80}
+	weighted := &annotations.RouteAction{
+		WeightedBackends: []annotations.WeightedBackend{{Service: *canary, Weight: 10}},
+	}
+	allMatch := []annotations.RouteMatch{{PrefixMatch: "/"}}
+
+	for _, tc := range []struct {
+		desc     string
+		rule     annotations.RouteRule
+		existing []utils.RouteRule
+		wantErr  bool
+	}{
+		{
+			desc: "service",
+			rule: annotations.RouteRule{Priority: 1, Matches: allMatch, Service: canary},
+		},
+		{
+			desc: "weighted backends",
+			rule: annotations.RouteRule{Priority: 1, Matches: allMatch, Action: weighted},
+		},
+		{
+			desc:    "no backend",
+			rule:    annotations.RouteRule{Priority: 1, Matches: allMatch},
+			wantErr: true,
+		},
+		{
+			desc:    "service and weighted backends",
+			rule:    annotations.RouteRule{Priority: 1, Matches: allMatch, Service: canary, Action: weighted},
+			wantErr: true,
+		},
+		{
+			desc:    "negative priority",
+			rule:    annotations.RouteRule{Priority: -1, Matches: allMatch, Service: canary},
+			wantErr: true,
+		},
+		{
+			desc:     "duplicate priority",
+			rule:     annotations.RouteRule{Priority: 1, Matches: allMatch, Service: canary},
+			existing: []utils.RouteRule{{Priority: 1}},
+			wantErr:  true,
+		},
+		{
+			desc:    "no matches",
+			rule:    annotations.RouteRule{Priority: 1, Service: canary},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := validateRouteRule(tc.rule, tc.existing)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("validateRouteRule(%+v) = %v, wantErr = %v", tc.rule, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateRouteRulePriorities(t *testing.T) {
+	for _, tc := range []struct {
+		desc       string
+		priorities []int64
+		pathCount  int
+		wantErr    bool
+	}{
+		{
+			desc:       "no paths",
+			priorities: []int64{1, math.MaxInt32 - 1},
+		},
+		{
+			desc:       "room for the paths",
+			priorities: []int64{math.MaxInt32 - 10, 1},
+			pathCount:  10,
+		},
+		{
+			desc:       "no room for the paths",
+			priorities: []int64{1, math.MaxInt32 - 10},
+			pathCount:  11,
+			wantErr:    true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var rules []utils.RouteRule
+			for _, priority := range tc.priorities {
+				rules = append(rules, utils.RouteRule{Priority: priority})
+			}
+			err := validateRouteRulePriorities(rules, tc.pathCount)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("validateRouteRulePriorities(%v, %d) = %v, wantErr = %v", tc.priorities, tc.pathCount, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateUrlRewrite(t *testing.T) {
+	for _, tc := range []struct {
+		desc    string
+		rewrite annotations.UrlRewrite
+		wantErr bool
+	}{
+		{
+			desc:    "path prefix rewrite",
+			rewrite: annotations.UrlRewrite{PathPrefixRewrite: "/"},
+		},
+		{
+			desc:    "host rewrite",
+			rewrite: annotations.UrlRewrite{HostRewrite: "foo.internal"},
+		},
+		{
+			desc:    "path prefix and host rewrite",
+			rewrite: annotations.UrlRewrite{PathPrefixRewrite: "/v2/", HostRewrite: "foo.internal"},
+		},
+		{
+			desc:    "empty",
+			wantErr: true,
+		},
+		{
+			desc:    "relative path prefix rewrite",
+			rewrite: annotations.UrlRewrite{PathPrefixRewrite: "v2/"},
+			wantErr: true,
+		},
+		{
+			desc:    "path prefix rewrite too long",
+			rewrite: annotations.UrlRewrite{PathPrefixRewrite: "/" + strings.Repeat("a", maxPathPrefixRewriteLength)},
+			wantErr: true,
+		},
+		{
+			desc:    "host rewrite too long",
+			rewrite: annotations.UrlRewrite{HostRewrite: strings.Repeat("a", maxHostRewriteLength+1)},
+			wantErr: true,
+		},
+		{
+			desc:    "host rewrite with path",
+			rewrite: annotations.UrlRewrite{HostRewrite: "foo.internal/v2"},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := validateUrlRewrite(tc.rewrite)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("validateUrlRewrite(%+v) = %v, wantErr = %v", tc.rewrite, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateRouteActionPolicies(t *testing.T) {
+	retry := &annotations.RetryPolicy{RetryConditions: []string{"5xx", "connect-failure"}, NumRetries: 3, PerTryTimeout: "1.5s"}
+	fault := &annotations.FaultInjectionPolicy{
+		Delay: &annotations.FaultDelay{FixedDelay: "500ms", Percentage: 50},
+		Abort: &annotations.FaultAbort{HttpStatus: 503, Percentage: 5},
+	}
+	ilb := &getServicePortParams{isL7ILB: true}
+	regional := &getServicePortParams{isL7XLBRegional: true}
+	classic := &getServicePortParams{}
+
+	for _, tc := range []struct {
+		desc    string
+		action  annotations.RouteAction
+		params  *getServicePortParams
+		wantErr bool
+	}{
+		{
+			desc:   "retry and fault injection on internal class",
+			action: annotations.RouteAction{RetryPolicy: retry, FaultInjectionPolicy: fault},
+			params: ilb,
+		},
+		{
+			desc:   "retry and fault injection on regional external class",
+			action: annotations.RouteAction{RetryPolicy: retry, FaultInjectionPolicy: fault},
+			params: regional,
+		},
+		{
+			desc:    "retry on classic external class",
+			action:  annotations.RouteAction{RetryPolicy: retry},
+			params:  classic,
+			wantErr: true,
+		},
+		{
+			desc:    "fault injection on classic external class",
+			action:  annotations.RouteAction{FaultInjectionPolicy: fault},
+			params:  classic,
+			wantErr: true,
+		},
+		{
+			desc:   "retry without numRetries and perTryTimeout",
+			action: annotations.RouteAction{RetryPolicy: &annotations.RetryPolicy{RetryConditions: []string{"gateway-error"}}},
+			params: ilb,
+		},
+		{
+			desc:    "retry without conditions",
+			action:  annotations.RouteAction{RetryPolicy: &annotations.RetryPolicy{NumRetries: 1}},
+			params:  ilb,
+			wantErr: true,
+		},
+		{
+			desc:    "unknown retry condition",
+			action:  annotations.RouteAction{RetryPolicy: &annotations.RetryPolicy{RetryConditions: []string{"4xx"}}},
+			params:  ilb,
+			wantErr: true,
+		},
+		{
+			desc:   "zero numRetries",
+			action: annotations.RouteAction{RetryPolicy: &annotations.RetryPolicy{RetryConditions: []string{"5xx"}, NumRetries: 0, PerTryTimeout: "1s"}},
+			params: ilb,
+		},
+		{
+			desc:    "negative numRetries",
+			action:  annotations.RouteAction{RetryPolicy: &annotations.RetryPolicy{RetryConditions: []string{"5xx"}, NumRetries: -1}},
+			params:  ilb,
+			wantErr: true,
+		},
+		{
+			desc:    "invalid perTryTimeout",
+			action:  annotations.RouteAction{RetryPolicy: &annotations.RetryPolicy{RetryConditions: []string{"5xx"}, PerTryTimeout: "2"}},
+			params:  ilb,
+			wantErr: true,
+		},
+		{
+			desc:    "empty fault injection",
+			action:  annotations.RouteAction{FaultInjectionPolicy: &annotations.FaultInjectionPolicy{}},
+			params:  ilb,
+			wantErr: true,
+		},
+		{
+			desc:    "delay without fixedDelay",
+			action:  annotations.RouteAction{FaultInjectionPolicy: &annotations.FaultInjectionPolicy{Delay: &annotations.FaultDelay{Percentage: 10}}},
+			params:  ilb,
+			wantErr: true,
+		},
+		{
+			desc:    "delay percentage out of range",
+			action:  annotations.RouteAction{FaultInjectionPolicy: &annotations.FaultInjectionPolicy{Delay: &annotations.FaultDelay{FixedDelay: "1s", Percentage: 101}}},
+			params:  ilb,
+			wantErr: true,
+		},
+		{
+			desc:    "abort status out of range",
+			action:  annotations.RouteAction{FaultInjectionPolicy: &annotations.FaultInjectionPolicy{Abort: &annotations.FaultAbort{HttpStatus: 600, Percentage: 10}}},
+			params:  ilb,
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := validateRouteAction(tc.action, tc.params)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("validateRouteAction(%+v) = %v, wantErr = %v", tc.action, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateRouteMatch(t *testing.T) {
+	for _, tc := range []struct {
+		desc    string
+		match   annotations.RouteMatch
+		wantErr bool
+	}{
+		{
+			desc:  "empty match",
+			match: annotations.RouteMatch{},
+		},
+		{
+			desc:    "prefix and full path",
+			match:   annotations.RouteMatch{PrefixMatch: "/a", FullPathMatch: "/a"},
+			wantErr: true,
+		},
+		{
+			desc:    "relative path",
+			match:   annotations.RouteMatch{PrefixMatch: "a"},
+			wantErr: true,
+		},
+		{
+			desc:  "header present match",
+			match: annotations.RouteMatch{HeaderMatches: []annotations.HeaderMatch{{HeaderName: "x-tenant-id", PresentMatch: true}}},
+		},
+		{
+			desc:    "header without name",
+			match:   annotations.RouteMatch{HeaderMatches: []annotations.HeaderMatch{{ExactMatch: "true"}}},
+			wantErr: true,
+		},
+		{
+			desc:    "header with two match types",
+			match:   annotations.RouteMatch{HeaderMatches: []annotations.HeaderMatch{{HeaderName: "x-canary", ExactMatch: "true", PresentMatch: true}}},
+			wantErr: true,
+		},
+		{
+			desc:    "query parameter without match type",
+			match:   annotations.RouteMatch{QueryParameterMatches: []annotations.QueryParameterMatch{{Name: "tenant"}}},
+			wantErr: true,
+		},
+		{
+			desc:  "query parameter regex match",
+			match: annotations.RouteMatch{QueryParameterMatches: []annotations.QueryParameterMatch{{Name: "tenant", RegexMatch: "a.*"}}},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := validateRouteMatch(tc.match)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("validateRouteMatch(%+v) = %v, wantErr = %v", tc.match, err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -36,9 +36,12 @@ import (
 
 	"k8s.io/ingress-gce/pkg/annotations"
 	backendconfigv1 "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
+	apisgcsbackend "k8s.io/ingress-gce/pkg/apis/gcsbackend"
+	gcsbackendv1beta1 "k8s.io/ingress-gce/pkg/apis/gcsbackend/v1beta1"
 	"k8s.io/ingress-gce/pkg/backendconfig"
 	"k8s.io/ingress-gce/pkg/controller/errors"
 	"k8s.io/ingress-gce/pkg/flags"
+	"k8s.io/ingress-gce/pkg/gcsbackend"
 	"k8s.io/ingress-gce/pkg/utils"
 	namer_util "k8s.io/ingress-gce/pkg/utils/namer"
 )
@@ -72,6 +75,7 @@ func NewTranslator(serviceInformer cache.SharedIndexInformer,
 	nodeInformer cache.SharedIndexInformer,
 	podInformer cache.SharedIndexInformer,
 	endpointSliceInformer cache.SharedIndexInformer,
+	gcsBackendInformer cache.SharedIndexInformer,
 	kubeClient kubernetes.Interface,
 	recorderGetter healthchecks.RecorderGetter,
 	enableTHC,
@@ -84,6 +88,7 @@ func NewTranslator(serviceInformer cache.SharedIndexInformer,
 		NodeInformer:          nodeInformer,
 		PodInformer:           podInformer,
 		EndpointSliceInformer: endpointSliceInformer,
+		GCSBackendInformer:    gcsBackendInformer,
 		KubeClient:            kubeClient,
 		enableTHC:             enableTHC,
 		recorderGetter:        recorderGetter,
@@ -99,6 +104,7 @@ type Translator struct {
 	NodeInformer          cache.SharedIndexInformer
 	PodInformer           cache.SharedIndexInformer
 	EndpointSliceInformer cache.SharedIndexInformer
+	GCSBackendInformer    cache.SharedIndexInformer
 	KubeClient            kubernetes.Interface
 	recorderGetter        healthchecks.RecorderGetter
 	enableTHC             bool
@@ -139,6 +145,15 @@ func (t *Translator) GetService(namespace, name string) (*api_v1.Service, error)
 
 // maybeEnableNEG enables NEG on the service port if necessary
 func maybeEnableNEG(sp *utils.ServicePort, svc *api_v1.Service) error {
+	if svc.Spec.Type == api_v1.ServiceTypeExternalName && flags.F.EnableInternetNEGs &&
+		!sp.L7ILBEnabled && !sp.L7XLBRegionalEnabled {
+		// ExternalName Services are served by a global Internet NEG, which
+		// only global external load balancers support.
+		sp.InternetNEGEnabled = true
+		sp.ExternalName = svc.Spec.ExternalName
+		return nil
+	}
+
 	negAnnotation, ok, err := annotations.FromService(svc).NEGAnnotation()
 	if ok && err == nil {
 		sp.NEGEnabled = negAnnotation.NEGEnabledForIngress()
@@ -319,6 +334,42 @@ func (t *Translator) getServicePort(id utils.ServicePortID, params *getServicePo
 	return svcPort, nil, flagWarning
 }
 
+// getBackendBucket returns the BackendBucket of the GCSBackend referenced by
+// a resource backend.
+func (t *Translator) getBackendBucket(ref *api_v1.TypedLocalObjectReference, namespace string, params *getServicePortParams) (*utils.BackendBucket, error) {
+	if t.GCSBackendInformer == nil {
+		return nil, fmt.Errorf("resource backend %s %q is not supported, GCSBackends are disabled", ref.Kind, ref.Name)
+	}
+	if ref.APIGroup == nil || *ref.APIGroup != apisgcsbackend.GroupName || ref.Kind != gcsbackend.Kind {
+		return nil, fmt.Errorf("resource backend %q must be a %s.%s", ref.Name, gcsbackend.Kind, apisgcsbackend.GroupName)
+	}
+	if params.isL7ILB || params.isL7XLBRegional {
+		return nil, fmt.Errorf("resource backend %s %q is only supported by global external Ingresses", ref.Kind, ref.Name)
+	}
+
+	id := types.NamespacedName{Namespace: namespace, Name: ref.Name}
+	obj, exists, err := t.GCSBackendInformer.GetIndexer().GetByKey(id.String())
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving GCSBackend %q: %v", id, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("GCSBackend %q not found", id)
+	}
+	gcsBackend, ok := obj.(*gcsbackendv1beta1.GCSBackend)
+	if !ok {
+		return nil, fmt.Errorf("cannot convert to GCSBackend (%T)", obj)
+	}
+	if err := gcsbackend.Validate(gcsBackend); err != nil {
+		return nil, err
+	}
+	gcsBackend = gcsBackend.DeepCopy()
+	return &utils.BackendBucket{
+		ID:         id,
+		BucketName: gcsBackend.Spec.BucketName,
+		Cdn:        gcsBackend.Spec.Cdn,
+	}, nil
+}
+
 // TranslateIngress converts an Ingress into our internal UrlMap representation.
 // The returned bool is for warnings (there is one type of warnings currently possible).
 func (t *Translator) TranslateIngress(ing *v1.Ingress, systemDefaultBackend utils.ServicePortID, namer namer_util.BackendNamer) (*utils.GCEURLMap, []error, bool) {
@@ -327,6 +378,12 @@ func (t *Translator) TranslateIngress(ing *v1.Ingress, systemDefaultBackend util
 	urlMap := utils.NewGCEURLMap(t.logger)
 	params := t.getServicePortParamsForIngress(ing)
 
+	actions, err := pathActions(ing)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	usedActions := map[string]bool{}
+
 	for _, rule := range ing.Spec.Rules {
 		if rule.HTTP == nil {
 			continue
@@ -334,6 +391,44 @@ func (t *Translator) TranslateIngress(ing *v1.Ingress, systemDefaultBackend util
 
 		pathRules := []utils.PathRule{}
 		for _, p := range rule.HTTP.Paths {
+			var action *utils.RouteAction
+			// Actions without a path apply to the whole host and are added below.
+			if pathAction, ok := actions[pathActionKey(rule.Host, p.Path)]; ok && p.Path != "" {
+				usedActions[pathActionKey(rule.Host, p.Path)] = true
+				var actionErrs []error
+				var warning bool
+				action, actionErrs, warning = t.translateRouteAction(pathAction.Action, ing.Namespace, params, namer)
+				warnings = warnings || warning
+				if len(actionErrs) > 0 {
+					errs = append(errs, actionErrs...)
+					continue
+				}
+			}
+
+			if p.Backend.Resource != nil {
+				if action != nil {
+					errs = append(errs, fmt.Errorf("path action for host %q and path %q cannot be used with a resource backend", rule.Host, p.Path))
+					continue
+				}
+				bucket, err := t.getBackendBucket(p.Backend.Resource, ing.Namespace, params)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				paths, err := validateAndGetPaths(p)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				for _, path := range paths {
+					if path == "" {
+						path = DefaultPath
+					}
+					pathRules = append(pathRules, utils.PathRule{Path: path, BackendBucket: bucket})
+				}
+				continue
+			}
+
 			svcPortID, err := utils.BackendToServicePortID(p.Backend, ing.Namespace)
 			if err != nil {
 				// Only error possible is Backend is not a Service Backend, so move to next path
@@ -359,7 +454,7 @@ func (t *Translator) TranslateIngress(ing *v1.Ingress, systemDefaultBackend util
 					if path == "" {
 						path = DefaultPath
 					}
-					pathRules = append(pathRules, utils.PathRule{Path: path, Backend: *svcPort})
+					pathRules = append(pathRules, utils.PathRule{Path: path, Backend: *svcPort, Action: action})
 				}
 			}
 		}
@@ -371,6 +466,29 @@ func (t *Translator) TranslateIngress(ing *v1.Ingress, systemDefaultBackend util
 		urlMap.PutPathRulesForHost(host, pathRules)
 	}
 
+	var unusedActions []string
+	for key, action := range actions {
+		if !usedActions[key] && action.Path != "" {
+			unusedActions = append(unusedActions, key)
+		}
+	}
+	sort.Strings(unusedActions)
+	for _, key := range unusedActions {
+		errs = append(errs, fmt.Errorf("path action for host %q and path %q does not match any path of the Ingress", actions[key].Host, actions[key].Path))
+	}
+
+	routeRuleErrs, warning := t.translateRouteRules(ing, urlMap, params, namer)
+	errs = append(errs, routeRuleErrs...)
+	warnings = warnings || warning
+
+	hostActionErrs, warning := t.translateHostActions(actions, ing.Namespace, urlMap, params, namer)
+	errs = append(errs, hostActionErrs...)
+	warnings = warnings || warning
+
+	errorResponseErrs, warning := t.translateErrorResponsePolicy(ing, urlMap, params, namer)
+	errs = append(errs, errorResponseErrs...)
+	warnings = warnings || warning
+
 	if ing.Spec.DefaultBackend != nil {
 		svcPortID, err := utils.BackendToServicePortID(*ing.Spec.DefaultBackend, ing.Namespace)
 		if err != nil {
@@ -399,6 +517,24 @@ func (t *Translator) TranslateIngress(ing *v1.Ingress, systemDefaultBackend util
 	return urlMap, errs, warnings
 }
 
+// ValidatePaths returns an error for each path of the Ingress rules that does
+// not match its path type. Unlike TranslateIngress, it does not need the
+// backends of the paths to exist.
+func ValidatePaths(ing *v1.Ingress) []error {
+	var errs []error
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, p := range rule.HTTP.Paths {
+			if _, err := validateAndGetPaths(p); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs
+}
+
 // validateAndGetPaths will validate the path based on the specified path type and will return the
 // the path rules that should be used. If no path type is provided, the path type will be assumed
 // to be ImplementationSpecific. If a non existent path type is provided, an error will be returned.
@@ -41,9 +41,13 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/ingress-gce/pkg/annotations"
 	backendconfig "k8s.io/ingress-gce/pkg/apis/backendconfig/v1"
+	apisgcsbackend "k8s.io/ingress-gce/pkg/apis/gcsbackend"
+	gcsbackendv1beta1 "k8s.io/ingress-gce/pkg/apis/gcsbackend/v1beta1"
 	backendconfigclient "k8s.io/ingress-gce/pkg/backendconfig/client/clientset/versioned/fake"
 	informerbackendconfig "k8s.io/ingress-gce/pkg/backendconfig/client/informers/externalversions/backendconfig/v1"
 	"k8s.io/ingress-gce/pkg/flags"
+	gcsbackendclient "k8s.io/ingress-gce/pkg/gcsbackend/client/clientset/versioned/fake"
+	informergcsbackend "k8s.io/ingress-gce/pkg/gcsbackend/client/informers/externalversions/gcsbackend/v1beta1"
 	"k8s.io/ingress-gce/pkg/healthchecks"
 	"k8s.io/ingress-gce/pkg/test"
 	"k8s.io/ingress-gce/pkg/utils"
@@ -74,6 +78,7 @@ func fakeTranslator() *Translator {
 func configuredFakeTranslator() *Translator {
 	client := fake.NewSimpleClientset()
 	backendConfigClient := backendconfigclient.NewSimpleClientset()
+	gcsBackendClient := gcsbackendclient.NewSimpleClientset()
 	namespace := apiv1.NamespaceAll
 	resyncPeriod := 1 * time.Second
 
@@ -81,6 +86,7 @@ func configuredFakeTranslator() *Translator {
 	BackendConfigInformer := informerbackendconfig.NewBackendConfigInformer(backendConfigClient, namespace, resyncPeriod, utils.NewNamespaceIndexer())
 	PodInformer := informerv1.NewPodInformer(client, namespace, resyncPeriod, utils.NewNamespaceIndexer())
 	NodeInformer := informerv1.NewNodeInformer(client, resyncPeriod, utils.NewNamespaceIndexer())
+	GCSBackendInformer := informergcsbackend.NewGCSBackendInformer(gcsBackendClient, namespace, resyncPeriod, utils.NewNamespaceIndexer())
 	EndpointSliceInformer := discoveryinformer.NewEndpointSliceInformer(client, namespace, 0,
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc, endpointslices.EndpointSlicesByServiceIndex: endpointslices.EndpointSlicesByServiceFunc})
 	return NewTranslator(
@@ -89,6 +95,7 @@ func configuredFakeTranslator() *Translator {
 		NodeInformer,
 		PodInformer,
 		EndpointSliceInformer,
+		GCSBackendInformer,
 		client,
 		healthchecks.NewFakeRecorderGetter(0),
 		false,
@@ -235,6 +242,122 @@ func TestTranslateIngress(t *testing.T) {
 	}
 }
 
+func TestTranslateIngressResourceBackend(t *testing.T) {
+	translator := fakeTranslator()
+	svc := test.NewService(types.NamespacedName{Name: "default-http-backend", Namespace: "kube-system"}, apiv1.ServiceSpec{
+		Type:  apiv1.ServiceTypeNodePort,
+		Ports: []apiv1.ServicePort{{Name: "http", Port: 80}},
+	})
+	translator.ServiceInformer.GetIndexer().Add(svc)
+	cdn := &gcsbackendv1beta1.CDNConfig{Enabled: true}
+	translator.GCSBackendInformer.GetIndexer().Add(&gcsbackendv1beta1.GCSBackend{
+		ObjectMeta: metav1.ObjectMeta{Name: "assets", Namespace: "default"},
+		Spec:       gcsbackendv1beta1.GCSBackendSpec{BucketName: "assets-bucket", Cdn: cdn},
+	})
+	translator.GCSBackendInformer.GetIndexer().Add(&gcsbackendv1beta1.GCSBackend{
+		ObjectMeta: metav1.ObjectMeta{Name: "no-bucket", Namespace: "default"},
+	})
+
+	apiGroup := apisgcsbackend.GroupName
+	otherGroup := "example.com"
+	newIngress := func(ref *apiv1.TypedLocalObjectReference, ingAnnotations map[string]string) *v1.Ingress {
+		pathType := v1.PathTypePrefix
+		ing := test.NewIngress(types.NamespacedName{Name: "my-ingress", Namespace: "default"}, v1.IngressSpec{
+			Rules: []v1.IngressRule{{
+				Host: "foo.bar",
+				IngressRuleValue: v1.IngressRuleValue{HTTP: &v1.HTTPIngressRuleValue{
+					Paths: []v1.HTTPIngressPath{{Path: "/assets", PathType: &pathType, Backend: v1.IngressBackend{Resource: ref}}},
+				}},
+			}},
+		})
+		ing.Annotations = ingAnnotations
+		return ing
+	}
+
+	wantBucket := &utils.BackendBucket{
+		ID:         types.NamespacedName{Name: "assets", Namespace: "default"},
+		BucketName: "assets-bucket",
+		Cdn:        cdn,
+	}
+	wantURLMap := utils.NewGCEURLMap(klog.TODO())
+	wantURLMap.DefaultBackend = &defaultBackend
+	wantURLMap.PutPathRulesForHost("foo.bar", []utils.PathRule{
+		{Path: "/assets", BackendBucket: wantBucket},
+		{Path: "/assets/*", BackendBucket: wantBucket},
+	})
+
+	for _, tc := range []struct {
+		desc        string
+		ing         *v1.Ingress
+		disabled    bool
+		wantURLMap  *utils.GCEURLMap
+		wantErrPath bool
+	}{
+		{
+			desc:       "GCSBackend",
+			ing:        newIngress(&apiv1.TypedLocalObjectReference{APIGroup: &apiGroup, Kind: "GCSBackend", Name: "assets"}, nil),
+			wantURLMap: wantURLMap,
+		},
+		{
+			desc:        "GCSBackends disabled",
+			ing:         newIngress(&apiv1.TypedLocalObjectReference{APIGroup: &apiGroup, Kind: "GCSBackend", Name: "assets"}, nil),
+			disabled:    true,
+			wantErrPath: true,
+		},
+		{
+			desc:        "missing GCSBackend",
+			ing:         newIngress(&apiv1.TypedLocalObjectReference{APIGroup: &apiGroup, Kind: "GCSBackend", Name: "missing"}, nil),
+			wantErrPath: true,
+		},
+		{
+			desc:        "GCSBackend without bucket name",
+			ing:         newIngress(&apiv1.TypedLocalObjectReference{APIGroup: &apiGroup, Kind: "GCSBackend", Name: "no-bucket"}, nil),
+			wantErrPath: true,
+		},
+		{
+			desc:        "resource of another group",
+			ing:         newIngress(&apiv1.TypedLocalObjectReference{APIGroup: &otherGroup, Kind: "GCSBackend", Name: "assets"}, nil),
+			wantErrPath: true,
+		},
+		{
+			desc:        "resource of another kind",
+			ing:         newIngress(&apiv1.TypedLocalObjectReference{APIGroup: &apiGroup, Kind: "Bucket", Name: "assets"}, nil),
+			wantErrPath: true,
+		},
+		{
+			desc:        "internal Ingress",
+			ing:         newIngress(&apiv1.TypedLocalObjectReference{APIGroup: &apiGroup, Kind: "GCSBackend", Name: "assets"}, map[string]string{annotations.IngressClassKey: annotations.GceL7ILBIngressClass}),
+			wantErrPath: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			tr := *translator
+			if tc.disabled {
+				tr.GCSBackendInformer = nil
+			}
+			gotURLMap, gotErrs, _ := tr.TranslateIngress(tc.ing, defaultBackend.ID, defaultNamer)
+			if tc.wantErrPath {
+				if len(gotErrs) != 1 {
+					t.Errorf("TranslateIngress() = _, %v, want 1 error", gotErrs)
+				}
+				if len(gotURLMap.AllBackendBuckets()) != 0 {
+					t.Errorf("TranslateIngress() = %s, want no backend bucket", gotURLMap.String())
+				}
+				return
+			}
+			if len(gotErrs) != 0 {
+				t.Fatalf("TranslateIngress() = _, %v, want no error", gotErrs)
+			}
+			if !utils.EqualMapping(gotURLMap, tc.wantURLMap) {
+				t.Errorf("TranslateIngress() = %s\nwant\n%s", gotURLMap.String(), tc.wantURLMap.String())
+			}
+			if diff := cmp.Diff([]utils.BackendBucket{*wantBucket}, gotURLMap.AllBackendBuckets()); diff != "" {
+				t.Errorf("AllBackendBuckets() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestGetServicePort(t *testing.T) {
 	cases := []struct {
 		desc            string
@@ -243,6 +366,7 @@ func TestGetServicePort(t *testing.T) {
 		id              utils.ServicePortID
 		wantErr         bool
 		params          getServicePortParams
+		internetNEGs    bool
 		wantServicePort *utils.ServicePort
 		wantWarning     bool
 	}{
@@ -255,6 +379,55 @@ func TestGetServicePort(t *testing.T) {
 			id:      utils.ServicePortID{Port: v1.ServiceBackendPort{Name: "http"}},
 			wantErr: true,
 		},
+		{
+			desc: "externalName service without internet NEGs",
+			spec: apiv1.ServiceSpec{
+				Type:         apiv1.ServiceTypeExternalName,
+				ExternalName: "api.example.com",
+				Ports:        []apiv1.ServicePort{{Name: "https", Port: 443}},
+			},
+			id:      utils.ServicePortID{Port: v1.ServiceBackendPort{Name: "https"}},
+			wantErr: true,
+		},
+		{
+			desc: "externalName service with internet NEGs",
+			spec: apiv1.ServiceSpec{
+				Type:         apiv1.ServiceTypeExternalName,
+				ExternalName: "api.example.com",
+				Ports:        []apiv1.ServicePort{{Name: "https", Port: 443}},
+			},
+			annotations: map[string]string{
+				"service.alpha.kubernetes.io/app-protocols": `{"https":"HTTPS"}`,
+			},
+			id:           utils.ServicePortID{Port: v1.ServiceBackendPort{Number: 443}},
+			internetNEGs: true,
+			wantServicePort: &utils.ServicePort{
+				ID: utils.ServicePortID{
+					Service: types.NamespacedName{
+						Namespace: "default",
+						Name:      "foo",
+					},
+					Port: v1.ServiceBackendPort{Number: 443},
+				},
+				Port:               443,
+				PortName:           "https",
+				Protocol:           "HTTPS",
+				InternetNEGEnabled: true,
+				ExternalName:       "api.example.com",
+			},
+		},
+		{
+			desc: "externalName service for gce-internal",
+			spec: apiv1.ServiceSpec{
+				Type:         apiv1.ServiceTypeExternalName,
+				ExternalName: "api.example.com",
+				Ports:        []apiv1.ServicePort{{Name: "https", Port: 443}},
+			},
+			id:           utils.ServicePortID{Port: v1.ServiceBackendPort{Name: "https"}},
+			params:       getServicePortParams{isL7ILB: true},
+			internetNEGs: true,
+			wantErr:      true,
+		},
 		{
 			desc: "missing port",
 			spec: apiv1.ServiceSpec{
@@ -477,6 +650,11 @@ func TestGetServicePort(t *testing.T) {
 	}
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
+			oldFlag := flags.F.EnableInternetNEGs
+			flags.F.EnableInternetNEGs = tc.internetNEGs
+			defer func() {
+				flags.F.EnableInternetNEGs = oldFlag
+			}()
 			translator := fakeTranslator()
 			svcLister := translator.ServiceInformer.GetIndexer()
 
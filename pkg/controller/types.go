@@ -18,6 +18,7 @@ package controller
 
 import (
 	v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/ingress-gce/pkg/loadbalancers"
 	"k8s.io/ingress-gce/pkg/utils"
 )
@@ -27,4 +28,13 @@ type syncState struct {
 	urlMap *utils.GCEURLMap
 	ing    *v1.Ingress
 	l7     *loadbalancers.L7
+	// lb is the runtime info of the loadbalancer if it was computed before
+	// the sync, for drift detection.
+	lb *loadbalancers.L7RuntimeInfo
+	// skipFrontend leaves the frontend resources unchanged because of the
+	// report-only drift policy of the Ingress.
+	skipFrontend bool
+	// skipBackends are the names of the backend services left unchanged
+	// because of the report-only drift policy of the Ingress.
+	skipBackends sets.Set[string]
 }
@@ -69,7 +69,7 @@ func convert(ings []*v1.Ingress) (retVal []interface{}) {
 func nodePorts(svcPorts []utils.ServicePort) []int64 {
 	ports := []int64{}
 	for _, p := range uniq(svcPorts) {
-		if !p.NEGEnabled {
+		if !p.NEGEnabled && !p.InternetNEGEnabled {
 			ports = append(ports, p.NodePort)
 		}
 	}
@@ -165,5 +165,6 @@ func crd(meta *CRDMeta, namespacedScoped bool, logger klog.Logger) *apiextension
 		versions = append(versions, version)
 	}
 	crd.Spec.Versions = versions
+	crd.Spec.Conversion = meta.conversion
 	return crd
 }
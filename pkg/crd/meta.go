@@ -17,6 +17,7 @@ limitations under the License.
 package crd
 
 import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/kube-openapi/pkg/common"
 )
 
@@ -33,6 +34,8 @@ type CRDMeta struct {
 	shortNames []string
 	typeSource string
 	fn         common.GetOpenAPIDefinitions
+	// conversion is the conversion strategy of the CRD, none if nil.
+	conversion *apiextensionsv1.CustomResourceConversion
 }
 
 // NewCRDMeta creates a CRDMeta type which can be passed to a CRDHandler in
@@ -49,6 +52,21 @@ func NewCRDMeta(groupName, kind, listKind, singular, plural string, versions []*
 	}
 }
 
+// SetConversionWebhook makes the API server convert the versions of the CRD
+// with the webhook served by the given Service.
+func (m *CRDMeta) SetConversionWebhook(service *apiextensionsv1.ServiceReference, caBundle []byte) {
+	m.conversion = &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{
+				Service:  service,
+				CABundle: caBundle,
+			},
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+}
+
 // Version specifies the API version and meta information that is needed to
 // generate OpenAPI schema based CRD validation.
 type Version struct {
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drift detects and reports out-of-band changes to the GCE resources
+// managed by the controllers, i.e. changes which were not made by a sync.
+package drift
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/ingress-gce/pkg/dryrun"
+	"k8s.io/klog/v2"
+)
+
+// EventReason is the reason of the events which report drift.
+const EventReason = "DriftDetected"
+
+// deletedField is the field label of the drift metric for the resources
+// deleted out-of-band.
+const deletedField = "<deleted>"
+
+var driftCount = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gce_resource_drift_count",
+		Help: "Number of out-of-band changes detected on fields of GCE resources, the field is <deleted> for deleted resources",
+	},
+	[]string{"resource", "field"},
+)
+
+func init() {
+	klog.V(3).Infof("Registering GCE resource drift metrics %v", driftCount)
+	prometheus.MustRegister(driftCount)
+}
+
+// Tracker remembers a hash of the desired state of each object at its last
+// successful sync. Differences between the live GCE resources and the desired
+// state of an object whose hash did not change since are drift.
+type Tracker struct {
+	lock   sync.Mutex
+	hashes map[string]string
+}
+
+// NewTracker returns an empty Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{hashes: make(map[string]string)}
+}
+
+// SetSynced records that the object with the given key was successfully
+// synced to the desired state with the given hash.
+func (t *Tracker) SetSynced(key, hash string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.hashes[key] = hash
+}
+
+// IsResync returns true if the object with the given key was successfully
+// synced to the desired state with the given hash before.
+func (t *Tracker) IsResync(key, hash string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	synced, ok := t.hashes[key]
+	return ok && synced == hash
+}
+
+// Delete forgets the object with the given key.
+func (t *Tracker) Delete(key string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.hashes, key)
+}
+
+// Hash returns a hash of the JSON encoding of the desired state.
+func Hash(desired interface{}) (string, error) {
+	b, err := json.Marshal(desired)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b)), nil
+}
+
+// Changes returns the updates and creations of a plan, which are the drift of
+// existing resources and the resources deleted out-of-band when the plan is
+// computed for a resync.
+func Changes(plan *dryrun.Plan) []dryrun.Change {
+	var changes []dryrun.Change
+	for _, c := range plan.Changes {
+		if c.Operation == dryrun.OperationUpdate || c.Operation == dryrun.OperationCreate {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// Observe exports the drift as metrics.
+func Observe(changes []dryrun.Change) {
+	for _, c := range changes {
+		if c.Operation == dryrun.OperationCreate {
+			driftCount.WithLabelValues(c.Resource, deletedField).Inc()
+			continue
+		}
+		for _, field := range c.Fields {
+			driftCount.WithLabelValues(c.Resource, field).Inc()
+		}
+	}
+}
+
+// Report exports the drift as metrics and emits it as an event on obj.
+// heal tells whether the drift is going to be reverted.
+func Report(recorder record.EventRecorder, obj runtime.Object, changes []dryrun.Change, heal bool) {
+	Observe(changes)
+	Event(recorder, obj, changes, heal)
+}
+
+// Event emits the drift as an event on obj, without exporting it as metrics.
+// It is used when the same drift is reported on several objects.
+func Event(recorder record.EventRecorder, obj runtime.Object, changes []dryrun.Change, heal bool) {
+	var drifted []string
+	for _, c := range changes {
+		if c.Operation == dryrun.OperationCreate {
+			drifted = append(drifted, fmt.Sprintf("deleted %s %q", c.Resource, c.Name))
+			continue
+		}
+		drifted = append(drifted, c.String())
+	}
+	action := "reverting them"
+	if !heal {
+		action = "leaving them unchanged"
+	}
+	recorder.Eventf(obj, v1.EventTypeWarning, EventReason, "Detected out-of-band changes to GCE resources, %s: %s", action, strings.Join(drifted, "; "))
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/ingress-gce/pkg/dryrun"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker()
+	if tracker.IsResync("ns/ing", "a") {
+		t.Errorf("IsResync() = true for an object never synced, want false")
+	}
+	tracker.SetSynced("ns/ing", "a")
+	if !tracker.IsResync("ns/ing", "a") {
+		t.Errorf("IsResync() = false for the synced desired state, want true")
+	}
+	if tracker.IsResync("ns/ing", "b") {
+		t.Errorf("IsResync() = true for a new desired state, want false")
+	}
+	tracker.Delete("ns/ing")
+	if tracker.IsResync("ns/ing", "a") {
+		t.Errorf("IsResync() = true for a deleted object, want false")
+	}
+}
+
+func TestHash(t *testing.T) {
+	type state struct {
+		Name  string
+		Ports []int
+	}
+	hash := func(s state) string {
+		t.Helper()
+		h, err := Hash(s)
+		if err != nil {
+			t.Fatalf("Hash(%+v) = %v", s, err)
+		}
+		return h
+	}
+	if hash(state{Name: "a", Ports: []int{80}}) != hash(state{Name: "a", Ports: []int{80}}) {
+		t.Errorf("Hash() of equal states differ, want equal")
+	}
+	if hash(state{Name: "a", Ports: []int{80}}) == hash(state{Name: "a", Ports: []int{443}}) {
+		t.Errorf("Hash() of different states are equal, want different")
+	}
+}
+
+func TestChanges(t *testing.T) {
+	plan := &dryrun.Plan{}
+	plan.Create("UrlMap", "um")
+	plan.Update("BackendService", "bs", "TimeoutSec")
+	plan.Delete("ForwardingRule", "fr")
+
+	want := []dryrun.Change{
+		{Operation: dryrun.OperationCreate, Resource: "UrlMap", Name: "um"},
+		{Operation: dryrun.OperationUpdate, Resource: "BackendService", Name: "bs", Fields: []string{"TimeoutSec"}},
+	}
+	if diff := cmp.Diff(want, Changes(plan)); diff != "" {
+		t.Errorf("Changes() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestReport(t *testing.T) {
+	ing := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "ing", Namespace: "ns"}}
+	changes := []dryrun.Change{
+		{Operation: dryrun.OperationUpdate, Resource: "UrlMap", Name: "um", Fields: []string{"HostRules", "PathMatchers"}},
+		{Operation: dryrun.OperationCreate, Resource: "TargetHttpProxy", Name: "tp"},
+	}
+
+	for _, tc := range []struct {
+		desc      string
+		heal      bool
+		wantEvent string
+	}{
+		{
+			desc:      "auto-heal",
+			heal:      true,
+			wantEvent: `Warning DriftDetected Detected out-of-band changes to GCE resources, reverting them: update UrlMap "um" (HostRules, PathMatchers); deleted TargetHttpProxy "tp"`,
+		},
+		{
+			desc:      "report only",
+			wantEvent: `Warning DriftDetected Detected out-of-band changes to GCE resources, leaving them unchanged: update UrlMap "um" (HostRules, PathMatchers); deleted TargetHttpProxy "tp"`,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			before := testutil.ToFloat64(driftCount.WithLabelValues("UrlMap", "HostRules"))
+			beforeDeleted := testutil.ToFloat64(driftCount.WithLabelValues("TargetHttpProxy", deletedField))
+			recorder := record.NewFakeRecorder(1)
+
+			Report(recorder, ing, changes, tc.heal)
+			if got := <-recorder.Events; got != tc.wantEvent {
+				t.Errorf("got event %q, want %q", got, tc.wantEvent)
+			}
+			if got := testutil.ToFloat64(driftCount.WithLabelValues("UrlMap", "HostRules")) - before; got != 1 {
+				t.Errorf("drift count of UrlMap HostRules increased by %v, want 1", got)
+			}
+			if got := testutil.ToFloat64(driftCount.WithLabelValues("TargetHttpProxy", deletedField)) - beforeDeleted; got != 1 {
+				t.Errorf("drift count of deleted TargetHttpProxy increased by %v, want 1", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dryrun describes the changes to GCE resources which a sync would
+// make, so that they can be reported instead of applied.
+package dryrun
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// EventReason is the reason of the events which report a plan.
+const EventReason = "DryRun"
+
+// Operation is the kind of change made to a GCE resource.
+type Operation string
+
+const (
+	OperationCreate Operation = "create"
+	OperationUpdate Operation = "update"
+	OperationDelete Operation = "delete"
+)
+
+// Change is a single mutation of a GCE resource.
+type Change struct {
+	Operation Operation
+	// Resource is the type of the GCE resource, e.g. UrlMap.
+	Resource string
+	// Name is the name of the GCE resource.
+	Name string
+	// Fields are the fields of the resource changed by an update. It is
+	// empty if the fields are not known.
+	Fields []string
+}
+
+// String returns the change in the form `update UrlMap "name" (HostRules)`.
+func (c Change) String() string {
+	s := fmt.Sprintf("%s %s %q", c.Operation, c.Resource, c.Name)
+	if len(c.Fields) > 0 {
+		s += fmt.Sprintf(" (%s)", strings.Join(c.Fields, ", "))
+	}
+	return s
+}
+
+// Plan is the ordered list of changes a sync would make.
+type Plan struct {
+	Changes []Change
+}
+
+// Create adds the creation of a resource to the plan.
+func (p *Plan) Create(resource, name string) {
+	p.Changes = append(p.Changes, Change{Operation: OperationCreate, Resource: resource, Name: name})
+}
+
+// Update adds the update of the given fields of a resource to the plan.
+func (p *Plan) Update(resource, name string, fields ...string) {
+	p.Changes = append(p.Changes, Change{Operation: OperationUpdate, Resource: resource, Name: name, Fields: fields})
+}
+
+// Delete adds the deletion of a resource to the plan.
+func (p *Plan) Delete(resource, name string) {
+	p.Changes = append(p.Changes, Change{Operation: OperationDelete, Resource: resource, Name: name})
+}
+
+// Append adds the changes of other to the plan.
+func (p *Plan) Append(other *Plan) {
+	if other != nil {
+		p.Changes = append(p.Changes, other.Changes...)
+	}
+}
+
+// Count returns the number of changes of the given operation.
+func (p *Plan) Count(op Operation) int {
+	var n int
+	for _, c := range p.Changes {
+		if c.Operation == op {
+			n++
+		}
+	}
+	return n
+}
+
+// String returns a summary of the plan followed by all changes.
+func (p *Plan) String() string {
+	summary := fmt.Sprintf("%d to create, %d to update, %d to delete", p.Count(OperationCreate), p.Count(OperationUpdate), p.Count(OperationDelete))
+	if len(p.Changes) == 0 {
+		return summary
+	}
+	var changes []string
+	for _, c := range p.Changes {
+		changes = append(changes, c.String())
+	}
+	return summary + ": " + strings.Join(changes, "; ")
+}
+
+// Report emits the plan as an event on obj.
+func Report(recorder record.EventRecorder, obj runtime.Object, plan *Plan) {
+	recorder.Eventf(obj, v1.EventTypeNormal, EventReason, "Planned GCE changes: %s", plan)
+}
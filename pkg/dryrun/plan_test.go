@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestPlanString(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc  string
+		build func(p *Plan)
+		want  string
+	}{
+		{
+			desc:  "empty plan",
+			build: func(p *Plan) {},
+			want:  "0 to create, 0 to update, 0 to delete",
+		},
+		{
+			desc: "all operations",
+			build: func(p *Plan) {
+				p.Create("UrlMap", "um")
+				p.Update("TargetHttpProxy", "tp", "UrlMap")
+				p.Update("BackendService", "bs", "TimeoutSec", "Iap")
+				p.Delete("ForwardingRule", "fr")
+			},
+			want: `1 to create, 2 to update, 1 to delete: create UrlMap "um"; update TargetHttpProxy "tp" (UrlMap); update BackendService "bs" (TimeoutSec, Iap); delete ForwardingRule "fr"`,
+		},
+		{
+			desc: "appended plan",
+			build: func(p *Plan) {
+				other := &Plan{}
+				other.Create("HealthCheck", "hc")
+				p.Append(other)
+				p.Append(nil)
+			},
+			want: `1 to create, 0 to update, 0 to delete: create HealthCheck "hc"`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			p := &Plan{}
+			tc.build(p)
+			if got := p.String(); got != tc.want {
+				t.Errorf("String() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	recorder := record.NewFakeRecorder(1)
+	svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "ns"}}
+	p := &Plan{}
+	p.Create("Firewall", "fw")
+
+	Report(recorder, svc, p)
+	want := `Normal DryRun Planned GCE changes: 1 to create, 0 to update, 0 to delete: create Firewall "fw"`
+	if got := <-recorder.Events; got != want {
+		t.Errorf("got event %q, want %q", got, want)
+	}
+}
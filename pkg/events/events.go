@@ -29,6 +29,7 @@ const (
 	TranslateIngress  = "Translate"
 	IPChanged         = "IPChanged"
 	GarbageCollection = "GarbageCollection"
+	DeletionProtected = "DeletionProtected"
 
 	SyncService = "Sync"
 )
@@ -3,6 +3,12 @@ package events
 import (
 	"fmt"
 	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
 )
 
 func TestTruncatedStringList(t *testing.T) {
@@ -32,3 +38,35 @@ func TestTruncatedStringList(t *testing.T) {
 		})
 	}
 }
+
+func TestLimiter(t *testing.T) {
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	limiter := NewLimiter(time.Hour)
+	limiter.clock = fakeClock
+	recorder := record.NewFakeRecorder(10)
+	svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "svc", UID: "uid"}}
+	otherSvc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "other", UID: "other-uid"}}
+
+	for _, step := range []struct {
+		desc  string
+		obj   *v1.Service
+		msg   string
+		after time.Duration
+		want  bool
+	}{
+		{desc: "first event", obj: svc, msg: "kept", want: true},
+		{desc: "same event", obj: svc, msg: "kept", after: time.Minute, want: false},
+		{desc: "other message", obj: svc, msg: "deleted", want: true},
+		{desc: "other object", obj: otherSvc, msg: "kept", want: true},
+		{desc: "after the interval", obj: svc, msg: "kept", after: time.Hour, want: true},
+	} {
+		fakeClock.Step(step.after)
+		limiter.Eventf(recorder, step.obj, v1.EventTypeWarning, DeletionProtected, "%s", step.msg)
+		if got := len(recorder.Events) == 1; got != step.want {
+			t.Errorf("%s: Eventf() recorded an event = %v, want %v", step.desc, got, step.want)
+		}
+		for len(recorder.Events) > 0 {
+			<-recorder.Events
+		}
+	}
+}
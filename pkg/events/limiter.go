@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+)
+
+// DeletionProtectedInterval is the minimum interval between the events which
+// tell that the load balancer of an object is kept because of the deletion
+// protection annotation, since they are recorded on every sync of the object.
+const DeletionProtectedInterval = time.Hour
+
+// Limiter records an event about an object at most once per interval, for
+// the events reported on every resync of the object.
+type Limiter struct {
+	interval time.Duration
+	clock    clock.Clock
+
+	mu sync.Mutex
+	// last holds the time each event was last recorded.
+	last map[string]time.Time
+}
+
+// NewLimiter returns a Limiter recording each event at most once per interval.
+func NewLimiter(interval time.Duration) *Limiter {
+	return &Limiter{interval: interval, clock: clock.RealClock{}, last: map[string]time.Time{}}
+}
+
+// Eventf records the event on the object with the recorder, unless the same
+// event was recorded on the object less than the interval ago.
+func (l *Limiter) Eventf(recorder record.EventRecorder, object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	message := fmt.Sprintf(messageFmt, args...)
+	key := reason + "/" + message
+	if accessor, err := meta.Accessor(object); err == nil {
+		key = fmt.Sprintf("%s/%s/%s/%s", accessor.GetNamespace(), accessor.GetName(), accessor.GetUID(), key)
+	}
+
+	l.mu.Lock()
+	now := l.clock.Now()
+	for k, t := range l.last {
+		// Forget the expired events, so that deleted objects are not kept.
+		if now.Sub(t) >= l.interval {
+			delete(l.last, k)
+		}
+	}
+	_, limited := l.last[key]
+	if !limited {
+		l.last[key] = now
+	}
+	l.mu.Unlock()
+
+	if !limited {
+		recorder.Event(object, eventtype, reason, message)
+	}
+}
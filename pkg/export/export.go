@@ -0,0 +1,364 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package export collects the GCE resources the controller manages for an
+// Ingress or an L4 Service into a snapshot, serialised as stable YAML which
+// can be diffed over time for audits and disaster recovery.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/networking/v1"
+	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/cloud-provider/service/helpers"
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/loadbalancers"
+	"k8s.io/ingress-gce/pkg/loadbalancers/features"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// KindIngress is the kind of the snapshots of Ingresses.
+	KindIngress = "Ingress"
+	// KindService is the kind of the snapshots of L4 Services.
+	KindService = "Service"
+
+	globalLocation = "global"
+)
+
+// volatileFields are the fields removed from the exported resources since
+// they change without a change of their configuration: the fingerprints on
+// every update, the size of NEGs with the endpoints and the users of
+// addresses with the forwarding rules.
+var volatileFields = []string{"fingerprint", "labelFingerprint", "size", "users"}
+
+// l4Protocols are the protocols of the L4 forwarding rules.
+var l4Protocols = []string{"tcp", "udp", "l3_default"}
+
+// Resource is an exported GCE resource.
+type Resource struct {
+	// Kind is the GCE resource type, e.g. UrlMap.
+	Kind string `json:"kind"`
+	// Name is the name of the resource.
+	Name string `json:"name"`
+	// Location is "global", a region or a zone.
+	Location string `json:"location"`
+	// Spec is the resource as returned by the GCE API, without its
+	// volatile fields.
+	Spec map[string]interface{} `json:"spec"`
+}
+
+// Snapshot is the set of GCE resources of an Ingress or an L4 Service.
+type Snapshot struct {
+	Kind      string     `json:"kind"`
+	Namespace string     `json:"namespace"`
+	Name      string     `json:"name"`
+	Resources []Resource `json:"resources"`
+}
+
+// YAML returns the snapshot as YAML. Resources are sorted by kind, location
+// and name so that snapshots of the same configuration are identical.
+func (s *Snapshot) YAML() ([]byte, error) {
+	sort.Slice(s.Resources, func(i, j int) bool {
+		a, b := s.Resources[i], s.Resources[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		return a.Name < b.Name
+	})
+	return yaml.Marshal(s)
+}
+
+// Exporter collects the GCE resources of Ingresses and L4 Services using the
+// namers of the controllers.
+type Exporter struct {
+	cloud        *gce.Cloud
+	clusterNamer *namer.Namer
+	namerFactory namer.IngressFrontendNamerFactory
+	l4Namer      namer.L4ResourcesNamer
+	logger       klog.Logger
+}
+
+// NewExporter returns an Exporter.
+func NewExporter(cloud *gce.Cloud, clusterNamer *namer.Namer, namerFactory namer.IngressFrontendNamerFactory, l4Namer namer.L4ResourcesNamer, logger klog.Logger) *Exporter {
+	return &Exporter{
+		cloud:        cloud,
+		clusterNamer: clusterNamer,
+		namerFactory: namerFactory,
+		l4Namer:      l4Namer,
+		logger:       logger.WithName("Exporter"),
+	}
+}
+
+// Ingress returns the snapshot of the load balancer of an Ingress: its
+// forwarding rules, static IP, target proxies, certificates, URL maps,
+// backend services with their health checks and NEGs, and the L7 firewall
+// of the cluster. Instance groups are shared by all the Ingresses of the
+// cluster and are not exported.
+func (e *Exporter) Ingress(ing *v1.Ingress) (*Snapshot, error) {
+	feNamer := e.namerFactory.Namer(ing)
+	versions := features.VersionsFromIngress(ing)
+	c := e.newCollector(KindIngress, ing.Namespace, ing.Name, features.ScopeFromIngress(ing))
+
+	for _, protocol := range []namer.NamerProtocol{namer.HTTPProtocol, namer.HTTPSProtocol} {
+		key, err := c.key(feNamer.ForwardingRule(protocol))
+		if err != nil {
+			return nil, err
+		}
+		fr, err := composite.GetForwardingRule(e.cloud, key, versions.ForwardingRule, e.logger)
+		if err := c.add("ForwardingRule", key, fr, err); err != nil {
+			return nil, err
+		}
+	}
+	// The static IP reserved by the controller has the name of the HTTP
+	// forwarding rule.
+	key, err := c.key(feNamer.ForwardingRule(namer.HTTPProtocol))
+	if err != nil {
+		return nil, err
+	}
+	addr, err := composite.GetAddress(e.cloud, key, meta.VersionGA, e.logger)
+	if err := c.add("Address", key, addr, err); err != nil {
+		return nil, err
+	}
+
+	if key, err = c.key(feNamer.TargetProxy(namer.HTTPProtocol)); err != nil {
+		return nil, err
+	}
+	tp, err := composite.GetTargetHttpProxy(e.cloud, key, versions.TargetHttpProxy, e.logger)
+	if err := c.add("TargetHttpProxy", key, tp, err); err != nil {
+		return nil, err
+	}
+	if key, err = c.key(feNamer.TargetProxy(namer.HTTPSProtocol)); err != nil {
+		return nil, err
+	}
+	tps, err := composite.GetTargetHttpsProxy(e.cloud, key, versions.TargetHttpsProxy, e.logger)
+	if err := c.add("TargetHttpsProxy", key, tps, err); err != nil {
+		return nil, err
+	}
+	if err == nil {
+		for _, link := range tps.SslCertificates {
+			key, err := linkKey(link)
+			if err != nil {
+				return nil, err
+			}
+			cert, err := composite.GetSslCertificate(e.cloud, key, versions.SslCertificate, e.logger)
+			if err := c.add("SslCertificate", key, cert, err); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if redirectName, ok := feNamer.RedirectUrlMap(); ok {
+		if key, err = c.key(redirectName); err != nil {
+			return nil, err
+		}
+		redirect, err := composite.GetUrlMap(e.cloud, key, versions.UrlMap, e.logger)
+		if err := c.add("UrlMap", key, redirect, err); err != nil {
+			return nil, err
+		}
+	}
+	if key, err = c.key(feNamer.UrlMap()); err != nil {
+		return nil, err
+	}
+	um, err := composite.GetUrlMap(e.cloud, key, versions.UrlMap, e.logger)
+	if err := c.add("UrlMap", key, um, err); err != nil {
+		return nil, err
+	}
+	if err == nil {
+		names, err := loadbalancers.GetBackendNames(um)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if err := c.addBackendService(name, versions.BackendService, versions.HealthCheck); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := c.addFirewall(e.clusterNamer.FirewallRule()); err != nil {
+		return nil, err
+	}
+	return c.snapshot, nil
+}
+
+// Service returns the snapshot of the load balancer of an L4 Service: its
+// forwarding rules, backend service with its health checks and NEGs, and its
+// firewalls.
+func (e *Exporter) Service(svc *corev1.Service) (*Snapshot, error) {
+	c := e.newCollector(KindService, svc.Namespace, svc.Name, meta.Regional)
+
+	for _, protocol := range l4Protocols {
+		for _, name := range []string{
+			e.l4Namer.L4ForwardingRule(svc.Namespace, svc.Name, protocol),
+			e.l4Namer.L4IPv6ForwardingRule(svc.Namespace, svc.Name, protocol),
+		} {
+			key, err := c.key(name)
+			if err != nil {
+				return nil, err
+			}
+			fr, err := composite.GetForwardingRule(e.cloud, key, meta.VersionGA, e.logger)
+			if err := c.add("ForwardingRule", key, fr, err); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := c.addBackendService(e.l4Namer.L4Backend(svc.Namespace, svc.Name), meta.VersionGA, meta.VersionGA); err != nil {
+		return nil, err
+	}
+
+	sharedHC := !helpers.RequestsOnlyLocalTraffic(svc)
+	for _, name := range []string{
+		e.l4Namer.L4Firewall(svc.Namespace, svc.Name),
+		e.l4Namer.L4IPv6Firewall(svc.Namespace, svc.Name),
+		e.l4Namer.L4HealthCheckFirewall(svc.Namespace, svc.Name, sharedHC),
+		e.l4Namer.L4IPv6HealthCheckFirewall(svc.Namespace, svc.Name, sharedHC),
+	} {
+		if err := c.addFirewall(name); err != nil {
+			return nil, err
+		}
+	}
+	return c.snapshot, nil
+}
+
+// collector adds the resources of a load balancer to a snapshot.
+type collector struct {
+	cloud    *gce.Cloud
+	scope    meta.KeyType
+	logger   klog.Logger
+	snapshot *Snapshot
+	seen     map[string]bool
+}
+
+func (e *Exporter) newCollector(kind, namespace, name string, scope meta.KeyType) *collector {
+	return &collector{
+		cloud:    e.cloud,
+		scope:    scope,
+		logger:   e.logger,
+		snapshot: &Snapshot{Kind: kind, Namespace: namespace, Name: name, Resources: []Resource{}},
+		seen:     map[string]bool{},
+	}
+}
+
+// key returns the key of a resource of the load balancer.
+func (c *collector) key(name string) (*meta.Key, error) {
+	return composite.CreateKey(c.cloud, name, c.scope)
+}
+
+// add adds a resource returned by a getter to the snapshot. Resources which
+// do not exist are skipped.
+func (c *collector) add(kind string, key *meta.Key, obj interface{}, err error) error {
+	if utils.IsNotFoundError(err) {
+		c.logger.V(4).Info("Resource not found, skipping its export", "kind", kind, "key", key)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %s %s: %w", kind, key, err)
+	}
+
+	location := globalLocation
+	switch key.Type() {
+	case meta.Regional:
+		location = key.Region
+	case meta.Zonal:
+		location = key.Zone
+	}
+	id := strings.Join([]string{kind, location, key.Name}, "/")
+	if c.seen[id] {
+		return nil
+	}
+	c.seen[id] = true
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	var spec map[string]interface{}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return err
+	}
+	for _, field := range volatileFields {
+		delete(spec, field)
+	}
+	c.snapshot.Resources = append(c.snapshot.Resources, Resource{Kind: kind, Name: key.Name, Location: location, Spec: spec})
+	return nil
+}
+
+// addBackendService adds a backend service of the load balancer with its
+// health checks and NEGs.
+func (c *collector) addBackendService(name string, version, hcVersion meta.Version) error {
+	key, err := c.key(name)
+	if err != nil {
+		return err
+	}
+	bs, err := composite.GetBackendService(c.cloud, key, version, c.logger)
+	if err := c.add("BackendService", key, bs, err); err != nil || bs == nil {
+		return err
+	}
+	for _, link := range bs.HealthChecks {
+		key, err := linkKey(link)
+		if err != nil {
+			return err
+		}
+		hc, err := composite.GetHealthCheck(c.cloud, key, hcVersion, c.logger)
+		if err := c.add("HealthCheck", key, hc, err); err != nil {
+			return err
+		}
+	}
+	for _, backend := range bs.Backends {
+		if !strings.Contains(backend.Group, "/networkEndpointGroups/") {
+			continue
+		}
+		key, err := linkKey(backend.Group)
+		if err != nil {
+			return err
+		}
+		neg, err := composite.GetNetworkEndpointGroup(c.cloud, key, meta.VersionGA, c.logger)
+		if err := c.add("NetworkEndpointGroup", key, neg, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addFirewall adds a firewall of the load balancer.
+func (c *collector) addFirewall(name string) error {
+	fw, err := c.cloud.GetFirewall(name)
+	return c.add("Firewall", meta.GlobalKey(name), fw, err)
+}
+
+// linkKey returns the key of the resource of a link.
+func linkKey(link string) (*meta.Key, error) {
+	id, err := cloud.ParseResourceURL(link)
+	if err != nil {
+		return nil, err
+	}
+	return id.Key, nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/compute/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/utils/common"
+	"k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/klog/v2"
+)
+
+const kubeSystemUID = "ksuid123"
+
+func newExporter(t *testing.T) (*Exporter, *gce.Cloud) {
+	t.Helper()
+	cloud := gce.NewFakeGCECloud(gce.DefaultTestClusterValues())
+	clusterNamer := namer.NewNamer("uid1", "fw1", klog.TODO())
+	namerFactory := namer.NewFrontendNamerFactory(clusterNamer, kubeSystemUID, klog.TODO())
+	return NewExporter(cloud, clusterNamer, namerFactory, namer.NewL4Namer(kubeSystemUID, clusterNamer), klog.TODO()), cloud
+}
+
+// createBackendService creates a backend service with a health check and a
+// NEG, and returns its link.
+func createBackendService(t *testing.T, cloud *gce.Cloud, key *meta.Key) string {
+	t.Helper()
+	hcKey := *key
+	if err := composite.CreateHealthCheck(cloud, &hcKey, &composite.HealthCheck{Name: key.Name, Type: "HTTP", Version: meta.VersionGA}, klog.TODO()); err != nil {
+		t.Fatalf("CreateHealthCheck() = %v", err)
+	}
+	hc, err := composite.GetHealthCheck(cloud, &hcKey, meta.VersionGA, klog.TODO())
+	if err != nil {
+		t.Fatalf("GetHealthCheck() = %v", err)
+	}
+	negKey := meta.ZonalKey(key.Name, "us-central1-b")
+	if err := composite.CreateNetworkEndpointGroup(cloud, negKey, &composite.NetworkEndpointGroup{Name: key.Name, Size: 3, Version: meta.VersionGA}, klog.TODO()); err != nil {
+		t.Fatalf("CreateNetworkEndpointGroup() = %v", err)
+	}
+	neg, err := composite.GetNetworkEndpointGroup(cloud, negKey, meta.VersionGA, klog.TODO())
+	if err != nil {
+		t.Fatalf("GetNetworkEndpointGroup() = %v", err)
+	}
+	bs := &composite.BackendService{
+		Name:         key.Name,
+		HealthChecks: []string{hc.SelfLink},
+		Backends:     []*composite.Backend{{Group: neg.SelfLink}},
+		Fingerprint:  "abc",
+		Version:      meta.VersionGA,
+	}
+	if err := composite.CreateBackendService(cloud, key, bs, klog.TODO()); err != nil {
+		t.Fatalf("CreateBackendService() = %v", err)
+	}
+	bs, err = composite.GetBackendService(cloud, key, meta.VersionGA, klog.TODO())
+	if err != nil {
+		t.Fatalf("GetBackendService() = %v", err)
+	}
+	return bs.SelfLink
+}
+
+// resourceNames returns the kind/location/name of the resources of the
+// snapshot, in order.
+func resourceNames(s *Snapshot) []string {
+	var names []string
+	for _, r := range s.Resources {
+		names = append(names, strings.Join([]string{r.Kind, r.Location, r.Name}, "/"))
+	}
+	return names
+}
+
+func TestExportIngress(t *testing.T) {
+	exporter, cloud := newExporter(t)
+	ing := &v1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "web", Finalizers: []string{common.FinalizerKeyV2}}}
+	feNamer := exporter.namerFactory.Namer(ing)
+
+	bsLink := createBackendService(t, cloud, meta.GlobalKey("k8s1-backend"))
+	umKey := meta.GlobalKey(feNamer.UrlMap())
+	if err := composite.CreateUrlMap(cloud, umKey, &composite.UrlMap{Name: umKey.Name, DefaultService: bsLink, Version: meta.VersionGA}, klog.TODO()); err != nil {
+		t.Fatalf("CreateUrlMap() = %v", err)
+	}
+	um, err := composite.GetUrlMap(cloud, umKey, meta.VersionGA, klog.TODO())
+	if err != nil {
+		t.Fatalf("GetUrlMap() = %v", err)
+	}
+	tpKey := meta.GlobalKey(feNamer.TargetProxy(namer.HTTPProtocol))
+	if err := composite.CreateTargetHttpProxy(cloud, tpKey, &composite.TargetHttpProxy{Name: tpKey.Name, UrlMap: um.SelfLink, Version: meta.VersionGA}, klog.TODO()); err != nil {
+		t.Fatalf("CreateTargetHttpProxy() = %v", err)
+	}
+	frKey := meta.GlobalKey(feNamer.ForwardingRule(namer.HTTPProtocol))
+	if err := composite.CreateForwardingRule(cloud, frKey, &composite.ForwardingRule{Name: frKey.Name, LabelFingerprint: "abc", Version: meta.VersionGA}, klog.TODO()); err != nil {
+		t.Fatalf("CreateForwardingRule() = %v", err)
+	}
+	if err := cloud.CreateFirewall(&compute.Firewall{Name: exporter.clusterNamer.FirewallRule()}); err != nil {
+		t.Fatalf("CreateFirewall() = %v", err)
+	}
+
+	snapshot, err := exporter.Ingress(ing)
+	if err != nil {
+		t.Fatalf("Ingress() = %v, want nil", err)
+	}
+	out, err := snapshot.YAML()
+	if err != nil {
+		t.Fatalf("YAML() = %v, want nil", err)
+	}
+	want := []string{
+		"BackendService/global/k8s1-backend",
+		"Firewall/global/" + exporter.clusterNamer.FirewallRule(),
+		"ForwardingRule/global/" + frKey.Name,
+		"HealthCheck/global/k8s1-backend",
+		"NetworkEndpointGroup/us-central1-b/k8s1-backend",
+		"TargetHttpProxy/global/" + tpKey.Name,
+		"UrlMap/global/" + umKey.Name,
+	}
+	if got := resourceNames(snapshot); !reflect.DeepEqual(got, want) {
+		t.Errorf("Ingress() exported %v, want %v", got, want)
+	}
+	if strings.Contains(string(out), "ingerprint") || strings.Contains(string(out), "size:") {
+		t.Errorf("YAML() = %s, want no fingerprints and NEG sizes", out)
+	}
+
+	// Snapshots of the same resources are identical.
+	again, err := exporter.Ingress(ing)
+	if err != nil {
+		t.Fatalf("Ingress() = %v, want nil", err)
+	}
+	if againOut, err := again.YAML(); err != nil || string(againOut) != string(out) {
+		t.Errorf("YAML() of a second snapshot = %s, %v, want %s", againOut, err, out)
+	}
+}
+
+func TestExportService(t *testing.T) {
+	exporter, cloud := newExporter(t)
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "lb"}}
+	bsName := exporter.l4Namer.L4Backend("ns", "lb")
+	createBackendService(t, cloud, meta.RegionalKey(bsName, cloud.Region()))
+	frName := exporter.l4Namer.L4ForwardingRule("ns", "lb", "tcp")
+	if err := composite.CreateForwardingRule(cloud, meta.RegionalKey(frName, cloud.Region()), &composite.ForwardingRule{Name: frName, Version: meta.VersionGA}, klog.TODO()); err != nil {
+		t.Fatalf("CreateForwardingRule() = %v", err)
+	}
+	if err := cloud.CreateFirewall(&compute.Firewall{Name: exporter.l4Namer.L4Firewall("ns", "lb")}); err != nil {
+		t.Fatalf("CreateFirewall() = %v", err)
+	}
+
+	snapshot, err := exporter.Service(svc)
+	if err != nil {
+		t.Fatalf("Service() = %v, want nil", err)
+	}
+	if _, err := snapshot.YAML(); err != nil {
+		t.Fatalf("YAML() = %v, want nil", err)
+	}
+	want := []string{
+		"BackendService/us-central1/" + bsName,
+		"Firewall/global/" + exporter.l4Namer.L4Firewall("ns", "lb"),
+		"ForwardingRule/us-central1/" + frName,
+		"HealthCheck/us-central1/" + bsName,
+		"NetworkEndpointGroup/us-central1-b/" + bsName,
+	}
+	if got := resourceNames(snapshot); !reflect.DeepEqual(got, want) {
+		t.Errorf("Service() exported %v, want %v", got, want)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	exporter, _ := newExporter(t)
+	ingLister := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	svcLister := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	ingLister.Add(&v1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "web"}})
+	handler := NewHandler(exporter, ingLister, svcLister, klog.TODO())
+
+	for path, wantCode := range map[string]int{
+		"/export/ingress/ns/web":  http.StatusOK,
+		"/export/ingress/ns/none": http.StatusNotFound,
+		"/export/service/ns/web":  http.StatusNotFound,
+		"/export/gateway/ns/web":  http.StatusBadRequest,
+		"/export/ingress/web":     http.StatusBadRequest,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != wantCode {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, wantCode)
+		}
+	}
+}
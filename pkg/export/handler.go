@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/networking/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// PathPrefix is the path of the export endpoint.
+const PathPrefix = "/export/"
+
+// Handler serves the snapshots of Ingresses and Services as YAML, at
+// /export/ingress/<namespace>/<name> and /export/service/<namespace>/<name>.
+type Handler struct {
+	exporter  *Exporter
+	ingLister cache.Indexer
+	svcLister cache.Indexer
+	logger    klog.Logger
+}
+
+// NewHandler returns a Handler looking up Ingresses and Services in the
+// given listers.
+func NewHandler(exporter *Exporter, ingLister, svcLister cache.Indexer, logger klog.Logger) *Handler {
+	return &Handler{exporter: exporter, ingLister: ingLister, svcLister: svcLister, logger: logger}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, PathPrefix), "/")
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		http.Error(w, fmt.Sprintf("path must be %singress/<namespace>/<name> or %sservice/<namespace>/<name>", PathPrefix, PathPrefix), http.StatusBadRequest)
+		return
+	}
+	kind, key := parts[0], parts[1]+"/"+parts[2]
+
+	var snapshot *Snapshot
+	var err error
+	switch kind {
+	case "ingress":
+		obj, exists, getErr := h.ingLister.GetByKey(key)
+		if getErr != nil || !exists {
+			http.Error(w, fmt.Sprintf("Ingress %s not found", key), http.StatusNotFound)
+			return
+		}
+		snapshot, err = h.exporter.Ingress(obj.(*v1.Ingress))
+	case "service":
+		obj, exists, getErr := h.svcLister.GetByKey(key)
+		if getErr != nil || !exists {
+			http.Error(w, fmt.Sprintf("Service %s not found", key), http.StatusNotFound)
+			return
+		}
+		snapshot, err = h.exporter.Service(obj.(*corev1.Service))
+	default:
+		http.Error(w, fmt.Sprintf("unknown kind %q, want ingress or service", kind), http.StatusBadRequest)
+		return
+	}
+
+	var out []byte
+	if err == nil {
+		out, err = snapshot.YAML()
+	}
+	if err != nil {
+		h.logger.Error(err, "Failed to export GCE resources", "kind", kind, "key", key)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	if _, err := w.Write(out); err != nil {
+		h.logger.Error(err, "Failed to write export response", "kind", kind, "key", key)
+	}
+}
@@ -35,6 +35,8 @@ import (
 	"k8s.io/ingress-gce/pkg/common/operator"
 	"k8s.io/ingress-gce/pkg/context"
 	"k8s.io/ingress-gce/pkg/controller/translator"
+	"k8s.io/ingress-gce/pkg/drift"
+	"k8s.io/ingress-gce/pkg/dryrun"
 	"k8s.io/ingress-gce/pkg/flags"
 	"k8s.io/ingress-gce/pkg/loadbalancers/features"
 	"k8s.io/ingress-gce/pkg/utils"
@@ -102,15 +104,6 @@ func NewFirewallController(
 ) *FirewallController {
 	logger = logger.WithName("FirewallController")
 	compositeFirewallPool := &compositeFirewallPool{}
-	if enableCR {
-		firewallCRPool := NewFirewallCRPool(ctx.FirewallClient, ctx.Cloud, ctx.ClusterNamer, gce.L7LoadBalancerSrcRanges(), portRanges, disableFWEnforcement, logger)
-		compositeFirewallPool.pools = append(compositeFirewallPool.pools, firewallCRPool)
-	}
-	if !disableFWEnforcement {
-		firewallPool := NewFirewallPool(ctx.Cloud, ctx.ClusterNamer, gce.L7LoadBalancerSrcRanges(), portRanges, logger)
-		compositeFirewallPool.pools = append(compositeFirewallPool.pools, firewallPool)
-	}
-
 	fwc := &FirewallController{
 		ctx:                           ctx,
 		firewallPool:                  compositeFirewallPool,
@@ -121,6 +114,14 @@ func NewFirewallController(
 		stopCh:                        stopCh,
 		logger:                        logger,
 	}
+	if enableCR {
+		firewallCRPool := NewFirewallCRPool(ctx.FirewallClient, ctx.Cloud, ctx.ClusterNamer, gce.L7LoadBalancerSrcRanges(), portRanges, disableFWEnforcement, logger)
+		compositeFirewallPool.pools = append(compositeFirewallPool.pools, firewallCRPool)
+	}
+	if !disableFWEnforcement {
+		firewallPool := NewFirewallPool(ctx.Cloud, ctx.ClusterNamer, gce.L7LoadBalancerSrcRanges(), portRanges, fwc.reportDrift, logger)
+		compositeFirewallPool.pools = append(compositeFirewallPool.pools, firewallPool)
+	}
 
 	fwc.queue = utils.NewPeriodicTaskQueue("", "firewall", fwc.sync, logger)
 
@@ -255,7 +256,7 @@ func (fwc *FirewallController) sync(key string) error {
 	// if so, then need to include nodePort ranges for firewall
 	needNodePort := false
 	for _, svcPort := range gceSvcPorts {
-		if !svcPort.NEGEnabled {
+		if !svcPort.NEGEnabled && !svcPort.InternetNEGEnabled {
 			needNodePort = true
 			break
 		}
@@ -304,6 +305,17 @@ func (fwc *FirewallController) sync(key string) error {
 	return nil
 }
 
+// reportDrift emits the out-of-band changes to the firewall rule, which is
+// shared by all the ingresses, as an event on each ingress.
+func (fwc *FirewallController) reportDrift(changes []dryrun.Change) {
+	for _, ing := range fwc.ctx.Ingresses().List() {
+		if !utils.IsGCEIngress(ing) {
+			continue
+		}
+		drift.Event(fwc.ctx.Recorder(ing.Namespace), ing, changes, true)
+	}
+}
+
 func (fwc *FirewallController) ilbFirewallSrcRange(gceIngresses []*v1.Ingress) (string, error) {
 	ilbEnabled := false
 	for _, ing := range gceIngresses {
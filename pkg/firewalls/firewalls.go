@@ -24,6 +24,9 @@ import (
 	"google.golang.org/api/compute/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/ingress-gce/pkg/drift"
+	"k8s.io/ingress-gce/pkg/dryrun"
+	"k8s.io/ingress-gce/pkg/flags"
 	"k8s.io/ingress-gce/pkg/utils"
 	namer_util "k8s.io/ingress-gce/pkg/utils/namer"
 	"k8s.io/klog/v2"
@@ -44,6 +47,12 @@ type FirewallRules struct {
 	// TODO(rramkumar): Eliminate this variable. We should just pass in
 	// all the port ranges to open with each call to Sync()
 	nodePortRanges []string
+	// lastSynced is the firewall rule written or found up to date by the
+	// last successful Sync, for drift detection.
+	lastSynced *compute.Firewall
+	// onDrift, if set, is called with the out-of-band changes to the
+	// firewall rule detected by Sync.
+	onDrift func(changes []dryrun.Change)
 
 	logger klog.Logger
 }
@@ -51,7 +60,8 @@ type FirewallRules struct {
 // NewFirewallPool creates a new firewall rule manager.
 // cloud: the cloud object implementing Firewall.
 // namer: cluster namer.
-func NewFirewallPool(cloud Firewall, namer *namer_util.Namer, l7SrcRanges []string, nodePortRanges []string, logger klog.Logger) SingleFirewallPool {
+// onDrift: if not nil, called with the out-of-band changes to the firewall rule.
+func NewFirewallPool(cloud Firewall, namer *namer_util.Namer, l7SrcRanges []string, nodePortRanges []string, onDrift func(changes []dryrun.Change), logger klog.Logger) SingleFirewallPool {
 	_, err := netset.ParseIPNets(l7SrcRanges...)
 	if err != nil {
 		klog.Fatalf("Could not parse L7 src ranges %v for firewall rule: %v", l7SrcRanges, err)
@@ -61,6 +71,7 @@ func NewFirewallPool(cloud Firewall, namer *namer_util.Namer, l7SrcRanges []stri
 		namer:          namer,
 		srcRanges:      l7SrcRanges,
 		nodePortRanges: nodePortRanges,
+		onDrift:        onDrift,
 		logger:         logger.WithName("FirewallRules"),
 	}
 }
@@ -69,7 +80,10 @@ func NewFirewallPool(cloud Firewall, namer *namer_util.Namer, l7SrcRanges []stri
 func (fr *FirewallRules) Sync(nodeNames, additionalPorts, additionalRanges []string, allowNodePort bool) error {
 	fr.logger.V(4).Info("Sync", "nodeNames", nodeNames)
 	name := fr.namer.FirewallRule()
-	existingFirewall, _ := fr.cloud.GetFirewall(name)
+	existingFirewall, err := fr.cloud.GetFirewall(name)
+	if utils.IgnoreHTTPNotFound(err) != nil {
+		return err
+	}
 
 	expectedFirewall, err := fr.buildExpectedFW(nodeNames, additionalPorts, additionalRanges, allowNodePort)
 	if err != nil {
@@ -77,18 +91,53 @@ func (fr *FirewallRules) Sync(nodeNames, additionalPorts, additionalRanges []str
 	}
 
 	if existingFirewall == nil {
+		if fr.isResync(expectedFirewall) {
+			fr.reportDrift(dryrun.Change{Operation: dryrun.OperationCreate, Resource: "Firewall", Name: name})
+		}
 		fr.logger.V(3).Info("Creating firewall rule", "firewallRuleName", name)
-		return fr.createFirewall(expectedFirewall)
+		return fr.setSynced(expectedFirewall, fr.createFirewall(expectedFirewall))
 	}
 
 	// Early return if an update is not required.
-	if equal(expectedFirewall, existingFirewall, fr.logger) {
+	changed := changedFirewallFields(expectedFirewall, existingFirewall, fr.logger)
+	if len(changed) == 0 {
 		fr.logger.V(4).Info("Firewall does not need update of ports or source ranges")
-		return nil
+		return fr.setSynced(expectedFirewall, nil)
+	}
+
+	if fr.isResync(expectedFirewall) {
+		fr.reportDrift(dryrun.Change{Operation: dryrun.OperationUpdate, Resource: "Firewall", Name: name, Fields: changed})
 	}
 
 	fr.logger.V(3).Info("Updating firewall rule", "firewallRuleName", name)
-	return fr.updateFirewall(expectedFirewall)
+	return fr.setSynced(expectedFirewall, fr.updateFirewall(expectedFirewall))
+}
+
+// isResync returns true if drift detection is enabled and the expected
+// firewall rule is the same as in the last sync, in which case the changes to
+// the firewall rule are drift.
+func (fr *FirewallRules) isResync(expected *compute.Firewall) bool {
+	return flags.F.EnableDriftDetection && fr.lastSynced != nil && equal(expected, fr.lastSynced, fr.logger)
+}
+
+// reportDrift exports the out-of-band change to the firewall rule as metrics
+// and passes it to onDrift. The change is always reverted.
+func (fr *FirewallRules) reportDrift(change dryrun.Change) {
+	fr.logger.Info("Detected out-of-band changes to firewall rule, reverting them", "firewallRuleName", change.Name, "change", change.String())
+	changes := []dryrun.Change{change}
+	drift.Observe(changes)
+	if fr.onDrift != nil {
+		fr.onDrift(changes)
+	}
+}
+
+// setSynced records f as the last synced firewall rule if err is nil. It
+// returns err.
+func (fr *FirewallRules) setSynced(f *compute.Firewall, err error) error {
+	if err == nil {
+		fr.lastSynced = f
+	}
+	return err
 }
 
 func (fr *FirewallRules) buildExpectedFW(nodeNames, additionalPorts, additionalRanges []string, allowNodePort bool) (*compute.Firewall, error) {
@@ -193,25 +242,32 @@ func (f *FirewallXPNError) Error() string {
 }
 
 func equal(expected *compute.Firewall, existing *compute.Firewall, logger klog.Logger) bool {
+	return len(changedFirewallFields(expected, existing, logger)) == 0
+}
+
+// changedFirewallFields returns the names of the fields set by the controller
+// which differ between expected and existing.
+func changedFirewallFields(expected *compute.Firewall, existing *compute.Firewall, logger klog.Logger) []string {
+	var changed []string
 	if !sets.NewString(expected.TargetTags...).Equal(sets.NewString(existing.TargetTags...)) {
 		logger.V(5).Info("Target tags", "expectedTags", expected.TargetTags, "actualTags", existing.TargetTags)
-		return false
+		changed = append(changed, "TargetTags")
 	}
 
 	expectedAllowed := allowedToStrings(expected.Allowed)
 	existingAllowed := allowedToStrings(existing.Allowed)
 	if !sets.NewString(expectedAllowed...).Equal(sets.NewString(existingAllowed...)) {
 		logger.V(5).Info("Allowed rules", "expectedAllowedRules", expectedAllowed, "actualAllowedRules", existingAllowed)
-		return false
+		changed = append(changed, "Allowed")
 	}
 
 	if !sets.NewString(expected.SourceRanges...).Equal(sets.NewString(existing.SourceRanges...)) {
 		logger.V(5).Info("Source ranges", "expectedSourceRanges", expected.SourceRanges, "actualSourceRanges", existing.SourceRanges)
-		return false
+		changed = append(changed, "SourceRanges")
 	}
 
 	// Ignore other firewall properties as the controller does not set them.
-	return true
+	return changed
 }
 
 func allowedToStrings(allowed []*compute.FirewallAllowed) []string {
@@ -23,11 +23,15 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/api/compute/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	firewallclient "k8s.io/cloud-provider-gcp/crd/client/gcpfirewall/clientset/versioned/fake"
 	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/ingress-gce/pkg/dryrun"
+	"k8s.io/ingress-gce/pkg/flags"
 	"k8s.io/ingress-gce/pkg/utils/namer"
 	"k8s.io/ingress-gce/pkg/utils/slice"
 )
@@ -42,7 +46,7 @@ func portRanges() []string {
 
 func TestFirewallPoolSync(t *testing.T) {
 	fwp := NewFakeFirewallsProvider(false, false)
-	fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), klog.TODO())
+	fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), nil, klog.TODO())
 	nodes := []string{"node-a", "node-b", "node-c"}
 	if err := fp.Sync(nodes, nil, nil, true); err != nil {
 		t.Fatal(err)
@@ -61,7 +65,7 @@ func TestFirewallPoolSync(t *testing.T) {
 func TestFirewallPoolSyncNodes(t *testing.T) {
 	fwp := NewFakeFirewallsProvider(false, false)
 	fwClient := firewallclient.NewSimpleClientset()
-	fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), klog.TODO())
+	fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), nil, klog.TODO())
 	fcrp := NewFirewallCRPool(fwClient, fwp, defaultNamer, srcRanges, portRanges(), true, klog.TODO())
 	nodes := []string{"node-a", "node-b", "node-c"}
 
@@ -94,7 +98,7 @@ func TestFirewallPoolSyncNodes(t *testing.T) {
 func TestFirewallPoolSyncSrcRanges(t *testing.T) {
 	fwp := NewFakeFirewallsProvider(false, false)
 	fwClient := firewallclient.NewSimpleClientset()
-	fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), klog.TODO())
+	fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), nil, klog.TODO())
 	fcrp := NewFirewallCRPool(fwClient, fwp, defaultNamer, srcRanges, portRanges(), true, klog.TODO())
 	nodes := []string{"node-a", "node-b", "node-c"}
 
@@ -124,10 +128,92 @@ func TestFirewallPoolSyncSrcRanges(t *testing.T) {
 	verifyFirewallCR(fwClient, ruleName, srcRanges, portRanges(), true, t)
 }
 
+func TestFirewallPoolSyncDrift(t *testing.T) {
+	flags.F.EnableDriftDetection = true
+	defer func() { flags.F.EnableDriftDetection = false }()
+	fwp := NewFakeFirewallsProvider(false, false)
+	var reported []dryrun.Change
+	fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), func(changes []dryrun.Change) { reported = append(reported, changes...) }, klog.TODO())
+	nodes := []string{"node-a", "node-b", "node-c"}
+
+	if err := fp.Sync(nodes, nil, nil, true); err != nil {
+		t.Fatal(err)
+	}
+	before := driftCount(t, "SourceRanges")
+
+	// A change of the expected state is not drift.
+	if err := fp.Sync(nodes, []string{"80"}, nil, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := driftCount(t, "SourceRanges") - before; got != 0 {
+		t.Errorf("drift count of SourceRanges increased by %v after an expected change, want 0", got)
+	}
+
+	// Manually modify source ranges.
+	f, _ := fwp.GetFirewall(ruleName)
+	f.SourceRanges = []string{"10.0.0.0/8"}
+	if err := fwp.UpdateFirewall(f); err != nil {
+		t.Fatal(err)
+	}
+	if err := fp.Sync(nodes, []string{"80"}, nil, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := driftCount(t, "SourceRanges") - before; got != 1 {
+		t.Errorf("drift count of SourceRanges increased by %v after an out-of-band change, want 1", got)
+	}
+	verifyFirewallRule(fwp, ruleName, nodes, srcRanges, append(portRanges(), "80"), t)
+
+	// Manually delete the firewall rule.
+	beforeDeleted := driftCount(t, "<deleted>")
+	if err := fwp.DeleteFirewall(ruleName); err != nil {
+		t.Fatal(err)
+	}
+	if err := fp.Sync(nodes, []string{"80"}, nil, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := driftCount(t, "<deleted>") - beforeDeleted; got != 1 {
+		t.Errorf("drift count of deleted firewall rules increased by %v after an out-of-band deletion, want 1", got)
+	}
+	verifyFirewallRule(fwp, ruleName, nodes, srcRanges, append(portRanges(), "80"), t)
+
+	want := []dryrun.Change{
+		{Operation: dryrun.OperationUpdate, Resource: "Firewall", Name: ruleName, Fields: []string{"SourceRanges"}},
+		{Operation: dryrun.OperationCreate, Resource: "Firewall", Name: ruleName},
+	}
+	if diff := cmp.Diff(want, reported); diff != "" {
+		t.Errorf("Sync() reported unexpected drift (-want +got):\n%s", diff)
+	}
+}
+
+// driftCount returns the value of the drift metric of the given field of
+// firewall rules.
+func driftCount(t *testing.T, field string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather() = %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != "gce_resource_drift_count" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["resource"] == "Firewall" && labels["field"] == field {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
 func TestFirewallPoolSyncPorts(t *testing.T) {
 	fwp := NewFakeFirewallsProvider(false, false)
 	fwClient := firewallclient.NewSimpleClientset()
-	fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), klog.TODO())
+	fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), nil, klog.TODO())
 	fcrp := NewFirewallCRPool(fwClient, fwp, defaultNamer, srcRanges, portRanges(), true, klog.TODO())
 	nodes := []string{"node-a", "node-b", "node-c"}
 
@@ -209,7 +295,7 @@ func TestFirewallPoolSyncRanges(t *testing.T) {
 		t.Run(tc.desc, func(t *testing.T) {
 			fwp := NewFakeFirewallsProvider(false, false)
 			fwClient := firewallclient.NewSimpleClientset()
-			fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), klog.TODO())
+			fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), nil, klog.TODO())
 			fcrp := NewFirewallCRPool(fwClient, fwp, defaultNamer, srcRanges, portRanges(), true, klog.TODO())
 			nodes := []string{"node-a", "node-b", "node-c"}
 
@@ -231,7 +317,7 @@ func TestFirewallPoolSyncRanges(t *testing.T) {
 func TestFirewallPoolGC(t *testing.T) {
 	fwp := NewFakeFirewallsProvider(false, false)
 	fwClient := firewallclient.NewSimpleClientset()
-	fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), klog.TODO())
+	fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), nil, klog.TODO())
 	fcrp := NewFirewallCRPool(fwClient, fwp, defaultNamer, srcRanges, portRanges(), true, klog.TODO())
 	nodes := []string{"node-a", "node-b", "node-c"}
 
@@ -267,7 +353,7 @@ func TestFirewallPoolGC(t *testing.T) {
 func TestSyncOnXPNWithPermission(t *testing.T) {
 	// Fake XPN cluster with permission
 	fwp := NewFakeFirewallsProvider(true, false)
-	fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), klog.TODO())
+	fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), nil, klog.TODO())
 	nodes := []string{"node-a", "node-b", "node-c"}
 
 	if err := fp.Sync(nodes, nil, nil, true); err != nil {
@@ -281,7 +367,7 @@ func TestSyncOnXPNWithPermission(t *testing.T) {
 // Specific errors should be returned.
 func TestSyncXPNReadOnly(t *testing.T) {
 	fwp := NewFakeFirewallsProvider(true, true)
-	fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), klog.TODO())
+	fp := NewFirewallPool(fwp, defaultNamer, srcRanges, portRanges(), nil, klog.TODO())
 	nodes := []string{"node-a", "node-b", "node-c"}
 
 	err := fp.Sync(nodes, nil, nil, true)
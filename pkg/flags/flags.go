@@ -59,11 +59,14 @@ var (
 		APIServerHost                    string
 		ASMConfigMapBasedConfigCMName    string
 		ASMConfigMapBasedConfigNamespace string
+		BackendConfigConversionService   string
+		BackendConfigConversionCAFile    string
 		ClusterName                      string
 		ConfigFilePath                   string
 		DefaultSvc                       string
 		DefaultSvcHealthCheckPath        string
 		DefaultSvcPortName               string
+		DryRun                           bool
 		GCEOperationPollInterval         time.Duration
 		GCERateLimit                     RateLimitSpecs
 		GCERateLimitScale                float64
@@ -97,7 +100,12 @@ var (
 		// Feature flags should be named Enablexxx.
 		EnableASMConfigMapBasedConfig            bool
 		EnableDeleteUnusedFrontends              bool
+		EnableDriftDetection                     bool
+		EnableResourceExport                     bool
+		ResourceExportPort                       int
 		EnableFrontendConfig                     bool
+		EnableGCSBackends                        bool
+		EnableInternetNEGs                       bool
 		EnableNonGCPMode                         bool
 		EnableReadinessReflector                 bool
 		EnableV2FrontendNamer                    bool
@@ -129,6 +137,8 @@ var (
 		EnableIngressRegionalExternal            bool
 		EnableIngressGlobalExternal              bool
 		OverrideComputeAPIEndpoint               string
+		EnableGatewayAPI                         bool
+		EnableIngressGroups                      bool
 	}{
 		GCERateLimitScale: 1.0,
 	}
@@ -193,6 +203,12 @@ form namespace/name.`)
 only the port's name - not its number.`)
 	flag.BoolVar(&F.EnableFrontendConfig, "enable-frontend-config", false,
 		`Optional, whether or not to enable FrontendConfig.`)
+	flag.BoolVar(&F.EnableGCSBackends, "enable-gcs-backends", false,
+		`Optional, whether or not to enable GCSBackends, which serve Cloud Storage buckets
+from the Ingress paths referencing them in a resource backend.`)
+	flag.BoolVar(&F.EnableInternetNEGs, "enable-internet-negs", false,
+		`Optional, whether or not to serve ExternalName Services referenced by Ingresses
+with global Internet NEGs pointing to their external name.`)
 	flag.Var(&F.GCERateLimit, "gce-ratelimit",
 		`Optional, can be used to rate limit certain GCE API calls. Example usage:
 --gce-ratelimit=ga.Addresses.Get,qps,1.5,5
@@ -295,6 +311,12 @@ L7 load balancing. CSV values accepted. Example: -node-port-ranges=80,8080,400-5
 	flag.BoolVar(&F.EnableMultipleIGs, "enable-multiple-igs", false, "Enable using multiple unmanaged instance groups")
 	flag.BoolVar(&F.EnableMultiNetworking, "enable-multi-networking", false, "Enable support for multi-networking L4 load balancers.")
 	flag.IntVar(&F.MaxIGSize, "max-ig-size", 1000, "Max number of instances in Instance Group")
+	flag.BoolVar(&F.EnableDriftDetection, "enable-drift-detection", false, `Optional, if enabled then every resync of an Ingress whose desired state did not change compares its GCE resources to the desired state and reports the differences as events and metrics. The networking.gke.io/drift-policy annotation on the Ingress chooses whether the differences are reverted.`)
+	flag.BoolVar(&F.EnableResourceExport, "enable-resource-export", false, `Optional, if enabled then the GCE resources of an Ingress or L4 Service are exported as YAML at /export/ingress/<namespace>/<name> and /export/service/<namespace>/<name> on --resource-export-port of localhost, e.g. through kubectl port-forward.`)
+	flag.IntVar(&F.ResourceExportPort, "resource-export-port", 8087, `Port of localhost the resources are exported on with --enable-resource-export. The export is not served on the healthz port since it has no authentication.`)
+	flag.StringVar(&F.BackendConfigConversionService, "backendconfig-conversion-service", "", `Optional, the <namespace>/<name> of the Service serving the admission-webhook, which then converts BackendConfigs between v1beta1 and v1. Requires --backendconfig-conversion-ca-file.`)
+	flag.StringVar(&F.BackendConfigConversionCAFile, "backendconfig-conversion-ca-file", "", `Optional, file containing the PEM encoded CA bundle the API server uses to verify the certificate of --backendconfig-conversion-service.`)
+	flag.BoolVar(&F.DryRun, "dry-run", false, `Optional, if enabled then the Ingress, L4 ILB and L4 NetLB controllers only report the GCE resources each sync would create, update or delete, as events on the Ingress or Service, without changing them. The firewall, NEG, instance group and PSC controllers, which cannot report their changes, are not started.`)
 	flag.DurationVar(&F.MetricsExportInterval, "metrics-export-interval", 10*time.Minute, `Period for calculating and exporting metrics related to state of managed objects.`)
 	flag.DurationVar(&F.NegMetricsExportInterval, "neg-metrics-export-interval", 5*time.Second, `Period for calculating and exporting internal neg controller metrics, not usage.`)
 	flag.BoolVar(&F.EnableDegradedMode, "enable-degraded-mode", false, `Enable degraded mode endpoint calculation and use results when error state is triggered. enabledDegradedMode also enables degrade mode correctness metrics with or without enabledDegradedModeMetrics.`)
@@ -306,6 +328,8 @@ L7 load balancing. CSV values accepted. Example: -node-port-ranges=80,8080,400-5
 	flag.BoolVar(&F.EnableIngressRegionalExternal, "enable-ingress-regional-external", false, "Enable L7 Ingress Regional External.")
 	flag.BoolVar(&F.EnableIngressGlobalExternal, "enable-ingress-global-external", true, "Enable L7 Ingress Global External. Should be disabled when Regional External is enabled.")
 	flag.StringVar(&F.OverrideComputeAPIEndpoint, "override-compute-api-endpoint", "", "Override endpoint that is used to communicate to GCP compute APIs.")
+	flag.BoolVar(&F.EnableGatewayAPI, "enable-gateway-api", false, "Enable the Gateway API controller, which provisions load balancers for Gateways and HTTPRoutes of the supported GatewayClasses.")
+	flag.BoolVar(&F.EnableIngressGroups, "enable-ingress-groups", false, "Enable ingress groups, which merge the Ingresses with the same networking.gke.io/ingress-group annotation into one load balancer.")
 }
 
 func Validate() {
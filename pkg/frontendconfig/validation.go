@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontendconfig
+
+import (
+	"fmt"
+	"regexp"
+
+	frontendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1"
+)
+
+var supportedRedirectResponseCodes = map[string]bool{
+	"MOVED_PERMANENTLY_DEFAULT": true,
+	"FOUND":                     true,
+	"SEE_OTHER":                 true,
+	"TEMPORARY_REDIRECT":        true,
+	"PERMANENT_REDIRECT":        true,
+}
+
+// Validate returns an error if the FrontendConfig cannot be applied to a load
+// balancer. Checks that depend on the class of the Ingress are done by the
+// translator.
+func Validate(feConfig *frontendconfigv1beta1.FrontendConfig) error {
+	if feConfig == nil {
+		return nil
+	}
+
+	if err := validateRedirectToHttps(feConfig); err != nil {
+		return err
+	}
+
+	if err := validateCorsPolicy(feConfig); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validateRedirectToHttps(feConfig *frontendconfigv1beta1.FrontendConfig) error {
+	redirect := feConfig.Spec.RedirectToHttps
+	if redirect == nil || redirect.ResponseCodeName == "" {
+		return nil
+	}
+
+	if !supportedRedirectResponseCodes[redirect.ResponseCodeName] {
+		return fmt.Errorf("unsupported ResponseCodeName: %s, should be one of MOVED_PERMANENTLY_DEFAULT, FOUND, SEE_OTHER, TEMPORARY_REDIRECT or PERMANENT_REDIRECT",
+			redirect.ResponseCodeName)
+	}
+	return nil
+}
+
+func validateCorsPolicy(feConfig *frontendconfigv1beta1.FrontendConfig) error {
+	cors := feConfig.Spec.CorsPolicy
+	if cors == nil {
+		return nil
+	}
+
+	if cors.MaxAge < 0 {
+		return fmt.Errorf("unsupported CorsPolicy MaxAge: %d, should not be negative", cors.MaxAge)
+	}
+
+	for _, re := range cors.AllowOriginRegexes {
+		if _, err := regexp.Compile(re); err != nil {
+			return fmt.Errorf("invalid CorsPolicy AllowOriginRegexes %q: %v", re, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontendconfig
+
+import (
+	"testing"
+
+	frontendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		spec        frontendconfigv1beta1.FrontendConfigSpec
+		expectError bool
+	}{
+		{
+			desc: "empty spec",
+		},
+		{
+			desc: "redirect without response code",
+			spec: frontendconfigv1beta1.FrontendConfigSpec{
+				RedirectToHttps: &frontendconfigv1beta1.HttpsRedirectConfig{Enabled: true},
+			},
+		},
+		{
+			desc: "redirect with supported response code",
+			spec: frontendconfigv1beta1.FrontendConfigSpec{
+				RedirectToHttps: &frontendconfigv1beta1.HttpsRedirectConfig{Enabled: true, ResponseCodeName: "PERMANENT_REDIRECT"},
+			},
+		},
+		{
+			desc: "redirect with unsupported response code",
+			spec: frontendconfigv1beta1.FrontendConfigSpec{
+				RedirectToHttps: &frontendconfigv1beta1.HttpsRedirectConfig{Enabled: true, ResponseCodeName: "301"},
+			},
+			expectError: true,
+		},
+		{
+			desc: "valid cors policy",
+			spec: frontendconfigv1beta1.FrontendConfigSpec{
+				CorsPolicy: &frontendconfigv1beta1.CorsPolicy{
+					AllowOriginRegexes: []string{`https://.*\.example\.com`},
+					MaxAge:             3600,
+				},
+			},
+		},
+		{
+			desc: "cors policy with negative max age",
+			spec: frontendconfigv1beta1.FrontendConfigSpec{
+				CorsPolicy: &frontendconfigv1beta1.CorsPolicy{MaxAge: -1},
+			},
+			expectError: true,
+		},
+		{
+			desc: "cors policy with invalid origin regex",
+			spec: frontendconfigv1beta1.FrontendConfigSpec{
+				CorsPolicy: &frontendconfigv1beta1.CorsPolicy{AllowOriginRegexes: []string{"https://(foo"}},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := Validate(&frontendconfigv1beta1.FrontendConfig{Spec: tc.spec})
+			if gotError := err != nil; gotError != tc.expectError {
+				t.Errorf("Validate() = %v, expectError = %t", err, tc.expectError)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// eventRecorder records the events about the Ingresses synthesized for
+// Gateways on the Gateways, since the synthesized Ingresses are never
+// persisted.
+type eventRecorder struct {
+	record.EventRecorder
+}
+
+// NewEventRecorder wraps the recorder so that the events about the Ingress
+// synthesized for a Gateway are recorded on the Gateway.
+func NewEventRecorder(rec record.EventRecorder) record.EventRecorder {
+	return &eventRecorder{EventRecorder: rec}
+}
+
+func (r *eventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.EventRecorder.Event(eventObject(object), eventtype, reason, message)
+}
+
+func (r *eventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.Eventf(eventObject(object), eventtype, reason, messageFmt, args...)
+}
+
+func (r *eventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.AnnotatedEventf(eventObject(object), annotations, eventtype, reason, messageFmt, args...)
+}
+
+// eventObject returns a reference to the Gateway if the object is the Ingress
+// synthesized for it, and the object otherwise.
+func eventObject(object runtime.Object) runtime.Object {
+	ing, ok := object.(*v1.Ingress)
+	if !ok {
+		return object
+	}
+	key, ok := GatewayForIngress(ing)
+	if !ok {
+		return object
+	}
+	return &apiv1.ObjectReference{
+		APIVersion: GatewayGVR.GroupVersion().String(),
+		Kind:       KindGateway,
+		Namespace:  key.Namespace,
+		Name:       key.Name,
+		// The synthesized Ingress has the UID of the Gateway.
+		UID: ing.UID,
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestEventRecorder(t *testing.T) {
+	synthesized, _ := ToIngress(testGateway(), nil)
+	ing := &v1.Ingress{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ing"},
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		obj      runtime.Object
+		wantKind string
+	}{
+		{desc: "synthesized ingress", obj: synthesized, wantKind: "involvedObject{kind=Gateway,apiVersion=gateway.networking.k8s.io/v1}"},
+		{desc: "ingress", obj: ing, wantKind: "involvedObject{kind=Ingress,apiVersion=networking.k8s.io/v1}"},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			fake := record.NewFakeRecorder(1)
+			fake.IncludeObject = true
+			NewEventRecorder(fake).Eventf(tc.obj, "Normal", "Sync", "synced %s", "ok")
+			if got := <-fake.Events; !strings.Contains(got, tc.wantKind) {
+				t.Errorf("Eventf() recorded %q, want %s", got, tc.wantKind)
+			}
+		})
+	}
+}
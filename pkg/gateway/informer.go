@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NewGatewayInformer constructs an informer for Gateways. Objects in the
+// informer cache are *unstructured.Unstructured.
+func NewGatewayInformer(client dynamic.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return dynamicinformer.NewFilteredDynamicInformer(client, GatewayGVR, namespace, resyncPeriod, indexers, nil).Informer()
+}
+
+// NewHTTPRouteInformer constructs an informer for HTTPRoutes. Objects in the
+// informer cache are *unstructured.Unstructured.
+func NewHTTPRouteInformer(client dynamic.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return dynamicinformer.NewFilteredDynamicInformer(client, HTTPRouteGVR, namespace, resyncPeriod, indexers, nil).Informer()
+}
+
+// ParentGatewayIndex is the name of the index of the HTTPRoutes by the keys
+// of the Gateways they are attached to or report a status for.
+const ParentGatewayIndex = "parentGateway"
+
+// ParentGatewayIndexFunc indexes HTTPRoutes by the keys of the Gateways of
+// their parent references, and of the Gateways of this controller in their
+// status. The latter keeps the routes detached from a Gateway indexed until
+// the Gateway removes itself from their status.
+func ParentGatewayIndexFunc(obj interface{}) ([]string, error) {
+	route, err := HTTPRouteFromUnstructured(obj)
+	if err != nil {
+		// The indexer panics on errors. Routes which cannot be decoded are
+		// not indexed, and are reported by the event handlers.
+		return nil, nil
+	}
+	keys := sets.NewString(ParentGateways(route)...)
+	for _, p := range route.Status.Parents {
+		if p.ControllerName != ControllerName {
+			continue
+		}
+		if key, ok := parentGatewayKey(p.ParentRef, route.Namespace); ok {
+			keys.Insert(key)
+		}
+	}
+	return keys.List(), nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParentGatewayIndexFunc(t *testing.T) {
+	for _, tc := range []struct {
+		desc  string
+		route func() *HTTPRoute
+		want  []string
+	}{
+		{
+			desc:  "parent in the namespace of the route",
+			route: func() *HTTPRoute { return testRoute("route") },
+			want:  []string{"default/gw"},
+		},
+		{
+			desc: "parents in other namespaces and of other kinds",
+			route: func() *HTTPRoute {
+				route := testRoute("route")
+				route.Spec.ParentRefs = []ParentReference{
+					{Namespace: strPtr("infra"), Name: "gw"},
+					{Kind: strPtr("Service"), Name: "svc"},
+				}
+				return route
+			},
+			want: []string{"infra/gw"},
+		},
+		{
+			desc: "detached route with a status for the gateway",
+			route: func() *HTTPRoute {
+				route := testRoute("route")
+				route.Spec.ParentRefs = nil
+				route.Status.Parents = []RouteParentStatus{
+					{ParentRef: ParentReference{Namespace: strPtr("infra"), Name: "gw"}, ControllerName: ControllerName},
+					{ParentRef: ParentReference{Name: "other-gw"}, ControllerName: "example.com/other"},
+				}
+				return route
+			},
+			want: []string{"infra/gw"},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			data, err := json.Marshal(tc.route())
+			if err != nil {
+				t.Fatalf("json.Marshal() = %v", err)
+			}
+			u := &unstructured.Unstructured{}
+			if err := json.Unmarshal(data, &u.Object); err != nil {
+				t.Fatalf("json.Unmarshal() = %v", err)
+			}
+			got, err := ParentGatewayIndexFunc(u)
+			if err != nil {
+				t.Fatalf("ParentGatewayIndexFunc() = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ParentGatewayIndexFunc() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/ingress-gce/pkg/utils"
+)
+
+const (
+	// ControllerName identifies the controller in the parent statuses of
+	// HTTPRoutes.
+	ControllerName = "networking.gke.io/gateway"
+
+	// ConditionAccepted indicates whether the controller accepted the object.
+	ConditionAccepted = "Accepted"
+	// ConditionProgrammed indicates whether the load balancer serves the object.
+	ConditionProgrammed = "Programmed"
+
+	ReasonAccepted         = "Accepted"
+	ReasonProgrammed       = "Programmed"
+	ReasonInvalid          = "Invalid"
+	ReasonPending          = "Pending"
+	ReasonUnsupportedValue = "UnsupportedValue"
+
+	GatewayReasonListenersNotValid  = "ListenersNotValid"
+	GatewayReasonUnsupportedAddress = "UnsupportedAddress"
+	GatewayReasonAddressNotAssigned = "AddressNotAssigned"
+
+	ListenerReasonPortUnavailable     = "PortUnavailable"
+	ListenerReasonUnsupportedProtocol = "UnsupportedProtocol"
+
+	RouteReasonNotAllowedByListeners      = "NotAllowedByListeners"
+	RouteReasonNoMatchingParent           = "NoMatchingParent"
+	RouteReasonNoMatchingListenerHostname = "NoMatchingListenerHostname"
+)
+
+// GatewayStatusFor returns the status of the Gateway after a sync. syncErr is
+// the error of the sync, if any, and ip the address of the load balancer if it
+// has one. The transition times of unchanged conditions are kept.
+func GatewayStatusFor(gw *Gateway, routes []*HTTPRoute, syncErr error, ip string) *GatewayStatus {
+	status := &GatewayStatus{
+		Addresses:  gw.Status.Addresses,
+		Conditions: copyConditions(gw.Status.Conditions),
+	}
+	if ip != "" {
+		addrType := IPAddressType
+		status.Addresses = []GatewayStatusAddress{{Type: &addrType, Value: ip}}
+	}
+
+	accepted := newCondition(gw.Generation, ConditionAccepted, metav1.ConditionTrue, ReasonAccepted, "")
+	class, ok := IngressClass(gw)
+	if !ok {
+		class = gw.Spec.GatewayClassName
+	}
+	var listenerErrs []error
+	for _, l := range gw.Spec.Listeners {
+		if _, err := validateListener(gw, l); err != nil {
+			listenerErrs = append(listenerErrs, err)
+		}
+	}
+	addrErrs := translateAddresses(gw, &v1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}, class)
+	switch {
+	case len(gw.Spec.Listeners) == 0:
+		accepted = newCondition(gw.Generation, ConditionAccepted, metav1.ConditionFalse, ReasonInvalid, "gateway must have at least one listener")
+	case len(addrErrs) > 0:
+		accepted = newCondition(gw.Generation, ConditionAccepted, metav1.ConditionFalse, GatewayReasonUnsupportedAddress, utils.JoinErrs(addrErrs).Error())
+	case len(listenerErrs) > 0:
+		accepted = newCondition(gw.Generation, ConditionAccepted, metav1.ConditionFalse, GatewayReasonListenersNotValid, utils.JoinErrs(listenerErrs).Error())
+	}
+	programmed := newCondition(gw.Generation, ConditionProgrammed, metav1.ConditionTrue, ReasonProgrammed, "")
+	switch {
+	case accepted.Status != metav1.ConditionTrue:
+		programmed = newCondition(gw.Generation, ConditionProgrammed, metav1.ConditionFalse, ReasonInvalid, accepted.Message)
+	case syncErr != nil:
+		programmed = newCondition(gw.Generation, ConditionProgrammed, metav1.ConditionFalse, ReasonPending, syncErr.Error())
+	case ip == "":
+		programmed = newCondition(gw.Generation, ConditionProgrammed, metav1.ConditionFalse, GatewayReasonAddressNotAssigned, "the load balancer has no address yet")
+	}
+	meta.SetStatusCondition(&status.Conditions, accepted)
+	meta.SetStatusCondition(&status.Conditions, programmed)
+
+	oldListeners := map[string]ListenerStatus{}
+	for _, ls := range gw.Status.Listeners {
+		oldListeners[ls.Name] = ls
+	}
+	group := GroupName
+	for _, l := range gw.Spec.Listeners {
+		ls := ListenerStatus{
+			Name:           l.Name,
+			SupportedKinds: []RouteGroupKind{{Group: &group, Kind: KindHTTPRoute}},
+			Conditions:     copyConditions(oldListeners[l.Name].Conditions),
+		}
+		listenerAccepted := newCondition(gw.Generation, ConditionAccepted, metav1.ConditionTrue, ReasonAccepted, "")
+		listenerProgrammed := newCondition(gw.Generation, ConditionProgrammed, programmed.Status, programmed.Reason, programmed.Message)
+		if reason, err := validateListener(gw, l); err != nil {
+			listenerAccepted = newCondition(gw.Generation, ConditionAccepted, metav1.ConditionFalse, reason, err.Error())
+			listenerProgrammed = newCondition(gw.Generation, ConditionProgrammed, metav1.ConditionFalse, ReasonInvalid, err.Error())
+		} else {
+			for _, route := range routes {
+				if attachesToListener(gw, route, l) {
+					ls.AttachedRoutes++
+				}
+			}
+		}
+		meta.SetStatusCondition(&ls.Conditions, listenerAccepted)
+		meta.SetStatusCondition(&ls.Conditions, listenerProgrammed)
+		status.Listeners = append(status.Listeners, ls)
+	}
+	return status
+}
+
+// RouteParentStatusesFor returns the parent statuses of the route after a sync
+// of the Gateway, given the status computed for the Gateway. The statuses of
+// the references to other parents, or written by other controllers, are kept
+// in place.
+func RouteParentStatusesFor(route *HTTPRoute, gw *Gateway, gwStatus *GatewayStatus) []RouteParentStatus {
+	oldConditions := map[string][]metav1.Condition{}
+	for _, p := range route.Status.Parents {
+		if p.ControllerName == ControllerName && refersTo(p.ParentRef, route.Namespace, gw) {
+			oldConditions[sectionName(p.ParentRef)] = p.Conditions
+		}
+	}
+
+	gwProgrammed := meta.FindStatusCondition(gwStatus.Conditions, ConditionProgrammed)
+	var keys []string
+	computed := map[string]RouteParentStatus{}
+	for _, ref := range route.Spec.ParentRefs {
+		key := sectionName(ref)
+		if _, ok := computed[key]; ok || !refersTo(ref, route.Namespace, gw) {
+			continue
+		}
+		p := RouteParentStatus{
+			ParentRef:      ref,
+			ControllerName: ControllerName,
+			Conditions:     copyConditions(oldConditions[key]),
+		}
+		accepted := routeAcceptedCondition(gw, route, ref)
+		programmed := newCondition(route.Generation, ConditionProgrammed, metav1.ConditionFalse, ReasonInvalid, accepted.Message)
+		if accepted.Status == metav1.ConditionTrue && gwProgrammed != nil {
+			programmed = newCondition(route.Generation, ConditionProgrammed, gwProgrammed.Status, gwProgrammed.Reason, gwProgrammed.Message)
+		}
+		meta.SetStatusCondition(&p.Conditions, accepted)
+		meta.SetStatusCondition(&p.Conditions, programmed)
+		keys = append(keys, key)
+		computed[key] = p
+	}
+
+	// The statuses are updated in place, so that the Gateways a route is
+	// attached to do not reorder the statuses of each other.
+	var parents []RouteParentStatus
+	written := map[string]bool{}
+	for _, p := range route.Status.Parents {
+		if p.ControllerName != ControllerName || !refersTo(p.ParentRef, route.Namespace, gw) {
+			parents = append(parents, p)
+			continue
+		}
+		key := sectionName(p.ParentRef)
+		if newStatus, ok := computed[key]; ok && !written[key] {
+			parents = append(parents, newStatus)
+			written[key] = true
+		}
+	}
+	for _, key := range keys {
+		if !written[key] {
+			parents = append(parents, computed[key])
+		}
+	}
+	return parents
+}
+
+// routeAcceptedCondition returns the Accepted condition of the route for a
+// parent reference to the Gateway.
+func routeAcceptedCondition(gw *Gateway, route *HTTPRoute, ref ParentReference) metav1.Condition {
+	if route.Namespace != gw.Namespace {
+		return newCondition(route.Generation, ConditionAccepted, metav1.ConditionFalse, RouteReasonNotAllowedByListeners, "routes in other namespaces than the gateway are not supported")
+	}
+	listeners := refListeners(gw, route, ref)
+	if len(listeners) == 0 {
+		msg := "the gateway has no valid listener"
+		if ref.SectionName != nil {
+			msg = fmt.Sprintf("the gateway has no valid listener named %q", *ref.SectionName)
+		}
+		return newCondition(route.Generation, ConditionAccepted, metav1.ConditionFalse, RouteReasonNoMatchingParent, msg)
+	}
+	if len(listenerHostnames(listeners, route)) == 0 {
+		return newCondition(route.Generation, ConditionAccepted, metav1.ConditionFalse, RouteReasonNoMatchingListenerHostname, "no hostname of the route matches the hostnames of the listeners")
+	}
+	var errs []string
+	for i, rule := range route.Spec.Rules {
+		if _, err := translateRouteRule(rule); err != nil {
+			errs = append(errs, fmt.Sprintf("rule %d: %v", i, err))
+		}
+	}
+	if len(errs) > 0 {
+		return newCondition(route.Generation, ConditionAccepted, metav1.ConditionFalse, ReasonUnsupportedValue, strings.Join(errs, "; "))
+	}
+	return newCondition(route.Generation, ConditionAccepted, metav1.ConditionTrue, ReasonAccepted, "")
+}
+
+// attachesToListener returns true if the route serves at least one hostname
+// on the listener.
+func attachesToListener(gw *Gateway, route *HTTPRoute, l Listener) bool {
+	for _, parent := range parentListeners(gw, route) {
+		if parent.Name == l.Name {
+			return len(listenerHostnames([]Listener{l}, route)) > 0
+		}
+	}
+	return false
+}
+
+// sectionName returns the section name of the parent reference, or the empty
+// string if it has none.
+func sectionName(ref ParentReference) string {
+	if ref.SectionName == nil {
+		return ""
+	}
+	return *ref.SectionName
+}
+
+func newCondition(generation int64, condType string, status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:               condType,
+		Status:             status,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	}
+}
+
+// copyConditions returns a copy of the conditions, so that the conditions
+// can be updated and compared with the original ones.
+func copyConditions(conditions []metav1.Condition) []metav1.Condition {
+	return append([]metav1.Condition(nil), conditions...)
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// conditionReasons returns the status and reason of the conditions by type.
+func conditionReasons(conditions []metav1.Condition) map[string]string {
+	ret := map[string]string{}
+	for _, c := range conditions {
+		ret[c.Type] = fmt.Sprintf("%s/%s", c.Status, c.Reason)
+	}
+	return ret
+}
+
+func TestGatewayStatusFor(t *testing.T) {
+	route := testRoute("route", HTTPRouteRule{BackendRefs: []HTTPBackendRef{backendRef("app", 1)}})
+	otherRoute := testRoute("other", HTTPRouteRule{BackendRefs: []HTTPBackendRef{backendRef("app", 1)}})
+	otherRoute.Spec.ParentRefs[0].Name = "other-gw"
+
+	for _, tc := range []struct {
+		desc               string
+		gw                 *Gateway
+		syncErr            error
+		ip                 string
+		wantConditions     map[string]string
+		wantListeners      map[string]map[string]string
+		wantAttachedRoutes int32
+	}{
+		{
+			desc: "programmed",
+			gw:   testGateway(),
+			ip:   "1.2.3.4",
+			wantConditions: map[string]string{
+				ConditionAccepted:   "True/" + ReasonAccepted,
+				ConditionProgrammed: "True/" + ReasonProgrammed,
+			},
+			wantListeners: map[string]map[string]string{
+				"http": {ConditionAccepted: "True/" + ReasonAccepted, ConditionProgrammed: "True/" + ReasonProgrammed},
+			},
+			wantAttachedRoutes: 1,
+		},
+		{
+			desc: "no address yet",
+			gw:   testGateway(),
+			wantConditions: map[string]string{
+				ConditionAccepted:   "True/" + ReasonAccepted,
+				ConditionProgrammed: "False/" + GatewayReasonAddressNotAssigned,
+			},
+			wantListeners: map[string]map[string]string{
+				"http": {ConditionAccepted: "True/" + ReasonAccepted, ConditionProgrammed: "False/" + GatewayReasonAddressNotAssigned},
+			},
+			wantAttachedRoutes: 1,
+		},
+		{
+			desc:    "sync error",
+			gw:      testGateway(),
+			syncErr: fmt.Errorf("quota exceeded"),
+			ip:      "1.2.3.4",
+			wantConditions: map[string]string{
+				ConditionAccepted:   "True/" + ReasonAccepted,
+				ConditionProgrammed: "False/" + ReasonPending,
+			},
+			wantListeners: map[string]map[string]string{
+				"http": {ConditionAccepted: "True/" + ReasonAccepted, ConditionProgrammed: "False/" + ReasonPending},
+			},
+			wantAttachedRoutes: 1,
+		},
+		{
+			desc: "invalid listener",
+			gw: func() *Gateway {
+				gw := testGateway()
+				gw.Spec.Listeners = append(gw.Spec.Listeners, Listener{Name: "tcp", Port: 8080, Protocol: "TCP"})
+				return gw
+			}(),
+			syncErr: fmt.Errorf("invalid gateway spec"),
+			wantConditions: map[string]string{
+				ConditionAccepted:   "False/" + GatewayReasonListenersNotValid,
+				ConditionProgrammed: "False/" + ReasonInvalid,
+			},
+			wantListeners: map[string]map[string]string{
+				"http": {ConditionAccepted: "True/" + ReasonAccepted, ConditionProgrammed: "False/" + ReasonInvalid},
+				"tcp":  {ConditionAccepted: "False/" + ListenerReasonUnsupportedProtocol, ConditionProgrammed: "False/" + ReasonInvalid},
+			},
+			wantAttachedRoutes: 1,
+		},
+		{
+			desc: "unsupported address",
+			gw: func() *Gateway {
+				gw := testGateway()
+				gw.Spec.Addresses = []GatewayAddress{{Value: "10.0.0.1"}}
+				return gw
+			}(),
+			syncErr: fmt.Errorf("invalid gateway spec"),
+			wantConditions: map[string]string{
+				ConditionAccepted:   "False/" + GatewayReasonUnsupportedAddress,
+				ConditionProgrammed: "False/" + ReasonInvalid,
+			},
+			wantListeners: map[string]map[string]string{
+				"http": {ConditionAccepted: "True/" + ReasonAccepted, ConditionProgrammed: "False/" + ReasonInvalid},
+			},
+			wantAttachedRoutes: 1,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			status := GatewayStatusFor(tc.gw, []*HTTPRoute{route, otherRoute}, tc.syncErr, tc.ip)
+			if got := conditionReasons(status.Conditions); fmt.Sprint(got) != fmt.Sprint(tc.wantConditions) {
+				t.Errorf("GatewayStatusFor() conditions = %v, want %v", got, tc.wantConditions)
+			}
+			if len(status.Listeners) != len(tc.wantListeners) {
+				t.Fatalf("GatewayStatusFor() listeners = %+v, want %d listeners", status.Listeners, len(tc.wantListeners))
+			}
+			for _, ls := range status.Listeners {
+				if got := conditionReasons(ls.Conditions); fmt.Sprint(got) != fmt.Sprint(tc.wantListeners[ls.Name]) {
+					t.Errorf("GatewayStatusFor() listener %q conditions = %v, want %v", ls.Name, got, tc.wantListeners[ls.Name])
+				}
+				if ls.Name == "http" && ls.AttachedRoutes != tc.wantAttachedRoutes {
+					t.Errorf("GatewayStatusFor() listener %q attached routes = %d, want %d", ls.Name, ls.AttachedRoutes, tc.wantAttachedRoutes)
+				}
+			}
+			if tc.ip != "" && (len(status.Addresses) != 1 || status.Addresses[0].Value != tc.ip) {
+				t.Errorf("GatewayStatusFor() addresses = %+v, want %s", status.Addresses, tc.ip)
+			}
+		})
+	}
+}
+
+func TestGatewayStatusForKeepsTransitionTime(t *testing.T) {
+	gw := testGateway()
+	status := GatewayStatusFor(gw, nil, nil, "1.2.3.4")
+	old := metav1.NewTime(meta.FindStatusCondition(status.Conditions, ConditionProgrammed).LastTransitionTime.Add(-1e12))
+	for i := range status.Conditions {
+		status.Conditions[i].LastTransitionTime = old
+	}
+	gw.Status = *status
+
+	status = GatewayStatusFor(gw, nil, nil, "1.2.3.4")
+	if got := meta.FindStatusCondition(status.Conditions, ConditionAccepted).LastTransitionTime; !got.Equal(&old) {
+		t.Errorf("GatewayStatusFor() Accepted transition time = %v, want %v", got, old)
+	}
+	status = GatewayStatusFor(gw, nil, fmt.Errorf("sync error"), "1.2.3.4")
+	if got := meta.FindStatusCondition(status.Conditions, ConditionProgrammed).LastTransitionTime; got.Equal(&old) {
+		t.Errorf("GatewayStatusFor() Programmed transition time = %v, want a new time", got)
+	}
+}
+
+func TestRouteParentStatusesFor(t *testing.T) {
+	gw := testGateway()
+	gw.Spec.Listeners[0].Hostname = strPtr("foo.com")
+	gwStatus := GatewayStatusFor(gw, nil, nil, "1.2.3.4")
+	otherParent := RouteParentStatus{ParentRef: ParentReference{Name: "other-gw"}, ControllerName: "example.com/other"}
+
+	for _, tc := range []struct {
+		desc  string
+		route *HTTPRoute
+		want  map[string]string
+	}{
+		{
+			desc:  "accepted",
+			route: testRoute("route", HTTPRouteRule{BackendRefs: []HTTPBackendRef{backendRef("app", 1)}}),
+			want:  map[string]string{ConditionAccepted: "True/" + ReasonAccepted, ConditionProgrammed: "True/" + ReasonProgrammed},
+		},
+		{
+			desc: "no matching hostname",
+			route: func() *HTTPRoute {
+				route := testRoute("route", HTTPRouteRule{BackendRefs: []HTTPBackendRef{backendRef("app", 1)}})
+				route.Spec.Hostnames = []string{"bar.com"}
+				return route
+			}(),
+			want: map[string]string{ConditionAccepted: "False/" + RouteReasonNoMatchingListenerHostname, ConditionProgrammed: "False/" + ReasonInvalid},
+		},
+		{
+			desc: "no matching listener",
+			route: func() *HTTPRoute {
+				route := testRoute("route", HTTPRouteRule{BackendRefs: []HTTPBackendRef{backendRef("app", 1)}})
+				route.Spec.ParentRefs[0].SectionName = strPtr("https")
+				return route
+			}(),
+			want: map[string]string{ConditionAccepted: "False/" + RouteReasonNoMatchingParent, ConditionProgrammed: "False/" + ReasonInvalid},
+		},
+		{
+			desc: "other namespace",
+			route: func() *HTTPRoute {
+				route := testRoute("route", HTTPRouteRule{BackendRefs: []HTTPBackendRef{backendRef("app", 1)}})
+				route.Namespace = "other"
+				route.Spec.ParentRefs[0].Namespace = strPtr("default")
+				return route
+			}(),
+			want: map[string]string{ConditionAccepted: "False/" + RouteReasonNotAllowedByListeners, ConditionProgrammed: "False/" + ReasonInvalid},
+		},
+		{
+			desc:  "invalid rule",
+			route: testRoute("route", HTTPRouteRule{BackendRefs: []HTTPBackendRef{{Name: "app"}}}),
+			want:  map[string]string{ConditionAccepted: "False/" + ReasonUnsupportedValue, ConditionProgrammed: "False/" + ReasonInvalid},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.route.Spec.ParentRefs = append(tc.route.Spec.ParentRefs, otherParent.ParentRef)
+			// The status of our previous sync is replaced in place, the
+			// status of the other controller is kept.
+			tc.route.Status.Parents = []RouteParentStatus{
+				{ParentRef: tc.route.Spec.ParentRefs[0], ControllerName: ControllerName},
+				otherParent,
+			}
+
+			parents := RouteParentStatusesFor(tc.route, gw, gwStatus)
+			if len(parents) != 2 {
+				t.Fatalf("RouteParentStatusesFor() = %+v, want 2 parents", parents)
+			}
+			if parents[0].ControllerName != ControllerName || parents[0].ParentRef.Name != gw.Name {
+				t.Errorf("RouteParentStatusesFor()[0] = %+v, want the status of gateway %s", parents[0], gw.Name)
+			}
+			if got := conditionReasons(parents[0].Conditions); fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Errorf("RouteParentStatusesFor() conditions = %v, want %v", got, tc.want)
+			}
+			if parents[1].ControllerName != otherParent.ControllerName {
+				t.Errorf("RouteParentStatusesFor()[1] = %+v, want %+v", parents[1], otherParent)
+			}
+		})
+	}
+
+	// The status of a parent reference removed from the route is removed.
+	route := testRoute("route")
+	route.Spec.ParentRefs = nil
+	route.Status.Parents = []RouteParentStatus{{ParentRef: ParentReference{Name: gw.Name}, ControllerName: ControllerName}}
+	if parents := RouteParentStatusesFor(route, gw, gwStatus); len(parents) != 0 {
+		t.Errorf("RouteParentStatusesFor() = %+v, want no parents", parents)
+	}
+}
@@ -0,0 +1,683 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	apiv1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/utils/common"
+)
+
+const (
+	// GatewayKey is set on the Ingress synthesized for a Gateway. The value
+	// is the namespace/name of the Gateway.
+	GatewayKey = "networking.gke.io/gateway"
+
+	// FinalizerKey is added to Gateways whose GCE resources are managed by
+	// the controller. It is removed once the resources are deleted.
+	FinalizerKey = "networking.gke.io/gateway-finalizer"
+
+	// ingressNamePrefix is prepended to the Gateway name to form the name of
+	// the synthesized Ingress, and hence of the GCE resources.
+	ingressNamePrefix = "gateway-"
+
+	// maxBackendWeight is the maximum weight of a backend supported by GCE.
+	maxBackendWeight = 1000
+)
+
+// gatewayClasses maps the supported GatewayClasses to the Ingress class
+// providing the same load balancer.
+var gatewayClasses = map[string]string{
+	"gke-l7-gxlb":                      annotations.GceIngressClass,
+	"gke-l7-rilb":                      annotations.GceL7ILBIngressClass,
+	"gke-l7-regional-external-managed": annotations.GceL7XLBRegionalIngressClass,
+}
+
+// IngressClass returns the Ingress class implementing the GatewayClass of
+// the Gateway, and false if the GatewayClass is not handled by this controller.
+func IngressClass(gw *Gateway) (string, bool) {
+	class, ok := gatewayClasses[gw.Spec.GatewayClassName]
+	return class, ok
+}
+
+// IngressName returns the name of the Ingress synthesized for the Gateway.
+func IngressName(gw *Gateway) string {
+	return ingressNamePrefix + gw.Name
+}
+
+// GatewayForIngressName returns the Gateway whose synthesized Ingress would
+// have the given namespace and name, and false if there is none.
+func GatewayForIngressName(namespace, name string) (types.NamespacedName, bool) {
+	if !strings.HasPrefix(name, ingressNamePrefix) || name == ingressNamePrefix {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: namespace, Name: strings.TrimPrefix(name, ingressNamePrefix)}, true
+}
+
+// GatewayForIngress returns the Gateway an Ingress was synthesized for, and
+// false if the Ingress is a regular Ingress.
+func GatewayForIngress(ing *v1.Ingress) (types.NamespacedName, bool) {
+	if ing == nil {
+		return types.NamespacedName{}, false
+	}
+	val, ok := ing.Annotations[GatewayKey]
+	if !ok {
+		return types.NamespacedName{}, false
+	}
+	parts := strings.SplitN(val, "/", 2)
+	if len(parts) != 2 {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, true
+}
+
+// IsAttached returns true if the route attaches to a valid listener of the
+// Gateway. Only routes in the namespace of the Gateway can be attached.
+func IsAttached(route *HTTPRoute, gw *Gateway) bool {
+	return len(parentListeners(gw, route)) > 0
+}
+
+// refersTo returns true if the parent reference of a route in the given
+// namespace points to the Gateway.
+func refersTo(ref ParentReference, routeNamespace string, gw *Gateway) bool {
+	namespace := routeNamespace
+	if ref.Namespace != nil {
+		namespace = *ref.Namespace
+	}
+	if ref.Name != gw.Name || namespace != gw.Namespace {
+		return false
+	}
+	if ref.Kind != nil && *ref.Kind != KindGateway {
+		return false
+	}
+	return ref.Group == nil || *ref.Group == GroupName
+}
+
+// parentListeners returns the valid listeners of the Gateway the route
+// attaches to.
+func parentListeners(gw *Gateway, route *HTTPRoute) []Listener {
+	var listeners []Listener
+	for _, ref := range route.Spec.ParentRefs {
+		listeners = append(listeners, refListeners(gw, route, ref)...)
+	}
+	return listeners
+}
+
+// refListeners returns the valid listeners of the Gateway a parent reference
+// of the route attaches to. A parent reference with a section name attaches
+// to the listener of that name only.
+func refListeners(gw *Gateway, route *HTTPRoute, ref ParentReference) []Listener {
+	if !refersTo(ref, route.Namespace, gw) || route.Namespace != gw.Namespace {
+		return nil
+	}
+	var listeners []Listener
+	for _, l := range gw.Spec.Listeners {
+		if ref.SectionName != nil && *ref.SectionName != l.Name {
+			continue
+		}
+		if _, err := validateListener(gw, l); err == nil {
+			listeners = append(listeners, l)
+		}
+	}
+	return listeners
+}
+
+// routeHostnames returns the hostnames the route serves on the Gateway. It
+// returns false if the route attaches to no listener or no hostname of the
+// route intersects with the hostnames of its listeners.
+func routeHostnames(gw *Gateway, route *HTTPRoute) ([]string, bool) {
+	hostnames := listenerHostnames(parentListeners(gw, route), route)
+	return hostnames, len(hostnames) > 0
+}
+
+// listenerHostnames returns the intersection of the hostnames of the route
+// with the hostnames of the listeners. The empty hostname matches all hosts.
+func listenerHostnames(listeners []Listener, route *HTTPRoute) []string {
+	var hostnames []string
+	seen := map[string]bool{}
+	add := func(host string) {
+		if !seen[host] {
+			seen[host] = true
+			hostnames = append(hostnames, host)
+		}
+	}
+	for _, l := range listeners {
+		listenerHost := ""
+		if l.Hostname != nil {
+			listenerHost = *l.Hostname
+		}
+		if len(route.Spec.Hostnames) == 0 {
+			add(listenerHost)
+			continue
+		}
+		for _, routeHost := range route.Spec.Hostnames {
+			if host, ok := intersectHostnames(listenerHost, routeHost); ok {
+				add(host)
+			}
+		}
+	}
+	return hostnames
+}
+
+// intersectHostnames returns the most specific of the two hostnames if one
+// matches the other. A wildcard hostname "*.example.com" matches all the
+// subdomains of example.com.
+func intersectHostnames(listenerHost, routeHost string) (string, bool) {
+	switch {
+	case listenerHost == "" || listenerHost == routeHost:
+		return routeHost, true
+	case strings.HasPrefix(listenerHost, "*.") && strings.HasSuffix(routeHost, listenerHost[1:]):
+		return routeHost, true
+	case strings.HasPrefix(routeHost, "*.") && strings.HasSuffix(listenerHost, routeHost[1:]):
+		return listenerHost, true
+	}
+	return "", false
+}
+
+// ParentGateways returns the keys of the Gateways the route is attached to.
+func ParentGateways(route *HTTPRoute) []string {
+	var keys []string
+	for _, ref := range route.Spec.ParentRefs {
+		if key, ok := parentGatewayKey(ref, route.Namespace); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// parentGatewayKey returns the key of the Gateway referenced by a parent
+// reference of a route in the given namespace, and false if the parent is
+// not a Gateway.
+func parentGatewayKey(ref ParentReference, routeNamespace string) (string, bool) {
+	if ref.Kind != nil && *ref.Kind != KindGateway {
+		return "", false
+	}
+	namespace := routeNamespace
+	if ref.Namespace != nil {
+		namespace = *ref.Namespace
+	}
+	return namespace + "/" + ref.Name, true
+}
+
+// ReferencesService returns true if any rule of the route forwards to the Service.
+func ReferencesService(route *HTTPRoute, svc *apiv1.Service) bool {
+	if route.Namespace != svc.Namespace {
+		return false
+	}
+	for _, rule := range route.Spec.Rules {
+		for _, ref := range rule.BackendRefs {
+			if isService(ref.Group, ref.Kind) && ref.Name == svc.Name {
+				return true
+			}
+		}
+		for _, f := range rule.Filters {
+			if m := f.RequestMirror; m != nil && isService(m.BackendRef.Group, m.BackendRef.Kind) && m.BackendRef.Name == svc.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ToIngress synthesizes the Ingress describing the load balancer of the
+// Gateway and its attached routes. The Ingress is never persisted: it lets the
+// Gateway flow through the Ingress translator, the backend syncers and the
+// load balancer pool, so that Gateways and Ingresses share the same GCE
+// resource lifecycle and naming.
+//
+// Listeners and addresses that cannot be expressed with a GCE load balancer,
+// as well as invalid route rules, are reported in the returned errors.
+func ToIngress(gw *Gateway, routes []*HTTPRoute) (*v1.Ingress, []error) {
+	class, ok := IngressClass(gw)
+	if !ok {
+		// The Ingress is not a GCE Ingress, so that its resources are cleaned up.
+		class = gw.Spec.GatewayClassName
+	}
+	ing := &v1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         gw.Namespace,
+			Name:              IngressName(gw),
+			UID:               gw.UID,
+			CreationTimestamp: gw.CreationTimestamp,
+			DeletionTimestamp: gw.DeletionTimestamp,
+			Annotations: map[string]string{
+				annotations.IngressClassKey: class,
+				GatewayKey:                  gw.Namespace + "/" + gw.Name,
+			},
+		},
+	}
+	// Gateways always use the v2 frontend naming scheme.
+	if common.HasGivenFinalizer(gw.ObjectMeta, FinalizerKey) {
+		ing.Finalizers = []string{common.FinalizerKeyV2}
+	}
+
+	var errs []error
+	errs = append(errs, translateListeners(gw, ing)...)
+	errs = append(errs, translateAddresses(gw, ing, class)...)
+	errs = append(errs, translateRoutes(gw, routes, ing)...)
+	return ing, errs
+}
+
+// translateListeners sets the TLS secrets and the HTTP setting of the Ingress.
+func translateListeners(gw *Gateway, ing *v1.Ingress) []error {
+	var errs []error
+	if len(gw.Spec.Listeners) == 0 {
+		return []error{fmt.Errorf("gateway must have at least one listener")}
+	}
+
+	allowHTTP := false
+	secrets := map[string]bool{}
+	for _, l := range gw.Spec.Listeners {
+		if _, err := validateListener(gw, l); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if l.Protocol == HTTPProtocolType {
+			allowHTTP = true
+			continue
+		}
+		for _, ref := range l.TLS.CertificateRefs {
+			if !secrets[ref.Name] {
+				secrets[ref.Name] = true
+				ing.Spec.TLS = append(ing.Spec.TLS, v1.IngressTLS{SecretName: ref.Name})
+			}
+		}
+	}
+	if !allowHTTP {
+		ing.Annotations[annotations.AllowHTTPKey] = "false"
+	}
+	return errs
+}
+
+// validateListener returns an error and the reason reported in the listener
+// status if the listener cannot be served by a GCE load balancer.
+func validateListener(gw *Gateway, l Listener) (string, error) {
+	switch l.Protocol {
+	case HTTPProtocolType:
+		if l.Port != 80 {
+			return ListenerReasonPortUnavailable, fmt.Errorf("listener %q: HTTP listeners must use port 80, got %d", l.Name, l.Port)
+		}
+	case HTTPSProtocolType:
+		if l.Port != 443 {
+			return ListenerReasonPortUnavailable, fmt.Errorf("listener %q: HTTPS listeners must use port 443, got %d", l.Name, l.Port)
+		}
+		if l.TLS == nil || len(l.TLS.CertificateRefs) == 0 {
+			return ReasonInvalid, fmt.Errorf("listener %q: HTTPS listeners must specify tls.certificateRefs", l.Name)
+		}
+		if l.TLS.Mode != nil && *l.TLS.Mode != TLSModeTerminate {
+			return ReasonUnsupportedValue, fmt.Errorf("listener %q: unsupported TLS mode %q", l.Name, *l.TLS.Mode)
+		}
+		for _, ref := range l.TLS.CertificateRefs {
+			if (ref.Group != nil && *ref.Group != "") || (ref.Kind != nil && *ref.Kind != KindSecret) {
+				return ReasonInvalid, fmt.Errorf("listener %q: certificateRefs must reference Secrets", l.Name)
+			}
+			if ref.Namespace != nil && *ref.Namespace != gw.Namespace {
+				return ReasonInvalid, fmt.Errorf("listener %q: cross namespace certificateRefs are not supported", l.Name)
+			}
+		}
+	default:
+		return ListenerReasonUnsupportedProtocol, fmt.Errorf("listener %q: unsupported protocol %q", l.Name, l.Protocol)
+	}
+	return "", nil
+}
+
+// translateAddresses sets the static IP of the Ingress.
+func translateAddresses(gw *Gateway, ing *v1.Ingress, class string) []error {
+	if len(gw.Spec.Addresses) == 0 {
+		return nil
+	}
+	if len(gw.Spec.Addresses) > 1 {
+		return []error{fmt.Errorf("at most one address can be specified")}
+	}
+	addr := gw.Spec.Addresses[0]
+	if addr.Type == nil || *addr.Type != NamedAddressType {
+		return []error{fmt.Errorf("only addresses of type %q are supported", NamedAddressType)}
+	}
+	if class == annotations.GceIngressClass {
+		ing.Annotations[annotations.GlobalStaticIPNameKey] = addr.Value
+	} else {
+		ing.Annotations[annotations.RegionalStaticIPNameKey] = addr.Value
+	}
+	return nil
+}
+
+// routeEntry is a single match of an HTTPRoute rule with the backends and
+// rewrite of the rule.
+type routeEntry struct {
+	pathType string
+	path     string
+	headers  []annotations.HeaderMatch
+	params   []annotations.QueryParameterMatch
+	backends []annotations.WeightedBackend
+	rewrite  *annotations.UrlRewrite
+	mirror   *annotations.RequestMirrorPolicy
+	// replacePrefix replaces the matched path prefix if set.
+	replacePrefix string
+}
+
+// isSimple returns true if the entry can be expressed as an Ingress path.
+func (e *routeEntry) isSimple() bool {
+	return len(e.headers) == 0 && len(e.params) == 0 && len(e.backends) == 1 && e.rewrite == nil && e.mirror == nil
+}
+
+// translateRoutes converts the rules of the routes attached to the Gateway
+// into Ingress rules and route rules. Hosts whose matches only use paths are
+// rendered as Ingress paths, other hosts as route rules.
+func translateRoutes(gw *Gateway, routes []*HTTPRoute, ing *v1.Ingress) []error {
+	var errs []error
+	var attached []*HTTPRoute
+	for _, route := range routes {
+		if IsAttached(route, gw) {
+			attached = append(attached, route)
+		}
+	}
+	// Conflicts are resolved in favor of the oldest route, as defined by the
+	// Gateway API.
+	sort.SliceStable(attached, func(i, j int) bool {
+		if !attached[i].CreationTimestamp.Equal(&attached[j].CreationTimestamp) {
+			return attached[i].CreationTimestamp.Before(&attached[j].CreationTimestamp)
+		}
+		return attached[i].Name < attached[j].Name
+	})
+
+	var hosts []string
+	hostEntries := map[string][]*routeEntry{}
+	for _, route := range attached {
+		hostnames, ok := routeHostnames(gw, route)
+		if !ok {
+			// The route is reported as not accepted in its status.
+			continue
+		}
+		for i, rule := range route.Spec.Rules {
+			entries, err := translateRouteRule(rule)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("HTTPRoute %s/%s rule %d: %w", route.Namespace, route.Name, i, err))
+				continue
+			}
+			for _, host := range hostnames {
+				if _, ok := hostEntries[host]; !ok {
+					hosts = append(hosts, host)
+				}
+				hostEntries[host] = append(hostEntries[host], entries...)
+			}
+		}
+	}
+
+	var routeRules []annotations.RouteRule
+	for _, host := range hosts {
+		entries := hostEntries[host]
+		simple := true
+		for _, e := range entries {
+			simple = simple && e.isSimple()
+		}
+		if simple {
+			ing.Spec.Rules = append(ing.Spec.Rules, toIngressRule(host, entries))
+			continue
+		}
+		routeRules = append(routeRules, toRouteRules(host, entries)...)
+	}
+	if len(routeRules) > 0 {
+		val, err := json.Marshal(routeRules)
+		if err != nil {
+			return append(errs, err)
+		}
+		ing.Annotations[annotations.RouteRulesKey] = string(val)
+	}
+	return errs
+}
+
+// translateRouteRule returns one entry per match of the rule.
+func translateRouteRule(rule HTTPRouteRule) ([]*routeEntry, error) {
+	if len(rule.BackendRefs) == 0 {
+		return nil, fmt.Errorf("at least one backendRef must be specified")
+	}
+	var backends []annotations.WeightedBackend
+	var totalWeight int64
+	for _, ref := range rule.BackendRefs {
+		if !isService(ref.Group, ref.Kind) {
+			return nil, fmt.Errorf("backendRef %q must reference a Service", ref.Name)
+		}
+		if ref.Namespace != nil {
+			return nil, fmt.Errorf("backendRef %q: cross namespace references are not supported", ref.Name)
+		}
+		if ref.Port == nil {
+			return nil, fmt.Errorf("backendRef %q must specify a port", ref.Name)
+		}
+		weight := int64(1)
+		if ref.Weight != nil {
+			weight = int64(*ref.Weight)
+		}
+		if weight < 0 || weight > maxBackendWeight {
+			return nil, fmt.Errorf("backendRef %q: weight must be between 0 and %d", ref.Name, maxBackendWeight)
+		}
+		totalWeight += weight
+		backends = append(backends, annotations.WeightedBackend{
+			Service: v1.IngressServiceBackend{Name: ref.Name, Port: v1.ServiceBackendPort{Number: *ref.Port}},
+			Weight:  weight,
+		})
+	}
+	if totalWeight == 0 {
+		return nil, fmt.Errorf("at least one backendRef must have a weight greater than 0")
+	}
+
+	var rewrite *annotations.UrlRewrite
+	var mirror *annotations.RequestMirrorPolicy
+	var prefixRewrite string
+	for _, f := range rule.Filters {
+		switch {
+		case f.Type == HTTPRouteFilterURLRewrite && f.URLRewrite != nil:
+			rewrite = &annotations.UrlRewrite{}
+			if f.URLRewrite.Hostname != nil {
+				rewrite.HostRewrite = *f.URLRewrite.Hostname
+			}
+			if p := f.URLRewrite.Path; p != nil {
+				if p.Type != PrefixMatchHTTPPathModifier || p.ReplacePrefixMatch == nil {
+					return nil, fmt.Errorf("unsupported path modifier %q", p.Type)
+				}
+				prefixRewrite = *p.ReplacePrefixMatch
+			}
+		case f.Type == HTTPRouteFilterRequestMirror && f.RequestMirror != nil:
+			if mirror != nil {
+				return nil, fmt.Errorf("only one RequestMirror filter is supported")
+			}
+			ref := f.RequestMirror.BackendRef
+			if !isService(ref.Group, ref.Kind) {
+				return nil, fmt.Errorf("mirror backendRef %q must reference a Service", ref.Name)
+			}
+			if ref.Namespace != nil {
+				return nil, fmt.Errorf("mirror backendRef %q: cross namespace references are not supported", ref.Name)
+			}
+			if ref.Port == nil {
+				return nil, fmt.Errorf("mirror backendRef %q must specify a port", ref.Name)
+			}
+			mirror = &annotations.RequestMirrorPolicy{
+				Service: v1.IngressServiceBackend{Name: ref.Name, Port: v1.ServiceBackendPort{Number: *ref.Port}},
+			}
+		default:
+			return nil, fmt.Errorf("unsupported filter type %q", f.Type)
+		}
+	}
+
+	matches := rule.Matches
+	if len(matches) == 0 {
+		matches = []HTTPRouteMatch{{}}
+	}
+	var entries []*routeEntry
+	for _, m := range matches {
+		if m.Method != nil {
+			return nil, fmt.Errorf("method matches are not supported")
+		}
+		e := &routeEntry{pathType: PathMatchPathPrefix, path: "/", backends: backends, rewrite: rewrite, mirror: mirror, replacePrefix: prefixRewrite}
+		if m.Path != nil {
+			if m.Path.Type != nil {
+				e.pathType = *m.Path.Type
+			}
+			if m.Path.Value != nil {
+				e.path = *m.Path.Value
+			}
+		}
+		if e.pathType != PathMatchPathPrefix && e.pathType != PathMatchExact {
+			return nil, fmt.Errorf("unsupported path match type %q", e.pathType)
+		}
+		if prefixRewrite != "" && e.pathType != PathMatchPathPrefix {
+			return nil, fmt.Errorf("replacePrefixMatch requires a PathPrefix match")
+		}
+		for _, h := range m.Headers {
+			hm := annotations.HeaderMatch{HeaderName: h.Name}
+			switch {
+			case h.Type == nil || *h.Type == HeaderMatchExact:
+				hm.ExactMatch = h.Value
+			case *h.Type == HeaderMatchRegularExpression:
+				hm.RegexMatch = h.Value
+			default:
+				return nil, fmt.Errorf("unsupported header match type %q", *h.Type)
+			}
+			e.headers = append(e.headers, hm)
+		}
+		for _, q := range m.QueryParams {
+			qm := annotations.QueryParameterMatch{Name: q.Name}
+			switch {
+			case q.Type == nil || *q.Type == QueryParamMatchExact:
+				qm.ExactMatch = q.Value
+			case *q.Type == QueryParamMatchRegularExpression:
+				qm.RegexMatch = q.Value
+			default:
+				return nil, fmt.Errorf("unsupported query parameter match type %q", *q.Type)
+			}
+			e.params = append(e.params, qm)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// toIngressRule converts path only entries into an Ingress rule. The first
+// entry wins if several entries use the same path.
+func toIngressRule(host string, entries []*routeEntry) v1.IngressRule {
+	rule := v1.IngressRule{Host: host, IngressRuleValue: v1.IngressRuleValue{HTTP: &v1.HTTPIngressRuleValue{}}}
+	seen := map[string]bool{}
+	for _, e := range entries {
+		pathType := v1.PathTypePrefix
+		if e.pathType == PathMatchExact {
+			pathType = v1.PathTypeExact
+		}
+		key := string(pathType) + e.path
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		svc := e.backends[0].Service
+		rule.HTTP.Paths = append(rule.HTTP.Paths, v1.HTTPIngressPath{
+			Path:     e.path,
+			PathType: &pathType,
+			Backend:  v1.IngressBackend{Service: &svc},
+		})
+	}
+	return rule
+}
+
+// toRouteRules converts the entries of a host into route rules, ordered by
+// the Gateway API match precedence: exact paths, then the longest paths, then
+// the largest number of header and query parameter matches.
+func toRouteRules(host string, entries []*routeEntry) []annotations.RouteRule {
+	sorted := make([]*routeEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if (a.pathType == PathMatchExact) != (b.pathType == PathMatchExact) {
+			return a.pathType == PathMatchExact
+		}
+		if len(a.path) != len(b.path) {
+			return len(a.path) > len(b.path)
+		}
+		if len(a.headers) != len(b.headers) {
+			return len(a.headers) > len(b.headers)
+		}
+		return len(a.params) > len(b.params)
+	})
+
+	var rules []annotations.RouteRule
+	for _, e := range sorted {
+		var matches []annotations.RouteMatch
+		var rewrites []string
+		if e.pathType == PathMatchExact {
+			matches = append(matches, annotations.RouteMatch{FullPathMatch: e.path})
+			rewrites = append(rewrites, "")
+		} else if trimmed := strings.TrimSuffix(e.path, "/"); trimmed == "" {
+			matches = append(matches, annotations.RouteMatch{PrefixMatch: "/"})
+			rewrites = append(rewrites, withTrailingSlash(e.replacePrefix))
+		} else {
+			// A PathPrefix match is element wise: /foo matches /foo and
+			// /foo/bar, but not /foobar.
+			matches = append(matches, annotations.RouteMatch{FullPathMatch: trimmed}, annotations.RouteMatch{PrefixMatch: trimmed + "/"})
+			rewrites = append(rewrites, e.replacePrefix, withTrailingSlash(e.replacePrefix))
+		}
+
+		for i, m := range matches {
+			m.HeaderMatches = e.headers
+			m.QueryParameterMatches = e.params
+			rule := annotations.RouteRule{
+				Host:     host,
+				Priority: int64(len(rules)),
+				Matches:  []annotations.RouteMatch{m},
+			}
+			var action annotations.RouteAction
+			if len(e.backends) == 1 {
+				svc := e.backends[0].Service
+				rule.Service = &svc
+			} else {
+				action.WeightedBackends = e.backends
+			}
+			if e.rewrite != nil {
+				rewrite := *e.rewrite
+				if e.replacePrefix != "" {
+					rewrite.PathPrefixRewrite = rewrites[i]
+				}
+				action.UrlRewrite = &rewrite
+			}
+			action.RequestMirrorPolicy = e.mirror
+			if action.WeightedBackends != nil || action.UrlRewrite != nil || action.RequestMirrorPolicy != nil {
+				rule.Action = &action
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// withTrailingSlash returns the path with a trailing slash, or the empty
+// string if the path is empty.
+func withTrailingSlash(path string) string {
+	if path == "" || strings.HasSuffix(path, "/") {
+		return path
+	}
+	return path + "/"
+}
+
+// isService returns true if the group and kind of a backend reference point
+// to a Service.
+func isService(group, kind *string) bool {
+	return (group == nil || *group == "") && (kind == nil || *kind == KindService)
+}
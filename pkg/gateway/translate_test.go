@@ -0,0 +1,358 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/utils/common"
+)
+
+func strPtr(s string) *string { return &s }
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func testGateway() *Gateway {
+	return &Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw", Finalizers: []string{FinalizerKey}},
+		Spec: GatewaySpec{
+			GatewayClassName: "gke-l7-rilb",
+			Listeners: []Listener{
+				{Name: "http", Port: 80, Protocol: HTTPProtocolType},
+			},
+		},
+	}
+}
+
+func testRoute(name string, rules ...HTTPRouteRule) *HTTPRoute {
+	return &HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: HTTPRouteSpec{
+			ParentRefs: []ParentReference{{Name: "gw"}},
+			Hostnames:  []string{"foo.com"},
+			Rules:      rules,
+		},
+	}
+}
+
+func backendRef(name string, weight int32) HTTPBackendRef {
+	return HTTPBackendRef{Name: name, Port: int32Ptr(80), Weight: int32Ptr(weight)}
+}
+
+func TestToIngress(t *testing.T) {
+	prefix := v1.PathTypePrefix
+	exact := v1.PathTypeExact
+
+	for _, tc := range []struct {
+		desc           string
+		gw             *Gateway
+		routes         []*HTTPRoute
+		wantErrCount   int
+		wantRules      []v1.IngressRule
+		wantTLS        []v1.IngressTLS
+		wantAnnotation map[string]string
+		wantRouteRules []annotations.RouteRule
+	}{
+		{
+			desc: "path only routes",
+			gw:   testGateway(),
+			routes: []*HTTPRoute{
+				testRoute("route", HTTPRouteRule{
+					Matches: []HTTPRouteMatch{
+						{Path: &HTTPPathMatch{Type: strPtr(PathMatchPathPrefix), Value: strPtr("/app")}},
+						{Path: &HTTPPathMatch{Type: strPtr(PathMatchExact), Value: strPtr("/exact")}},
+					},
+					BackendRefs: []HTTPBackendRef{backendRef("app", 1)},
+				}),
+				// Not attached to the Gateway.
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"},
+					Spec: HTTPRouteSpec{
+						ParentRefs: []ParentReference{{Name: "other-gw"}},
+						Rules:      []HTTPRouteRule{{BackendRefs: []HTTPBackendRef{backendRef("other", 1)}}},
+					},
+				},
+			},
+			wantRules: []v1.IngressRule{
+				{
+					Host: "foo.com",
+					IngressRuleValue: v1.IngressRuleValue{HTTP: &v1.HTTPIngressRuleValue{Paths: []v1.HTTPIngressPath{
+						{Path: "/app", PathType: &prefix, Backend: v1.IngressBackend{Service: &v1.IngressServiceBackend{Name: "app", Port: v1.ServiceBackendPort{Number: 80}}}},
+						{Path: "/exact", PathType: &exact, Backend: v1.IngressBackend{Service: &v1.IngressServiceBackend{Name: "app", Port: v1.ServiceBackendPort{Number: 80}}}},
+					}}},
+				},
+			},
+		},
+		{
+			desc: "listener hostnames",
+			gw: func() *Gateway {
+				gw := testGateway()
+				gw.Spec.Listeners[0].Hostname = strPtr("*.foo.com")
+				return gw
+			}(),
+			routes: []*HTTPRoute{
+				func() *HTTPRoute {
+					route := testRoute("route", HTTPRouteRule{BackendRefs: []HTTPBackendRef{backendRef("app", 1)}})
+					route.Spec.Hostnames = []string{"foo.com", "a.foo.com", "b.bar.com"}
+					return route
+				}(),
+				func() *HTTPRoute {
+					route := testRoute("no-hostnames", HTTPRouteRule{BackendRefs: []HTTPBackendRef{backendRef("other", 1)}})
+					route.CreationTimestamp = metav1.NewTime(time.Unix(1, 0))
+					route.Spec.Hostnames = nil
+					return route
+				}(),
+				// No hostname intersects with the listener hostname.
+				func() *HTTPRoute {
+					route := testRoute("bar", HTTPRouteRule{BackendRefs: []HTTPBackendRef{backendRef("bar", 1)}})
+					route.Spec.Hostnames = []string{"bar.com"}
+					return route
+				}(),
+				// Attached to a listener which does not exist.
+				func() *HTTPRoute {
+					route := testRoute("section", HTTPRouteRule{BackendRefs: []HTTPBackendRef{backendRef("section", 1)}})
+					route.Spec.ParentRefs[0].SectionName = strPtr("https")
+					return route
+				}(),
+			},
+			wantRules: []v1.IngressRule{
+				{
+					Host: "a.foo.com",
+					IngressRuleValue: v1.IngressRuleValue{HTTP: &v1.HTTPIngressRuleValue{Paths: []v1.HTTPIngressPath{
+						{Path: "/", PathType: &prefix, Backend: v1.IngressBackend{Service: &v1.IngressServiceBackend{Name: "app", Port: v1.ServiceBackendPort{Number: 80}}}},
+					}}},
+				},
+				{
+					Host: "*.foo.com",
+					IngressRuleValue: v1.IngressRuleValue{HTTP: &v1.HTTPIngressRuleValue{Paths: []v1.HTTPIngressPath{
+						{Path: "/", PathType: &prefix, Backend: v1.IngressBackend{Service: &v1.IngressServiceBackend{Name: "other", Port: v1.ServiceBackendPort{Number: 80}}}},
+					}}},
+				},
+			},
+		},
+		{
+			desc: "header match and weighted backends",
+			gw:   testGateway(),
+			routes: []*HTTPRoute{
+				testRoute("route",
+					HTTPRouteRule{
+						Matches:     []HTTPRouteMatch{{Path: &HTTPPathMatch{Value: strPtr("/")}}},
+						BackendRefs: []HTTPBackendRef{backendRef("app", 90), backendRef("canary", 10)},
+					},
+					HTTPRouteRule{
+						Matches: []HTTPRouteMatch{{
+							Path:    &HTTPPathMatch{Value: strPtr("/api/")},
+							Headers: []HTTPHeaderMatch{{Name: "x-canary", Value: "true"}},
+						}},
+						Filters:     []HTTPRouteFilter{{Type: HTTPRouteFilterURLRewrite, URLRewrite: &HTTPURLRewriteFilter{Path: &HTTPPathModifier{Type: PrefixMatchHTTPPathModifier, ReplacePrefixMatch: strPtr("/v2")}}}},
+						BackendRefs: []HTTPBackendRef{backendRef("canary", 1)},
+					},
+				),
+			},
+			wantRouteRules: []annotations.RouteRule{
+				{
+					Host:     "foo.com",
+					Priority: 0,
+					Matches:  []annotations.RouteMatch{{FullPathMatch: "/api", HeaderMatches: []annotations.HeaderMatch{{HeaderName: "x-canary", ExactMatch: "true"}}}},
+					Service:  &v1.IngressServiceBackend{Name: "canary", Port: v1.ServiceBackendPort{Number: 80}},
+					Action:   &annotations.RouteAction{UrlRewrite: &annotations.UrlRewrite{PathPrefixRewrite: "/v2"}},
+				},
+				{
+					Host:     "foo.com",
+					Priority: 1,
+					Matches:  []annotations.RouteMatch{{PrefixMatch: "/api/", HeaderMatches: []annotations.HeaderMatch{{HeaderName: "x-canary", ExactMatch: "true"}}}},
+					Service:  &v1.IngressServiceBackend{Name: "canary", Port: v1.ServiceBackendPort{Number: 80}},
+					Action:   &annotations.RouteAction{UrlRewrite: &annotations.UrlRewrite{PathPrefixRewrite: "/v2/"}},
+				},
+				{
+					Host:     "foo.com",
+					Priority: 2,
+					Matches:  []annotations.RouteMatch{{PrefixMatch: "/"}},
+					Action: &annotations.RouteAction{WeightedBackends: []annotations.WeightedBackend{
+						{Service: v1.IngressServiceBackend{Name: "app", Port: v1.ServiceBackendPort{Number: 80}}, Weight: 90},
+						{Service: v1.IngressServiceBackend{Name: "canary", Port: v1.ServiceBackendPort{Number: 80}}, Weight: 10},
+					}},
+				},
+			},
+		},
+		{
+			desc: "request mirror",
+			gw:   testGateway(),
+			routes: []*HTTPRoute{
+				testRoute("route", HTTPRouteRule{
+					Matches:     []HTTPRouteMatch{{Path: &HTTPPathMatch{Type: strPtr(PathMatchExact), Value: strPtr("/app")}}},
+					Filters:     []HTTPRouteFilter{{Type: HTTPRouteFilterRequestMirror, RequestMirror: &HTTPRequestMirrorFilter{BackendRef: BackendObjectReference{Name: "app-v2", Port: int32Ptr(8080)}}}},
+					BackendRefs: []HTTPBackendRef{backendRef("app", 1)},
+				}),
+			},
+			wantRouteRules: []annotations.RouteRule{
+				{
+					Host:    "foo.com",
+					Matches: []annotations.RouteMatch{{FullPathMatch: "/app"}},
+					Service: &v1.IngressServiceBackend{Name: "app", Port: v1.ServiceBackendPort{Number: 80}},
+					Action: &annotations.RouteAction{RequestMirrorPolicy: &annotations.RequestMirrorPolicy{
+						Service: v1.IngressServiceBackend{Name: "app-v2", Port: v1.ServiceBackendPort{Number: 8080}},
+					}},
+				},
+			},
+		},
+		{
+			desc: "https only listener with named address",
+			gw: func() *Gateway {
+				gw := testGateway()
+				gw.Spec.Listeners = []Listener{{
+					Name:     "https",
+					Port:     443,
+					Protocol: HTTPSProtocolType,
+					TLS:      &GatewayTLSConfig{CertificateRefs: []SecretObjectReference{{Name: "cert"}}},
+				}}
+				gw.Spec.Addresses = []GatewayAddress{{Type: strPtr(NamedAddressType), Value: "my-address"}}
+				return gw
+			}(),
+			wantTLS: []v1.IngressTLS{{SecretName: "cert"}},
+			wantAnnotation: map[string]string{
+				annotations.AllowHTTPKey:            "false",
+				annotations.RegionalStaticIPNameKey: "my-address",
+			},
+		},
+		{
+			desc: "unsupported listeners, address and rules",
+			gw: func() *Gateway {
+				gw := testGateway()
+				gw.Spec.Listeners = append(gw.Spec.Listeners,
+					Listener{Name: "http-8080", Port: 8080, Protocol: HTTPProtocolType},
+					Listener{Name: "tcp", Port: 443, Protocol: "TCP"},
+				)
+				gw.Spec.Addresses = []GatewayAddress{{Value: "10.0.0.1"}}
+				return gw
+			}(),
+			routes: []*HTTPRoute{
+				testRoute("route",
+					HTTPRouteRule{Matches: []HTTPRouteMatch{{Method: strPtr("GET")}}, BackendRefs: []HTTPBackendRef{backendRef("app", 1)}},
+					HTTPRouteRule{BackendRefs: []HTTPBackendRef{backendRef("app", 0)}},
+					HTTPRouteRule{BackendRefs: []HTTPBackendRef{{Name: "app"}}},
+				),
+			},
+			wantErrCount: 6,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ing, errs := ToIngress(tc.gw, tc.routes)
+			if len(errs) != tc.wantErrCount {
+				t.Errorf("ToIngress() returned errors %v, want %d errors", errs, tc.wantErrCount)
+			}
+			if ing.Name != "gateway-gw" || ing.Namespace != "default" {
+				t.Errorf("ToIngress() = %s/%s, want default/gateway-gw", ing.Namespace, ing.Name)
+			}
+			if got := ing.Annotations[annotations.IngressClassKey]; got != annotations.GceL7ILBIngressClass {
+				t.Errorf("ToIngress() ingress class = %q, want %q", got, annotations.GceL7ILBIngressClass)
+			}
+			if !common.HasGivenFinalizer(ing.ObjectMeta, common.FinalizerKeyV2) {
+				t.Errorf("ToIngress() finalizers = %v, want %q", ing.Finalizers, common.FinalizerKeyV2)
+			}
+			gwKey, ok := GatewayForIngress(ing)
+			if want := (types.NamespacedName{Namespace: "default", Name: "gw"}); !ok || gwKey != want {
+				t.Errorf("GatewayForIngress() = %v, %t, want %v, true", gwKey, ok, want)
+			}
+			if diff := cmp.Diff(tc.wantRules, ing.Spec.Rules); diff != "" {
+				t.Errorf("ToIngress() rules mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantTLS, ing.Spec.TLS); diff != "" {
+				t.Errorf("ToIngress() TLS mismatch (-want +got):\n%s", diff)
+			}
+			for k, v := range tc.wantAnnotation {
+				if got := ing.Annotations[k]; got != v {
+					t.Errorf("ToIngress() annotation %q = %q, want %q", k, got, v)
+				}
+			}
+			gotRouteRules, err := annotations.FromIngress(ing).RouteRules()
+			if err != nil {
+				t.Fatalf("RouteRules() = %v", err)
+			}
+			if diff := cmp.Diff(tc.wantRouteRules, gotRouteRules); diff != "" {
+				t.Errorf("ToIngress() route rules mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIntersectHostnames(t *testing.T) {
+	for _, tc := range []struct {
+		listenerHost string
+		routeHost    string
+		want         string
+		wantOK       bool
+	}{
+		{listenerHost: "", routeHost: "foo.com", want: "foo.com", wantOK: true},
+		{listenerHost: "foo.com", routeHost: "foo.com", want: "foo.com", wantOK: true},
+		{listenerHost: "foo.com", routeHost: "bar.com"},
+		{listenerHost: "*.foo.com", routeHost: "a.b.foo.com", want: "a.b.foo.com", wantOK: true},
+		{listenerHost: "*.foo.com", routeHost: "foo.com"},
+		{listenerHost: "a.foo.com", routeHost: "*.foo.com", want: "a.foo.com", wantOK: true},
+		{listenerHost: "*.a.foo.com", routeHost: "*.foo.com", want: "*.a.foo.com", wantOK: true},
+		{listenerHost: "*.foo.com", routeHost: "*.bar.com"},
+	} {
+		got, ok := intersectHostnames(tc.listenerHost, tc.routeHost)
+		if got != tc.want || ok != tc.wantOK {
+			t.Errorf("intersectHostnames(%q, %q) = %q, %t, want %q, %t", tc.listenerHost, tc.routeHost, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
+
+func TestGatewayForIngressName(t *testing.T) {
+	gw := testGateway()
+	if got, ok := GatewayForIngressName(gw.Namespace, IngressName(gw)); !ok || got.Namespace != gw.Namespace || got.Name != gw.Name {
+		t.Errorf("GatewayForIngressName(%s, %s) = %v, %t, want %s/%s, true", gw.Namespace, IngressName(gw), got, ok, gw.Namespace, gw.Name)
+	}
+	for _, name := range []string{"ing", "gateway-"} {
+		if got, ok := GatewayForIngressName(gw.Namespace, name); ok {
+			t.Errorf("GatewayForIngressName(%s, %s) = %v, true, want false", gw.Namespace, name, got)
+		}
+	}
+}
+
+func TestGatewayFromUnstructured(t *testing.T) {
+	gw := testGateway()
+	gw.Spec.Addresses = []GatewayAddress{{Type: strPtr(NamedAddressType), Value: "my-address"}}
+	data, err := json.Marshal(gw)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &u.Object); err != nil {
+		t.Fatalf("json.Unmarshal() = %v", err)
+	}
+
+	got, err := GatewayFromUnstructured(u)
+	if err != nil {
+		t.Fatalf("GatewayFromUnstructured() = %v", err)
+	}
+	if diff := cmp.Diff(gw, got); diff != "" {
+		t.Errorf("GatewayFromUnstructured() mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := GatewayFromUnstructured(gw); err == nil {
+		t.Errorf("GatewayFromUnstructured(%T) = nil, want error", gw)
+	}
+}
@@ -0,0 +1,315 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// The types below mirror the subset of the gateway.networking.k8s.io/v1 API
+// consumed by the controller. Gateway API objects are watched through the
+// dynamic client and decoded into these types with FromUnstructured.
+
+const (
+	// GroupName is the API group of the Gateway API resources.
+	GroupName = "gateway.networking.k8s.io"
+
+	// KindGateway is the kind of a Gateway parent reference.
+	KindGateway = "Gateway"
+	// KindHTTPRoute is the kind of an HTTPRoute.
+	KindHTTPRoute = "HTTPRoute"
+	// KindService is the kind of a Service backend reference.
+	KindService = "Service"
+	// KindSecret is the kind of a Secret certificate reference.
+	KindSecret = "Secret"
+
+	// HTTPProtocolType accepts cleartext HTTP/1.1 sessions over TCP.
+	HTTPProtocolType = "HTTP"
+	// HTTPSProtocolType accepts HTTP/1.1 or HTTP/2 sessions over TLS.
+	HTTPSProtocolType = "HTTPS"
+
+	// TLSModeTerminate terminates TLS at the load balancer.
+	TLSModeTerminate = "Terminate"
+
+	// NamedAddressType is an address referring to a reserved GCE address by name.
+	NamedAddressType = "NamedAddress"
+	// IPAddressType is an address that is an IP.
+	IPAddressType = "IPAddress"
+
+	// PathMatchExact matches the full request path.
+	PathMatchExact = "Exact"
+	// PathMatchPathPrefix matches the request path on element boundaries.
+	PathMatchPathPrefix = "PathPrefix"
+	// PathMatchRegularExpression matches the request path with a regex.
+	PathMatchRegularExpression = "RegularExpression"
+
+	// HeaderMatchExact matches the exact value of a header.
+	HeaderMatchExact = "Exact"
+	// HeaderMatchRegularExpression matches the value of a header with a regex.
+	HeaderMatchRegularExpression = "RegularExpression"
+
+	// QueryParamMatchExact matches the exact value of a query parameter.
+	QueryParamMatchExact = "Exact"
+	// QueryParamMatchRegularExpression matches the value of a query parameter with a regex.
+	QueryParamMatchRegularExpression = "RegularExpression"
+
+	// HTTPRouteFilterURLRewrite rewrites the request before it is forwarded.
+	HTTPRouteFilterURLRewrite = "URLRewrite"
+	// HTTPRouteFilterRequestMirror mirrors the request to another backend.
+	HTTPRouteFilterRequestMirror = "RequestMirror"
+	// PrefixMatchHTTPPathModifier replaces the matched path prefix.
+	PrefixMatchHTTPPathModifier = "ReplacePrefixMatch"
+)
+
+var (
+	// GatewayGVR is the resource of Gateways.
+	GatewayGVR = schema.GroupVersionResource{Group: GroupName, Version: "v1", Resource: "gateways"}
+	// HTTPRouteGVR is the resource of HTTPRoutes.
+	HTTPRouteGVR = schema.GroupVersionResource{Group: GroupName, Version: "v1", Resource: "httproutes"}
+)
+
+// Gateway represents an instance of a service-traffic handling infrastructure
+// by binding Listeners to a set of IP addresses.
+type Gateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GatewaySpec   `json:"spec"`
+	Status GatewayStatus `json:"status,omitempty"`
+}
+
+// GatewaySpec defines the desired state of Gateway.
+type GatewaySpec struct {
+	GatewayClassName string           `json:"gatewayClassName"`
+	Listeners        []Listener       `json:"listeners"`
+	Addresses        []GatewayAddress `json:"addresses,omitempty"`
+}
+
+// Listener embodies the concept of a logical endpoint where a Gateway accepts
+// network connections.
+type Listener struct {
+	Name     string            `json:"name"`
+	Hostname *string           `json:"hostname,omitempty"`
+	Port     int32             `json:"port"`
+	Protocol string            `json:"protocol"`
+	TLS      *GatewayTLSConfig `json:"tls,omitempty"`
+}
+
+// GatewayTLSConfig describes a TLS configuration of a Listener.
+type GatewayTLSConfig struct {
+	Mode            *string                 `json:"mode,omitempty"`
+	CertificateRefs []SecretObjectReference `json:"certificateRefs,omitempty"`
+}
+
+// SecretObjectReference identifies an API object including its namespace,
+// defaulting to Secret.
+type SecretObjectReference struct {
+	Group     *string `json:"group,omitempty"`
+	Kind      *string `json:"kind,omitempty"`
+	Name      string  `json:"name"`
+	Namespace *string `json:"namespace,omitempty"`
+}
+
+// GatewayAddress describes an address that can be bound to a Gateway.
+type GatewayAddress struct {
+	Type  *string `json:"type,omitempty"`
+	Value string  `json:"value"`
+}
+
+// GatewayStatus defines the observed state of Gateway.
+type GatewayStatus struct {
+	Addresses  []GatewayStatusAddress `json:"addresses,omitempty"`
+	Conditions []metav1.Condition     `json:"conditions,omitempty"`
+	Listeners  []ListenerStatus       `json:"listeners,omitempty"`
+}
+
+// ListenerStatus is the status associated with a Listener.
+type ListenerStatus struct {
+	Name           string             `json:"name"`
+	SupportedKinds []RouteGroupKind   `json:"supportedKinds"`
+	AttachedRoutes int32              `json:"attachedRoutes"`
+	Conditions     []metav1.Condition `json:"conditions"`
+}
+
+// RouteGroupKind indicates the group and kind of a Route resource.
+type RouteGroupKind struct {
+	Group *string `json:"group,omitempty"`
+	Kind  string  `json:"kind"`
+}
+
+// GatewayStatusAddress describes a network address that is bound to a Gateway.
+type GatewayStatusAddress struct {
+	Type  *string `json:"type,omitempty"`
+	Value string  `json:"value"`
+}
+
+// HTTPRoute provides a way to route HTTP requests.
+type HTTPRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HTTPRouteSpec   `json:"spec"`
+	Status HTTPRouteStatus `json:"status,omitempty"`
+}
+
+// HTTPRouteSpec defines the desired state of HTTPRoute.
+type HTTPRouteSpec struct {
+	ParentRefs []ParentReference `json:"parentRefs,omitempty"`
+	Hostnames  []string          `json:"hostnames,omitempty"`
+	Rules      []HTTPRouteRule   `json:"rules,omitempty"`
+}
+
+// HTTPRouteStatus defines the observed state of HTTPRoute.
+type HTTPRouteStatus struct {
+	Parents []RouteParentStatus `json:"parents,omitempty"`
+}
+
+// RouteParentStatus describes the status of a route with respect to an
+// associated parent.
+type RouteParentStatus struct {
+	ParentRef      ParentReference    `json:"parentRef"`
+	ControllerName string             `json:"controllerName"`
+	Conditions     []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ParentReference identifies an API object, usually a Gateway, the route
+// wants to be attached to.
+type ParentReference struct {
+	Group       *string `json:"group,omitempty"`
+	Kind        *string `json:"kind,omitempty"`
+	Namespace   *string `json:"namespace,omitempty"`
+	Name        string  `json:"name"`
+	SectionName *string `json:"sectionName,omitempty"`
+}
+
+// HTTPRouteRule defines semantics for matching an HTTP request based on
+// conditions, optionally executing additional processing steps, and
+// forwarding the request to an API object.
+type HTTPRouteRule struct {
+	Matches     []HTTPRouteMatch  `json:"matches,omitempty"`
+	Filters     []HTTPRouteFilter `json:"filters,omitempty"`
+	BackendRefs []HTTPBackendRef  `json:"backendRefs,omitempty"`
+}
+
+// HTTPRouteMatch defines the predicate used to match requests to a given action.
+type HTTPRouteMatch struct {
+	Path        *HTTPPathMatch        `json:"path,omitempty"`
+	Headers     []HTTPHeaderMatch     `json:"headers,omitempty"`
+	QueryParams []HTTPQueryParamMatch `json:"queryParams,omitempty"`
+	Method      *string               `json:"method,omitempty"`
+}
+
+// HTTPPathMatch describes how to select an HTTP route by matching the HTTP
+// request path.
+type HTTPPathMatch struct {
+	Type  *string `json:"type,omitempty"`
+	Value *string `json:"value,omitempty"`
+}
+
+// HTTPHeaderMatch describes how to select an HTTP route by matching HTTP
+// request headers.
+type HTTPHeaderMatch struct {
+	Type  *string `json:"type,omitempty"`
+	Name  string  `json:"name"`
+	Value string  `json:"value"`
+}
+
+// HTTPQueryParamMatch describes how to select an HTTP route by matching HTTP
+// query parameters.
+type HTTPQueryParamMatch struct {
+	Type  *string `json:"type,omitempty"`
+	Name  string  `json:"name"`
+	Value string  `json:"value"`
+}
+
+// HTTPRouteFilter defines processing steps that must be completed during the
+// request or response lifecycle.
+type HTTPRouteFilter struct {
+	Type          string                   `json:"type"`
+	URLRewrite    *HTTPURLRewriteFilter    `json:"urlRewrite,omitempty"`
+	RequestMirror *HTTPRequestMirrorFilter `json:"requestMirror,omitempty"`
+}
+
+// HTTPRequestMirrorFilter defines configuration for the RequestMirror filter.
+type HTTPRequestMirrorFilter struct {
+	BackendRef BackendObjectReference `json:"backendRef"`
+}
+
+// BackendObjectReference defines how an ObjectReference that is specific to
+// BackendRef.
+type BackendObjectReference struct {
+	Group     *string `json:"group,omitempty"`
+	Kind      *string `json:"kind,omitempty"`
+	Name      string  `json:"name"`
+	Namespace *string `json:"namespace,omitempty"`
+	Port      *int32  `json:"port,omitempty"`
+}
+
+// HTTPURLRewriteFilter defines a filter that modifies a request during
+// forwarding.
+type HTTPURLRewriteFilter struct {
+	Hostname *string           `json:"hostname,omitempty"`
+	Path     *HTTPPathModifier `json:"path,omitempty"`
+}
+
+// HTTPPathModifier defines configuration for path modifiers.
+type HTTPPathModifier struct {
+	Type               string  `json:"type"`
+	ReplaceFullPath    *string `json:"replaceFullPath,omitempty"`
+	ReplacePrefixMatch *string `json:"replacePrefixMatch,omitempty"`
+}
+
+// HTTPBackendRef defines how an HTTPRoute forwards an HTTP request.
+type HTTPBackendRef struct {
+	Group     *string `json:"group,omitempty"`
+	Kind      *string `json:"kind,omitempty"`
+	Name      string  `json:"name"`
+	Namespace *string `json:"namespace,omitempty"`
+	Port      *int32  `json:"port,omitempty"`
+	Weight    *int32  `json:"weight,omitempty"`
+}
+
+// GatewayFromUnstructured decodes a Gateway watched through the dynamic client.
+func GatewayFromUnstructured(obj interface{}) (*Gateway, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("expected *unstructured.Unstructured, got %T", obj)
+	}
+	gw := &Gateway{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, gw); err != nil {
+		return nil, fmt.Errorf("failed to decode Gateway %s/%s: %w", u.GetNamespace(), u.GetName(), err)
+	}
+	return gw, nil
+}
+
+// HTTPRouteFromUnstructured decodes an HTTPRoute watched through the dynamic client.
+func HTTPRouteFromUnstructured(obj interface{}) (*HTTPRoute, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("expected *unstructured.Unstructured, got %T", obj)
+	}
+	route := &HTTPRoute{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, route); err != nil {
+		return nil, fmt.Errorf("failed to decode HTTPRoute %s/%s: %w", u.GetNamespace(), u.GetName(), err)
+	}
+	return route, nil
+}
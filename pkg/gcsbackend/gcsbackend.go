@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcsbackend
+
+import (
+	"fmt"
+
+	apisgcsbackend "k8s.io/ingress-gce/pkg/apis/gcsbackend"
+	gcsbackendv1beta1 "k8s.io/ingress-gce/pkg/apis/gcsbackend/v1beta1"
+	"k8s.io/ingress-gce/pkg/crd"
+)
+
+// Kind is the kind of GCSBackends, as referenced by the resource backends
+// of Ingresses.
+const Kind = "GCSBackend"
+
+func CRDMeta() *crd.CRDMeta {
+	meta := crd.NewCRDMeta(
+		apisgcsbackend.GroupName,
+		Kind,
+		"GCSBackendList",
+		"gcsbackend",
+		"gcsbackends",
+		[]*crd.Version{
+			crd.NewVersion("v1beta1", "k8s.io/ingress-gce/pkg/apis/gcsbackend/v1beta1.GCSBackend", gcsbackendv1beta1.GetOpenAPIDefinitions, false),
+		},
+	)
+	return meta
+}
+
+// Validate returns an error if the GCSBackend cannot be turned into a
+// backend bucket.
+func Validate(gcsBackend *gcsbackendv1beta1.GCSBackend) error {
+	if gcsBackend.Spec.BucketName == "" {
+		return fmt.Errorf("GCSBackend %s/%s has no bucket name", gcsBackend.Namespace, gcsBackend.Name)
+	}
+	return nil
+}
@@ -0,0 +1,333 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ingressgroup merges the Ingresses of an ingress group into one load
+// balancer. The members of a group are synced through an Ingress synthesized
+// for the group, which is never persisted and gives the GCE resources of the
+// group their names.
+package ingressgroup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/loadbalancers"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/common"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// GroupKey is set on the Ingress synthesized for a group. The value is
+	// the name of the group.
+	GroupKey = "networking.gke.io/ingress-group-name"
+
+	// AllowedGroupsKey is the annotation of an IngressClass listing the
+	// comma-separated groups the Ingresses of the class may join, or "*" for
+	// any group. Ingresses without a class are allowed by the IngressClass
+	// named "gce".
+	// Examples:
+	// - annotations:
+	//     networking.gke.io/ingress-groups: team-a,team-b
+	AllowedGroupsKey = "networking.gke.io/ingress-groups"
+
+	// EventReason is the reason of the events which report the rules of a
+	// member dropped because of a conflict.
+	EventReason = "IngressGroupConflict"
+
+	// ingressNamespace is the namespace of the Ingresses synthesized for
+	// groups, whose members may be in any namespace.
+	ingressNamespace = metav1.NamespaceSystem
+
+	// ingressNamePrefix is prepended to the group name to form the name of
+	// the synthesized Ingress, and hence of the GCE resources.
+	ingressNamePrefix = "ingress-group-"
+)
+
+// Validate returns an error if the group name is invalid.
+func Validate(group string) error {
+	if errs := validation.IsDNS1123Label(group); len(errs) > 0 {
+		return fmt.Errorf("invalid %s annotation %q: %s", annotations.IngressGroupKey, group, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// IngressKey returns the namespace/name of the Ingress synthesized for the group.
+func IngressKey(group string) string {
+	return ingressNamespace + "/" + ingressNamePrefix + group
+}
+
+// GroupForIngressName returns the group whose synthesized Ingress has the
+// given namespace and name, and false if there is none.
+func GroupForIngressName(namespace, name string) (string, bool) {
+	if namespace != ingressNamespace || !strings.HasPrefix(name, ingressNamePrefix) {
+		return "", false
+	}
+	group := strings.TrimPrefix(name, ingressNamePrefix)
+	return group, Validate(group) == nil
+}
+
+// ClassName returns the name of the IngressClass which allows the Ingress to
+// join groups: the class of the Ingress, or "gce" if it has none.
+func ClassName(ing *v1.Ingress) string {
+	if class := ingressClass(ing); class != "" {
+		return class
+	}
+	return annotations.GceIngressClass
+}
+
+// Allowed returns true if the AllowedGroupsKey annotation of the IngressClass
+// allows its Ingresses to join the group.
+func Allowed(class *v1.IngressClass, group string) bool {
+	if class == nil {
+		return false
+	}
+	for _, allowed := range strings.Split(class.Annotations[AllowedGroupsKey], ",") {
+		if allowed = strings.TrimSpace(allowed); allowed == "*" || allowed == group {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupForIngress returns the group an Ingress was synthesized for, and false
+// if the Ingress is a regular Ingress.
+func GroupForIngress(ing *v1.Ingress) (string, bool) {
+	if ing == nil {
+		return "", false
+	}
+	group, ok := ing.Annotations[GroupKey]
+	return group, ok
+}
+
+// Conflict is a rule of a member of a group which is ignored because an older
+// member of the group already defines it.
+type Conflict struct {
+	// Ingress is the member whose rule is ignored.
+	Ingress *v1.Ingress
+	// Owner is the member whose rule is used.
+	Owner *v1.Ingress
+	// Rule describes the ignored rule.
+	Rule string
+}
+
+// String returns the conflict in the form `path "/" of host "foo.com" is
+// already defined by Ingress ns/name`.
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s is already defined by Ingress %s", c.Rule, common.NamespacedName(c.Owner))
+}
+
+// Members sorts the Ingresses of a group from the oldest to the newest, which
+// is the order in which conflicts are resolved. Ingresses of a different class
+// than the oldest Ingress are not members and are reported as conflicts.
+func Members(ings []*v1.Ingress) ([]*v1.Ingress, []Conflict) {
+	sorted := append([]*v1.Ingress(nil), ings...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+		return common.NamespacedName(a) < common.NamespacedName(b)
+	})
+	if len(sorted) == 0 {
+		return nil, nil
+	}
+
+	primary := sorted[0]
+	members := []*v1.Ingress{primary}
+	var conflicts []Conflict
+	for _, ing := range sorted[1:] {
+		if class := ingressClass(ing); class != ingressClass(primary) {
+			conflicts = append(conflicts, Conflict{Ingress: ing, Owner: primary, Rule: fmt.Sprintf("ingress class %q", class)})
+			continue
+		}
+		members = append(members, ing)
+	}
+	return members, conflicts
+}
+
+// ingressClass returns the class of the Ingress set by the annotation or the
+// spec.
+func ingressClass(ing *v1.Ingress) string {
+	if class := annotations.FromIngress(ing).IngressClass(); class != "" {
+		return class
+	}
+	if ing.Spec.IngressClassName != nil {
+		return *ing.Spec.IngressClassName
+	}
+	return ""
+}
+
+// ToIngress synthesizes the Ingress of the load balancer of a group from its
+// members, sorted by Members. The settings of the load balancer, such as its
+// class, static IP or FrontendConfig, are those of the oldest member, and so
+// is its creation time. The rules of the members are merged by MergeURLMaps.
+func ToIngress(group string, members []*v1.Ingress) *v1.Ingress {
+	primary := members[0]
+	ingAnnotations := map[string]string{GroupKey: group}
+	for k, v := range primary.Annotations {
+		// Skip the status annotations recorded on the primary member.
+		if k == annotations.IngressGroupKey || strings.HasPrefix(k, annotations.StatusPrefix+"/") {
+			continue
+		}
+		ingAnnotations[k] = v
+	}
+	return &v1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: "networking.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         ingressNamespace,
+			Name:              ingressNamePrefix + group,
+			CreationTimestamp: primary.CreationTimestamp,
+			Annotations:       ingAnnotations,
+			// The groups always use the v2 frontend naming scheme.
+			Finalizers: []string{common.FinalizerKeyV2},
+		},
+		Spec: v1.IngressSpec{
+			IngressClassName: primary.Spec.IngressClassName,
+		},
+	}
+}
+
+// Member is a member of a group and its translated URL map.
+type Member struct {
+	Ingress *v1.Ingress
+	URLMap  *utils.GCEURLMap
+}
+
+// hostRules are the merged rules of a host.
+type hostRules struct {
+	paths      []utils.PathRule
+	routeRules []utils.RouteRule
+	action     *utils.RouteAction
+	// first is the first member which defined rules for the host.
+	first *v1.Ingress
+	// owner is set if a member configures the whole host, with route
+	// rules or a host action. No other member can add rules to the host.
+	owner *v1.Ingress
+	// pathOwners are the members which defined each path.
+	pathOwners map[string]*v1.Ingress
+}
+
+// MergeURLMaps merges the URL maps of the members of a group, sorted by
+// Members, into the URL map of the group. A rule defined by several members
+// is taken from the oldest one, and reported as a conflict for the others.
+// The default backend of the group is the first default backend set in the
+// spec of a member.
+func MergeURLMaps(members []Member, logger klog.Logger) (*utils.GCEURLMap, []Conflict) {
+	urlMap := utils.NewGCEURLMap(logger)
+	var conflicts []Conflict
+	var defaultBackendOwner, errorResponsesOwner *v1.Ingress
+	var hosts []string
+	rulesByHost := map[string]*hostRules{}
+
+	for _, m := range members {
+		conflict := func(owner *v1.Ingress, format string, args ...interface{}) {
+			conflicts = append(conflicts, Conflict{Ingress: m.Ingress, Owner: owner, Rule: fmt.Sprintf(format, args...)})
+		}
+
+		if m.Ingress.Spec.DefaultBackend != nil {
+			if defaultBackendOwner == nil {
+				defaultBackendOwner = m.Ingress
+				urlMap.DefaultBackend = m.URLMap.DefaultBackend
+			} else {
+				conflict(defaultBackendOwner, "default backend")
+			}
+		}
+		if m.URLMap.ErrorResponsePolicy != nil {
+			if errorResponsesOwner == nil {
+				errorResponsesOwner = m.Ingress
+				urlMap.ErrorResponsePolicy = m.URLMap.ErrorResponsePolicy
+			} else {
+				conflict(errorResponsesOwner, "custom error responses")
+			}
+		}
+
+		for _, hr := range m.URLMap.HostRules {
+			rules, exists := rulesByHost[hr.Hostname]
+			wholeHost := len(hr.RouteRules) > 0 || hr.Action != nil
+			switch {
+			case !exists:
+				rules = &hostRules{first: m.Ingress, pathOwners: map[string]*v1.Ingress{}}
+				rulesByHost[hr.Hostname] = rules
+				hosts = append(hosts, hr.Hostname)
+			case rules.owner != nil:
+				conflict(rules.owner, "host %q", hr.Hostname)
+				continue
+			case wholeHost:
+				conflict(rules.first, "host %q", hr.Hostname)
+				continue
+			}
+			if wholeHost {
+				rules.owner = m.Ingress
+				rules.routeRules = hr.RouteRules
+				rules.action = hr.Action
+			}
+			for _, pr := range hr.Paths {
+				if owner, ok := rules.pathOwners[pr.Path]; ok {
+					conflict(owner, "path %q of host %q", pr.Path, hr.Hostname)
+					continue
+				}
+				rules.pathOwners[pr.Path] = m.Ingress
+				rules.paths = append(rules.paths, pr)
+			}
+		}
+	}
+
+	if defaultBackendOwner == nil && len(members) > 0 {
+		// All members use the default backend of the cluster.
+		urlMap.DefaultBackend = members[0].URLMap.DefaultBackend
+	}
+	for _, host := range hosts {
+		rules := rulesByHost[host]
+		urlMap.PutPathRulesForHost(host, rules.paths)
+		if len(rules.routeRules) > 0 {
+			urlMap.PutRouteRulesForHost(host, rules.routeRules)
+		}
+		if rules.action != nil {
+			urlMap.PutActionForHost(host, rules.action)
+		}
+	}
+	return urlMap, conflicts
+}
+
+// MergeRuntimeInfo merges the runtime info of the members of a group, sorted
+// by Members, into the runtime info of the load balancer of the group. The
+// load balancer serves the TLS certificates of all members, and the other
+// settings of the oldest member.
+func MergeRuntimeInfo(ing *v1.Ingress, urlMap *utils.GCEURLMap, infos []*loadbalancers.L7RuntimeInfo) *loadbalancers.L7RuntimeInfo {
+	merged := *infos[0]
+	merged.Ingress = ing
+	merged.UrlMap = urlMap
+	merged.TLS = nil
+	seen := map[string]bool{}
+	for _, info := range infos {
+		for _, cert := range info.TLS {
+			if !seen[cert.CertHash] {
+				seen[cert.CertHash] = true
+				merged.TLS = append(merged.TLS, cert)
+			}
+		}
+	}
+	return &merged
+}
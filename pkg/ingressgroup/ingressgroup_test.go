@@ -0,0 +1,328 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingressgroup
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/loadbalancers"
+	"k8s.io/ingress-gce/pkg/translator"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/common"
+	"k8s.io/klog/v2"
+)
+
+var baseTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newIngress(namespace, name string, age time.Duration, ann map[string]string) *v1.Ingress {
+	return &v1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         namespace,
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(baseTime.Add(-age)),
+			Annotations:       ann,
+		},
+	}
+}
+
+func svcPort(namespace, name string) utils.ServicePort {
+	return utils.ServicePort{ID: utils.ServicePortID{Service: types.NamespacedName{Namespace: namespace, Name: name}}}
+}
+
+func TestMembers(t *testing.T) {
+	newest := newIngress("b", "ing", time.Minute, nil)
+	oldest := newIngress("a", "ing", time.Hour, nil)
+	sameAgeFirst := newIngress("a", "other", time.Minute, nil)
+	internal := newIngress("c", "ing", time.Second, map[string]string{annotations.IngressClassKey: annotations.GceL7ILBIngressClass})
+
+	members, conflicts := Members([]*v1.Ingress{newest, internal, oldest, sameAgeFirst})
+	if want := []*v1.Ingress{oldest, sameAgeFirst, newest}; !reflect.DeepEqual(members, want) {
+		var got []string
+		for _, ing := range members {
+			got = append(got, common.NamespacedName(ing))
+		}
+		t.Errorf("Members() = %v, want a/ing, a/other, b/ing", got)
+	}
+	if len(conflicts) != 1 || conflicts[0].Ingress != internal || conflicts[0].Owner != oldest {
+		t.Errorf("Members() conflicts = %v, want the conflict of c/ing with a/ing", conflicts)
+	}
+}
+
+func TestMergeURLMaps(t *testing.T) {
+	teamA := newIngress("team-a", "ing", time.Hour, nil)
+	teamB := newIngress("team-b", "ing", time.Minute, nil)
+	defaultBackend := svcPort("kube-system", "default-http-backend")
+
+	for _, tc := range []struct {
+		desc          string
+		a, b          func(*utils.GCEURLMap)
+		bHasDefault   bool
+		wantHosts     map[string][]utils.PathRule
+		wantDefault   utils.ServicePort
+		wantConflicts []string
+	}{
+		{
+			desc: "disjoint hosts",
+			a: func(m *utils.GCEURLMap) {
+				m.PutPathRulesForHost("a.com", []utils.PathRule{{Path: "/", Backend: svcPort("team-a", "web")}})
+			},
+			b: func(m *utils.GCEURLMap) {
+				m.PutPathRulesForHost("b.com", []utils.PathRule{{Path: "/", Backend: svcPort("team-b", "web")}})
+			},
+			wantHosts: map[string][]utils.PathRule{
+				"a.com": {{Path: "/", Backend: svcPort("team-a", "web")}},
+				"b.com": {{Path: "/", Backend: svcPort("team-b", "web")}},
+			},
+			wantDefault: defaultBackend,
+		},
+		{
+			desc: "paths of the same host",
+			a: func(m *utils.GCEURLMap) {
+				m.PutPathRulesForHost("shop.com", []utils.PathRule{{Path: "/cart", Backend: svcPort("team-a", "cart")}})
+			},
+			b: func(m *utils.GCEURLMap) {
+				m.PutPathRulesForHost("shop.com", []utils.PathRule{{Path: "/search", Backend: svcPort("team-b", "search")}})
+			},
+			wantHosts: map[string][]utils.PathRule{
+				"shop.com": {
+					{Path: "/cart", Backend: svcPort("team-a", "cart")},
+					{Path: "/search", Backend: svcPort("team-b", "search")},
+				},
+			},
+			wantDefault: defaultBackend,
+		},
+		{
+			desc: "duplicate path",
+			a: func(m *utils.GCEURLMap) {
+				m.PutPathRulesForHost("shop.com", []utils.PathRule{{Path: "/cart", Backend: svcPort("team-a", "cart")}})
+			},
+			b: func(m *utils.GCEURLMap) {
+				m.PutPathRulesForHost("shop.com", []utils.PathRule{
+					{Path: "/cart", Backend: svcPort("team-b", "cart")},
+					{Path: "/search", Backend: svcPort("team-b", "search")},
+				})
+			},
+			wantHosts: map[string][]utils.PathRule{
+				"shop.com": {
+					{Path: "/cart", Backend: svcPort("team-a", "cart")},
+					{Path: "/search", Backend: svcPort("team-b", "search")},
+				},
+			},
+			wantDefault:   defaultBackend,
+			wantConflicts: []string{`path "/cart" of host "shop.com" is already defined by Ingress team-a/ing`},
+		},
+		{
+			desc: "host with route rules",
+			a: func(m *utils.GCEURLMap) {
+				m.PutPathRulesForHost("shop.com", []utils.PathRule{{Path: "/cart", Backend: svcPort("team-a", "cart")}})
+				m.PutRouteRulesForHost("shop.com", []utils.RouteRule{{Priority: 1, Backend: svcPort("team-a", "canary")}})
+			},
+			b: func(m *utils.GCEURLMap) {
+				m.PutPathRulesForHost("shop.com", []utils.PathRule{{Path: "/search", Backend: svcPort("team-b", "search")}})
+			},
+			wantHosts: map[string][]utils.PathRule{
+				"shop.com": {{Path: "/cart", Backend: svcPort("team-a", "cart")}},
+			},
+			wantDefault:   defaultBackend,
+			wantConflicts: []string{`host "shop.com" is already defined by Ingress team-a/ing`},
+		},
+		{
+			desc: "default backend of a newer member",
+			a: func(m *utils.GCEURLMap) {
+				m.PutPathRulesForHost("a.com", []utils.PathRule{{Path: "/", Backend: svcPort("team-a", "web")}})
+			},
+			b: func(m *utils.GCEURLMap) {
+				m.DefaultBackend = &utils.ServicePort{ID: svcPort("team-b", "fallback").ID}
+			},
+			bHasDefault: true,
+			wantHosts: map[string][]utils.PathRule{
+				"a.com": {{Path: "/", Backend: svcPort("team-a", "web")}},
+			},
+			wantDefault: svcPort("team-b", "fallback"),
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			a := newURLMap(defaultBackend)
+			tc.a(a)
+			b := newURLMap(defaultBackend)
+			tc.b(b)
+			ingB := teamB.DeepCopy()
+			if tc.bHasDefault {
+				ingB.Spec.DefaultBackend = &v1.IngressBackend{}
+			}
+
+			got, conflicts := MergeURLMaps([]Member{{Ingress: teamA, URLMap: a}, {Ingress: ingB, URLMap: b}}, klog.TODO())
+			gotHosts := map[string][]utils.PathRule{}
+			for _, hr := range got.HostRules {
+				gotHosts[hr.Hostname] = hr.Paths
+			}
+			if !reflect.DeepEqual(gotHosts, tc.wantHosts) {
+				t.Errorf("MergeURLMaps() hosts = %+v, want %+v", gotHosts, tc.wantHosts)
+			}
+			if got.DefaultBackend == nil || got.DefaultBackend.ID != tc.wantDefault.ID {
+				t.Errorf("MergeURLMaps() default backend = %v, want %v", got.DefaultBackend, tc.wantDefault.ID)
+			}
+			var gotConflicts []string
+			for _, c := range conflicts {
+				gotConflicts = append(gotConflicts, c.String())
+			}
+			if !reflect.DeepEqual(gotConflicts, tc.wantConflicts) {
+				t.Errorf("MergeURLMaps() conflicts = %q, want %q", gotConflicts, tc.wantConflicts)
+			}
+		})
+	}
+}
+
+func newURLMap(defaultBackend utils.ServicePort) *utils.GCEURLMap {
+	m := utils.NewGCEURLMap(klog.TODO())
+	m.DefaultBackend = &defaultBackend
+	return m
+}
+
+func TestToIngress(t *testing.T) {
+	primary := newIngress("team-a", "ing", time.Hour, map[string]string{
+		annotations.IngressGroupKey:       "shop",
+		annotations.IngressClassKey:       annotations.GceIngressClass,
+		annotations.GlobalStaticIPNameKey: "shop-ip",
+		annotations.UrlMapKey:             "k8s2-um-standalone",
+	})
+	other := newIngress("team-b", "ing", time.Minute, map[string]string{
+		annotations.IngressGroupKey: "shop",
+		annotations.AllowHTTPKey:    "false",
+	})
+
+	ing := ToIngress("shop", []*v1.Ingress{primary, other})
+	if got := common.NamespacedName(ing); got != IngressKey("shop") {
+		t.Errorf("ToIngress() = %s, want %s", got, IngressKey("shop"))
+	}
+	if group, ok := GroupForIngress(ing); !ok || group != "shop" {
+		t.Errorf("GroupForIngress(ToIngress()) = %q, %v, want %q, true", group, ok, "shop")
+	}
+	wantAnnotations := map[string]string{
+		GroupKey:                          "shop",
+		annotations.IngressClassKey:       annotations.GceIngressClass,
+		annotations.GlobalStaticIPNameKey: "shop-ip",
+	}
+	if !reflect.DeepEqual(ing.Annotations, wantAnnotations) {
+		t.Errorf("ToIngress() annotations = %v, want %v", ing.Annotations, wantAnnotations)
+	}
+	if !reflect.DeepEqual(ing.Finalizers, []string{common.FinalizerKeyV2}) {
+		t.Errorf("ToIngress() finalizers = %v, want %v", ing.Finalizers, []string{common.FinalizerKeyV2})
+	}
+	if !ing.CreationTimestamp.Equal(&primary.CreationTimestamp) {
+		t.Errorf("ToIngress() creation time = %v, want the creation time of the oldest member %v", ing.CreationTimestamp, primary.CreationTimestamp)
+	}
+	if group, ok := GroupForIngressName(ing.Namespace, ing.Name); !ok || group != "shop" {
+		t.Errorf("GroupForIngressName(%s) = %q, %v, want %q, true", common.NamespacedName(ing), group, ok, "shop")
+	}
+	if group, ok := GroupForIngressName("default", ing.Name); ok {
+		t.Errorf("GroupForIngressName(default/%s) = %q, true, want false", ing.Name, group)
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	for _, tc := range []struct {
+		desc    string
+		allowed *string
+		want    bool
+	}{
+		{desc: "no IngressClass"},
+		{desc: "no annotation", allowed: new(string)},
+		{desc: "listed", allowed: strPtr("team-a, shop"), want: true},
+		{desc: "not listed", allowed: strPtr("team-a,team-b")},
+		{desc: "any group", allowed: strPtr("*"), want: true},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var class *v1.IngressClass
+			if tc.allowed != nil {
+				class = &v1.IngressClass{ObjectMeta: metav1.ObjectMeta{Name: "gce", Annotations: map[string]string{AllowedGroupsKey: *tc.allowed}}}
+			}
+			if got := Allowed(class, "shop"); got != tc.want {
+				t.Errorf("Allowed(%+v, %q) = %v, want %v", class, "shop", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClassName(t *testing.T) {
+	className := "internal"
+	for _, tc := range []struct {
+		desc string
+		ing  *v1.Ingress
+		want string
+	}{
+		{desc: "annotation", ing: newIngress("ns", "ing", 0, map[string]string{annotations.IngressClassKey: annotations.GceL7ILBIngressClass}), want: annotations.GceL7ILBIngressClass},
+		{desc: "spec", ing: func() *v1.Ingress {
+			ing := newIngress("ns", "ing", 0, nil)
+			ing.Spec.IngressClassName = &className
+			return ing
+		}(), want: className},
+		{desc: "no class", ing: newIngress("ns", "ing", 0, nil), want: annotations.GceIngressClass},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := ClassName(tc.ing); got != tc.want {
+				t.Errorf("ClassName() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestMergeRuntimeInfo(t *testing.T) {
+	ing := ToIngress("shop", []*v1.Ingress{newIngress("team-a", "ing", time.Hour, nil)})
+	urlMap := utils.NewGCEURLMap(klog.TODO())
+	certA := &translator.TLSCerts{Name: "a", CertHash: "aaa"}
+	certB := &translator.TLSCerts{Name: "b", CertHash: "bbb"}
+	infos := []*loadbalancers.L7RuntimeInfo{
+		{TLS: []*translator.TLSCerts{certA}, AllowHTTP: false, StaticIPName: "shop-ip"},
+		{TLS: []*translator.TLSCerts{certA, certB}, AllowHTTP: true},
+	}
+
+	got := MergeRuntimeInfo(ing, urlMap, infos)
+	if got.Ingress != ing || got.UrlMap != urlMap {
+		t.Errorf("MergeRuntimeInfo() = %+v, want the Ingress and URL map of the group", got)
+	}
+	if !reflect.DeepEqual(got.TLS, []*translator.TLSCerts{certA, certB}) {
+		t.Errorf("MergeRuntimeInfo() TLS = %v, want the certificates of all members", got.TLS)
+	}
+	if got.AllowHTTP || got.StaticIPName != "shop-ip" {
+		t.Errorf("MergeRuntimeInfo() = %+v, want the settings of the oldest member", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for group, wantErr := range map[string]bool{
+		"shop":      false,
+		"shop-2024": false,
+		"Shop":      true,
+		"shop.com":  true,
+		"":          true,
+	} {
+		if err := Validate(group); (err != nil) != wantErr {
+			t.Errorf("Validate(%q) = %v, want error: %v", group, err, wantErr)
+		}
+	}
+}
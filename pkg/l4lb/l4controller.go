@@ -34,12 +34,15 @@ import (
 	"k8s.io/ingress-gce/pkg/annotations"
 	"k8s.io/ingress-gce/pkg/backends"
 	"k8s.io/ingress-gce/pkg/context"
+	"k8s.io/ingress-gce/pkg/dryrun"
+	"k8s.io/ingress-gce/pkg/flags"
 	"k8s.io/ingress-gce/pkg/forwardingrules"
 	l4metrics "k8s.io/ingress-gce/pkg/l4lb/metrics"
 	"k8s.io/ingress-gce/pkg/loadbalancers"
 	"k8s.io/ingress-gce/pkg/metrics"
 	negtypes "k8s.io/ingress-gce/pkg/neg/types"
 	"k8s.io/ingress-gce/pkg/network"
+	"k8s.io/ingress-gce/pkg/pause"
 	"k8s.io/ingress-gce/pkg/utils"
 	"k8s.io/ingress-gce/pkg/utils/common"
 	"k8s.io/ingress-gce/pkg/utils/namer"
@@ -89,6 +92,7 @@ type L4Controller struct {
 // NewILBController creates a new instance of the L4 ILB controller.
 func NewILBController(ctx *context.ControllerContext, stopCh <-chan struct{}, logger klog.Logger) *L4Controller {
 	logger = logger.WithName("L4Controller")
+	pause.RegisterMetrics()
 	if ctx.NumL4Workers <= 0 {
 		logger.Info("L4 Internal LB Service worker count has not been set, setting to 1")
 		ctx.NumL4Workers = 1
@@ -425,13 +429,27 @@ func (l4c *L4Controller) sync(key string, svcLogger klog.Logger) error {
 		// The service will not exist if its resources and finalizer are handled by the legacy service controller and
 		// it has been deleted. As long as the V2 finalizer is present, the service will not be deleted by apiserver.
 		svcLogger.V(3).Info("Ignoring delete of service not managed by L4 controller")
+		pause.Resume(pause.KindService, l4ILBControllerName, key)
 		return nil
 	}
+	if annotations.FromService(svc).ReconciliationPaused() {
+		svcLogger.Info("Reconciliation of service is paused, skipping sync")
+		pause.Report(l4c.ctx.Recorder(svc.Namespace), svc, pause.KindService, l4ILBControllerName)
+		return nil
+	}
+	pause.Resume(pause.KindService, l4ILBControllerName, key)
+	if flags.F.DryRun {
+		return l4c.planService(svc, svcLogger)
+	}
 	isResync := l4c.serviceVersions.IsResync(key, svc.ResourceVersion, svcLogger)
 	svcLogger.V(2).Info("Processing update operation for service", "resync", isResync, "resourceVersion", svc.ResourceVersion)
 	namespacedName := types.NamespacedName{Name: svc.Name, Namespace: svc.Namespace}.String()
 	var result *loadbalancers.L4ILBSyncResult
 	if l4c.needsDeletion(svc) {
+		if isDeletionProtected(l4c.ctx, svc) {
+			svcLogger.Info("Service is protected from deletion, keeping ILB resources")
+			return nil
+		}
 		svcLogger.V(2).Info("Deleting ILB resources for service managed by L4 controller")
 		result = l4c.processServiceDeletion(key, svc, svcLogger)
 		if result == nil {
@@ -458,6 +476,35 @@ func (l4c *L4Controller) sync(key string, svcLogger klog.Logger) error {
 	return nil
 }
 
+// planService reports the changes a sync of svc would make to GCE resources
+// as an event, without making them.
+func (l4c *L4Controller) planService(svc *v1.Service, svcLogger klog.Logger) error {
+	l4ilbParams := &loadbalancers.L4ILBParams{
+		Service:          svc,
+		Cloud:            l4c.ctx.Cloud,
+		Namer:            l4c.namer,
+		Recorder:         l4c.ctx.Recorder(svc.Namespace),
+		DualStackEnabled: l4c.enableDualStack,
+		NetworkResolver:  l4c.networkResolver,
+	}
+	l4 := loadbalancers.NewL4Handler(l4ilbParams, svcLogger)
+	var plan *dryrun.Plan
+	var err error
+	if l4c.needsDeletion(svc) {
+		plan, err = l4.PlanInternalLoadBalancerDeleted(svc)
+	} else if wantsILB, _ := annotations.WantsL4ILB(svc); wantsILB && l4c.shouldProcessService(svc, svcLogger) {
+		plan, err = l4.PlanInternalLoadBalancer(svc)
+	} else {
+		return nil
+	}
+	if err != nil {
+		l4c.ctx.Recorder(svc.Namespace).Eventf(svc, v1.EventTypeWarning, dryrun.EventReason, "Error planning load balancer: %v", err)
+		return err
+	}
+	dryrun.Report(l4c.ctx.Recorder(svc.Namespace), svc, plan)
+	return nil
+}
+
 func (l4c *L4Controller) needsDeletion(svc *v1.Service) bool {
 	if !utils.IsSubsettingL4ILBService(svc) {
 		return false
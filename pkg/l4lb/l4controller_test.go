@@ -260,6 +260,81 @@ func TestProcessDeletion(t *testing.T) {
 	}
 }
 
+func TestProcessDeletionOfPausedService(t *testing.T) {
+	l4c := newServiceController(t, newFakeGCE())
+	newSvc := test.NewL4ILBService(false, 8080)
+	addILBService(l4c, newSvc)
+	addNEG(l4c, newSvc)
+	if err := l4c.sync(getKeyForSvc(newSvc, t), klog.TODO()); err != nil {
+		t.Errorf("Failed to sync newly added service %s, err %v", newSvc.Name, err)
+	}
+	newSvc, err := l4c.client.CoreV1().Services(newSvc.Namespace).Get(context2.TODO(), newSvc.Name, v1.GetOptions{})
+	if err != nil {
+		t.Errorf("Failed to lookup service %s, err: %v", newSvc.Name, err)
+	}
+	verifyILBServiceProvisioned(t, newSvc)
+
+	// Mark the paused service for deletion, its resources must be kept.
+	newSvc.Annotations[annotations.PauseReconciliationKey] = "true"
+	newSvc.DeletionTimestamp = &v1.Time{}
+	updateILBService(l4c, newSvc)
+	if err := l4c.sync(getKeyForSvc(newSvc, t), klog.TODO()); err != nil {
+		t.Errorf("Failed to sync paused service %s, err %v", newSvc.Name, err)
+	}
+	newSvc, err = l4c.client.CoreV1().Services(newSvc.Namespace).Get(context2.TODO(), newSvc.Name, v1.GetOptions{})
+	if err != nil {
+		t.Errorf("Failed to lookup service %s, err: %v", newSvc.Name, err)
+	}
+	verifyILBServiceProvisioned(t, newSvc)
+	frName := l4c.namer.L4ForwardingRule(newSvc.Namespace, newSvc.Name, "tcp")
+	if _, err := l4c.ctx.Cloud.GetRegionForwardingRule(frName, l4c.ctx.Cloud.Region()); err != nil {
+		t.Errorf("Forwarding rule %s of the paused service was deleted, err: %v", frName, err)
+	}
+}
+
+func TestProcessDeletionOfProtectedService(t *testing.T) {
+	l4c := newServiceController(t, newFakeGCE())
+	newSvc := test.NewL4ILBService(false, 8080)
+	newSvc.Annotations[annotations.DeletionProtectionKey] = "true"
+	addILBService(l4c, newSvc)
+	addNEG(l4c, newSvc)
+	if err := l4c.sync(getKeyForSvc(newSvc, t), klog.TODO()); err != nil {
+		t.Errorf("Failed to sync newly added service %s, err %v", newSvc.Name, err)
+	}
+	newSvc, err := l4c.client.CoreV1().Services(newSvc.Namespace).Get(context2.TODO(), newSvc.Name, v1.GetOptions{})
+	if err != nil {
+		t.Errorf("Failed to lookup service %s, err: %v", newSvc.Name, err)
+	}
+	verifyILBServiceProvisioned(t, newSvc)
+
+	newSvc.DeletionTimestamp = &v1.Time{}
+	updateILBService(l4c, newSvc)
+	if err := l4c.sync(getKeyForSvc(newSvc, t), klog.TODO()); err != nil {
+		t.Errorf("Failed to sync protected service %s, err %v", newSvc.Name, err)
+	}
+	newSvc, err = l4c.client.CoreV1().Services(newSvc.Namespace).Get(context2.TODO(), newSvc.Name, v1.GetOptions{})
+	if err != nil {
+		t.Errorf("Failed to lookup service %s, err: %v", newSvc.Name, err)
+	}
+	verifyILBServiceProvisioned(t, newSvc)
+	frName := l4c.namer.L4ForwardingRule(newSvc.Namespace, newSvc.Name, "tcp")
+	if _, err := l4c.ctx.Cloud.GetRegionForwardingRule(frName, l4c.ctx.Cloud.Region()); err != nil {
+		t.Errorf("Forwarding rule %s of the protected service was deleted, err: %v", frName, err)
+	}
+
+	// Removing the protection deletes the load balancer.
+	delete(newSvc.Annotations, annotations.DeletionProtectionKey)
+	updateILBService(l4c, newSvc)
+	if err := l4c.sync(getKeyForSvc(newSvc, t), klog.TODO()); err != nil {
+		t.Errorf("Failed to sync updated service %s, err %v", newSvc.Name, err)
+	}
+	newSvc, err = l4c.client.CoreV1().Services(newSvc.Namespace).Get(context2.TODO(), newSvc.Name, v1.GetOptions{})
+	if err != nil {
+		t.Errorf("Failed to lookup service %s, err: %v", newSvc.Name, err)
+	}
+	verifyILBServiceNotProvisioned(t, newSvc)
+}
+
 func TestProcessCreateLegacyService(t *testing.T) {
 	l4c := newServiceController(t, newFakeGCE())
 	prevMetrics, err := test.GetL4ILBLatencyMetric()
@@ -845,7 +920,7 @@ func newServiceController(t *testing.T, fakeGCE *gce.Cloud) *L4Controller {
 		ResyncPeriod: 1 * time.Minute,
 		NumL4Workers: 5,
 	}
-	ctx := context.NewControllerContext(nil, kubeClient, nil, nil, nil, nil, nil, nil, nil, fakeGCE, namer, "" /*kubeSystemUID*/, ctxConfig, klog.TODO())
+	ctx := context.NewControllerContext(nil, kubeClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, fakeGCE, namer, "" /*kubeSystemUID*/, ctxConfig, klog.TODO())
 	// Add some nodes so that NEG linker kicks in during ILB creation.
 	nodes, err := test.CreateAndInsertNodes(ctx.Cloud, []string{"instance-1"}, vals.ZoneName)
 	if err != nil {
@@ -25,8 +25,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cloud-provider-gcp/providers/gce"
 	"k8s.io/cloud-provider/service/helpers"
+	"k8s.io/ingress-gce/pkg/annotations"
 	"k8s.io/ingress-gce/pkg/composite"
 	"k8s.io/ingress-gce/pkg/context"
+	"k8s.io/ingress-gce/pkg/events"
 	"k8s.io/ingress-gce/pkg/loadbalancers"
 	"k8s.io/ingress-gce/pkg/utils"
 	"k8s.io/ingress-gce/pkg/utils/patch"
@@ -120,3 +122,18 @@ func skipUserError(err error, logger klog.Logger) error {
 	}
 	return err
 }
+
+// deletionProtectedEvents limits the events of isDeletionProtected.
+var deletionProtectedEvents = events.NewLimiter(events.DeletionProtectedInterval)
+
+// isDeletionProtected returns true if the load balancer of the service must
+// not be deleted, and emits an event telling why at most once per
+// events.DeletionProtectedInterval.
+func isDeletionProtected(ctx *context.ControllerContext, svc *v1.Service) bool {
+	if !annotations.FromService(svc).DeletionProtected() {
+		return false
+	}
+	deletionProtectedEvents.Eventf(ctx.Recorder(svc.Namespace), svc, v1.EventTypeWarning, events.DeletionProtected,
+		"Keeping the load balancer of the service because of the %s annotation", annotations.DeletionProtectionKey)
+	return true
+}
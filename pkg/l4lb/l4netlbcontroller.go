@@ -33,6 +33,8 @@ import (
 	"k8s.io/ingress-gce/pkg/annotations"
 	"k8s.io/ingress-gce/pkg/backends"
 	"k8s.io/ingress-gce/pkg/context"
+	"k8s.io/ingress-gce/pkg/dryrun"
+	"k8s.io/ingress-gce/pkg/flags"
 	"k8s.io/ingress-gce/pkg/forwardingrules"
 	"k8s.io/ingress-gce/pkg/instancegroups"
 	l4metrics "k8s.io/ingress-gce/pkg/l4lb/metrics"
@@ -40,6 +42,7 @@ import (
 	"k8s.io/ingress-gce/pkg/metrics"
 	negtypes "k8s.io/ingress-gce/pkg/neg/types"
 	"k8s.io/ingress-gce/pkg/network"
+	"k8s.io/ingress-gce/pkg/pause"
 	"k8s.io/ingress-gce/pkg/utils"
 	"k8s.io/ingress-gce/pkg/utils/common"
 	"k8s.io/ingress-gce/pkg/utils/namer"
@@ -90,6 +93,7 @@ func NewL4NetLBController(
 	stopCh <-chan struct{},
 	logger klog.Logger) *L4NetLBController {
 	logger = logger.WithName("L4NetLBController")
+	pause.RegisterMetrics()
 	if ctx.NumL4NetLBWorkers <= 0 {
 		logger.Info("External L4 worker count has not been set, setting to 1")
 		ctx.NumL4NetLBWorkers = 1
@@ -344,10 +348,14 @@ func (lc *L4NetLBController) hasTargetPoolForwardingRule(service *v1.Service, sv
 }
 
 func (lc *L4NetLBController) preventTargetPoolRaceWithRBSOnCreation(service *v1.Service, key string, svcLogger klog.Logger) error {
+	l4metrics.IncreaseL4NetLBTargetPoolRaceWithRBS()
+	// The RBS resources of a protected service are kept, the service stays
+	// with the Target Pool implementation.
+	if isDeletionProtected(lc.ctx, service) {
+		return nil
+	}
 	lc.ctx.Recorder(service.Namespace).Eventf(service, v1.EventTypeWarning, "TargetPoolRaceWithRBS",
 		"Target Pool found on provisioned RBS service. Deleting RBS resources")
-
-	l4metrics.IncreaseL4NetLBTargetPoolRaceWithRBS()
 	result := lc.garbageCollectRBSNetLB(key, service, svcLogger)
 	if result.Error != nil {
 		lc.ctx.Recorder(service.Namespace).Eventf(service, v1.EventTypeWarning, "CleanRBSResourcesForLegacyService",
@@ -443,8 +451,18 @@ func (lc *L4NetLBController) sync(key string, svcLogger klog.Logger) error {
 	}
 	if !exists || svc == nil {
 		svcLogger.V(3).Info("Ignoring sync of non-existent service")
+		pause.Resume(pause.KindService, l4NetLBControllerName, key)
+		return nil
+	}
+	if annotations.FromService(svc).ReconciliationPaused() {
+		svcLogger.Info("Reconciliation of service is paused, skipping sync")
+		pause.Report(lc.ctx.Recorder(svc.Namespace), svc, pause.KindService, l4NetLBControllerName)
 		return nil
 	}
+	pause.Resume(pause.KindService, l4NetLBControllerName, key)
+	if flags.F.DryRun {
+		return lc.planService(svc, svcLogger)
+	}
 	isLegacyService, err := lc.preventLegacyServiceHandling(svc, key, svcLogger)
 	if err != nil {
 		svcLogger.Info("lc.preventLegacyServiceHandling returned error, want nil", "service", svc, "err", err)
@@ -457,6 +475,10 @@ func (lc *L4NetLBController) sync(key string, svcLogger klog.Logger) error {
 	isResync := lc.serviceVersions.IsResync(key, svc.ResourceVersion, svcLogger)
 	svcLogger.Info("Processing update operation for service", "resync", isResync, "resourceVersion", svc.ResourceVersion)
 	if lc.needsDeletion(svc, svcLogger) {
+		if isDeletionProtected(lc.ctx, svc) {
+			svcLogger.Info("Service is protected from deletion, keeping L4 External LoadBalancer resources")
+			return nil
+		}
 		svcLogger.V(3).Info("Deleting L4 External LoadBalancer resources for service")
 		result := lc.garbageCollectRBSNetLB(key, svc, svcLogger)
 		if result == nil {
@@ -481,6 +503,39 @@ func (lc *L4NetLBController) sync(key string, svcLogger klog.Logger) error {
 	return nil
 }
 
+// planService reports the changes a sync of svc would make to GCE resources
+// as an event, without making them. Legacy target pool services are skipped.
+func (lc *L4NetLBController) planService(svc *v1.Service, svcLogger klog.Logger) error {
+	if annotations.HasRBSAnnotation(svc) && lc.hasTargetPoolForwardingRule(svc, svcLogger) {
+		return nil
+	}
+	l4NetLBParams := &loadbalancers.L4NetLBParams{
+		Service:                      svc,
+		Cloud:                        lc.ctx.Cloud,
+		Namer:                        lc.namer,
+		Recorder:                     lc.ctx.Recorder(svc.Namespace),
+		DualStackEnabled:             lc.enableDualStack,
+		StrongSessionAffinityEnabled: lc.enableStrongSessionAffinity,
+		NetworkResolver:              lc.networkResolver,
+	}
+	l4netlb := loadbalancers.NewL4NetLB(l4NetLBParams, svcLogger)
+	var plan *dryrun.Plan
+	var err error
+	if lc.needsDeletion(svc, svcLogger) {
+		plan, err = l4netlb.PlanLoadBalancerDeleted(svc)
+	} else if wantsNetLB, _ := annotations.WantsL4NetLB(svc); wantsNetLB && lc.isRBSBasedService(svc, svcLogger) {
+		plan, err = l4netlb.PlanFrontend(svc)
+	} else {
+		return nil
+	}
+	if err != nil {
+		lc.ctx.Recorder(svc.Namespace).Eventf(svc, v1.EventTypeWarning, dryrun.EventReason, "Error planning load balancer: %v", err)
+		return err
+	}
+	dryrun.Report(lc.ctx.Recorder(svc.Namespace), svc, plan)
+	return nil
+}
+
 // syncInternal ensures load balancer resources for the given service, as needed.
 // Returns an error if processing the service update failed.
 func (lc *L4NetLBController) syncInternal(service *v1.Service, svcLogger klog.Logger) *loadbalancers.L4NetLBSyncResult {
@@ -280,7 +280,7 @@ func buildContext(vals gce.TestClusterValues) *ingctx.ControllerContext {
 		NumL4NetLBWorkers: 5,
 		MaxIGSize:         1000,
 	}
-	return ingctx.NewControllerContext(nil, kubeClient, nil, nil, nil, nil, nil, nil, networkClient, fakeGCE, namer, "" /*kubeSystemUID*/, ctxConfig, klog.TODO())
+	return ingctx.NewControllerContext(nil, kubeClient, nil, nil, nil, nil, nil, nil, networkClient, nil, nil, fakeGCE, namer, "" /*kubeSystemUID*/, ctxConfig, klog.TODO())
 }
 
 func newL4NetLBServiceController() *L4NetLBController {
@@ -1445,6 +1445,7 @@ func TestPreventTargetPoolToRBSMigration(t *testing.T) {
 		desc                            string
 		frHook                          getForwardingRuleHook
 		finalizer                       string
+		deletionProtected               bool
 		expectV2NetLBFinalizerAfterSync bool
 		expectRBSAnnotationAfterSync    bool
 	}{
@@ -1461,6 +1462,14 @@ func TestPreventTargetPoolToRBSMigration(t *testing.T) {
 			expectV2NetLBFinalizerAfterSync: false,
 			expectRBSAnnotationAfterSync:    false,
 		},
+		{
+			desc:                            "Should keep finalizer and RBS annotation of deletion protected target pool service with RBS finalizer",
+			frHook:                          test.GetLegacyForwardingRule,
+			finalizer:                       common.NetLBFinalizerV2,
+			deletionProtected:               true,
+			expectV2NetLBFinalizerAfterSync: true,
+			expectRBSAnnotationAfterSync:    true,
+		},
 		{
 			desc:                            "Should not remove finalizer and RBS annotation from RBS based service",
 			finalizer:                       common.NetLBFinalizerV2,
@@ -1480,6 +1489,9 @@ func TestPreventTargetPoolToRBSMigration(t *testing.T) {
 		t.Run(testCase.desc, func(t *testing.T) {
 			svc := test.NewL4NetLBRBSServiceMultiplePorts("test", []int32{30234})
 			svc.ObjectMeta.Finalizers = []string{testCase.finalizer}
+			if testCase.deletionProtected {
+				svc.Annotations[annotations.DeletionProtectionKey] = "true"
+			}
 
 			controller := newL4NetLBServiceController()
 			controller.ctx.Cloud.Compute().(*cloud.MockGCE).MockForwardingRules.GetHook = testCase.frHook
@@ -1512,6 +1524,9 @@ func TestPreventTargetPoolToRBSMigration(t *testing.T) {
 			// test that whole sync process is skipped
 			svc2 := test.NewL4NetLBRBSServiceMultiplePorts("test-2", []int32{30234})
 			svc2.ObjectMeta.Finalizers = []string{testCase.finalizer}
+			if testCase.deletionProtected {
+				svc2.Annotations[annotations.DeletionProtectionKey] = "true"
+			}
 			addNetLBService(controller, svc2)
 
 			key, err = common.KeyFunc(svc2)
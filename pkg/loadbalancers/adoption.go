@@ -0,0 +1,275 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/common"
+	"k8s.io/ingress-gce/pkg/utils/namer"
+)
+
+// ingressNameDescriptionKey is the key of the Ingress name in the
+// description of the GCE resources managed by the controller.
+const ingressNameDescriptionKey = "kubernetes.io/ingress-name"
+
+// adoptedFrontend are the adopted names of the target proxy and forwarding
+// rule of a protocol.
+type adoptedFrontend struct {
+	protocol       namer.NamerProtocol
+	proxy          string
+	forwardingRule string
+}
+
+func adoptedFrontends(adopted *annotations.AdoptedResources) []adoptedFrontend {
+	return []adoptedFrontend{
+		{protocol: namer.HTTPProtocol, proxy: adopted.TargetHTTPProxy, forwardingRule: adopted.HTTPForwardingRule},
+		{protocol: namer.HTTPSProtocol, proxy: adopted.TargetHTTPSProxy, forwardingRule: adopted.HTTPSForwardingRule},
+	}
+}
+
+// adoptedResources returns the resources adopted by the Ingress of the load
+// balancer, or nil if it does not adopt any.
+func (l7 *L7) adoptedResources() (*annotations.AdoptedResources, error) {
+	adopted, found, err := annotations.FromIngress(&l7.ingress).AdoptedResources()
+	if err != nil || !found {
+		return nil, err
+	}
+	if scheme := namer.FrontendNamingScheme(&l7.ingress, l7.logger); scheme != namer.V2NamingScheme {
+		return nil, fmt.Errorf("the %s annotation requires the %s naming scheme, Ingress %s uses %s", annotations.AdoptResourcesKey, namer.V2NamingScheme, common.NamespacedName(&l7.ingress), scheme)
+	}
+	return adopted, nil
+}
+
+// adoptionError returns the reason why the namer does not use the names of
+// the resources listed by the Ingress, if any.
+func (l7 *L7) adoptionError() error {
+	if an, ok := l7.namer.(*namer.AdoptedIngressFrontendNamer); ok {
+		return an.Err()
+	}
+	return nil
+}
+
+// checkReleasedResources verifies that the resources adopted by the last sync
+// of the Ingress are still listed by its annotation. A released resource
+// would neither be managed nor deleted with the Ingress, and would keep
+// using its backend services, so releasing resources is not supported.
+func (l7 *L7) checkReleasedResources() error {
+	synced, _, err := annotations.FromIngress(&l7.ingress).AdoptedResourcesStatus()
+	if synced == nil {
+		return err
+	}
+	adopted, _, _ := annotations.FromIngress(&l7.ingress).AdoptedResources()
+	if adopted == nil || l7.adoptionError() != nil {
+		adopted = &annotations.AdoptedResources{}
+	}
+	for _, r := range []struct{ synced, adopted string }{
+		{synced.URLMap, adopted.URLMap},
+		{synced.TargetHTTPProxy, adopted.TargetHTTPProxy},
+		{synced.TargetHTTPSProxy, adopted.TargetHTTPSProxy},
+		{synced.HTTPForwardingRule, adopted.HTTPForwardingRule},
+		{synced.HTTPSForwardingRule, adopted.HTTPSForwardingRule},
+	} {
+		if r.synced != "" && r.synced != r.adopted {
+			return fmt.Errorf("resource %q adopted by Ingress %s is no longer listed by the %s annotation, adopted resources cannot be released", r.synced, common.NamespacedName(&l7.ingress), annotations.AdoptResourcesKey)
+		}
+	}
+	return nil
+}
+
+// checkAdoptedResources verifies that the resources adopted by the Ingress
+// exist, form a single load balancer and are not managed by another Ingress.
+// The sync then takes them over under their adopted names.
+func (l7 *L7) checkAdoptedResources() error {
+	if err := l7.checkReleasedResources(); err != nil {
+		return err
+	}
+	adopted, err := l7.adoptedResources()
+	if adopted == nil {
+		return err
+	}
+	if err := l7.adoptionError(); err != nil {
+		return err
+	}
+	versions := l7.Versions()
+
+	if adopted.URLMap != "" {
+		key, err := l7.CreateKey(adopted.URLMap)
+		if err != nil {
+			return err
+		}
+		um, err := composite.GetUrlMap(l7.cloud, key, versions.UrlMap, l7.logger)
+		if err != nil {
+			return fmt.Errorf("cannot adopt URL map %q: %w", adopted.URLMap, err)
+		}
+		if owner := ingressForDescription(um.Description); owner != "" && owner != common.NamespacedName(&l7.ingress) {
+			return fmt.Errorf("cannot adopt URL map %q managed by Ingress %s", adopted.URLMap, owner)
+		}
+	}
+
+	for _, fe := range adoptedFrontends(adopted) {
+		if fe.proxy != "" {
+			urlMapLink, err := l7.proxyURLMap(fe.protocol, fe.proxy)
+			if err != nil {
+				return fmt.Errorf("cannot adopt target proxy %q: %w", fe.proxy, err)
+			}
+			if adopted.URLMap != "" && !hasName(urlMapLink, adopted.URLMap) {
+				return fmt.Errorf("cannot adopt target proxy %q which does not use the adopted URL map %q", fe.proxy, adopted.URLMap)
+			}
+		}
+		if fe.forwardingRule != "" {
+			key, err := l7.CreateKey(fe.forwardingRule)
+			if err != nil {
+				return err
+			}
+			fr, err := composite.GetForwardingRule(l7.cloud, key, versions.ForwardingRule, l7.logger)
+			if err != nil {
+				return fmt.Errorf("cannot adopt forwarding rule %q: %w", fe.forwardingRule, err)
+			}
+			if fe.proxy != "" && !hasName(fr.Target, fe.proxy) {
+				return fmt.Errorf("cannot adopt forwarding rule %q which does not target the adopted proxy %q", fe.forwardingRule, fe.proxy)
+			}
+		}
+	}
+	return nil
+}
+
+// checkAdoptedOwnership verifies that the existing resources adopted by the
+// Ingress were taken over by a sync before they are deleted with it, so that
+// a wrong annotation does not delete the resources of another load balancer.
+// The URL map has the description of the Ingress, and target proxies and
+// forwarding rules are linked to the resources of the Ingress.
+func (l7 *L7) checkAdoptedOwnership() error {
+	if err := l7.checkReleasedResources(); err != nil {
+		return err
+	}
+	if _, ok := l7.namer.(*namer.AdoptedIngressFrontendNamer); !ok || l7.adoptionError() != nil {
+		return nil
+	}
+	adopted, err := l7.adoptedResources()
+	if adopted == nil {
+		return err
+	}
+	versions := l7.Versions()
+
+	if adopted.URLMap != "" {
+		key, err := l7.CreateKey(adopted.URLMap)
+		if err != nil {
+			return err
+		}
+		um, err := composite.GetUrlMap(l7.cloud, key, versions.UrlMap, l7.logger)
+		if utils.IgnoreHTTPNotFound(err) != nil {
+			return err
+		}
+		description, err := l7.description()
+		if err != nil {
+			return err
+		}
+		if um != nil && um.Description != description {
+			return fmt.Errorf("refusing to delete URL map %q which was not adopted by Ingress %s", adopted.URLMap, common.NamespacedName(&l7.ingress))
+		}
+	}
+
+	redirectUrlMap, _ := l7.namer.RedirectUrlMap()
+	for _, fe := range adoptedFrontends(adopted) {
+		if fe.proxy != "" {
+			urlMapLink, err := l7.proxyURLMap(fe.protocol, fe.proxy)
+			if utils.IgnoreHTTPNotFound(err) != nil {
+				return err
+			}
+			if err == nil && !hasName(urlMapLink, l7.namer.UrlMap()) && !hasName(urlMapLink, redirectUrlMap) {
+				return fmt.Errorf("refusing to delete target proxy %q which was not adopted by Ingress %s", fe.proxy, common.NamespacedName(&l7.ingress))
+			}
+		}
+		if fe.forwardingRule != "" {
+			key, err := l7.CreateKey(fe.forwardingRule)
+			if err != nil {
+				return err
+			}
+			fr, err := composite.GetForwardingRule(l7.cloud, key, versions.ForwardingRule, l7.logger)
+			if utils.IgnoreHTTPNotFound(err) != nil {
+				return err
+			}
+			if fr != nil && !hasName(fr.Target, l7.namer.TargetProxy(fe.protocol)) {
+				return fmt.Errorf("refusing to delete forwarding rule %q which was not adopted by Ingress %s", fe.forwardingRule, common.NamespacedName(&l7.ingress))
+			}
+		}
+	}
+	return nil
+}
+
+// proxyURLMap returns the link of the URL map used by the target proxy of
+// the given protocol.
+func (l7 *L7) proxyURLMap(protocol namer.NamerProtocol, name string) (string, error) {
+	key, err := l7.CreateKey(name)
+	if err != nil {
+		return "", err
+	}
+	if protocol == namer.HTTPSProtocol {
+		proxy, err := composite.GetTargetHttpsProxy(l7.cloud, key, l7.Versions().TargetHttpsProxy, l7.logger)
+		if err != nil {
+			return "", err
+		}
+		return proxy.UrlMap, nil
+	}
+	proxy, err := composite.GetTargetHttpProxy(l7.cloud, key, l7.Versions().TargetHttpProxy, l7.logger)
+	if err != nil {
+		return "", err
+	}
+	return proxy.UrlMap, nil
+}
+
+// isAdoptedURLMap returns true if the URL map of the load balancer is adopted
+// by its Ingress.
+func (l7 *L7) isAdoptedURLMap() bool {
+	adopted, err := l7.adoptedResources()
+	return err == nil && adopted != nil && adopted.URLMap != "" && adopted.URLMap == l7.namer.UrlMap()
+}
+
+// adoptedResourcesStatus returns the value of the status annotation recording
+// the resources adopted by the sync, or an empty string if none were adopted.
+func (l7 *L7) adoptedResourcesStatus() string {
+	adopted, err := l7.adoptedResources()
+	if adopted == nil || err != nil || l7.adoptionError() != nil {
+		return ""
+	}
+	status, err := json.Marshal(adopted)
+	if err != nil {
+		return ""
+	}
+	return string(status)
+}
+
+// ingressForDescription returns the Ingress named by the description of a GCE
+// resource, or an empty string if it does not name any.
+func ingressForDescription(description string) string {
+	var values map[string]string
+	if err := json.Unmarshal([]byte(description), &values); err != nil {
+		return ""
+	}
+	return values[ingressNameDescriptionKey]
+}
+
+// hasName returns true if the resource link has the given name.
+func hasName(link, name string) bool {
+	linkName, err := utils.KeyName(link)
+	return err == nil && name != "" && linkName == name
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/common"
+	namer_util "k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/klog/v2"
+)
+
+const adoptAnnotation = `{"urlMap":"web","targetHttpProxy":"web-http","httpForwardingRule":"web-http"}`
+
+// createUnmanagedLoadBalancer creates an HTTP load balancer which is not
+// managed by the controller, named web and web-http.
+func createUnmanagedLoadBalancer(t *testing.T, j *testJig, urlMapDescription string) {
+	t.Helper()
+	umKey := meta.GlobalKey("web")
+	if err := composite.CreateUrlMap(j.fakeGCE, umKey, &composite.UrlMap{Name: "web", Description: urlMapDescription, Version: defaultVersion}, klog.TODO()); err != nil {
+		t.Fatalf("CreateUrlMap() = %v", err)
+	}
+	um, err := composite.GetUrlMap(j.fakeGCE, umKey, defaultVersion, klog.TODO())
+	if err != nil {
+		t.Fatalf("GetUrlMap() = %v", err)
+	}
+	tpKey := meta.GlobalKey("web-http")
+	if err := composite.CreateTargetHttpProxy(j.fakeGCE, tpKey, &composite.TargetHttpProxy{Name: "web-http", UrlMap: um.SelfLink, Version: defaultVersion}, klog.TODO()); err != nil {
+		t.Fatalf("CreateTargetHttpProxy() = %v", err)
+	}
+	tp, err := composite.GetTargetHttpProxy(j.fakeGCE, tpKey, defaultVersion, klog.TODO())
+	if err != nil {
+		t.Fatalf("GetTargetHttpProxy() = %v", err)
+	}
+	fr := &composite.ForwardingRule{Name: "web-http", IPAddress: "1.2.3.4", PortRange: "80-80", IPProtocol: "TCP", Target: tp.SelfLink, Version: defaultVersion}
+	if err := composite.CreateForwardingRule(j.fakeGCE, meta.GlobalKey("web-http"), fr, klog.TODO()); err != nil {
+		t.Fatalf("CreateForwardingRule() = %v", err)
+	}
+}
+
+func newAdoptingIngress(adopt string) *networkingv1.Ingress {
+	ing := newIngress()
+	ing.Finalizers = []string{common.FinalizerKeyV2}
+	ing.Annotations = map[string]string{annotations.AdoptResourcesKey: adopt}
+	return ing
+}
+
+func newAdoptingRuntimeInfo(j *testJig, ing *networkingv1.Ingress) *L7RuntimeInfo {
+	gceUrlMap := utils.NewGCEURLMap(klog.TODO())
+	gceUrlMap.DefaultBackend = &utils.ServicePort{NodePort: 31234, BackendNamer: j.namer}
+	return &L7RuntimeInfo{AllowHTTP: true, UrlMap: gceUrlMap, Ingress: ing}
+}
+
+func TestAdoptResources(t *testing.T) {
+	j := newTestJig(t)
+	createUnmanagedLoadBalancer(t, j, "")
+	ing := newAdoptingIngress(adoptAnnotation)
+
+	l7, err := j.pool.Ensure(newAdoptingRuntimeInfo(j, ing))
+	if err != nil {
+		t.Fatalf("j.pool.Ensure() = %v, want nil", err)
+	}
+	if l7.um.Name != "web" || l7.tp.Name != "web-http" || l7.fw.Name != "web-http" {
+		t.Errorf("Ensure() used resources %s, %s and %s, want web, web-http and web-http", l7.um.Name, l7.tp.Name, l7.fw.Name)
+	}
+	if n := len(j.mock.MockUrlMaps.Objects); n != 1 {
+		t.Errorf("got %d URL maps, want the adopted one", n)
+	}
+	if n := len(j.mock.MockTargetHttpProxies.Objects); n != 1 {
+		t.Errorf("got %d target proxies, want the adopted one", n)
+	}
+	if n := len(j.mock.MockGlobalForwardingRules.Objects); n != 1 {
+		t.Errorf("got %d forwarding rules, want the adopted one", n)
+	}
+	fr, err := composite.GetForwardingRule(j.fakeGCE, meta.GlobalKey("web-http"), defaultVersion, klog.TODO())
+	if err != nil || fr.IPAddress != "1.2.3.4" {
+		t.Errorf("GetForwardingRule(web-http) = %v, %v, want the adopted IP 1.2.3.4", fr, err)
+	}
+	um, err := composite.GetUrlMap(j.fakeGCE, meta.GlobalKey("web"), defaultVersion, klog.TODO())
+	if err != nil {
+		t.Fatalf("GetUrlMap(web) = %v", err)
+	}
+	if want := `{"kubernetes.io/ingress-name": "namespace1/test"}`; um.Description != want {
+		t.Errorf("adopted URL map description = %q, want %q", um.Description, want)
+	}
+	// The description is only managed on adopted URL maps.
+	current := &composite.UrlMap{Description: "other", DefaultService: "global/backendServices/be"}
+	expected := &composite.UrlMap{DefaultService: "global/backendServices/be"}
+	if got := l7.changedURLMapFields(current, expected); len(got) != 1 || got[0] != "Description" {
+		t.Errorf("changedURLMapFields() of the adopted URL map = %v, want [Description]", got)
+	}
+	notAdopted := &L7{ingress: *newIngress(), namer: j.pool.namerFactory.Namer(newIngress()), logger: klog.TODO()}
+	if got := notAdopted.changedURLMapFields(current, expected); len(got) != 0 {
+		t.Errorf("changedURLMapFields() of a URL map not adopted = %v, want none", got)
+	}
+
+	// The adopted resources are deleted with the Ingress.
+	if err := j.pool.GCv2(ing, meta.Global); err != nil {
+		t.Fatalf("j.pool.GCv2() = %v, want nil", err)
+	}
+	if n := len(j.mock.MockUrlMaps.Objects) + len(j.mock.MockTargetHttpProxies.Objects) + len(j.mock.MockGlobalForwardingRules.Objects); n != 0 {
+		t.Errorf("got %d resources after GCv2(), want none", n)
+	}
+}
+
+func TestAdoptResourcesInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		desc              string
+		adopt             string
+		v1Naming          bool
+		urlMapDescription string
+	}{
+		{desc: "invalid annotation", adopt: `{"backendService":"web"}`},
+		{desc: "v1 naming scheme", adopt: adoptAnnotation, v1Naming: true},
+		{desc: "missing resource", adopt: `{"urlMap":"web","targetHttpProxy":"api-http"}`},
+		{desc: "proxy of another URL map", adopt: `{"urlMap":"api","targetHttpProxy":"web-http"}`},
+		{desc: "forwarding rule of another proxy", adopt: `{"targetHttpProxy":"web-http","httpForwardingRule":"api-http"}`},
+		{desc: "URL map of another Ingress", adopt: adoptAnnotation, urlMapDescription: `{"kubernetes.io/ingress-name": "other/ing"}`},
+		{desc: "name of the controllers", adopt: fmt.Sprintf(`{"urlMap":%q}`, otherIngressURLMap())},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			j := newTestJig(t)
+			createUnmanagedLoadBalancer(t, j, tc.urlMapDescription)
+			if err := composite.CreateUrlMap(j.fakeGCE, meta.GlobalKey("api"), &composite.UrlMap{Name: "api", Version: defaultVersion}, klog.TODO()); err != nil {
+				t.Fatalf("CreateUrlMap() = %v", err)
+			}
+			if err := composite.CreateTargetHttpProxy(j.fakeGCE, meta.GlobalKey("api-http"), &composite.TargetHttpProxy{Name: "api-http", Version: defaultVersion}, klog.TODO()); err != nil {
+				t.Fatalf("CreateTargetHttpProxy() = %v", err)
+			}
+			if err := composite.CreateForwardingRule(j.fakeGCE, meta.GlobalKey("api-http"), &composite.ForwardingRule{Name: "api-http", Target: "api-http", Version: defaultVersion}, klog.TODO()); err != nil {
+				t.Fatalf("CreateForwardingRule() = %v", err)
+			}
+			ing := newAdoptingIngress(tc.adopt)
+			if tc.v1Naming {
+				ing.Finalizers = []string{common.FinalizerKey}
+			}
+
+			if _, err := j.pool.Ensure(newAdoptingRuntimeInfo(j, ing)); err == nil {
+				t.Errorf("j.pool.Ensure() = nil, want error")
+			}
+			if _, err := j.pool.Plan(newAdoptingRuntimeInfo(j, ing)); err == nil {
+				t.Errorf("j.pool.Plan() = nil, want error")
+			}
+		})
+	}
+}
+
+// TestAdoptedResourcesNotSyncedGC asserts that the resources listed by the
+// annotation of an Ingress are not deleted with it before they were adopted
+// by a sync.
+func TestAdoptedResourcesNotSyncedGC(t *testing.T) {
+	j := newTestJig(t)
+	createUnmanagedLoadBalancer(t, j, "")
+	ing := newAdoptingIngress(adoptAnnotation)
+
+	if err := j.pool.GCv2(ing, meta.Global); err == nil {
+		t.Errorf("j.pool.GCv2() = nil, want error")
+	}
+	if n := len(j.mock.MockUrlMaps.Objects) + len(j.mock.MockTargetHttpProxies.Objects) + len(j.mock.MockGlobalForwardingRules.Objects); n != 3 {
+		t.Errorf("got %d resources after GCv2(), want the 3 unmanaged ones", n)
+	}
+}
+
+// otherIngressURLMap returns the name of the URL map of another Ingress of
+// the cluster.
+func otherIngressURLMap() string {
+	ing := newIngress()
+	ing.Name = "other"
+	ing.Finalizers = []string{common.FinalizerKeyV2}
+	factory := namer_util.NewFrontendNamerFactory(namer_util.NewNamer(clusterName, "fw1", klog.TODO()), "ks-uid", klog.TODO())
+	return factory.Namer(ing).UrlMap()
+}
+
+// TestAdoptedResourcesReleased asserts that the resources adopted by a sync
+// cannot be released by removing them from the annotation, neither by a sync
+// nor by GC.
+func TestAdoptedResourcesReleased(t *testing.T) {
+	for _, tc := range []struct {
+		desc string
+		// adopt is the new annotation, or empty if it is removed.
+		adopt string
+	}{
+		{desc: "annotation removed"},
+		{desc: "resource removed", adopt: `{"urlMap":"web","targetHttpProxy":"web-http"}`},
+		{desc: "resource replaced", adopt: `{"urlMap":"web","targetHttpProxy":"web-http","httpForwardingRule":"api-http"}`},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			j := newTestJig(t)
+			createUnmanagedLoadBalancer(t, j, "")
+			ing := newAdoptingIngress(adoptAnnotation)
+			l7, err := j.pool.Ensure(newAdoptingRuntimeInfo(j, ing))
+			if err != nil {
+				t.Fatalf("j.pool.Ensure() = %v, want nil", err)
+			}
+			ing.Annotations = l7.getFrontendAnnotations(ing.Annotations)
+			if _, ok := ing.Annotations[annotations.AdoptedResourcesKey]; !ok {
+				t.Fatalf("getFrontendAnnotations() = %v, want the %s annotation", ing.Annotations, annotations.AdoptedResourcesKey)
+			}
+
+			delete(ing.Annotations, annotations.AdoptResourcesKey)
+			if tc.adopt != "" {
+				ing.Annotations[annotations.AdoptResourcesKey] = tc.adopt
+			}
+			if _, err := j.pool.Ensure(newAdoptingRuntimeInfo(j, ing)); err == nil {
+				t.Errorf("j.pool.Ensure() = nil, want error")
+			}
+			if err := j.pool.GCv2(ing, meta.Global); err == nil {
+				t.Errorf("j.pool.GCv2() = nil, want error")
+			}
+			if n := len(j.mock.MockUrlMaps.Objects) + len(j.mock.MockTargetHttpProxies.Objects) + len(j.mock.MockGlobalForwardingRules.Objects); n != 3 {
+				t.Errorf("got %d resources, want the 3 adopted ones", n)
+			}
+
+			// Listing the resources again deletes them with the Ingress.
+			ing.Annotations[annotations.AdoptResourcesKey] = adoptAnnotation
+			if err := j.pool.GCv2(ing, meta.Global); err != nil {
+				t.Fatalf("j.pool.GCv2() = %v, want nil", err)
+			}
+			if n := len(j.mock.MockUrlMaps.Objects) + len(j.mock.MockTargetHttpProxies.Objects) + len(j.mock.MockGlobalForwardingRules.Objects); n != 0 {
+				t.Errorf("got %d resources after GCv2(), want none", n)
+			}
+		})
+	}
+}
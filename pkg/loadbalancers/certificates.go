@@ -20,7 +20,10 @@ import (
 	"fmt"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/events"
+	"k8s.io/ingress-gce/pkg/flags"
 	"k8s.io/ingress-gce/pkg/translator"
 	"k8s.io/ingress-gce/pkg/utils"
 )
@@ -47,6 +50,16 @@ func (l7 *L7) checkSSLCert() error {
 	}
 
 	l7.oldSSLCerts = existingSecretsSslCerts
+	if l7.certificateMapConfigured() {
+		// The proxy serves the certificate map instead of any SslCertificate.
+		// Certificates created for the Ingress are garbage collected once the
+		// proxy has been switched to the map.
+		if len(translatorCerts) > 0 {
+			l7.recorder.Eventf(l7.runtimeInfo.Ingress, corev1.EventTypeWarning, events.SyncIngress, "Ingress certificates are ignored, FrontendConfig %q sets CertificateMap %q", l7.runtimeInfo.FrontendConfig.Name, l7.runtimeInfo.FrontendConfig.Spec.CertificateMap)
+		}
+		l7.sslCerts = nil
+		return nil
+	}
 	sslCerts, err := l7.createSslCertificates(existingSecretsSslCerts, translatorCerts)
 	if err != nil {
 		errs = append(errs, err)
@@ -58,6 +71,12 @@ func (l7 *L7) checkSSLCert() error {
 	return nil
 }
 
+// certificateMapConfigured returns true if the FrontendConfig of the Ingress
+// points the HTTPS proxy at a Certificate Manager certificate map.
+func (l7 *L7) certificateMapConfigured() bool {
+	return flags.F.EnableFrontendConfig && l7.runtimeInfo.FrontendConfig != nil && l7.runtimeInfo.FrontendConfig.Spec.CertificateMap != ""
+}
+
 // createSslCertificates creates SslCertificates based on kubernetes secrets in Ingress configuration.
 func (l7 *L7) createSslCertificates(existingCerts, translatorCerts []*composite.SslCertificate) ([]*composite.SslCertificate, error) {
 	var result []*composite.SslCertificate
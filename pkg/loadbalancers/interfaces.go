@@ -20,6 +20,7 @@ import (
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	v1 "k8s.io/api/networking/v1"
 	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/dryrun"
 	"k8s.io/klog/v2"
 )
 
@@ -28,6 +29,10 @@ import (
 type LoadBalancerPool interface {
 	// Ensure ensures a loadbalancer and its resources given the RuntimeInfo.
 	Ensure(ri *L7RuntimeInfo) (*L7, error)
+	// Plan returns the changes Ensure would make to the loadbalancer resources.
+	Plan(ri *L7RuntimeInfo) (*dryrun.Plan, error)
+	// PlanDelete returns the changes GCv2 would make to delete the loadbalancer of the ingress.
+	PlanDelete(ing *v1.Ingress) (*dryrun.Plan, error)
 	// GCv2 garbage collects loadbalancer associated with given ingress using v2 naming scheme.
 	GCv2(ing *v1.Ingress, scope meta.KeyType) error
 	// GCv1 garbage collects loadbalancers not in the input list using v1 naming scheme.
@@ -105,6 +105,8 @@ type L7 struct {
 	recorder record.EventRecorder
 	// resource type stores the KeyType of the resources in the loadbalancer (e.g. Regional)
 	scope meta.KeyType
+	// serverTlsPolicies sets the server TLS policy of the TargetHTTPSProxy.
+	serverTlsPolicies serverTlsPolicySetter
 
 	logger klog.Logger
 }
@@ -142,7 +144,7 @@ func (l7 *L7) UrlMap() *composite.UrlMap {
 }
 
 func (l7 *L7) edgeHop() error {
-	sslConfigured := l7.runtimeInfo.TLS != nil || l7.runtimeInfo.TLSName != ""
+	sslConfigured := l7.runtimeInfo.TLS != nil || l7.runtimeInfo.TLSName != "" || l7.certificateMapConfigured()
 	// Return an error if user configuration species that both HTTP & HTTPS are not to be configured.
 	if !l7.runtimeInfo.AllowHTTP && !sslConfigured {
 		return errAllProtocolsDisabled
@@ -416,6 +418,11 @@ func (l7 *L7) getFrontendAnnotations(existing map[string]string) map[string]stri
 	}
 
 	existing[annotations.UrlMapKey] = l7.um.Name
+	if status := l7.adoptedResourcesStatus(); status != "" {
+		existing[annotations.AdoptedResourcesKey] = status
+	} else {
+		delete(existing, annotations.AdoptedResourcesKey)
+	}
 	// Forwarding rule and target proxy might not exist if allowHTTP == false
 	if l7.fw != nil {
 		existing[annotations.HttpForwardingRuleKey] = l7.fw.Name
@@ -464,7 +471,7 @@ func (l7 *L7) getFrontendAnnotations(existing map[string]string) map[string]stri
 
 // GetLBAnnotations returns the annotations of an l7. This includes it's current status.
 func GetLBAnnotations(l7 *L7, existing map[string]string, backendSyncer backends.Syncer, ingLogger klog.Logger) (map[string]string, error) {
-	backends, err := getBackendNames(l7.um)
+	backends, err := GetBackendNames(l7.um)
 	if err != nil {
 		return nil, err
 	}
@@ -510,5 +517,5 @@ func (l7 *L7) description() (string, error) {
 	ingressName := l7.runtimeInfo.Ingress.ObjectMeta.Name
 	namespacedName := types.NamespacedName{Name: ingressName, Namespace: namespace}
 
-	return fmt.Sprintf(`{"kubernetes.io/ingress-name": %q}`, namespacedName.String()), nil
+	return fmt.Sprintf(`{%q: %q}`, ingressNameDescriptionKey, namespacedName.String()), nil
 }
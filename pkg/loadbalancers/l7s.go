@@ -21,8 +21,10 @@ import (
 	"net/http"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
 	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/ingress-gce/pkg/annotations"
 	"k8s.io/ingress-gce/pkg/common/operator"
 	"k8s.io/ingress-gce/pkg/composite"
 	"k8s.io/ingress-gce/pkg/events"
@@ -41,6 +43,8 @@ type L7s struct {
 	recorderProducer events.RecorderProducer
 	// namerFactory creates frontend naming policy for ingress/ load balancer.
 	namerFactory namer_util.IngressFrontendNamerFactory
+	// serverTlsPolicies sets the server TLS policies of the https proxies.
+	serverTlsPolicies serverTlsPolicySetter
 
 	logger klog.Logger
 }
@@ -55,7 +59,11 @@ func NewLoadBalancerPool(cloud *gce.Cloud, v1NamerHelper namer_util.V1FrontendNa
 		v1NamerHelper:    v1NamerHelper,
 		recorderProducer: recorderProducer,
 		namerFactory:     namerFactory,
-		logger:           logger.WithName("L7Pool"),
+		serverTlsPolicies: &gceServerTlsPolicySetter{
+			cloud:  cloud,
+			logger: logger.WithName("ServerTlsPolicySetter"),
+		},
+		logger: logger.WithName("L7Pool"),
 	}
 }
 
@@ -69,6 +77,8 @@ func (l7s *L7s) Ensure(ri *L7RuntimeInfo) (*L7, error) {
 		scope:       features.ScopeFromIngress(ri.Ingress),
 		ingress:     *ri.Ingress,
 		logger:      l7s.logger,
+
+		serverTlsPolicies: l7s.serverTlsPolicies,
 	}
 
 	if !lb.namer.IsValidLoadBalancer() {
@@ -76,6 +86,9 @@ func (l7s *L7s) Ensure(ri *L7RuntimeInfo) (*L7, error) {
 		l7s.logger.Error(err, "invalid loadbalancer")
 		return nil, err
 	}
+	if err := lb.checkAdoptedResources(); err != nil {
+		return nil, err
+	}
 
 	if err := lb.edgeHop(); err != nil {
 		return nil, fmt.Errorf("loadbalancer %v does not exist: %v", lb.String(), err)
@@ -126,13 +139,29 @@ func (l7s *L7s) list(key *meta.Key, version meta.Version) ([]*composite.UrlMap,
 func (l7s *L7s) GCv2(ing *v1.Ingress, scope meta.KeyType) error {
 	ingKey := common.NamespacedName(ing)
 	l7s.logger.V(2).Info("GCv2", "key", ingKey)
-	if err := l7s.delete(l7s.namerFactory.Namer(ing), features.VersionsFromIngress(ing), scope); err != nil {
+	namer := l7s.namerFactory.Namer(ing)
+	lb := &L7{
+		runtimeInfo: &L7RuntimeInfo{Ingress: ing},
+		cloud:       l7s.cloud,
+		namer:       namer,
+		scope:       scope,
+		ingress:     *ing,
+		logger:      l7s.logger,
+	}
+	if err := lb.checkAdoptedOwnership(); err != nil {
+		return err
+	}
+	if err := l7s.delete(namer, features.VersionsFromIngress(ing), scope); err != nil {
 		return err
 	}
 	l7s.logger.V(2).Info("GCv2 ok", "key", ingKey)
 	return nil
 }
 
+// scopeChangeProtectedEvents limits the events of FrontendScopeChangeGC,
+// which runs on every sync.
+var scopeChangeProtectedEvents = events.NewLimiter(events.DeletionProtectedInterval)
+
 // FrontendScopeChangeGC returns the scope to GC if the LB has changed scopes
 // (e.g. when a user migrates from ILB to ELB on the same ingress or vice versa.)
 // This only applies to the V2 Naming Scheme
@@ -156,6 +185,11 @@ func (l7s *L7s) FrontendScopeChangeGC(ing *v1.Ingress, ingLogger klog.Logger) (*
 			// Look for existing LBs with the same name but of a different scope
 			_, err = composite.GetUrlMap(l7s.cloud, key, features.VersionsFromIngress(ing).UrlMap, l7s.logger)
 			if err == nil {
+				if annotations.FromIngress(ing).DeletionProtected() {
+					scopeChangeProtectedEvents.Eventf(l7s.recorderProducer.Recorder(ing.Namespace), ing, corev1.EventTypeWarning, events.DeletionProtected,
+						"Keeping the %s load balancer of the previous scope because of the %s annotation", scope, annotations.DeletionProtectionKey)
+					return nil, nil
+				}
 				l7s.logger.V(2).Info("GC'ing ing for scope", "ing", ing, "scope", scope)
 				return &scope, nil
 			}
@@ -32,6 +32,7 @@ import (
 	"google.golang.org/api/googleapi"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/cloud-provider-gcp/providers/gce"
 	"k8s.io/ingress-gce/pkg/annotations"
 	frontendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1"
@@ -153,7 +154,7 @@ func newIngress() *networkingv1.Ingress {
 }
 
 func newFakeLoadBalancerPool(cloud *gce.Cloud, t *testing.T, namer *namer_util.Namer) L7s {
-	return L7s{cloud, namer, events.RecorderProducerMock{}, namer_util.NewFrontendNamerFactory(namer, "", klog.TODO()), klog.TODO()}
+	return L7s{cloud, namer, events.RecorderProducerMock{}, namer_util.NewFrontendNamerFactory(namer, "", klog.TODO()), &gceServerTlsPolicySetter{cloud: cloud, logger: klog.TODO()}, klog.TODO()}
 }
 
 func newILBIngress() *networkingv1.Ingress {
@@ -1188,6 +1189,28 @@ func TestFrontendConfigRedirects(t *testing.T) {
 	}
 }
 
+func TestFrontendConfigCorsPolicy(t *testing.T) {
+	flags.F.EnableFrontendConfig = true
+	defer func() { flags.F.EnableFrontendConfig = false }()
+
+	j := newTestJig(t)
+
+	gceUrlMap := utils.NewGCEURLMap(klog.TODO())
+	gceUrlMap.DefaultBackend = &utils.ServicePort{NodePort: 31234, BackendNamer: j.namer}
+	gceUrlMap.PutPathRulesForHost("bar.example.com", []utils.PathRule{{Path: "/bar", Backend: utils.ServicePort{NodePort: 30000, BackendNamer: j.namer}}})
+	lbInfo := &L7RuntimeInfo{
+		AllowHTTP:      true,
+		UrlMap:         gceUrlMap,
+		Ingress:        newIngress(),
+		FrontendConfig: &frontendconfigv1beta1.FrontendConfig{Spec: frontendconfigv1beta1.FrontendConfigSpec{CorsPolicy: &frontendconfigv1beta1.CorsPolicy{AllowOrigins: []string{"https://example.com"}}}},
+	}
+
+	// CORS policies are not supported by the classic external load balancer.
+	if _, err := j.pool.Ensure(lbInfo); err == nil {
+		t.Errorf("j.pool.Ensure(%v) = nil, want error", lbInfo)
+	}
+}
+
 func TestEnsureSslPolicy(t *testing.T) {
 	t.Parallel()
 	j := newTestJig(t)
@@ -1263,6 +1286,182 @@ func TestEnsureSslPolicy(t *testing.T) {
 	}
 }
 
+func TestFrontendConfigServerTlsPolicy(t *testing.T) {
+	flags.F.EnableFrontendConfig = true
+	defer func() { flags.F.EnableFrontendConfig = false }()
+
+	j := newTestJig(t)
+
+	gceUrlMap := utils.NewGCEURLMap(klog.TODO())
+	gceUrlMap.DefaultBackend = &utils.ServicePort{NodePort: 31234, BackendNamer: j.namer}
+	gceUrlMap.PutPathRulesForHost("bar.example.com", []utils.PathRule{{Path: "/bar", Backend: utils.ServicePort{NodePort: 30000, BackendNamer: j.namer}}})
+	lbInfo := &L7RuntimeInfo{
+		AllowHTTP:      false,
+		TLS:            []*translator.TLSCerts{createCert("key", "cert", "name")},
+		UrlMap:         gceUrlMap,
+		Ingress:        newIngress(),
+		FrontendConfig: &frontendconfigv1beta1.FrontendConfig{Spec: frontendconfigv1beta1.FrontendConfigSpec{ServerTlsPolicy: utils.NewStringPointer("mtls-policy")}},
+	}
+
+	l7, err := j.pool.Ensure(lbInfo)
+	if err != nil {
+		t.Fatalf("j.pool.Ensure(%v) = %v, want nil", lbInfo, err)
+	}
+
+	tps, _ := composite.GetTargetHttpsProxy(j.fakeGCE, meta.GlobalKey(l7.tps.Name), meta.VersionGA, klog.TODO())
+	want := fmt.Sprintf("projects/%s/locations/global/serverTlsPolicies/mtls-policy", j.fakeGCE.ProjectID())
+	if tps.ServerTlsPolicy != want {
+		t.Errorf("tps server tls policy = %q, want %q", tps.ServerTlsPolicy, want)
+	}
+}
+
+func TestFrontendConfigCertificateMap(t *testing.T) {
+	flags.F.EnableFrontendConfig = true
+	defer func() { flags.F.EnableFrontendConfig = false }()
+
+	j := newTestJig(t)
+	var calls []string
+	setSslCertificatesHook := j.mock.MockTargetHttpsProxies.SetSslCertificatesHook
+	j.mock.MockTargetHttpsProxies.SetSslCertificatesHook = func(ctx context.Context, key *meta.Key, request *compute.TargetHttpsProxiesSetSslCertificatesRequest, proxies *cloud.MockTargetHttpsProxies, options ...cloud.Option) error {
+		calls = append(calls, "SetSslCertificates")
+		return setSslCertificatesHook(ctx, key, request, proxies, options...)
+	}
+	j.mock.MockTargetHttpsProxies.SetCertificateMapHook = func(ctx context.Context, key *meta.Key, request *compute.TargetHttpsProxiesSetCertificateMapRequest, proxies *cloud.MockTargetHttpsProxies, _ ...cloud.Option) error {
+		calls = append(calls, "SetCertificateMap")
+		tp, err := proxies.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		tp.CertificateMap = request.CertificateMap
+		return nil
+	}
+
+	gceUrlMap := utils.NewGCEURLMap(klog.TODO())
+	gceUrlMap.DefaultBackend = &utils.ServicePort{NodePort: 31234, BackendNamer: j.namer}
+	lbInfo := &L7RuntimeInfo{
+		AllowHTTP:      false,
+		TLS:            []*translator.TLSCerts{createCert("key", "cert", "name")},
+		UrlMap:         gceUrlMap,
+		Ingress:        newIngress(),
+		FrontendConfig: &frontendconfigv1beta1.FrontendConfig{},
+	}
+	certMapLink := fmt.Sprintf("//certificatemanager.googleapis.com/projects/%s/locations/global/certificateMaps/my-map", j.fakeGCE.ProjectID())
+
+	for _, tc := range []struct {
+		desc      string
+		certMap   string
+		wantCalls []string
+	}{
+		{desc: "secret based certificates"},
+		{desc: "migrate to certificate map", certMap: "my-map", wantCalls: []string{"SetCertificateMap", "SetSslCertificates"}},
+		{desc: "certificate map unchanged", certMap: "my-map"},
+		{desc: "migrate back to secret based certificates", wantCalls: []string{"SetSslCertificates", "SetCertificateMap"}},
+	} {
+		calls = nil
+		lbInfo.FrontendConfig.Spec.CertificateMap = tc.certMap
+		l7, err := j.pool.Ensure(lbInfo)
+		if err != nil {
+			t.Fatalf("%s: j.pool.Ensure(%v) = %v, want nil", tc.desc, lbInfo, err)
+		}
+		if diff := cmp.Diff(tc.wantCalls, calls); diff != "" {
+			t.Errorf("%s: unexpected proxy updates (-want +got):\n%s", tc.desc, diff)
+		}
+
+		tps, _ := composite.GetTargetHttpsProxy(j.fakeGCE, meta.GlobalKey(l7.tps.Name), meta.VersionGA, klog.TODO())
+		certs, _ := composite.ListSslCertificates(j.fakeGCE, meta.GlobalKey(""), meta.VersionGA, klog.TODO())
+		if tc.certMap == "" {
+			if tps.CertificateMap != "" || len(tps.SslCertificates) != 1 || len(certs) != 1 {
+				t.Errorf("%s: got certificate map %q, proxy certs %v and %d certs, want no certificate map and 1 cert", tc.desc, tps.CertificateMap, tps.SslCertificates, len(certs))
+			}
+		} else {
+			if tps.CertificateMap != certMapLink || len(tps.SslCertificates) != 0 || len(certs) != 0 {
+				t.Errorf("%s: got certificate map %q, proxy certs %v and %d certs, want certificate map %q and no certs", tc.desc, tps.CertificateMap, tps.SslCertificates, len(certs), certMapLink)
+			}
+		}
+	}
+}
+
+// fakeServerTlsPolicySetter records the server TLS policies set on the
+// proxies. Policies named "missing" do not exist.
+type fakeServerTlsPolicySetter struct {
+	policyLinks []string
+}
+
+func (f *fakeServerTlsPolicySetter) SetServerTlsPolicy(key *meta.Key, proxy *composite.TargetHttpsProxy, policyLink string) error {
+	f.policyLinks = append(f.policyLinks, policyLink)
+	if policyLink == "missing" {
+		return &googleapi.Error{Code: http.StatusBadRequest, Message: "The resource 'serverTlsPolicies/missing' was not found"}
+	}
+	return nil
+}
+
+func TestEnsureServerTlsPolicy(t *testing.T) {
+	j := newTestJig(t)
+	policyLink := fmt.Sprintf("projects/%s/locations/global/serverTlsPolicies/mtls-policy", j.fakeGCE.ProjectID())
+
+	testCases := []struct {
+		desc        string
+		proxy       *composite.TargetHttpsProxy
+		policyLink  string
+		wantPatch   bool
+		wantErr     bool
+		wantWarning bool
+	}{
+		{
+			desc:       "proxy with same policy",
+			proxy:      &composite.TargetHttpsProxy{Name: "test-proxy-1", ServerTlsPolicy: "//networksecurity.googleapis.com/projects/123/locations/global/serverTlsPolicies/mtls-policy"},
+			policyLink: policyLink,
+		},
+		{
+			desc:       "add policy",
+			proxy:      &composite.TargetHttpsProxy{Name: "test-proxy-2"},
+			policyLink: policyLink,
+			wantPatch:  true,
+		},
+		{
+			desc:      "remove policy",
+			proxy:     &composite.TargetHttpsProxy{Name: "test-proxy-3", ServerTlsPolicy: policyLink},
+			wantPatch: true,
+		},
+		{
+			desc:        "policy does not exist",
+			proxy:       &composite.TargetHttpsProxy{Name: "test-proxy-4"},
+			policyLink:  "missing",
+			wantPatch:   true,
+			wantErr:     true,
+			wantWarning: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		setter := &fakeServerTlsPolicySetter{}
+		recorder := record.NewFakeRecorder(10)
+		fc := &frontendconfigv1beta1.FrontendConfig{ObjectMeta: metav1.ObjectMeta{Name: "fc"}}
+		l7 := L7{runtimeInfo: &L7RuntimeInfo{FrontendConfig: fc, Ingress: newIngress()}, cloud: j.fakeGCE, scope: meta.Global, recorder: recorder, serverTlsPolicies: setter}
+
+		err := l7.ensureServerTlsPolicy(tc.proxy, tc.policyLink)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("desc: %q, l7.ensureServerTlsPolicy() = %v, wantErr = %v", tc.desc, err, tc.wantErr)
+		}
+		if gotPatch := len(setter.policyLinks) > 0; gotPatch != tc.wantPatch {
+			t.Errorf("desc: %q, set policies = %q, want patch: %v", tc.desc, setter.policyLinks, tc.wantPatch)
+		}
+		if tc.wantPatch && !tc.wantErr && tc.proxy.ServerTlsPolicy != tc.policyLink {
+			t.Errorf("desc: %q, proxy.ServerTlsPolicy = %q, want %q", tc.desc, tc.proxy.ServerTlsPolicy, tc.policyLink)
+		}
+		gotWarning := false
+		close(recorder.Events)
+		for event := range recorder.Events {
+			if strings.HasPrefix(event, "Warning") {
+				gotWarning = true
+			}
+		}
+		if gotWarning != tc.wantWarning {
+			t.Errorf("desc: %q, got warning event: %v, want %v", tc.desc, gotWarning, tc.wantWarning)
+		}
+	}
+}
+
 // verifyURLMap gets the created URLMap and compares it against an expected one.
 func verifyURLMap(t *testing.T, j *testJig, feNamer namer_util.IngressFrontendNamer, wantGCEURLMap *utils.GCEURLMap) {
 	t.Helper()
@@ -1931,6 +2130,13 @@ func TestResourceDeletionWithScopeChange(t *testing.T) {
 				t.Errorf("FrontendScopeChangeGC(%v) = (%v, %v), want (%q, nil)", tc.ing, scope, err, tc.gcScope)
 			}
 
+			// Nothing is GC'ed while the ingress is protected from deletion.
+			tc.ing.Annotations[annotations.DeletionProtectionKey] = "true"
+			if scope, err := j.pool.FrontendScopeChangeGC(tc.ing, klog.TODO()); scope != nil || err != nil {
+				t.Errorf("FrontendScopeChangeGC(%v) = (%v, %v), want (nil, nil) for a protected ingress", tc.ing, scope, err)
+			}
+			delete(tc.ing.Annotations, annotations.DeletionProtectionKey)
+
 			if err := j.pool.GCv2(tc.ing, tc.gcScope); err != nil {
 				t.Errorf("GCv2(%v, %q) = %v, want nil", tc.ing, tc.gcScope, err)
 			}
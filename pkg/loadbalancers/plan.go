@@ -0,0 +1,718 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/cloud-provider/service/helpers"
+	"k8s.io/ingress-gce/pkg/backends"
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/dryrun"
+	"k8s.io/ingress-gce/pkg/flags"
+	"k8s.io/ingress-gce/pkg/loadbalancers/features"
+	"k8s.io/ingress-gce/pkg/translator"
+	"k8s.io/ingress-gce/pkg/utils"
+	"k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/klog/v2"
+)
+
+// Plan implements LoadBalancerPool.
+func (l7s *L7s) Plan(ri *L7RuntimeInfo) (*dryrun.Plan, error) {
+	lb := &L7{
+		runtimeInfo: ri,
+		cloud:       l7s.cloud,
+		namer:       l7s.namerFactory.Namer(ri.Ingress),
+		recorder:    l7s.recorderProducer.Recorder(ri.Ingress.Namespace),
+		scope:       features.ScopeFromIngress(ri.Ingress),
+		ingress:     *ri.Ingress,
+		logger:      l7s.logger,
+	}
+	if !lb.namer.IsValidLoadBalancer() {
+		return nil, fmt.Errorf("invalid loadbalancer name %s, the resource name must comply with RFC1035 (https://www.ietf.org/rfc/rfc1035.txt)", lb.namer.LoadBalancer())
+	}
+	if err := lb.checkAdoptedResources(); err != nil {
+		return nil, err
+	}
+	return lb.plan()
+}
+
+// PlanDelete implements LoadBalancerPool.
+func (l7s *L7s) PlanDelete(ing *v1.Ingress) (*dryrun.Plan, error) {
+	lb := &L7{
+		runtimeInfo: &L7RuntimeInfo{Ingress: ing},
+		cloud:       l7s.cloud,
+		namer:       l7s.namerFactory.Namer(ing),
+		scope:       features.ScopeFromIngress(ing),
+		ingress:     *ing,
+		logger:      l7s.logger,
+	}
+	if !lb.namer.IsValidLoadBalancer() {
+		return &dryrun.Plan{}, nil
+	}
+	return lb.planCleanup()
+}
+
+// plan returns the changes edgeHop would make, in the same order, without
+// making them.
+func (l7 *L7) plan() (*dryrun.Plan, error) {
+	plan := &dryrun.Plan{}
+	sslConfigured := l7.runtimeInfo.TLS != nil || l7.runtimeInfo.TLSName != "" || l7.certificateMapConfigured()
+	if !l7.runtimeInfo.AllowHTTP && !sslConfigured {
+		return nil, errAllProtocolsDisabled
+	}
+	versions := l7.Versions()
+
+	key, expectedMap, err := l7.expectedURLMap()
+	if err != nil {
+		return nil, err
+	}
+	currentMap, err := composite.GetUrlMap(l7.cloud, key, expectedMap.Version, l7.logger)
+	if utils.IgnoreHTTPNotFound(err) != nil {
+		return nil, err
+	}
+	if currentMap == nil {
+		plan.Create("UrlMap", expectedMap.Name)
+	} else if fields := l7.changedURLMapFields(currentMap, expectedMap); len(fields) > 0 {
+		plan.Update("UrlMap", expectedMap.Name, fields...)
+	}
+	umName := expectedMap.Name
+	if flags.F.EnableFrontendConfig {
+		redirectName, redirectEnabled, err := l7.planRedirectURLMap(plan)
+		if err != nil {
+			return nil, err
+		}
+		if redirectEnabled {
+			umName = redirectName
+		}
+	}
+
+	if l7.runtimeInfo.AllowHTTP {
+		if err := l7.planHttp(plan, umName); err != nil {
+			return nil, err
+		}
+	} else if flags.F.EnableDeleteUnusedFrontends && requireDeleteFrontend(l7.ingress, namer.HTTPProtocol, l7.logger) {
+		if err := l7.planDeleteHttp(plan, versions); err != nil {
+			return nil, err
+		}
+	}
+	if l7.runtimeInfo.AllowHTTP && sslConfigured && l7.runtimeInfo.StaticIPName == "" {
+		ipName := l7.namer.ForwardingRule(namer.HTTPProtocol)
+		key, err := l7.CreateKey(ipName)
+		if err != nil {
+			return nil, err
+		}
+		ip, err := composite.GetAddress(l7.cloud, key, meta.VersionGA, l7.logger)
+		if utils.IgnoreHTTPNotFound(err) != nil {
+			return nil, err
+		}
+		if ip == nil {
+			plan.Create("Address", ipName)
+		}
+	}
+	if sslConfigured {
+		if err := l7.planHttps(plan, expectedMap.Name); err != nil {
+			return nil, err
+		}
+	} else if flags.F.EnableDeleteUnusedFrontends && requireDeleteFrontend(l7.ingress, namer.HTTPSProtocol, l7.logger) {
+		if err := l7.planDeleteHttps(plan, versions); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// planRedirectURLMap adds the changes of ensureRedirectURLMap to the plan. It
+// returns the name of the redirect url map and whether it is used by the http
+// proxy.
+func (l7 *L7) planRedirectURLMap(plan *dryrun.Plan) (string, bool, error) {
+	isL7ILB := utils.IsGCEL7ILBIngress(&l7.ingress)
+	t := translator.NewTranslator(isL7ILB, utils.IsGCEL7XLBRegionalIngress(&l7.ingress), l7.namer)
+	env := &translator.Env{FrontendConfig: l7.runtimeInfo.FrontendConfig, Ing: &l7.ingress}
+	name, namerSupported := l7.namer.RedirectUrlMap()
+	expectedMap := t.ToRedirectUrlMap(env, l7.Versions().UrlMap)
+	if expectedMap != nil && isL7ILB {
+		return "", false, fmt.Errorf("error: cannot enable HTTPS Redirects with L7 ILB")
+	}
+	if !namerSupported {
+		if expectedMap != nil {
+			return "", false, fmt.Errorf("error: cannot enable HTTPS Redirects with the V1 Ingress naming scheme.  Please recreate your ingress to use the newest naming scheme.")
+		}
+		return "", false, nil
+	}
+	key, err := l7.CreateKey(name)
+	if err != nil {
+		return "", false, err
+	}
+	currentMap, err := composite.GetUrlMap(l7.cloud, key, l7.Versions().UrlMap, l7.logger)
+	if utils.IgnoreHTTPNotFound(err) != nil {
+		return "", false, err
+	}
+	switch {
+	case expectedMap == nil && currentMap != nil:
+		plan.Delete("UrlMap", name)
+	case expectedMap != nil && currentMap == nil:
+		plan.Create("UrlMap", name)
+	case expectedMap != nil && compareRedirectUrlMaps(expectedMap, currentMap):
+		plan.Update("UrlMap", name, "DefaultUrlRedirect")
+	}
+	fc := l7.runtimeInfo.FrontendConfig
+	return name, expectedMap != nil && fc != nil && fc.Spec.RedirectToHttps != nil && fc.Spec.RedirectToHttps.Enabled, nil
+}
+
+// planHttp adds the changes of edgeHopHttp to the plan.
+func (l7 *L7) planHttp(plan *dryrun.Plan, umName string) error {
+	urlMapKey, err := l7.CreateKey(umName)
+	if err != nil {
+		return err
+	}
+	description, err := l7.description()
+	if err != nil {
+		return err
+	}
+	tr := translator.NewTranslator(utils.IsGCEL7ILBIngress(&l7.ingress), utils.IsGCEL7XLBRegionalIngress(&l7.ingress), l7.namer)
+	proxy := tr.ToCompositeTargetHttpProxy(description, l7.Versions().TargetHttpProxy, urlMapKey)
+	key, err := l7.CreateKey(proxy.Name)
+	if err != nil {
+		return err
+	}
+	currentProxy, err := composite.GetTargetHttpProxy(l7.cloud, key, proxy.Version, l7.logger)
+	if utils.IgnoreHTTPNotFound(err) != nil {
+		return err
+	}
+	var proxyLink string
+	if currentProxy == nil {
+		plan.Create("TargetHttpProxy", proxy.Name)
+	} else {
+		proxyLink = currentProxy.SelfLink
+		if !utils.EqualResourcePaths(currentProxy.UrlMap, proxy.UrlMap) {
+			plan.Update("TargetHttpProxy", proxy.Name, "UrlMap")
+		}
+	}
+	return l7.planForwardingRule(plan, namer.HTTPProtocol, proxyLink)
+}
+
+// planHttps adds the changes of edgeHopHttps to the plan.
+func (l7 *L7) planHttps(plan *dryrun.Plan, umName string) error {
+	isL7ILB := utils.IsGCEL7ILBIngress(&l7.ingress)
+	isL7XLBRegional := utils.IsGCEL7XLBRegionalIngress(&l7.ingress)
+	tr := translator.NewTranslator(isL7ILB, isL7XLBRegional, l7.namer)
+	env := &translator.Env{FrontendConfig: l7.runtimeInfo.FrontendConfig, Region: l7.cloud.Region(), Project: l7.cloud.ProjectID()}
+	versions := l7.Versions()
+
+	existingCerts, err := l7.getIngressManagedSslCerts()
+	if err != nil {
+		return err
+	}
+	var sslCerts []*composite.SslCertificate
+	if !l7.certificateMapConfigured() {
+		sslCerts = tr.ToCompositeSSLCertificates(env, l7.runtimeInfo.TLSName, l7.runtimeInfo.TLS, versions.SslCertificate)
+	}
+	wantCerts := sets.NewString()
+	existingCertsMap := getMapFromCertList(existingCerts)
+	for _, cert := range sslCerts {
+		wantCerts.Insert(cert.Name)
+		if _, ok := existingCertsMap[cert.Name]; cert.Certificate != "" && !ok {
+			plan.Create("SslCertificate", cert.Name)
+		}
+	}
+
+	if len(sslCerts) > 0 || l7.certificateMapConfigured() {
+		urlMapKey, err := l7.CreateKey(umName)
+		if err != nil {
+			return err
+		}
+		description, err := l7.description()
+		if err != nil {
+			return err
+		}
+		proxy, sslPolicySet, err := tr.ToCompositeTargetHttpsProxy(env, description, versions.TargetHttpProxy, urlMapKey, sslCerts)
+		if err != nil {
+			return err
+		}
+		key, err := l7.CreateKey(proxy.Name)
+		if err != nil {
+			return err
+		}
+		currentProxy, err := composite.GetTargetHttpsProxy(l7.cloud, key, proxy.Version, l7.logger)
+		if utils.IgnoreHTTPNotFound(err) != nil {
+			return err
+		}
+		var proxyLink string
+		if currentProxy == nil {
+			plan.Create("TargetHttpsProxy", proxy.Name)
+		} else {
+			proxyLink = currentProxy.SelfLink
+			if fields := changedHttpsProxyFields(currentProxy, proxy, sslPolicySet, env.FrontendConfig != nil && env.FrontendConfig.Spec.ServerTlsPolicy != nil); len(fields) > 0 {
+				plan.Update("TargetHttpsProxy", proxy.Name, fields...)
+			}
+		}
+		if err := l7.planForwardingRule(plan, namer.HTTPSProtocol, proxyLink); err != nil {
+			return err
+		}
+	}
+
+	for _, cert := range existingCerts {
+		if (l7.namer.IsCertNameForLB(cert.Name) || l7.namer.IsLegacySSLCert(cert.Name)) && !wantCerts.Has(cert.Name) {
+			plan.Delete("SslCertificate", cert.Name)
+		}
+	}
+	return nil
+}
+
+// changedHttpsProxyFields returns the fields checkHttpsProxy would update on
+// the current proxy.
+func changedHttpsProxyFields(current, desired *composite.TargetHttpsProxy, sslPolicySet, serverTlsPolicySet bool) []string {
+	var fields []string
+	if !utils.EqualResourcePaths(current.UrlMap, desired.UrlMap) {
+		fields = append(fields, "UrlMap")
+	}
+	if !equalCertificateMaps(current.CertificateMap, desired.CertificateMap) {
+		fields = append(fields, "CertificateMap")
+	}
+	currentCerts, desiredCerts := sets.NewString(), sets.NewString()
+	for _, link := range current.SslCertificates {
+		name, _ := utils.KeyName(link)
+		currentCerts.Insert(name)
+	}
+	for _, link := range desired.SslCertificates {
+		name, _ := utils.KeyName(link)
+		desiredCerts.Insert(name)
+	}
+	if !currentCerts.Equal(desiredCerts) {
+		fields = append(fields, "SslCertificates")
+	}
+	if flags.F.EnableFrontendConfig && sslPolicySet && !utils.EqualResourcePaths(current.SslPolicy, desired.SslPolicy) {
+		fields = append(fields, "SslPolicy")
+	}
+	if flags.F.EnableFrontendConfig && serverTlsPolicySet && !equalServerTlsPolicies(current.ServerTlsPolicy, desired.ServerTlsPolicy) {
+		fields = append(fields, "ServerTlsPolicy")
+	}
+	return fields
+}
+
+// planForwardingRule adds the changes of checkForwardingRule to the plan.
+// proxyLink is empty if the target proxy does not exist yet.
+func (l7 *L7) planForwardingRule(plan *dryrun.Plan, protocol namer.NamerProtocol, proxyLink string) error {
+	name := l7.namer.ForwardingRule(protocol)
+	key, err := l7.CreateKey(name)
+	if err != nil {
+		return err
+	}
+	address, _, err := l7.getEffectiveIP()
+	if err != nil {
+		return err
+	}
+	description, err := l7.description()
+	if err != nil {
+		return err
+	}
+	version := l7.Versions().ForwardingRule
+	tr := translator.NewTranslator(utils.IsGCEL7ILBIngress(&l7.ingress), utils.IsGCEL7XLBRegionalIngress(&l7.ingress), l7.namer)
+	env := &translator.Env{VIP: address, Network: l7.cloud.NetworkURL(), Subnetwork: l7.cloud.SubnetworkURL()}
+	fr := tr.ToCompositeForwardingRule(env, protocol, version, proxyLink, description, l7.runtimeInfo.StaticIPSubnet)
+
+	existing, err := composite.GetForwardingRule(l7.cloud, key, version, l7.logger)
+	if utils.IgnoreHTTPNotFound(err) != nil {
+		return err
+	}
+	switch {
+	case existing == nil:
+		plan.Create("ForwardingRule", name)
+	case fr.IPAddress != "" && existing.IPAddress != fr.IPAddress || existing.PortRange != fr.PortRange:
+		// Forwarding rules are recreated to change their IP or ports.
+		plan.Delete("ForwardingRule", name)
+		plan.Create("ForwardingRule", name)
+	case proxyLink == "" || !utils.EqualResourceIDs(existing.Target, proxyLink):
+		plan.Update("ForwardingRule", name, "Target")
+	}
+	return nil
+}
+
+// planDeleteHttp adds the changes of deleteHttp to the plan.
+func (l7 *L7) planDeleteHttp(plan *dryrun.Plan, versions *features.ResourceVersions) error {
+	if err := l7.planDeleteForwardingRule(plan, versions, namer.HTTPProtocol); err != nil {
+		return err
+	}
+	key, err := l7.CreateKey(l7.namer.TargetProxy(namer.HTTPProtocol))
+	if err != nil {
+		return err
+	}
+	proxy, err := composite.GetTargetHttpProxy(l7.cloud, key, versions.TargetHttpProxy, l7.logger)
+	if utils.IgnoreHTTPNotFound(err) != nil {
+		return err
+	}
+	if proxy != nil {
+		plan.Delete("TargetHttpProxy", key.Name)
+	}
+	return nil
+}
+
+// planDeleteHttps adds the changes of deleteHttps to the plan.
+func (l7 *L7) planDeleteHttps(plan *dryrun.Plan, versions *features.ResourceVersions) error {
+	if err := l7.planDeleteForwardingRule(plan, versions, namer.HTTPSProtocol); err != nil {
+		return err
+	}
+	certs, err := l7.getIngressManagedSslCerts()
+	if err != nil {
+		return err
+	}
+	key, err := l7.CreateKey(l7.namer.TargetProxy(namer.HTTPSProtocol))
+	if err != nil {
+		return err
+	}
+	proxy, err := composite.GetTargetHttpsProxy(l7.cloud, key, versions.TargetHttpsProxy, l7.logger)
+	if utils.IgnoreHTTPNotFound(err) != nil {
+		return err
+	}
+	if proxy != nil {
+		plan.Delete("TargetHttpsProxy", key.Name)
+	}
+	for _, cert := range certs {
+		plan.Delete("SslCertificate", cert.Name)
+	}
+	return nil
+}
+
+func (l7 *L7) planDeleteForwardingRule(plan *dryrun.Plan, versions *features.ResourceVersions, protocol namer.NamerProtocol) error {
+	key, err := l7.CreateKey(l7.namer.ForwardingRule(protocol))
+	if err != nil {
+		return err
+	}
+	fr, err := composite.GetForwardingRule(l7.cloud, key, versions.ForwardingRule, l7.logger)
+	if utils.IgnoreHTTPNotFound(err) != nil {
+		return err
+	}
+	if fr != nil {
+		plan.Delete("ForwardingRule", key.Name)
+	}
+	return nil
+}
+
+// planCleanup returns the changes Cleanup would make, in the same order,
+// without making them.
+func (l7 *L7) planCleanup() (*dryrun.Plan, error) {
+	plan := &dryrun.Plan{}
+	versions := l7.Versions()
+	if err := l7.planDeleteHttp(plan, versions); err != nil {
+		return nil, err
+	}
+	ipName := l7.namer.ForwardingRule(namer.HTTPProtocol)
+	ip, err := l7.cloud.GetGlobalAddress(ipName)
+	if utils.IgnoreHTTPNotFound(err) != nil {
+		return nil, err
+	}
+	if ip != nil {
+		plan.Delete("Address", ipName)
+	}
+	if err := l7.planDeleteHttps(plan, versions); err != nil {
+		return nil, err
+	}
+	umNames := []string{l7.namer.UrlMap()}
+	if name, supported := l7.namer.RedirectUrlMap(); flags.F.EnableFrontendConfig && supported {
+		umNames = append(umNames, name)
+	}
+	for _, name := range umNames {
+		key, err := l7.CreateKey(name)
+		if err != nil {
+			return nil, err
+		}
+		um, err := composite.GetUrlMap(l7.cloud, key, versions.UrlMap, l7.logger)
+		if utils.IgnoreHTTPNotFound(err) != nil {
+			return nil, err
+		}
+		if um != nil {
+			plan.Delete("UrlMap", name)
+		}
+	}
+	return plan, nil
+}
+
+// l4PlanParams are the inputs shared by the L4 ILB and NetLB planners.
+type l4PlanParams struct {
+	cloud           *gce.Cloud
+	backendPool     *backends.Backends
+	forwardingRules ForwardingRulesProvider
+	namer           namer.L4ResourcesNamer
+	service         *corev1.Service
+	// hcScope is the scope of the health check, global for ILB and regional
+	// for NetLB.
+	hcScope meta.KeyType
+	frName  string
+	logger  klog.Logger
+}
+
+// PlanInternalLoadBalancer returns the changes EnsureInternalLoadBalancer
+// would make for svc, without making them. Only the IPv4 resources are
+// planned.
+func (l4 *L4) PlanInternalLoadBalancer(svc *corev1.Service) (*dryrun.Plan, error) {
+	l4.Service = svc
+	plan := &dryrun.Plan{}
+	params := l4.planParams()
+	if err := planL4HealthCheck(plan, params); err != nil {
+		return nil, err
+	}
+	if err := planL4BackendService(plan, params); err != nil {
+		return nil, err
+	}
+	fr, err := l4.forwardingRules.Get(params.frName)
+	if err != nil {
+		return nil, err
+	}
+	ports := utils.GetPorts(svc.Spec.Ports)
+	allPorts := len(ports) > maxL4ILBPorts
+	if allPorts {
+		ports = nil
+	}
+	planL4ForwardingRule(plan, params, fr, func(fr *composite.ForwardingRule) bool {
+		return fr.AllPorts == allPorts && sets.NewString(fr.Ports...).Equal(sets.NewString(ports...))
+	})
+	if err := planL4NodesFirewall(plan, params); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// PlanInternalLoadBalancerDeleted returns the changes
+// EnsureInternalLoadBalancerDeleted would make for svc, without making them.
+func (l4 *L4) PlanInternalLoadBalancerDeleted(svc *corev1.Service) (*dryrun.Plan, error) {
+	l4.Service = svc
+	return planL4Deleted(l4.planParams())
+}
+
+func (l4 *L4) planParams() *l4PlanParams {
+	return &l4PlanParams{
+		cloud:           l4.cloud,
+		backendPool:     l4.backendPool,
+		forwardingRules: l4.forwardingRules,
+		namer:           l4.namer,
+		service:         l4.Service,
+		hcScope:         meta.Global,
+		frName:          l4.GetFRName(),
+		logger:          l4.svcLogger,
+	}
+}
+
+// PlanFrontend returns the changes EnsureFrontend would make for svc, without
+// making them. Only the IPv4 resources are planned.
+func (l4netlb *L4NetLB) PlanFrontend(svc *corev1.Service) (*dryrun.Plan, error) {
+	l4netlb.Service = svc
+	plan := &dryrun.Plan{}
+	params := l4netlb.planParams()
+	if err := planL4HealthCheck(plan, params); err != nil {
+		return nil, err
+	}
+	if err := planL4BackendService(plan, params); err != nil {
+		return nil, err
+	}
+	fr, err := l4netlb.forwardingRules.Get(params.frName)
+	if err != nil {
+		return nil, err
+	}
+	portRange, _ := utils.MinMaxPortRangeAndProtocol(svc.Spec.Ports)
+	planL4ForwardingRule(plan, params, fr, func(fr *composite.ForwardingRule) bool {
+		return fr.PortRange == portRange
+	})
+	if err := planL4NodesFirewall(plan, params); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// PlanLoadBalancerDeleted returns the changes EnsureLoadBalancerDeleted would
+// make for svc, without making them.
+func (l4netlb *L4NetLB) PlanLoadBalancerDeleted(svc *corev1.Service) (*dryrun.Plan, error) {
+	l4netlb.Service = svc
+	return planL4Deleted(l4netlb.planParams())
+}
+
+func (l4netlb *L4NetLB) planParams() *l4PlanParams {
+	return &l4PlanParams{
+		cloud:           l4netlb.cloud,
+		backendPool:     l4netlb.backendPool,
+		forwardingRules: l4netlb.forwardingRules,
+		namer:           l4netlb.namer,
+		service:         l4netlb.Service,
+		hcScope:         l4netlb.scope,
+		frName:          l4netlb.frName(),
+		logger:          l4netlb.svcLogger,
+	}
+}
+
+// planL4HealthCheck plans the creation of the health check of the service
+// and of its firewall rule. Updates of existing health checks are not
+// planned.
+func planL4HealthCheck(plan *dryrun.Plan, p *l4PlanParams) error {
+	sharedHC := !helpers.RequestsOnlyLocalTraffic(p.service)
+	hcName := p.namer.L4HealthCheck(p.service.Namespace, p.service.Name, sharedHC)
+	key, err := composite.CreateKey(p.cloud, hcName, p.hcScope)
+	if err != nil {
+		return err
+	}
+	hc, err := composite.GetHealthCheck(p.cloud, key, meta.VersionGA, p.logger)
+	if utils.IgnoreHTTPNotFound(err) != nil {
+		return err
+	}
+	if hc == nil {
+		plan.Create("HealthCheck", hcName)
+	}
+	fwName := p.namer.L4HealthCheckFirewall(p.service.Namespace, p.service.Name, sharedHC)
+	fw, err := p.cloud.GetFirewall(fwName)
+	if utils.IgnoreHTTPNotFound(err) != nil {
+		return err
+	}
+	if fw == nil {
+		plan.Create("Firewall", fwName)
+	}
+	return nil
+}
+
+// planL4BackendService adds the changes of EnsureL4BackendService to the
+// plan.
+func planL4BackendService(plan *dryrun.Plan, p *l4PlanParams) error {
+	bsName := p.namer.L4Backend(p.service.Namespace, p.service.Name)
+	bs, err := p.backendPool.Get(bsName, meta.VersionGA, meta.Regional, p.logger)
+	if utils.IgnoreHTTPNotFound(err) != nil {
+		return err
+	}
+	if bs == nil {
+		plan.Create("BackendService", bsName)
+		return nil
+	}
+	var fields []string
+	if bs.Protocol != string(utils.GetProtocol(p.service.Spec.Ports)) {
+		fields = append(fields, "Protocol")
+	}
+	if bs.SessionAffinity != utils.TranslateAffinityType(string(p.service.Spec.SessionAffinity), p.logger) {
+		fields = append(fields, "SessionAffinity")
+	}
+	hcName := p.namer.L4HealthCheck(p.service.Namespace, p.service.Name, !helpers.RequestsOnlyLocalTraffic(p.service))
+	if len(bs.HealthChecks) != 1 || !strings.HasSuffix(bs.HealthChecks[0], "/"+hcName) {
+		fields = append(fields, "HealthChecks")
+	}
+	if len(fields) > 0 {
+		plan.Update("BackendService", bsName, fields...)
+	}
+	return nil
+}
+
+// planL4ForwardingRule adds the changes to the forwarding rule fr to the
+// plan. portsEqual reports whether the ports of an existing rule match the
+// service. Forwarding rules are recreated to change them.
+func planL4ForwardingRule(plan *dryrun.Plan, p *l4PlanParams, fr *composite.ForwardingRule, portsEqual func(*composite.ForwardingRule) bool) {
+	if fr == nil {
+		plan.Create("ForwardingRule", p.frName)
+		return
+	}
+	bsName := p.namer.L4Backend(p.service.Namespace, p.service.Name)
+	if fr.IPProtocol != string(utils.GetProtocol(p.service.Spec.Ports)) || !portsEqual(fr) || !strings.HasSuffix(fr.BackendService, "/"+bsName) {
+		plan.Delete("ForwardingRule", p.frName)
+		plan.Create("ForwardingRule", p.frName)
+	}
+}
+
+// planL4NodesFirewall adds the changes to the firewall rule allowing traffic
+// to the nodes to the plan.
+func planL4NodesFirewall(plan *dryrun.Plan, p *l4PlanParams) error {
+	fwName := p.namer.L4Firewall(p.service.Namespace, p.service.Name)
+	fw, err := p.cloud.GetFirewall(fwName)
+	if utils.IgnoreHTTPNotFound(err) != nil {
+		return err
+	}
+	if fw == nil {
+		plan.Create("Firewall", fwName)
+		return nil
+	}
+	sourceRanges, err := utils.IPv4ServiceSourceRanges(p.service)
+	if err != nil {
+		return err
+	}
+	var fields []string
+	if !sets.NewString(fw.SourceRanges...).Equal(sets.NewString(sourceRanges...)) {
+		fields = append(fields, "SourceRanges")
+	}
+	portRanges := utils.GetServicePortRanges(p.service.Spec.Ports)
+	protocol := strings.ToLower(string(utils.GetProtocol(p.service.Spec.Ports)))
+	if len(fw.Allowed) != 1 || strings.ToLower(fw.Allowed[0].IPProtocol) != protocol || !sets.NewString(fw.Allowed[0].Ports...).Equal(sets.NewString(portRanges...)) {
+		fields = append(fields, "Allowed")
+	}
+	if len(fields) > 0 {
+		plan.Update("Firewall", fwName, fields...)
+	}
+	return nil
+}
+
+// planL4Deleted plans the deletion of all existing IPv4 resources of the
+// service. Shared health checks are left out, since they are only deleted
+// when no other service uses them.
+func planL4Deleted(p *l4PlanParams) (*dryrun.Plan, error) {
+	plan := &dryrun.Plan{}
+	fr, err := p.forwardingRules.Get(p.frName)
+	if err != nil {
+		return nil, err
+	}
+	if fr != nil {
+		plan.Delete("ForwardingRule", p.frName)
+	}
+	addr, err := p.cloud.GetRegionAddress(p.frName, p.cloud.Region())
+	if utils.IgnoreHTTPNotFound(err) != nil {
+		return nil, err
+	}
+	if addr != nil {
+		plan.Delete("Address", p.frName)
+	}
+	fwNames := []string{
+		p.namer.L4Firewall(p.service.Namespace, p.service.Name),
+		p.namer.L4HealthCheckFirewall(p.service.Namespace, p.service.Name, false),
+	}
+	for _, name := range fwNames {
+		fw, err := p.cloud.GetFirewall(name)
+		if utils.IgnoreHTTPNotFound(err) != nil {
+			return nil, err
+		}
+		if fw != nil {
+			plan.Delete("Firewall", name)
+		}
+	}
+	bsName := p.namer.L4Backend(p.service.Namespace, p.service.Name)
+	bs, err := p.backendPool.Get(bsName, meta.VersionGA, meta.Regional, p.logger)
+	if utils.IgnoreHTTPNotFound(err) != nil {
+		return nil, err
+	}
+	if bs != nil {
+		plan.Delete("BackendService", bsName)
+	}
+	hcName := p.namer.L4HealthCheck(p.service.Namespace, p.service.Name, false)
+	key, err := composite.CreateKey(p.cloud, hcName, p.hcScope)
+	if err != nil {
+		return nil, err
+	}
+	hc, err := composite.GetHealthCheck(p.cloud, key, meta.VersionGA, p.logger)
+	if utils.IgnoreHTTPNotFound(err) != nil {
+		return nil, err
+	}
+	if hc != nil {
+		plan.Delete("HealthCheck", hcName)
+	}
+	return plan, nil
+}
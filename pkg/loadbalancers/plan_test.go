@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/ingress-gce/pkg/dryrun"
+	"k8s.io/ingress-gce/pkg/healthchecksl4"
+	"k8s.io/ingress-gce/pkg/network"
+	"k8s.io/ingress-gce/pkg/test"
+	"k8s.io/ingress-gce/pkg/utils"
+	namer_util "k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/klog/v2"
+)
+
+func TestL7Plan(t *testing.T) {
+	j := newTestJig(t)
+
+	gceUrlMap := utils.NewGCEURLMap(klog.TODO())
+	gceUrlMap.DefaultBackend = &utils.ServicePort{NodePort: 31234, BackendNamer: j.namer}
+	gceUrlMap.PutPathRulesForHost("bar.example.com", []utils.PathRule{{Path: "/bar", Backend: utils.ServicePort{NodePort: 30000, BackendNamer: j.namer}}})
+	lbInfo := &L7RuntimeInfo{
+		AllowHTTP: true,
+		UrlMap:    gceUrlMap,
+		Ingress:   newIngress(),
+	}
+	feNamer := namer_util.NewFrontendNamerFactory(j.namer, "", klog.TODO()).Namer(lbInfo.Ingress)
+	umName := feNamer.UrlMap()
+	proxyName := feNamer.TargetProxy(namer_util.HTTPProtocol)
+	frName := feNamer.ForwardingRule(namer_util.HTTPProtocol)
+
+	plan, err := j.pool.Plan(lbInfo)
+	if err != nil {
+		t.Fatalf("Plan() = %v", err)
+	}
+	want := []dryrun.Change{
+		{Operation: dryrun.OperationCreate, Resource: "UrlMap", Name: umName},
+		{Operation: dryrun.OperationCreate, Resource: "TargetHttpProxy", Name: proxyName},
+		{Operation: dryrun.OperationCreate, Resource: "ForwardingRule", Name: frName},
+	}
+	if diff := cmp.Diff(want, plan.Changes); diff != "" {
+		t.Errorf("Plan() returned unexpected diff (-want +got):\n%s", diff)
+	}
+	if len(j.mock.MockUrlMaps.Objects) != 0 {
+		t.Errorf("Plan() created %d url maps, want none", len(j.mock.MockUrlMaps.Objects))
+	}
+
+	if _, err := j.pool.Ensure(lbInfo); err != nil {
+		t.Fatalf("Ensure() = %v", err)
+	}
+	plan, err = j.pool.Plan(lbInfo)
+	if err != nil {
+		t.Fatalf("Plan() = %v", err)
+	}
+	if len(plan.Changes) != 0 {
+		t.Errorf("Plan() after Ensure() = %v, want no changes", plan)
+	}
+
+	gceUrlMap.PutPathRulesForHost("foo.example.com", []utils.PathRule{{Path: "/foo", Backend: utils.ServicePort{NodePort: 30001, BackendNamer: j.namer}}})
+	plan, err = j.pool.Plan(lbInfo)
+	if err != nil {
+		t.Fatalf("Plan() = %v", err)
+	}
+	want = []dryrun.Change{
+		{Operation: dryrun.OperationUpdate, Resource: "UrlMap", Name: umName, Fields: []string{"HostRules", "PathMatchers"}},
+	}
+	if diff := cmp.Diff(want, plan.Changes); diff != "" {
+		t.Errorf("Plan() returned unexpected diff (-want +got):\n%s", diff)
+	}
+
+	plan, err = j.pool.PlanDelete(lbInfo.Ingress)
+	if err != nil {
+		t.Fatalf("PlanDelete() = %v", err)
+	}
+	want = []dryrun.Change{
+		{Operation: dryrun.OperationDelete, Resource: "ForwardingRule", Name: frName},
+		{Operation: dryrun.OperationDelete, Resource: "TargetHttpProxy", Name: proxyName},
+		{Operation: dryrun.OperationDelete, Resource: "UrlMap", Name: umName},
+	}
+	if diff := cmp.Diff(want, plan.Changes); diff != "" {
+		t.Errorf("PlanDelete() returned unexpected diff (-want +got):\n%s", diff)
+	}
+
+	// Errors other than not found fail the plan instead of planning a
+	// creation.
+	j.mock.MockTargetHttpProxies.GetHook = func(ctx context.Context, key *meta.Key, m *cloud.MockTargetHttpProxies, options ...cloud.Option) (bool, *compute.TargetHttpProxy, error) {
+		return true, nil, &googleapi.Error{Code: http.StatusInternalServerError}
+	}
+	if plan, err := j.pool.Plan(lbInfo); err == nil {
+		t.Errorf("Plan() = %v, nil, want an error", plan)
+	}
+	if plan, err := j.pool.PlanDelete(lbInfo.Ingress); err == nil {
+		t.Errorf("PlanDelete() = %v, nil, want an error", plan)
+	}
+}
+
+func TestPlanInternalLoadBalancer(t *testing.T) {
+	t.Parallel()
+	nodeNames := []string{"test-node-1"}
+	vals := gce.DefaultTestClusterValues()
+	fakeGCE := getFakeGCECloud(vals)
+
+	svc := test.NewL4ILBService(false, 8080)
+	l4ilbParams := &L4ILBParams{
+		Service:         svc,
+		Cloud:           fakeGCE,
+		Namer:           namer_util.NewL4Namer(kubeSystemUID, nil),
+		Recorder:        record.NewFakeRecorder(100),
+		NetworkResolver: network.NewFakeResolver(network.DefaultNetwork(fakeGCE)),
+	}
+	l4 := NewL4Handler(l4ilbParams, klog.TODO())
+	l4.healthChecks = healthchecksl4.Fake(fakeGCE, l4ilbParams.Recorder)
+	if _, err := test.CreateAndInsertNodes(l4.cloud, nodeNames, vals.ZoneName); err != nil {
+		t.Fatalf("Unexpected error when adding nodes %v", err)
+	}
+	hcName := l4.namer.L4HealthCheck(svc.Namespace, svc.Name, true)
+	hcFwName := l4.namer.L4HealthCheckFirewall(svc.Namespace, svc.Name, true)
+	bsName := l4.namer.L4Backend(svc.Namespace, svc.Name)
+	frName := l4.GetFRName()
+	fwName := l4.namer.L4Firewall(svc.Namespace, svc.Name)
+
+	plan, err := l4.PlanInternalLoadBalancer(svc)
+	if err != nil {
+		t.Fatalf("PlanInternalLoadBalancer() = %v", err)
+	}
+	want := []dryrun.Change{
+		{Operation: dryrun.OperationCreate, Resource: "HealthCheck", Name: hcName},
+		{Operation: dryrun.OperationCreate, Resource: "Firewall", Name: hcFwName},
+		{Operation: dryrun.OperationCreate, Resource: "BackendService", Name: bsName},
+		{Operation: dryrun.OperationCreate, Resource: "ForwardingRule", Name: frName},
+		{Operation: dryrun.OperationCreate, Resource: "Firewall", Name: fwName},
+	}
+	if diff := cmp.Diff(want, plan.Changes); diff != "" {
+		t.Errorf("PlanInternalLoadBalancer() returned unexpected diff (-want +got):\n%s", diff)
+	}
+	if n := len(fakeGCE.Compute().(*cloud.MockGCE).MockRegionBackendServices.Objects); n != 0 {
+		t.Errorf("PlanInternalLoadBalancer() created %d backend services, want none", n)
+	}
+
+	if result := l4.EnsureInternalLoadBalancer(nodeNames, svc); result.Error != nil {
+		t.Fatalf("EnsureInternalLoadBalancer() = %v", result.Error)
+	}
+	plan, err = l4.PlanInternalLoadBalancer(svc)
+	if err != nil {
+		t.Fatalf("PlanInternalLoadBalancer() = %v", err)
+	}
+	if len(plan.Changes) != 0 {
+		t.Errorf("PlanInternalLoadBalancer() after EnsureInternalLoadBalancer() = %v, want no changes", plan)
+	}
+
+	svc.Spec.Ports[0].Port = 8081
+	plan, err = l4.PlanInternalLoadBalancer(svc)
+	if err != nil {
+		t.Fatalf("PlanInternalLoadBalancer() = %v", err)
+	}
+	want = []dryrun.Change{
+		{Operation: dryrun.OperationDelete, Resource: "ForwardingRule", Name: frName},
+		{Operation: dryrun.OperationCreate, Resource: "ForwardingRule", Name: frName},
+		{Operation: dryrun.OperationUpdate, Resource: "Firewall", Name: fwName, Fields: []string{"Allowed"}},
+	}
+	if diff := cmp.Diff(want, plan.Changes); diff != "" {
+		t.Errorf("PlanInternalLoadBalancer() returned unexpected diff (-want +got):\n%s", diff)
+	}
+
+	plan, err = l4.PlanInternalLoadBalancerDeleted(svc)
+	if err != nil {
+		t.Fatalf("PlanInternalLoadBalancerDeleted() = %v", err)
+	}
+	want = []dryrun.Change{
+		{Operation: dryrun.OperationDelete, Resource: "ForwardingRule", Name: frName},
+		{Operation: dryrun.OperationDelete, Resource: "Firewall", Name: fwName},
+		{Operation: dryrun.OperationDelete, Resource: "BackendService", Name: bsName},
+	}
+	if diff := cmp.Diff(want, plan.Changes); diff != "" {
+		t.Errorf("PlanInternalLoadBalancerDeleted() returned unexpected diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancers
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/compute/v1"
+	"k8s.io/cloud-provider-gcp/providers/gce"
+	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/composite/metrics"
+	"k8s.io/klog/v2"
+)
+
+// serverTlsPolicySetter sets the server TLS policy of target https proxies.
+type serverTlsPolicySetter interface {
+	// SetServerTlsPolicy attaches the policy to the global proxy, or detaches
+	// the current policy if policyLink is empty.
+	SetServerTlsPolicy(key *meta.Key, proxy *composite.TargetHttpsProxy, policyLink string) error
+}
+
+// gceServerTlsPolicySetter sets the server TLS policies in GCE. The compute
+// stubs have no method to update the policy of global target https proxies,
+// so the proxies are patched through the GA compute API.
+type gceServerTlsPolicySetter struct {
+	cloud *gce.Cloud
+
+	logger klog.Logger
+}
+
+// gceServerTlsPolicySetter is a serverTlsPolicySetter
+var _ serverTlsPolicySetter = (*gceServerTlsPolicySetter)(nil)
+
+// SetServerTlsPolicy implements serverTlsPolicySetter.
+func (s *gceServerTlsPolicySetter) SetServerTlsPolicy(key *meta.Key, proxy *composite.TargetHttpsProxy, policyLink string) error {
+	if key.Type() != meta.Global {
+		return fmt.Errorf("SetServerTlsPolicy() is only supported for global Target Https Proxies")
+	}
+	ctx, cancel := cloud.ContextWithCallTimeout()
+	defer cancel()
+	mc := metrics.NewMetricContext("TargetHttpsProxy", "set_server_tls_policy", key.Region, key.Zone, string(meta.VersionGA))
+
+	// Set name in case it is not present in the key
+	key.Name = proxy.Name
+	s.logger.V(3).Info("Setting ServerTlsPolicy for TargetHttpsProxy", "key", key)
+
+	patch := &compute.TargetHttpsProxy{
+		Fingerprint:     proxy.Fingerprint,
+		ServerTlsPolicy: policyLink,
+	}
+	if policyLink == "" {
+		patch.NullFields = []string{"ServerTlsPolicy"}
+	}
+	service := s.cloud.ComputeServices().GA
+	op, err := service.TargetHttpsProxies.Patch(s.cloud.ProjectID(), key.Name, patch).Context(ctx).Do()
+	for err == nil && op.Status != "DONE" {
+		op, err = service.GlobalOperations.Wait(s.cloud.ProjectID(), op.Name).Context(ctx).Do()
+	}
+	if err == nil && op.Error != nil && len(op.Error.Errors) > 0 {
+		err = fmt.Errorf("operation %q failed: %s", op.Name, op.Error.Errors[0].Message)
+	}
+	return mc.Observe(err)
+}
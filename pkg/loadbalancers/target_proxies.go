@@ -17,6 +17,8 @@ limitations under the License.
 package loadbalancers
 
 import (
+	"strings"
+
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/cloud-provider-gcp/providers/gce"
@@ -112,9 +114,9 @@ func (l7 *L7) checkHttpsProxy() (err error) {
 	isL7ILB := utils.IsGCEL7ILBIngress(l7.runtimeInfo.Ingress)
 	isL7XLBRegional := utils.IsGCEL7XLBRegionalIngress(l7.runtimeInfo.Ingress)
 	tr := translator.NewTranslator(isL7ILB, isL7XLBRegional, l7.namer)
-	env := &translator.Env{FrontendConfig: l7.runtimeInfo.FrontendConfig, Region: l7.cloud.Region()}
+	env := &translator.Env{FrontendConfig: l7.runtimeInfo.FrontendConfig, Region: l7.cloud.Region(), Project: l7.cloud.ProjectID()}
 
-	if len(l7.sslCerts) == 0 {
+	if len(l7.sslCerts) == 0 && !l7.certificateMapConfigured() {
 		l7.logger.V(2).Info("No SSL certificates for load-balancer, will not create HTTPS Proxy.", "l7", l7)
 		return nil
 	}
@@ -144,6 +146,9 @@ func (l7 *L7) checkHttpsProxy() (err error) {
 		l7.logger.V(3).Info("Creating new https Proxy for urlmap", "urlMapName", l7.um.Name)
 
 		if err = composite.CreateTargetHttpsProxy(l7.cloud, key, proxy, l7.logger); err != nil {
+			if proxy.ServerTlsPolicy != "" && isServerTlsPolicyNotFound(err) {
+				l7.recordServerTlsPolicyNotFound(proxy.ServerTlsPolicy)
+			}
 			return err
 		}
 		l7.recorder.Eventf(l7.runtimeInfo.Ingress, corev1.EventTypeNormal, events.SyncIngress, "TargetProxy %q created", key.Name)
@@ -173,6 +178,16 @@ func (l7 *L7) checkHttpsProxy() (err error) {
 		l7.recorder.Eventf(l7.runtimeInfo.Ingress, corev1.EventTypeNormal, events.SyncIngress, "TargetProxy %q updated", key.Name)
 	}
 
+	// The certificate map takes precedence over the SslCertificates of the
+	// proxy. When migrating to the map it is set before the certificates are
+	// removed, when migrating back it is cleared after the certificates are
+	// attached, so that the proxy always has certificates to serve.
+	if proxy.CertificateMap != "" {
+		if err := l7.ensureCertificateMap(currentProxy, proxy.CertificateMap); err != nil {
+			return err
+		}
+	}
+
 	if !l7.compareCerts(currentProxy.SslCertificates) {
 		l7.logger.V(2).Info("Https Proxy has the wrong ssl certs, overwriting",
 			"proxyName", currentProxy.Name, "newCerts", toCertNames(l7.sslCerts), "existingCerts", currentProxy.SslCertificates)
@@ -190,12 +205,24 @@ func (l7 *L7) checkHttpsProxy() (err error) {
 		l7.recorder.Eventf(l7.runtimeInfo.Ingress, corev1.EventTypeNormal, events.SyncIngress, "TargetProxy %q certs updated", key.Name)
 	}
 
+	if proxy.CertificateMap == "" && currentProxy.CertificateMap != "" {
+		if err := l7.ensureCertificateMap(currentProxy, ""); err != nil {
+			return err
+		}
+	}
+
 	if flags.F.EnableFrontendConfig && sslPolicySet {
 		if err := l7.ensureSslPolicy(env, currentProxy, proxy.SslPolicy); err != nil {
 			return err
 		}
 	}
 
+	if flags.F.EnableFrontendConfig && env.FrontendConfig != nil && env.FrontendConfig.Spec.ServerTlsPolicy != nil {
+		if err := l7.ensureServerTlsPolicy(currentProxy, proxy.ServerTlsPolicy); err != nil {
+			return err
+		}
+	}
+
 	l7.tps = currentProxy
 	return nil
 }
@@ -238,6 +265,80 @@ func (l7 *L7) ensureSslPolicy(env *translator.Env, currentProxy *composite.Targe
 	return nil
 }
 
+// ensureServerTlsPolicy attaches the server TLS policy to the https proxy, or
+// removes it if policyLink is empty.
+func (l7 *L7) ensureServerTlsPolicy(currentProxy *composite.TargetHttpsProxy, policyLink string) error {
+	if equalServerTlsPolicies(policyLink, currentProxy.ServerTlsPolicy) {
+		return nil
+	}
+	l7.logger.Info("ensureServerTlsPolicy", "newPolicyLink", policyLink, "currentPolicyLink", currentProxy.ServerTlsPolicy)
+	key, err := l7.CreateKey(currentProxy.Name)
+	if err != nil {
+		return err
+	}
+	if err := l7.serverTlsPolicies.SetServerTlsPolicy(key, currentProxy, policyLink); err != nil {
+		if policyLink != "" && isServerTlsPolicyNotFound(err) {
+			l7.recordServerTlsPolicyNotFound(policyLink)
+		}
+		return err
+	}
+	currentProxy.ServerTlsPolicy = policyLink
+	l7.recorder.Eventf(l7.runtimeInfo.Ingress, corev1.EventTypeNormal, events.SyncIngress, "TargetProxy %q ServerTlsPolicy updated", key.Name)
+	return nil
+}
+
+// ensureCertificateMap points the https proxy at the certificate map, or
+// detaches the map if certMapLink is empty.
+func (l7 *L7) ensureCertificateMap(currentProxy *composite.TargetHttpsProxy, certMapLink string) error {
+	if equalCertificateMaps(certMapLink, currentProxy.CertificateMap) {
+		return nil
+	}
+	l7.logger.Info("ensureCertificateMap", "newCertificateMap", certMapLink, "currentCertificateMap", currentProxy.CertificateMap)
+	key, err := l7.CreateKey(currentProxy.Name)
+	if err != nil {
+		return err
+	}
+	if err := composite.SetCertificateMapForTargetHttpsProxy(l7.cloud, key, currentProxy, certMapLink, l7.logger); err != nil {
+		return err
+	}
+	currentProxy.CertificateMap = certMapLink
+	l7.recorder.Eventf(l7.runtimeInfo.Ingress, corev1.EventTypeNormal, events.SyncIngress, "TargetProxy %q CertificateMap updated", key.Name)
+	return nil
+}
+
+// equalCertificateMaps returns true if both links refer to the same
+// certificate map. Like server TLS policies, certificate maps may be returned
+// with the project number instead of the project id.
+func equalCertificateMaps(a, b string) bool {
+	return equalServerTlsPolicies(a, b)
+}
+
+// recordServerTlsPolicyNotFound emits an event on the Ingress for a server
+// TLS policy which does not exist.
+func (l7 *L7) recordServerTlsPolicyNotFound(policyLink string) {
+	l7.recorder.Eventf(l7.runtimeInfo.Ingress, corev1.EventTypeWarning, events.SyncIngress, "ServerTlsPolicy %q of FrontendConfig %q does not exist", policyLink, l7.runtimeInfo.FrontendConfig.Name)
+}
+
+// isServerTlsPolicyNotFound returns true if the error reports a missing
+// resource. GCE reports missing policies referenced by a proxy either as not
+// found or as an invalid request.
+func isServerTlsPolicyNotFound(err error) bool {
+	return utils.IsNotFoundError(err) || (strings.Contains(err.Error(), "serverTlsPolicies") && strings.Contains(err.Error(), "not found"))
+}
+
+// equalServerTlsPolicies returns true if both links refer to the same server
+// TLS policy. GCE returns the links with the project number instead of the
+// project id, so only the location and name of the policies are compared.
+func equalServerTlsPolicies(a, b string) bool {
+	trim := func(link string) string {
+		if i := strings.Index(link, "/locations/"); i >= 0 {
+			return link[i:]
+		}
+		return link
+	}
+	return trim(a) == trim(b)
+}
+
 // ensureRegionalSslPolicy updates sslPolicy for regional HTTPs Proxy.
 // Regional HTTPs Proxies do not support setSslPolicy, and require using patch
 // method.
@@ -18,7 +18,10 @@ package loadbalancers
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/ingress-gce/pkg/annotations"
@@ -35,17 +38,10 @@ func (l7 *L7) ensureComputeURLMap() error {
 		return fmt.Errorf("cannot create urlmap without internal representation")
 	}
 
-	// Every update replaces the entire urlmap.
-	// Use an empty name parameter since we only care about the scope
-	// TODO: (shance) refactor this so we don't need an empty arg
-	key, err := l7.CreateKey("")
+	key, expectedMap, err := l7.expectedURLMap()
 	if err != nil {
 		return err
 	}
-	expectedMap := translator.ToCompositeURLMap(l7.runtimeInfo.UrlMap, l7.namer, key)
-	key.Name = expectedMap.Name
-
-	expectedMap.Version = l7.Versions().UrlMap
 	currentMap, err := composite.GetUrlMap(l7.cloud, key, expectedMap.Version, l7.logger)
 	if utils.IgnoreHTTPNotFound(err) != nil {
 		return err
@@ -64,7 +60,7 @@ func (l7 *L7) ensureComputeURLMap() error {
 		return nil
 	}
 
-	if mapsEqual(currentMap, expectedMap) {
+	if len(l7.changedURLMapFields(currentMap, expectedMap)) == 0 {
 		l7.logger.V(4).Info("URLMap for load-balancer is unchanged", "l7", l7)
 		l7.um = currentMap
 		return nil
@@ -82,6 +78,43 @@ func (l7 *L7) ensureComputeURLMap() error {
 	return nil
 }
 
+// expectedURLMap returns the UrlMap translated from the runtime info and its key.
+func (l7 *L7) expectedURLMap() (*meta.Key, *composite.UrlMap, error) {
+	// Every update replaces the entire urlmap.
+	// Use an empty name parameter since we only care about the scope
+	// TODO: (shance) refactor this so we don't need an empty arg
+	key, err := l7.CreateKey("")
+	if err != nil {
+		return nil, nil, err
+	}
+	expectedMap := translator.ToCompositeURLMap(l7.runtimeInfo.UrlMap, l7.namer, key)
+	key.Name = expectedMap.Name
+
+	feConfig := l7.runtimeInfo.FrontendConfig
+	if feConfig != nil && feConfig.Spec.CorsPolicy != nil {
+		// CORS policies are only supported by the advanced traffic management of
+		// the internal and regional external load balancers.
+		if !utils.IsGCEL7ILBIngress(&l7.ingress) && !utils.IsGCEL7XLBRegionalIngress(&l7.ingress) {
+			return nil, nil, fmt.Errorf("error: cannot enable CORS policy with the %q Ingress class", annotations.GceIngressClass)
+		}
+		translator.SetCorsPolicy(expectedMap, &translator.Env{FrontendConfig: feConfig, Ing: &l7.ingress})
+	}
+
+	// An adopted URL map gets the description of the URL maps managed by the
+	// controller. Target proxies and forwarding rules keep their description
+	// since the API cannot update it.
+	if l7.isAdoptedURLMap() {
+		description, err := l7.description()
+		if err != nil {
+			return nil, nil, err
+		}
+		expectedMap.Description = description
+	}
+
+	expectedMap.Version = l7.Versions().UrlMap
+	return key, expectedMap, nil
+}
+
 func (l7 *L7) ensureRedirectURLMap() error {
 	feConfig := l7.runtimeInfo.FrontendConfig
 	isL7ILB := utils.IsGCEL7ILBIngress(&l7.ingress)
@@ -158,8 +191,8 @@ func compareRedirectUrlMaps(a, b *composite.UrlMap) bool {
 	return false
 }
 
-// getBackendNames returns the names of backends in this L7 urlmap.
-func getBackendNames(computeURLMap *composite.UrlMap) ([]string, error) {
+// GetBackendNames returns the names of backends in this L7 urlmap.
+func GetBackendNames(computeURLMap *composite.UrlMap) ([]string, error) {
 	beNames := sets.NewString()
 	for _, pathMatcher := range computeURLMap.PathMatchers {
 		name, err := utils.KeyName(pathMatcher.DefaultService)
@@ -168,14 +201,29 @@ func getBackendNames(computeURLMap *composite.UrlMap) ([]string, error) {
 		}
 		beNames.Insert(name)
 
+		links := ruleBackendLinks("", pathMatcher.DefaultRouteAction)
 		for _, pathRule := range pathMatcher.PathRules {
-			name, err = utils.KeyName(pathRule.Service)
+			links = append(links, ruleBackendLinks(pathRule.Service, pathRule.RouteAction)...)
+		}
+		for _, routeRule := range pathMatcher.RouteRules {
+			links = append(links, ruleBackendLinks(routeRule.Service, routeRule.RouteAction)...)
+		}
+		for _, link := range links {
+			name, err = utils.KeyName(link)
 			if err != nil {
 				return nil, err
 			}
 			beNames.Insert(name)
 		}
 	}
+	// Custom error responses are served by a backend service or a backend bucket.
+	if policy := computeURLMap.DefaultCustomErrorResponsePolicy; policy != nil && strings.Contains(policy.ErrorService, "/backendServices/") {
+		name, err := utils.KeyName(policy.ErrorService)
+		if err != nil {
+			return nil, err
+		}
+		beNames.Insert(name)
+	}
 	// The default Service recorded in the urlMap is a link to the backend.
 	// Note that this can either be user specified, or the L7 controller's
 	// global default.
@@ -187,19 +235,71 @@ func getBackendNames(computeURLMap *composite.UrlMap) ([]string, error) {
 	return beNames.List(), nil
 }
 
+// ruleBackendLinks returns the links of all backend services used by a path
+// or route rule.
+func ruleBackendLinks(service string, action *composite.HttpRouteAction) []string {
+	var links []string
+	if service != "" {
+		links = append(links, service)
+	}
+	if action != nil {
+		for _, wb := range action.WeightedBackendServices {
+			links = append(links, wb.BackendService)
+		}
+		if action.RequestMirrorPolicy != nil {
+			links = append(links, action.RequestMirrorPolicy.BackendService)
+		}
+	}
+	return links
+}
+
 // mapsEqual compares the structure of two compute.UrlMaps.
 // The service strings are parsed and compared as resource paths (such as
 // "global/backendServices/my-service") to ignore variables: endpoint, version, and project.
 func mapsEqual(a, b *composite.UrlMap) bool {
+	return len(changedURLMapFields(a, b)) == 0
+}
+
+// changedURLMapFields returns the names of the top level fields the sync
+// changes in the current UrlMap. The description is only managed on an
+// adopted UrlMap.
+func (l7 *L7) changedURLMapFields(current, expected *composite.UrlMap) []string {
+	fields := changedURLMapFields(current, expected)
+	if l7.isAdoptedURLMap() && current.Description != expected.Description {
+		fields = append([]string{"Description"}, fields...)
+	}
+	return fields
+}
+
+// changedURLMapFields returns the names of the top level fields which differ
+// between the two UrlMaps, compared like mapsEqual.
+func changedURLMapFields(a, b *composite.UrlMap) []string {
+	var fields []string
 	if !utils.EqualResourcePaths(a.DefaultService, b.DefaultService) {
-		return false
+		fields = append(fields, "DefaultService")
 	}
-	if len(a.HostRules) != len(b.HostRules) {
+	if !routeActionsEqual(a.DefaultRouteAction, b.DefaultRouteAction) {
+		fields = append(fields, "DefaultRouteAction")
+	}
+	if !errorResponsePoliciesEqual(a.DefaultCustomErrorResponsePolicy, b.DefaultCustomErrorResponsePolicy) {
+		fields = append(fields, "DefaultCustomErrorResponsePolicy")
+	}
+	if !hostRulesEqual(a.HostRules, b.HostRules) {
+		fields = append(fields, "HostRules")
+	}
+	if !pathMatchersEqual(a.PathMatchers, b.PathMatchers) {
+		fields = append(fields, "PathMatchers")
+	}
+	return fields
+}
+
+func hostRulesEqual(a, b []*composite.HostRule) bool {
+	if len(a) != len(b) {
 		return false
 	}
-	for i := range a.HostRules {
-		a := a.HostRules[i]
-		b := b.HostRules[i]
+	for i := range a {
+		a := a[i]
+		b := b[i]
 		if a.Description != b.Description {
 			return false
 		}
@@ -215,12 +315,16 @@ func mapsEqual(a, b *composite.UrlMap) bool {
 			return false
 		}
 	}
-	if len(a.PathMatchers) != len(b.PathMatchers) {
+	return true
+}
+
+func pathMatchersEqual(a, b []*composite.PathMatcher) bool {
+	if len(a) != len(b) {
 		return false
 	}
-	for i := range a.PathMatchers {
-		a := a.PathMatchers[i]
-		b := b.PathMatchers[i]
+	for i := range a {
+		a := a[i]
+		b := b[i]
 		if !utils.EqualResourcePaths(a.DefaultService, b.DefaultService) {
 			return false
 		}
@@ -230,6 +334,9 @@ func mapsEqual(a, b *composite.UrlMap) bool {
 		if a.Name != b.Name {
 			return false
 		}
+		if !routeActionsEqual(a.DefaultRouteAction, b.DefaultRouteAction) {
+			return false
+		}
 		if len(a.PathRules) != len(b.PathRules) {
 			return false
 		}
@@ -244,10 +351,81 @@ func mapsEqual(a, b *composite.UrlMap) bool {
 					return false
 				}
 			}
-			if !utils.EqualResourcePaths(a.Service, b.Service) {
+			if a.Service != b.Service && !utils.EqualResourcePaths(a.Service, b.Service) {
 				return false
 			}
+			if !routeActionsEqual(a.RouteAction, b.RouteAction) {
+				return false
+			}
+		}
+		if !routeRulesEqual(a.RouteRules, b.RouteRules) {
+			return false
+		}
+	}
+	return true
+}
+
+// errorResponsePoliciesEqual compares two custom error response policies. The
+// error service links are compared as resource paths.
+func errorResponsePoliciesEqual(a, b *composite.CustomErrorResponsePolicy) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if !utils.EqualResourcePaths(a.ErrorService, b.ErrorService) {
+		return false
+	}
+	return reflect.DeepEqual(a.ErrorResponseRules, b.ErrorResponseRules)
+}
+
+// routeRulesEqual compares two lists of route rules. Backend service links
+// are compared as resource paths, like in mapsEqual.
+func routeRulesEqual(a, b []*composite.HttpRouteRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		a := a[i]
+		b := b[i]
+		if a.Priority != b.Priority {
+			return false
+		}
+		if !reflect.DeepEqual(a.MatchRules, b.MatchRules) {
+			return false
+		}
+		if a.Service != b.Service && !utils.EqualResourcePaths(a.Service, b.Service) {
+			return false
+		}
+		if !routeActionsEqual(a.RouteAction, b.RouteAction) {
+			return false
 		}
 	}
 	return true
 }
+
+// routeActionsEqual compares two route actions. Backend service links are
+// compared as resource paths, all other fields must be identical.
+func routeActionsEqual(a, b *composite.HttpRouteAction) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if len(a.WeightedBackendServices) != len(b.WeightedBackendServices) {
+		return false
+	}
+	for i := range a.WeightedBackendServices {
+		a := a.WeightedBackendServices[i]
+		b := b.WeightedBackendServices[i]
+		if a.Weight != b.Weight || !utils.EqualResourcePaths(a.BackendService, b.BackendService) {
+			return false
+		}
+	}
+	if (a.RequestMirrorPolicy == nil) != (b.RequestMirrorPolicy == nil) {
+		return false
+	}
+	if a.RequestMirrorPolicy != nil && !utils.EqualResourcePaths(a.RequestMirrorPolicy.BackendService, b.RequestMirrorPolicy.BackendService) {
+		return false
+	}
+	aCopy, bCopy := *a, *b
+	aCopy.WeightedBackendServices, bCopy.WeightedBackendServices = nil, nil
+	aCopy.RequestMirrorPolicy, bCopy.RequestMirrorPolicy = nil, nil
+	return reflect.DeepEqual(aCopy, bCopy)
+}
@@ -19,8 +19,13 @@ package loadbalancers
 import (
 	"testing"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/ingress-gce/pkg/composite"
+	"k8s.io/ingress-gce/pkg/translator"
+	"k8s.io/ingress-gce/pkg/utils"
+	namer_util "k8s.io/ingress-gce/pkg/utils/namer"
+	"k8s.io/klog/v2"
 )
 
 func TestComputeURLMapEquals(t *testing.T) {
@@ -39,6 +44,140 @@ func TestComputeURLMapEquals(t *testing.T) {
 	if mapsEqual(m, diffDefault) {
 		t.Errorf("mapsEqual(%+v, %+v) = true, want false", m, diffDefault)
 	}
+
+	// Test different route rules.
+	withRouteRules := testCompositeURLMap()
+	withRouteRules.PathMatchers[0].RouteRules = []*composite.HttpRouteRule{
+		{
+			Priority: 1,
+			MatchRules: []*composite.HttpRouteRuleMatch{
+				{PrefixMatch: "/", HeaderMatches: []*composite.HttpHeaderMatch{{HeaderName: "x-canary", ExactMatch: "true"}}},
+			},
+			Service: "global/backendServices/k8s-be-34000--uid1",
+		},
+	}
+	if mapsEqual(m, withRouteRules) {
+		t.Errorf("mapsEqual(%+v, %+v) = true, want false", m, withRouteRules)
+	}
+	sameRouteRules := testCompositeURLMap()
+	sameRouteRules.PathMatchers[0].RouteRules = []*composite.HttpRouteRule{
+		{
+			Priority: 1,
+			MatchRules: []*composite.HttpRouteRuleMatch{
+				{PrefixMatch: "/", HeaderMatches: []*composite.HttpHeaderMatch{{HeaderName: "x-canary", ExactMatch: "true"}}},
+			},
+			Service: "https://www.googleapis.com/compute/v1/projects/p/global/backendServices/k8s-be-34000--uid1",
+		},
+	}
+	if !mapsEqual(withRouteRules, sameRouteRules) {
+		t.Errorf("mapsEqual(%+v, %+v) = false, want true", withRouteRules, sameRouteRules)
+	}
+	diffHeader := testCompositeURLMap()
+	diffHeader.PathMatchers[0].RouteRules = []*composite.HttpRouteRule{
+		{
+			Priority: 1,
+			MatchRules: []*composite.HttpRouteRuleMatch{
+				{PrefixMatch: "/", HeaderMatches: []*composite.HttpHeaderMatch{{HeaderName: "x-canary", ExactMatch: "false"}}},
+			},
+			Service: "global/backendServices/k8s-be-34000--uid1",
+		},
+	}
+	if mapsEqual(withRouteRules, diffHeader) {
+		t.Errorf("mapsEqual(%+v, %+v) = true, want false", withRouteRules, diffHeader)
+	}
+
+	// Test different weights.
+	weighted := testCompositeURLMap()
+	weighted.PathMatchers[0].PathRules[0].Service = ""
+	weighted.PathMatchers[0].PathRules[0].RouteAction = &composite.HttpRouteAction{
+		WeightedBackendServices: []*composite.WeightedBackendService{
+			{BackendService: "global/backendServices/k8s-be-32000--uid1", Weight: 90},
+			{BackendService: "global/backendServices/k8s-be-34000--uid1", Weight: 10},
+		},
+	}
+	if mapsEqual(m, weighted) {
+		t.Errorf("mapsEqual(%+v, %+v) = true, want false", m, weighted)
+	}
+	diffWeights := testCompositeURLMap()
+	diffWeights.PathMatchers[0].PathRules[0].Service = ""
+	diffWeights.PathMatchers[0].PathRules[0].RouteAction = &composite.HttpRouteAction{
+		WeightedBackendServices: []*composite.WeightedBackendService{
+			{BackendService: "global/backendServices/k8s-be-32000--uid1", Weight: 50},
+			{BackendService: "global/backendServices/k8s-be-34000--uid1", Weight: 50},
+		},
+	}
+	if mapsEqual(weighted, diffWeights) {
+		t.Errorf("mapsEqual(%+v, %+v) = true, want false", weighted, diffWeights)
+	}
+
+	// Test request mirroring, with links that only differ in the project.
+	mirrored := testCompositeURLMap()
+	mirrored.PathMatchers[0].DefaultRouteAction = &composite.HttpRouteAction{
+		RequestMirrorPolicy: &composite.RequestMirrorPolicy{BackendService: "global/backendServices/k8s-be-34000--uid1"},
+	}
+	if mapsEqual(m, mirrored) {
+		t.Errorf("mapsEqual(%+v, %+v) = true, want false", m, mirrored)
+	}
+	sameMirror := testCompositeURLMap()
+	sameMirror.PathMatchers[0].DefaultRouteAction = &composite.HttpRouteAction{
+		RequestMirrorPolicy: &composite.RequestMirrorPolicy{BackendService: "https://www.googleapis.com/compute/v1/projects/p/global/backendServices/k8s-be-34000--uid1"},
+	}
+	if !mapsEqual(mirrored, sameMirror) {
+		t.Errorf("mapsEqual(%+v, %+v) = false, want true", mirrored, sameMirror)
+	}
+
+	// Test custom error responses, with links that only differ in the project.
+	errorResponses := testCompositeURLMap()
+	errorResponses.DefaultCustomErrorResponsePolicy = &composite.CustomErrorResponsePolicy{
+		ErrorService:       "global/backendBuckets/error-pages",
+		ErrorResponseRules: []*composite.CustomErrorResponsePolicyCustomErrorResponseRule{{MatchResponseCodes: []string{"5xx"}, Path: "/500.html"}},
+	}
+	if mapsEqual(m, errorResponses) {
+		t.Errorf("mapsEqual(%+v, %+v) = true, want false", m, errorResponses)
+	}
+	sameErrorResponses := testCompositeURLMap()
+	sameErrorResponses.DefaultCustomErrorResponsePolicy = &composite.CustomErrorResponsePolicy{
+		ErrorService:       "https://www.googleapis.com/compute/v1/projects/p/global/backendBuckets/error-pages",
+		ErrorResponseRules: []*composite.CustomErrorResponsePolicyCustomErrorResponseRule{{MatchResponseCodes: []string{"5xx"}, Path: "/500.html"}},
+	}
+	if !mapsEqual(errorResponses, sameErrorResponses) {
+		t.Errorf("mapsEqual(%+v, %+v) = false, want true", errorResponses, sameErrorResponses)
+	}
+	diffErrorRules := testCompositeURLMap()
+	diffErrorRules.DefaultCustomErrorResponsePolicy = &composite.CustomErrorResponsePolicy{
+		ErrorService:       "global/backendBuckets/error-pages",
+		ErrorResponseRules: []*composite.CustomErrorResponsePolicyCustomErrorResponseRule{{MatchResponseCodes: []string{"4xx"}, Path: "/404.html"}},
+	}
+	if mapsEqual(errorResponses, diffErrorRules) {
+		t.Errorf("mapsEqual(%+v, %+v) = true, want false", errorResponses, diffErrorRules)
+	}
+}
+
+// TestComputeURLMapEqualsGCEDefaults asserts that a translated UrlMap equals
+// the one GCE returns with its defaults filled in, so that it is not updated
+// on every sync.
+func TestComputeURLMapEqualsGCEDefaults(t *testing.T) {
+	t.Parallel()
+
+	namer := namer_util.NewNamer(clusterName, "fw1", klog.TODO())
+	feNamer := namer_util.NewFrontendNamerFactory(namer, "ks-uid", klog.TODO()).Namer(newIngress())
+	gceURLMap := utils.NewGCEURLMap(klog.TODO())
+	gceURLMap.DefaultBackend = &utils.ServicePort{NodePort: 30000, BackendNamer: namer}
+	gceURLMap.PutPathRulesForHost("foo.bar.com", []utils.PathRule{
+		{
+			Path:    "/api",
+			Backend: utils.ServicePort{NodePort: 32000, BackendNamer: namer},
+			Action:  &utils.RouteAction{RetryPolicy: &utils.RetryPolicy{RetryConditions: []string{"5xx"}}},
+		},
+	})
+
+	expected := translator.ToCompositeURLMap(gceURLMap, feNamer, meta.GlobalKey(""))
+	// GCE stores one retry when the number of retries is unset.
+	current := translator.ToCompositeURLMap(gceURLMap, feNamer, meta.GlobalKey(""))
+	current.PathMatchers[0].PathRules[0].RouteAction.RetryPolicy.NumRetries = 1
+	if fields := changedURLMapFields(current, expected); len(fields) != 0 {
+		t.Errorf("changedURLMapFields(%+v, %+v) = %v, want no changes", current, expected, fields)
+	}
 }
 
 func testCompositeURLMap() *composite.UrlMap {
@@ -113,6 +252,75 @@ func TestGetBackendNames(t *testing.T) {
 			},
 			wantNames: []string{"service-A", "service-B", "service-C"},
 		},
+		"Weighted backend services": {
+			urlMap: &composite.UrlMap{
+				DefaultService: "global/backendServices/service-A",
+				PathMatchers: []*composite.PathMatcher{
+					{
+						DefaultService: "global/backendServices/service-A",
+						PathRules: []*composite.PathRule{
+							{
+								Paths: []string{"/"},
+								RouteAction: &composite.HttpRouteAction{
+									WeightedBackendServices: []*composite.WeightedBackendService{
+										{BackendService: "global/backendServices/service-B", Weight: 90},
+										{BackendService: "global/backendServices/service-C", Weight: 10},
+									},
+								},
+							},
+						},
+						RouteRules: []*composite.HttpRouteRule{
+							{
+								Priority: 1,
+								Service:  "global/backendServices/service-D",
+							},
+						},
+					},
+				},
+			},
+			wantNames: []string{"service-A", "service-B", "service-C", "service-D"},
+		},
+		"Mirrored backend services": {
+			urlMap: &composite.UrlMap{
+				DefaultService: "global/backendServices/service-A",
+				PathMatchers: []*composite.PathMatcher{
+					{
+						DefaultService: "global/backendServices/service-A",
+						DefaultRouteAction: &composite.HttpRouteAction{
+							RequestMirrorPolicy: &composite.RequestMirrorPolicy{BackendService: "global/backendServices/service-B"},
+						},
+						PathRules: []*composite.PathRule{
+							{
+								Paths:   []string{"/"},
+								Service: "global/backendServices/service-A",
+								RouteAction: &composite.HttpRouteAction{
+									RequestMirrorPolicy: &composite.RequestMirrorPolicy{BackendService: "global/backendServices/service-C"},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantNames: []string{"service-A", "service-B", "service-C"},
+		},
+		"Custom error responses": {
+			urlMap: &composite.UrlMap{
+				DefaultService: "global/backendServices/service-A",
+				DefaultCustomErrorResponsePolicy: &composite.CustomErrorResponsePolicy{
+					ErrorService: "global/backendServices/service-errors",
+				},
+			},
+			wantNames: []string{"service-A", "service-errors"},
+		},
+		"Custom error responses from a backend bucket": {
+			urlMap: &composite.UrlMap{
+				DefaultService: "global/backendServices/service-A",
+				DefaultCustomErrorResponsePolicy: &composite.CustomErrorResponsePolicy{
+					ErrorService: "global/backendBuckets/error-pages",
+				},
+			},
+			wantNames: []string{"service-A"},
+		},
 		"Invalid DefaultService": {
 			urlMap: &composite.UrlMap{
 				DefaultService: "/global/backendServices/service-A",
@@ -151,16 +359,16 @@ func TestGetBackendNames(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			gotNames, gotErr := getBackendNames(tc.urlMap)
+			gotNames, gotErr := GetBackendNames(tc.urlMap)
 			if (gotErr != nil) != tc.wantErr {
-				t.Errorf("getBackendNames(%v) = _, %v, want err? %v", tc.urlMap, gotErr, tc.wantErr)
+				t.Errorf("GetBackendNames(%v) = _, %v, want err? %v", tc.urlMap, gotErr, tc.wantErr)
 			}
 			if gotErr != nil {
 				return
 			}
 
 			if !sets.NewString(gotNames...).Equal(sets.NewString(tc.wantNames...)) {
-				t.Errorf("getBackendNames(%v) = %v, want %v", tc.urlMap, gotNames, tc.wantNames)
+				t.Errorf("GetBackendNames(%v) = %v, want %v", tc.urlMap, gotNames, tc.wantNames)
 			}
 		})
 	}
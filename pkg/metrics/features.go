@@ -102,6 +102,9 @@ const (
 	// FrontendConfig Features
 	sslPolicy      = feature("SSLPolicy")
 	httpsRedirects = feature("HTTPSRedirects")
+	corsPolicy     = feature("CorsPolicy")
+	mutualTLS      = feature("MutualTLS")
+	certificateMap = feature("CertificateMap")
 
 	l4ILBService      = feature("L4ILBService")
 	l4ILBGlobalAccess = feature("L4ILBGlobalAccess")
@@ -251,6 +254,15 @@ func featuresForIngress(ing *v1.Ingress, fc *frontendconfigv1beta1.FrontendConfi
 		if fc.Spec.RedirectToHttps != nil && fc.Spec.RedirectToHttps.Enabled {
 			features = append(features, httpsRedirects)
 		}
+		if fc.Spec.CorsPolicy != nil {
+			features = append(features, corsPolicy)
+		}
+		if fc.Spec.ServerTlsPolicy != nil && *fc.Spec.ServerTlsPolicy != "" {
+			features = append(features, mutualTLS)
+		}
+		if fc.Spec.CertificateMap != "" {
+			features = append(features, certificateMap)
+		}
 	}
 
 	logger.V(4).Info("Features for ingress", "ingressKey", ingKey, "ingressFeatures", features)
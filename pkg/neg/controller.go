@@ -45,6 +45,7 @@ import (
 	"k8s.io/ingress-gce/pkg/neg/syncers/labels"
 	negtypes "k8s.io/ingress-gce/pkg/neg/types"
 	"k8s.io/ingress-gce/pkg/network"
+	"k8s.io/ingress-gce/pkg/pause"
 	svcnegclient "k8s.io/ingress-gce/pkg/svcneg/client/clientset/versioned"
 	"k8s.io/ingress-gce/pkg/utils"
 	"k8s.io/ingress-gce/pkg/utils/endpointslices"
@@ -54,10 +55,14 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// controllerName is the name of the NEG controller in events and metrics.
+const controllerName = "neg-controller"
+
 func init() {
 	// register prometheus metrics
 	metrics.RegisterMetrics()
 	syncMetrics.RegisterMetrics()
+	pause.RegisterMetrics()
 }
 
 // Controller is network endpoint group controller.
@@ -165,7 +170,7 @@ func NewController(
 		metrics.PublishNegControllerErrorCountMetrics(err, true)
 	}
 	recorder := eventBroadcaster.NewRecorder(negScheme,
-		apiv1.EventSource{Component: "neg-controller"})
+		apiv1.EventSource{Component: controllerName})
 
 	syncerMetrics := syncMetrics.NewNegMetricsCollector(flags.F.NegMetricsExportInterval, logger)
 	manager := newSyncerManager(
@@ -463,6 +468,7 @@ func (c *Controller) processService(key string) error {
 		return err
 	}
 	if !exists {
+		pause.Resume(pause.KindService, controllerName, key)
 		c.syncerMetrics.DeleteNegService(key)
 		c.manager.StopSyncer(namespace, name)
 		return nil
@@ -471,6 +477,15 @@ func (c *Controller) processService(key string) error {
 	if service == nil {
 		return fmt.Errorf("cannot convert to Service (%T)", obj)
 	}
+	// The NEGs of paused services are left as they are. Their syncers are
+	// not stopped, so that they keep attaching and detaching the endpoints
+	// of the NEGs as pods change and the load balancer keeps serving.
+	if annotations.FromService(service).ReconciliationPaused() {
+		c.logger.V(2).Info("Reconciliation of service is paused, skipping", "service", key)
+		pause.Report(c.recorder, service, pause.KindService, controllerName)
+		return nil
+	}
+	pause.Resume(pause.KindService, controllerName, key)
 	// TODO(cheungdavid): Remove this validation when single stack ipv6 endpoint is supported
 	if service.Spec.Type != apiv1.ServiceTypeLoadBalancer && isSingleStackIPv6Service(service) {
 		return fmt.Errorf("NEG is not supported for ipv6 only service (%T)", service)
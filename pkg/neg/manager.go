@@ -35,6 +35,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/ingress-gce/pkg/annotations"
 	negv1beta1 "k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1"
 	"k8s.io/ingress-gce/pkg/neg/metrics"
 	"k8s.io/ingress-gce/pkg/neg/metrics/metricscollector"
@@ -518,6 +519,17 @@ func (manager *syncerManager) garbageCollectNEG() error {
 			}
 		}
 	}()
+	for _, svc := range manager.pausedServices() {
+		// The NEGs of paused services are kept until their syncers are
+		// recreated, e.g. after a restart of the controller.
+		negStatus, found, err := annotations.FromService(svc).NEGStatus()
+		if err != nil || !found {
+			continue
+		}
+		for _, negName := range negStatus.NetworkEndpointGroups {
+			delete(deleteCandidates, negName)
+		}
+	}
 
 	// This section includes a potential race condition between deleting neg here and users adds the neg annotation.
 	// The worst outcome of the race condition is that neg is deleted in the end but user actually specifies a neg.
@@ -533,6 +545,19 @@ func (manager *syncerManager) garbageCollectNEG() error {
 	return nil
 }
 
+// pausedServices returns the services whose reconciliation is paused. Their
+// NEGs are not garbage collected.
+func (manager *syncerManager) pausedServices() []*v1.Service {
+	var paused []*v1.Service
+	for _, obj := range manager.serviceLister.List() {
+		svc, ok := obj.(*v1.Service)
+		if ok && annotations.FromService(svc).ReconciliationPaused() {
+			paused = append(paused, svc)
+		}
+	}
+	return paused
+}
+
 // garbageCollectNEGWithCRD uses the NEG CRs and the svcPortMap to determine which NEGs
 // need to be garbage collected. Neg CRs that do not have a configuration in the svcPortMap will deleted
 // along with all corresponding NEGs in the CR's list of NetworkEndpointGroups. If NEG deletion fails in
@@ -562,6 +587,15 @@ func (manager *syncerManager) garbageCollectNEGWithCRD() error {
 			}
 		}
 	}()
+	paused := sets.NewString()
+	for _, svc := range manager.pausedServices() {
+		paused.Insert(serviceKey{namespace: svc.Namespace, name: svc.Name}.Key())
+	}
+	for name, neg := range deletionCandidates {
+		if paused.Has(serviceKey{namespace: neg.Namespace, name: neg.Labels[negtypes.NegCRServiceNameKey]}.Key()) {
+			delete(deletionCandidates, name)
+		}
+	}
 
 	// This section includes a potential race condition between deleting neg here and users adds the neg annotation.
 	// The worst outcome of the race condition is that neg is deleted in the end but user actually specifies a neg.
@@ -41,6 +41,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/ingress-gce/pkg/annotations"
 	negv1beta1 "k8s.io/ingress-gce/pkg/apis/svcneg/v1beta1"
 	"k8s.io/ingress-gce/pkg/neg/metrics/metricscollector"
 	"k8s.io/ingress-gce/pkg/neg/syncers/labels"
@@ -433,6 +434,42 @@ func TestGarbageCollectionNEG(t *testing.T) {
 	manager.StopSyncer(testServiceNamespace, testServiceName)
 }
 
+func TestGarbageCollectionNEGOfPausedService(t *testing.T) {
+	t.Parallel()
+	manager, _ := NewTestSyncerManager(fake.NewSimpleClientset())
+	manager.serviceLister.Add(&v1.Service{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "test",
+		Name:        "paused",
+		Annotations: map[string]string{annotations.PauseReconciliationKey: "true"},
+	}})
+	negName := manager.namer.NEG("test", "paused", 80)
+	manager.cloud.CreateNetworkEndpointGroup(&composite.NetworkEndpointGroup{
+		Version:             meta.VersionGA,
+		Name:                negName,
+		NetworkEndpointType: string(negtypes.VmIpPortEndpointType),
+	}, negtypes.TestZone1, klog.TODO())
+	svcNeg := &negv1beta1.ServiceNetworkEndpointGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      negName,
+			Labels:    map[string]string{negtypes.NegCRServiceNameKey: "paused"},
+		},
+	}
+	manager.svcNegLister.Add(svcNeg)
+	manager.svcNegClient.NetworkingV1beta1().ServiceNetworkEndpointGroups("test").Create(context2.Background(), svcNeg, metav1.CreateOptions{})
+
+	// The paused service has no syncer, e.g. after a restart.
+	if err := manager.GC(); err != nil {
+		t.Fatalf("Failed to GC: %v", err)
+	}
+	if _, err := manager.cloud.GetNetworkEndpointGroup(negName, negtypes.TestZone1, meta.VersionGA, klog.TODO()); err != nil {
+		t.Errorf("Expect NEG %q of the paused service to be kept, got err: %v", negName, err)
+	}
+	if _, err := manager.svcNegClient.NetworkingV1beta1().ServiceNetworkEndpointGroups("test").Get(context2.Background(), negName, metav1.GetOptions{}); err != nil {
+		t.Errorf("Expect NEG CR %q of the paused service to be kept, got err: %v", negName, err)
+	}
+}
+
 func TestReadinessGateEnabledNegs(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pause reports the objects whose reconciliation is paused by the
+// networking.gke.io/pause-reconciliation annotation.
+package pause
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// EventReason is the reason of the events which report a paused object.
+	EventReason = "ReconciliationPaused"
+
+	// KindIngress is the kind of paused Ingresses in metrics.
+	KindIngress = "Ingress"
+	// KindService is the kind of paused Services in metrics.
+	KindService = "Service"
+)
+
+var pausedObjects = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "reconciliation_paused",
+		Help: "Number of objects whose reconciliation is paused",
+	},
+	[]string{"kind", "controller"},
+)
+
+var register sync.Once
+
+// RegisterMetrics registers the paused reconciliation metrics.
+func RegisterMetrics() {
+	register.Do(func() {
+		prometheus.MustRegister(pausedObjects)
+	})
+}
+
+// pausedKey identifies the paused objects of a kind reported by a
+// controller. Services are synced by several controllers, each of which
+// reports and resumes them independently.
+type pausedKey struct {
+	kind       string
+	controller string
+}
+
+var (
+	lock sync.Mutex
+	// paused holds the keys of the paused objects of each kind and
+	// controller.
+	paused = map[pausedKey]sets.String{}
+)
+
+// Report exports obj as paused by the given controller. The first time the
+// controller reports the object after it is paused, an event is emitted on
+// it, telling that its syncs are skipped.
+func Report(recorder record.EventRecorder, obj runtime.Object, kind, controller string) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.Errorf("Failed to report paused %s: %v", kind, err)
+		return
+	}
+	pk := pausedKey{kind: kind, controller: controller}
+	lock.Lock()
+	defer lock.Unlock()
+	if paused[pk].Has(key) {
+		return
+	}
+	if paused[pk] == nil {
+		paused[pk] = sets.NewString()
+	}
+	paused[pk].Insert(key)
+	pausedObjects.WithLabelValues(kind, controller).Set(float64(paused[pk].Len()))
+	recorder.Eventf(obj, v1.EventTypeNormal, EventReason, "Reconciliation is paused by the %s annotation, skipping the sync of GCE resources", annotations.PauseReconciliationKey)
+}
+
+// Resume stops exporting the object with the given namespace/name key as
+// paused by the given controller. It must also be called when the object is
+// deleted.
+func Resume(kind, controller, key string) {
+	pk := pausedKey{kind: kind, controller: controller}
+	lock.Lock()
+	defer lock.Unlock()
+	if !paused[pk].Has(key) {
+		return
+	}
+	paused[pk].Delete(key)
+	pausedObjects.WithLabelValues(kind, controller).Set(float64(paused[pk].Len()))
+}
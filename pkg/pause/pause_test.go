@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pause
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestReportAndResume(t *testing.T) {
+	ing := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "ing", Namespace: "ns"}}
+	otherIng := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns"}}
+	recorder := record.NewFakeRecorder(10)
+
+	Report(recorder, ing, KindIngress, "ingress")
+	Report(recorder, otherIng, KindIngress, "ingress")
+	if got := testutil.ToFloat64(pausedObjects.WithLabelValues(KindIngress, "ingress")); got != 2 {
+		t.Errorf("reconciliation_paused{Ingress, ingress} = %v, want 2", got)
+	}
+	want := "Normal ReconciliationPaused Reconciliation is paused by the networking.gke.io/pause-reconciliation annotation, skipping the sync of GCE resources"
+	if got := <-recorder.Events; got != want {
+		t.Errorf("Report() emitted event %q, want %q", got, want)
+	}
+	<-recorder.Events
+
+	// Resyncs of a paused object do not emit events again.
+	Report(recorder, ing, KindIngress, "ingress")
+	if n := len(recorder.Events); n != 0 {
+		t.Errorf("Report() of an object already paused emitted %d events, want none", n)
+	}
+	if got := testutil.ToFloat64(pausedObjects.WithLabelValues(KindIngress, "ingress")); got != 2 {
+		t.Errorf("reconciliation_paused{Ingress, ingress} = %v, want 2", got)
+	}
+
+	Resume(KindIngress, "ingress", "ns/ing")
+	Resume(KindIngress, "ingress", "ns/ing")
+	if got := testutil.ToFloat64(pausedObjects.WithLabelValues(KindIngress, "ingress")); got != 1 {
+		t.Errorf("reconciliation_paused{Ingress, ingress} = %v after Resume(), want 1", got)
+	}
+	// An object paused again is reported again.
+	Report(recorder, ing, KindIngress, "ingress")
+	if n := len(recorder.Events); n != 1 {
+		t.Errorf("Report() of an object paused again emitted %d events, want 1", n)
+	}
+	Resume(KindIngress, "ingress", "ns/ing")
+	Resume(KindIngress, "ingress", "ns/other")
+	if got := testutil.ToFloat64(pausedObjects.WithLabelValues(KindIngress, "ingress")); got != 0 {
+		t.Errorf("reconciliation_paused{Ingress, ingress} = %v after Resume(), want 0", got)
+	}
+}
+
+// TestReportAndResumeControllers asserts that the controllers syncing the
+// same object report and resume it independently.
+func TestReportAndResumeControllers(t *testing.T) {
+	svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "ns"}}
+	recorder := record.NewFakeRecorder(10)
+
+	Report(recorder, svc, KindService, "neg")
+	Report(recorder, svc, KindService, "l4")
+	if n := len(recorder.Events); n != 2 {
+		t.Errorf("Report() by two controllers emitted %d events, want 2", n)
+	}
+
+	// Resuming the object in one controller keeps it paused in the other.
+	Resume(KindService, "l4", "ns/svc")
+	Report(recorder, svc, KindService, "neg")
+	if n := len(recorder.Events); n != 2 {
+		t.Errorf("Report() of an object still paused emitted %d events, want none", n-2)
+	}
+	if got := testutil.ToFloat64(pausedObjects.WithLabelValues(KindService, "neg")); got != 1 {
+		t.Errorf("reconciliation_paused{Service, neg} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(pausedObjects.WithLabelValues(KindService, "l4")); got != 0 {
+		t.Errorf("reconciliation_paused{Service, l4} = %v, want 0", got)
+	}
+	Resume(KindService, "neg", "ns/svc")
+}
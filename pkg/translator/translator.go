@@ -21,6 +21,9 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
@@ -28,6 +31,7 @@ import (
 	v1 "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/ingress-gce/pkg/annotations"
 	frontendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1"
 	"k8s.io/ingress-gce/pkg/composite"
 	"k8s.io/ingress-gce/pkg/flags"
@@ -193,6 +197,9 @@ func ToCompositeURLMap(g *utils.GCEURLMap, namer namer.IngressFrontendNamer, key
 		Name:           namer.UrlMap(),
 		DefaultService: resourceID.ResourcePath(),
 	}
+	if g.ErrorResponsePolicy != nil {
+		m.DefaultCustomErrorResponsePolicy = toCompositeErrorResponsePolicy(g.ErrorResponsePolicy, key)
+	}
 
 	for _, hostRule := range g.HostRules {
 		// Create a host rule
@@ -205,27 +212,254 @@ func ToCompositeURLMap(g *utils.GCEURLMap, namer namer.IngressFrontendNamer, key
 		})
 
 		pathMatcher := &composite.PathMatcher{
-			Name:           pmName,
-			DefaultService: m.DefaultService,
-			PathRules:      []*composite.PathRule{},
+			Name:               pmName,
+			DefaultService:     m.DefaultService,
+			DefaultRouteAction: toCompositeRouteAction(hostRule.Action, key),
+		}
+
+		if len(hostRule.RouteRules) > 0 {
+			pathMatcher.RouteRules = toCompositeRouteRules(hostRule, namer, key)
+			m.PathMatchers = append(m.PathMatchers, pathMatcher)
+			continue
 		}
 
+		pathMatcher.PathRules = []*composite.PathRule{}
 		// GCE ensures that matched rule with longest prefix wins.
 		for _, rule := range hostRule.Paths {
-			beName := rule.Backend.BackendName()
-			key.Name = beName
-			resourceID := cloud.ResourceID{ProjectID: "", Resource: "backendServices", Key: key}
-			beLink := resourceID.ResourcePath()
-			pathMatcher.PathRules = append(pathMatcher.PathRules, &composite.PathRule{
-				Paths:   []string{rule.Path},
-				Service: beLink,
-			})
+			pathRule := &composite.PathRule{
+				Paths:       []string{rule.Path},
+				RouteAction: toCompositeRouteAction(withHostAction(rule.Action, hostRule.Action), key),
+			}
+			if !hasWeightedBackends(rule.Action) {
+				pathRule.Service = pathRuleServiceLink(rule, namer, key)
+			}
+			pathMatcher.PathRules = append(pathMatcher.PathRules, pathRule)
 		}
 		m.PathMatchers = append(m.PathMatchers, pathMatcher)
 	}
 	return m
 }
 
+// toCompositeErrorResponsePolicy converts the error response policy to its
+// composite representation.
+func toCompositeErrorResponsePolicy(policy *utils.ErrorResponsePolicy, key *meta.Key) *composite.CustomErrorResponsePolicy {
+	ret := &composite.CustomErrorResponsePolicy{}
+	if policy.Backend != nil {
+		ret.ErrorService = backendServiceLink(*policy.Backend, key)
+	} else {
+		ret.ErrorService = backendBucketLink(policy.BackendBucket)
+	}
+	for _, rule := range policy.Rules {
+		ret.ErrorResponseRules = append(ret.ErrorResponseRules, &composite.CustomErrorResponsePolicyCustomErrorResponseRule{
+			MatchResponseCodes:   rule.MatchResponseCodes,
+			Path:                 rule.Path,
+			OverrideResponseCode: rule.OverrideResponseCode,
+		})
+	}
+	return ret
+}
+
+// backendServiceLink returns the resource path of the backend service for the given ServicePort.
+func backendServiceLink(sp utils.ServicePort, key *meta.Key) string {
+	key.Name = sp.BackendName()
+	resourceID := cloud.ResourceID{ProjectID: "", Resource: "backendServices", Key: key}
+	return resourceID.ResourcePath()
+}
+
+// backendBucketLink returns the resource path of the backend bucket with the
+// given name. Backend buckets are global resources.
+func backendBucketLink(name string) string {
+	resourceID := cloud.ResourceID{ProjectID: "", Resource: "backendBuckets", Key: meta.GlobalKey(name)}
+	return resourceID.ResourcePath()
+}
+
+// pathRuleServiceLink returns the resource path of the backend bucket or
+// backend service serving the path rule.
+func pathRuleServiceLink(rule utils.PathRule, namer namer.IngressFrontendNamer, key *meta.Key) string {
+	if rule.BackendBucket != nil {
+		return backendBucketLink(namer.BackendBucket(rule.BackendBucket.ID.Namespace, rule.BackendBucket.ID.Name))
+	}
+	return backendServiceLink(rule.Backend, key)
+}
+
+// toCompositeRouteRules returns the route rules for a host that uses advanced
+// routing. A path matcher cannot mix path rules and route rules, so the plain
+// paths of the host are converted into route rules that are evaluated after
+// the user specified ones. Path derived rules are ordered by decreasing
+// length to keep the longest-prefix-wins semantics of path rules.
+func toCompositeRouteRules(hostRule utils.HostRule, namer namer.IngressFrontendNamer, key *meta.Key) []*composite.HttpRouteRule {
+	userRules := make([]utils.RouteRule, len(hostRule.RouteRules))
+	copy(userRules, hostRule.RouteRules)
+	sort.SliceStable(userRules, func(i, j int) bool { return userRules[i].Priority < userRules[j].Priority })
+
+	var routeRules []*composite.HttpRouteRule
+	var nextPriority int64
+	for _, rule := range userRules {
+		routeRule := &composite.HttpRouteRule{
+			Priority:    rule.Priority,
+			RouteAction: toCompositeRouteAction(withHostAction(rule.Action, hostRule.Action), key),
+		}
+		if !hasWeightedBackends(rule.Action) {
+			routeRule.Service = backendServiceLink(rule.Backend, key)
+		}
+		for _, match := range rule.Matches {
+			routeRule.MatchRules = append(routeRule.MatchRules, toCompositeRouteRuleMatch(match))
+		}
+		routeRules = append(routeRules, routeRule)
+		nextPriority = rule.Priority + 1
+	}
+
+	pathRules := make([]utils.PathRule, len(hostRule.Paths))
+	copy(pathRules, hostRule.Paths)
+	sort.SliceStable(pathRules, func(i, j int) bool {
+		return len(strings.TrimSuffix(pathRules[i].Path, "*")) > len(strings.TrimSuffix(pathRules[j].Path, "*"))
+	})
+	for _, rule := range pathRules {
+		match := &composite.HttpRouteRuleMatch{}
+		if strings.HasSuffix(rule.Path, "*") {
+			match.PrefixMatch = strings.TrimSuffix(rule.Path, "*")
+		} else {
+			match.FullPathMatch = rule.Path
+		}
+		routeRule := &composite.HttpRouteRule{
+			Priority:    nextPriority,
+			MatchRules:  []*composite.HttpRouteRuleMatch{match},
+			RouteAction: toCompositeRouteAction(withHostAction(rule.Action, hostRule.Action), key),
+		}
+		if !hasWeightedBackends(rule.Action) {
+			routeRule.Service = pathRuleServiceLink(rule, namer, key)
+		}
+		routeRules = append(routeRules, routeRule)
+		nextPriority++
+	}
+	return routeRules
+}
+
+// hasWeightedBackends returns true if the action replaces the backend of a
+// rule with weighted backend services.
+func hasWeightedBackends(action *utils.RouteAction) bool {
+	return action != nil && len(action.WeightedBackends) > 0
+}
+
+// withHostAction returns the action of a rule completed with the request
+// mirroring of the host. Rules that mirror requests themselves are unchanged.
+func withHostAction(action, hostAction *utils.RouteAction) *utils.RouteAction {
+	if hostAction == nil || hostAction.MirrorBackend == nil {
+		return action
+	}
+	if action == nil {
+		return &utils.RouteAction{MirrorBackend: hostAction.MirrorBackend}
+	}
+	if action.MirrorBackend != nil {
+		return action
+	}
+	ret := *action
+	ret.MirrorBackend = hostAction.MirrorBackend
+	return &ret
+}
+
+// defaultNumRetries is the number of retries GCE stores when a retry policy
+// leaves it unset. It is written explicitly so that the UrlMap read back from
+// GCE equals the translated one.
+const defaultNumRetries = 1
+
+// toCompositeRouteAction converts a RouteAction into its GCE equivalent.
+func toCompositeRouteAction(action *utils.RouteAction, key *meta.Key) *composite.HttpRouteAction {
+	if action == nil {
+		return nil
+	}
+	ret := &composite.HttpRouteAction{}
+	for _, wb := range action.WeightedBackends {
+		ret.WeightedBackendServices = append(ret.WeightedBackendServices, &composite.WeightedBackendService{
+			BackendService: backendServiceLink(wb.Backend, key),
+			Weight:         wb.Weight,
+		})
+	}
+	if action.UrlRewrite != nil {
+		ret.UrlRewrite = &composite.UrlRewrite{
+			PathPrefixRewrite: action.UrlRewrite.PathPrefixRewrite,
+			HostRewrite:       action.UrlRewrite.HostRewrite,
+		}
+	}
+	if action.MirrorBackend != nil {
+		ret.RequestMirrorPolicy = &composite.RequestMirrorPolicy{
+			BackendService: backendServiceLink(*action.MirrorBackend, key),
+		}
+	}
+	if policy := action.RetryPolicy; policy != nil {
+		numRetries := policy.NumRetries
+		if numRetries == 0 {
+			numRetries = defaultNumRetries
+		}
+		ret.RetryPolicy = &composite.HttpRetryPolicy{
+			RetryConditions: policy.RetryConditions,
+			NumRetries:      numRetries,
+			PerTryTimeout:   toCompositeDuration(policy.PerTryTimeout),
+		}
+	}
+	if policy := action.FaultInjectionPolicy; policy != nil {
+		ret.FaultInjectionPolicy = &composite.HttpFaultInjection{}
+		if delay := policy.Delay; delay != nil {
+			ret.FaultInjectionPolicy.Delay = &composite.HttpFaultDelay{
+				FixedDelay: toCompositeDuration(delay.FixedDelay),
+				Percentage: delay.Percentage,
+			}
+		}
+		if abort := policy.Abort; abort != nil {
+			ret.FaultInjectionPolicy.Abort = &composite.HttpFaultAbort{
+				HttpStatus: abort.HttpStatus,
+				Percentage: abort.Percentage,
+			}
+		}
+	}
+	return ret
+}
+
+// toCompositeDuration converts a duration string, such as "1.5s", into its
+// GCE equivalent. Nil is returned for empty or invalid durations.
+func toCompositeDuration(value string) *composite.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return nil
+	}
+	return &composite.Duration{
+		Seconds: int64(d / time.Second),
+		Nanos:   int64(d % time.Second),
+	}
+}
+
+// toCompositeRouteRuleMatch converts a RouteMatch into its GCE equivalent.
+func toCompositeRouteRuleMatch(match utils.RouteMatch) *composite.HttpRouteRuleMatch {
+	ret := &composite.HttpRouteRuleMatch{
+		PrefixMatch:   match.PrefixMatch,
+		FullPathMatch: match.FullPathMatch,
+		IgnoreCase:    match.IgnoreCase,
+	}
+	if ret.PrefixMatch == "" && ret.FullPathMatch == "" {
+		ret.PrefixMatch = "/"
+	}
+	for _, hm := range match.HeaderMatches {
+		ret.HeaderMatches = append(ret.HeaderMatches, &composite.HttpHeaderMatch{
+			HeaderName:   hm.HeaderName,
+			ExactMatch:   hm.ExactMatch,
+			PrefixMatch:  hm.PrefixMatch,
+			SuffixMatch:  hm.SuffixMatch,
+			RegexMatch:   hm.RegexMatch,
+			PresentMatch: hm.PresentMatch,
+			InvertMatch:  hm.InvertMatch,
+		})
+	}
+	for _, qm := range match.QueryParameterMatches {
+		ret.QueryParameterMatches = append(ret.QueryParameterMatches, &composite.HttpQueryParameterMatch{
+			Name:         qm.Name,
+			ExactMatch:   qm.ExactMatch,
+			RegexMatch:   qm.RegexMatch,
+			PresentMatch: qm.PresentMatch,
+		})
+	}
+	return ret
+}
+
 // ToRedirectUrlMap returns the UrlMap used for HTTPS Redirects on a L7 ELB
 // This function returns nil if no url map needs to be created
 func (t *Translator) ToRedirectUrlMap(env *Env, version meta.Version) *composite.UrlMap {
@@ -248,6 +482,46 @@ func (t *Translator) ToRedirectUrlMap(env *Env, version meta.Version) *composite
 	return expectedMap
 }
 
+// SetCorsPolicy sets the CORS policy of the FrontendConfig on the default
+// route action of the UrlMap, its path matchers and all of their rules, since
+// GCE only evaluates the route action of the rule a request matches.
+// The UrlMap is left untouched if the FrontendConfig has no CORS policy.
+func SetCorsPolicy(m *composite.UrlMap, env *Env) {
+	if env.FrontendConfig == nil || env.FrontendConfig.Spec.CorsPolicy == nil {
+		return
+	}
+	config := env.FrontendConfig.Spec.CorsPolicy
+	policy := func() *composite.CorsPolicy {
+		return &composite.CorsPolicy{
+			AllowOrigins:       config.AllowOrigins,
+			AllowOriginRegexes: config.AllowOriginRegexes,
+			AllowMethods:       config.AllowMethods,
+			AllowHeaders:       config.AllowHeaders,
+			ExposeHeaders:      config.ExposeHeaders,
+			MaxAge:             config.MaxAge,
+			AllowCredentials:   config.AllowCredentials,
+		}
+	}
+	withCorsPolicy := func(action *composite.HttpRouteAction) *composite.HttpRouteAction {
+		if action == nil {
+			action = &composite.HttpRouteAction{}
+		}
+		action.CorsPolicy = policy()
+		return action
+	}
+
+	m.DefaultRouteAction = withCorsPolicy(m.DefaultRouteAction)
+	for _, pm := range m.PathMatchers {
+		pm.DefaultRouteAction = withCorsPolicy(pm.DefaultRouteAction)
+		for _, rule := range pm.PathRules {
+			rule.RouteAction = withCorsPolicy(rule.RouteAction)
+		}
+		for _, rule := range pm.RouteRules {
+			rule.RouteAction = withCorsPolicy(rule.RouteAction)
+		}
+	}
+}
+
 // getNameForPathMatcher returns a name for a pathMatcher based on the given host rule.
 // The host rule can be a regex, the path matcher name used to associate the 2 cannot.
 func getNameForPathMatcher(hostRule string) string {
@@ -341,6 +615,21 @@ func (t *Translator) ToCompositeTargetHttpsProxy(env *Env, description string, v
 			proxy.SslPolicy = *sslPolicy
 			sslPolicySet = true
 		}
+		serverTlsPolicy, err := t.ServerTlsPolicyLink(env)
+		if err != nil {
+			return nil, sslPolicySet, err
+		}
+		if serverTlsPolicy != nil {
+			proxy.ServerTlsPolicy = *serverTlsPolicy
+		}
+		certificateMap, err := t.CertificateMapLink(env)
+		if err != nil {
+			return nil, sslPolicySet, err
+		}
+		if certificateMap != "" {
+			proxy.CertificateMap = certificateMap
+			proxy.SslCertificates = nil
+		}
 	}
 
 	return proxy, sslPolicySet, nil
@@ -415,6 +704,47 @@ func sslPolicyLink(env *Env, isRegional bool) (*string, error) {
 	return &resID, nil
 }
 
+// ServerTlsPolicyLink returns the ref to the server TLS policy that is
+// described by the frontend config. Like sslPolicyLink, nil is returned if the
+// policy is not set and an empty string if the policy should be removed.
+// Names are resolved to global policies of the project, full resource names
+// are returned as is.
+func (t *Translator) ServerTlsPolicyLink(env *Env) (*string, error) {
+	if env.FrontendConfig == nil || env.FrontendConfig.Spec.ServerTlsPolicy == nil {
+		return nil, nil
+	}
+	policy := *env.FrontendConfig.Spec.ServerTlsPolicy
+	if policy != "" && (t.IsL7ILB || t.IsL7XLBRegional) {
+		return nil, fmt.Errorf("ServerTlsPolicy %q is only supported by the %q Ingress class", policy, annotations.GceIngressClass)
+	}
+	if policy == "" || strings.Contains(policy, "/") {
+		return &policy, nil
+	}
+	link := fmt.Sprintf("projects/%s/locations/global/serverTlsPolicies/%s", env.Project, policy)
+	return &link, nil
+}
+
+// CertificateMapLink returns the ref to the Certificate Manager certificate
+// map that is described by the frontend config, or an empty string if the
+// load balancer serves the certificates of the Ingress. Names are resolved to
+// global maps of the project, full resource names are returned as is.
+func (t *Translator) CertificateMapLink(env *Env) (string, error) {
+	if env.FrontendConfig == nil || env.FrontendConfig.Spec.CertificateMap == "" {
+		return "", nil
+	}
+	certMap := env.FrontendConfig.Spec.CertificateMap
+	if t.IsL7ILB || t.IsL7XLBRegional {
+		return "", fmt.Errorf("CertificateMap %q is only supported by the %q Ingress class", certMap, annotations.GceIngressClass)
+	}
+	if strings.HasPrefix(certMap, "//") {
+		return certMap, nil
+	}
+	if strings.Contains(certMap, "/") {
+		return "//certificatemanager.googleapis.com/" + strings.TrimPrefix(certMap, "/"), nil
+	}
+	return fmt.Sprintf("//certificatemanager.googleapis.com/projects/%s/locations/global/certificateMaps/%s", env.Project, certMap), nil
+}
+
 // TODO(shance): find a way to unexport this
 func GetCertHash(contents string) string {
 	return fmt.Sprintf("%x", sha256.Sum256([]byte(contents)))[:16]
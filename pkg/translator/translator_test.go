@@ -25,6 +25,7 @@ import (
 	api_v1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
 	frontendconfigv1beta1 "k8s.io/ingress-gce/pkg/apis/frontendconfig/v1beta1"
 	"k8s.io/ingress-gce/pkg/flags"
@@ -67,6 +68,10 @@ func (n *testNamer) IsLegacySSLCert(string) bool {
 	panic("Unimplemented")
 }
 
+func (n *testNamer) BackendBucket(namespace, name string) string {
+	return fmt.Sprintf("%s-bb-%s-%s", n.prefix, namespace, name)
+}
+
 func (n *testNamer) LoadBalancer() namer_util.LoadBalancerName {
 	panic("Unimplemented")
 }
@@ -120,6 +125,528 @@ func TestToComputeURLMap(t *testing.T) {
 	}
 }
 
+func TestToComputeURLMapWithRouteRules(t *testing.T) {
+	t.Parallel()
+
+	namer := namer_util.NewNamer("uid1", "fw1", klog.TODO())
+	gceURLMap := &utils.GCEURLMap{
+		DefaultBackend: &utils.ServicePort{NodePort: 30000, BackendNamer: namer},
+		HostRules: []utils.HostRule{
+			{
+				Hostname: "abc.com",
+				Paths: []utils.PathRule{
+					{
+						Path:    "/web",
+						Backend: utils.ServicePort{NodePort: 32000, BackendNamer: namer},
+					},
+					{
+						Path:    "/*",
+						Backend: utils.ServicePort{NodePort: 32500, BackendNamer: namer},
+					},
+					{
+						Path:    "/web/*",
+						Backend: utils.ServicePort{NodePort: 32000, BackendNamer: namer},
+					},
+				},
+				RouteRules: []utils.RouteRule{
+					{
+						Priority: 10,
+						Matches: []utils.RouteMatch{
+							{QueryParameterMatches: []utils.QueryParameterMatch{{Name: "tenant", ExactMatch: "a"}}},
+						},
+						Backend: utils.ServicePort{NodePort: 34500, BackendNamer: namer},
+					},
+					{
+						Priority: 5,
+						Matches: []utils.RouteMatch{
+							{PrefixMatch: "/web", HeaderMatches: []utils.HeaderMatch{{HeaderName: "x-canary", ExactMatch: "true"}}},
+						},
+						Backend: utils.ServicePort{NodePort: 34000, BackendNamer: namer},
+					},
+				},
+			},
+			{
+				Hostname: "foo.bar.com",
+				Paths: []utils.PathRule{
+					{
+						Path:    "/",
+						Backend: utils.ServicePort{NodePort: 33000, BackendNamer: namer},
+					},
+				},
+			},
+		},
+	}
+
+	wantComputeMap := &composite.UrlMap{
+		Name:           "k8s-um-lb-name",
+		DefaultService: "global/backendServices/k8s-be-30000--uid1",
+		HostRules: []*composite.HostRule{
+			{
+				Hosts:       []string{"abc.com"},
+				PathMatcher: "host929ba26f492f86d4a9d66a080849865a",
+			},
+			{
+				Hosts:       []string{"foo.bar.com"},
+				PathMatcher: "host2d50cf9711f59181be6a5e5658e42c21",
+			},
+		},
+		PathMatchers: []*composite.PathMatcher{
+			{
+				DefaultService: "global/backendServices/k8s-be-30000--uid1",
+				Name:           "host929ba26f492f86d4a9d66a080849865a",
+				RouteRules: []*composite.HttpRouteRule{
+					{
+						Priority: 5,
+						MatchRules: []*composite.HttpRouteRuleMatch{
+							{
+								PrefixMatch:   "/web",
+								HeaderMatches: []*composite.HttpHeaderMatch{{HeaderName: "x-canary", ExactMatch: "true"}},
+							},
+						},
+						Service: "global/backendServices/k8s-be-34000--uid1",
+					},
+					{
+						Priority: 10,
+						MatchRules: []*composite.HttpRouteRuleMatch{
+							{
+								PrefixMatch:           "/",
+								QueryParameterMatches: []*composite.HttpQueryParameterMatch{{Name: "tenant", ExactMatch: "a"}},
+							},
+						},
+						Service: "global/backendServices/k8s-be-34500--uid1",
+					},
+					{
+						Priority:   11,
+						MatchRules: []*composite.HttpRouteRuleMatch{{PrefixMatch: "/web/"}},
+						Service:    "global/backendServices/k8s-be-32000--uid1",
+					},
+					{
+						Priority:   12,
+						MatchRules: []*composite.HttpRouteRuleMatch{{FullPathMatch: "/web"}},
+						Service:    "global/backendServices/k8s-be-32000--uid1",
+					},
+					{
+						Priority:   13,
+						MatchRules: []*composite.HttpRouteRuleMatch{{PrefixMatch: "/"}},
+						Service:    "global/backendServices/k8s-be-32500--uid1",
+					},
+				},
+			},
+			{
+				DefaultService: "global/backendServices/k8s-be-30000--uid1",
+				Name:           "host2d50cf9711f59181be6a5e5658e42c21",
+				PathRules: []*composite.PathRule{
+					{
+						Paths:   []string{"/"},
+						Service: "global/backendServices/k8s-be-33000--uid1",
+					},
+				},
+			},
+		},
+	}
+
+	namerFactory := namer_util.NewFrontendNamerFactory(namer, "", klog.TODO())
+	feNamer := namerFactory.NamerForLoadBalancer("lb-name")
+	gotComputeURLMap := ToCompositeURLMap(gceURLMap, feNamer, meta.GlobalKey("ns-lb-name"))
+	if diff := cmp.Diff(wantComputeMap, gotComputeURLMap); diff != "" {
+		t.Errorf("Unexpected diff from ToComputeURLMap() (-want +got):\n%s", diff)
+	}
+}
+
+func TestToComputeURLMapWithWeightedBackends(t *testing.T) {
+	t.Parallel()
+
+	namer := namer_util.NewNamer("uid1", "fw1", klog.TODO())
+	weighted := &utils.RouteAction{
+		WeightedBackends: []utils.WeightedBackend{
+			{Backend: utils.ServicePort{NodePort: 32000, BackendNamer: namer}, Weight: 90},
+			{Backend: utils.ServicePort{NodePort: 32500, BackendNamer: namer}, Weight: 10},
+		},
+	}
+	gceURLMap := &utils.GCEURLMap{
+		DefaultBackend: &utils.ServicePort{NodePort: 30000, BackendNamer: namer},
+		HostRules: []utils.HostRule{
+			{
+				Hostname: "abc.com",
+				Paths: []utils.PathRule{
+					{
+						Path:    "/web",
+						Backend: utils.ServicePort{NodePort: 32000, BackendNamer: namer},
+						Action:  weighted,
+					},
+				},
+			},
+			{
+				Hostname: "foo.bar.com",
+				RouteRules: []utils.RouteRule{
+					{
+						Priority: 1,
+						Matches:  []utils.RouteMatch{{PrefixMatch: "/"}},
+						Action:   weighted,
+					},
+				},
+			},
+		},
+	}
+
+	wantWeightedAction := &composite.HttpRouteAction{
+		WeightedBackendServices: []*composite.WeightedBackendService{
+			{BackendService: "global/backendServices/k8s-be-32000--uid1", Weight: 90},
+			{BackendService: "global/backendServices/k8s-be-32500--uid1", Weight: 10},
+		},
+	}
+	wantComputeMap := &composite.UrlMap{
+		Name:           "k8s-um-lb-name",
+		DefaultService: "global/backendServices/k8s-be-30000--uid1",
+		HostRules: []*composite.HostRule{
+			{
+				Hosts:       []string{"abc.com"},
+				PathMatcher: "host929ba26f492f86d4a9d66a080849865a",
+			},
+			{
+				Hosts:       []string{"foo.bar.com"},
+				PathMatcher: "host2d50cf9711f59181be6a5e5658e42c21",
+			},
+		},
+		PathMatchers: []*composite.PathMatcher{
+			{
+				DefaultService: "global/backendServices/k8s-be-30000--uid1",
+				Name:           "host929ba26f492f86d4a9d66a080849865a",
+				PathRules: []*composite.PathRule{
+					{
+						Paths:       []string{"/web"},
+						RouteAction: wantWeightedAction,
+					},
+				},
+			},
+			{
+				DefaultService: "global/backendServices/k8s-be-30000--uid1",
+				Name:           "host2d50cf9711f59181be6a5e5658e42c21",
+				RouteRules: []*composite.HttpRouteRule{
+					{
+						Priority:    1,
+						MatchRules:  []*composite.HttpRouteRuleMatch{{PrefixMatch: "/"}},
+						RouteAction: wantWeightedAction,
+					},
+				},
+			},
+		},
+	}
+
+	namerFactory := namer_util.NewFrontendNamerFactory(namer, "", klog.TODO())
+	feNamer := namerFactory.NamerForLoadBalancer("lb-name")
+	gotComputeURLMap := ToCompositeURLMap(gceURLMap, feNamer, meta.GlobalKey("ns-lb-name"))
+	if diff := cmp.Diff(wantComputeMap, gotComputeURLMap); diff != "" {
+		t.Errorf("Unexpected diff from ToComputeURLMap() (-want +got):\n%s", diff)
+	}
+}
+
+func TestToCompositeRouteAction(t *testing.T) {
+	t.Parallel()
+
+	namer := namer_util.NewNamer("uid1", "fw1", klog.TODO())
+	for _, tc := range []struct {
+		desc   string
+		action *utils.RouteAction
+		want   *composite.HttpRouteAction
+	}{
+		{
+			desc: "nil action",
+		},
+		{
+			desc: "url rewrite",
+			action: &utils.RouteAction{
+				UrlRewrite: &utils.UrlRewrite{PathPrefixRewrite: "/", HostRewrite: "legacy.internal"},
+			},
+			want: &composite.HttpRouteAction{
+				UrlRewrite: &composite.UrlRewrite{PathPrefixRewrite: "/", HostRewrite: "legacy.internal"},
+			},
+		},
+		{
+			desc: "weighted backends with url rewrite",
+			action: &utils.RouteAction{
+				WeightedBackends: []utils.WeightedBackend{
+					{Backend: utils.ServicePort{NodePort: 32000, BackendNamer: namer}, Weight: 1},
+				},
+				UrlRewrite: &utils.UrlRewrite{PathPrefixRewrite: "/v2/"},
+			},
+			want: &composite.HttpRouteAction{
+				WeightedBackendServices: []*composite.WeightedBackendService{
+					{BackendService: "global/backendServices/k8s-be-32000--uid1", Weight: 1},
+				},
+				UrlRewrite: &composite.UrlRewrite{PathPrefixRewrite: "/v2/"},
+			},
+		},
+		{
+			desc: "retry and fault injection",
+			action: &utils.RouteAction{
+				RetryPolicy: &utils.RetryPolicy{RetryConditions: []string{"5xx"}, NumRetries: 3, PerTryTimeout: "1.5s"},
+				FaultInjectionPolicy: &utils.FaultInjectionPolicy{
+					Delay: &utils.FaultDelay{FixedDelay: "2s", Percentage: 50},
+					Abort: &utils.FaultAbort{HttpStatus: 503, Percentage: 0.5},
+				},
+			},
+			want: &composite.HttpRouteAction{
+				RetryPolicy: &composite.HttpRetryPolicy{
+					RetryConditions: []string{"5xx"},
+					NumRetries:      3,
+					PerTryTimeout:   &composite.Duration{Seconds: 1, Nanos: 500000000},
+				},
+				FaultInjectionPolicy: &composite.HttpFaultInjection{
+					Delay: &composite.HttpFaultDelay{FixedDelay: &composite.Duration{Seconds: 2}, Percentage: 50},
+					Abort: &composite.HttpFaultAbort{HttpStatus: 503, Percentage: 0.5},
+				},
+			},
+		},
+		{
+			desc: "retry with the default number of retries",
+			action: &utils.RouteAction{
+				RetryPolicy: &utils.RetryPolicy{RetryConditions: []string{"5xx"}},
+			},
+			want: &composite.HttpRouteAction{
+				RetryPolicy: &composite.HttpRetryPolicy{RetryConditions: []string{"5xx"}, NumRetries: 1},
+			},
+		},
+		{
+			desc: "request mirror",
+			action: &utils.RouteAction{
+				MirrorBackend: &utils.ServicePort{NodePort: 32001, BackendNamer: namer},
+			},
+			want: &composite.HttpRouteAction{
+				RequestMirrorPolicy: &composite.RequestMirrorPolicy{BackendService: "global/backendServices/k8s-be-32001--uid1"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			got := toCompositeRouteAction(tc.action, meta.GlobalKey("ns-lb-name"))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("toCompositeRouteAction() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestToComputeURLMapWithHostMirror(t *testing.T) {
+	t.Parallel()
+
+	namer := namer_util.NewNamer("uid1", "fw1", klog.TODO())
+	backend := utils.ServicePort{NodePort: 32000, BackendNamer: namer}
+	mirror := utils.ServicePort{NodePort: 32001, BackendNamer: namer}
+	other := utils.ServicePort{NodePort: 32002, BackendNamer: namer}
+	gceURLMap := utils.NewGCEURLMap(klog.TODO())
+	gceURLMap.DefaultBackend = &backend
+	gceURLMap.PutPathRulesForHost("foo.com", []utils.PathRule{
+		{Path: "/*", Backend: backend},
+		{Path: "/own/*", Backend: backend, Action: &utils.RouteAction{MirrorBackend: &other}},
+	})
+	gceURLMap.PutActionForHost("foo.com", &utils.RouteAction{MirrorBackend: &mirror})
+
+	feNamer := namer_util.NewFrontendNamerFactory(namer, "", klog.TODO()).NamerForLoadBalancer("ns/lb-name")
+	got := ToCompositeURLMap(gceURLMap, feNamer, meta.GlobalKey("ns-lb-name"))
+
+	mirrorPolicy := &composite.HttpRouteAction{
+		RequestMirrorPolicy: &composite.RequestMirrorPolicy{BackendService: "global/backendServices/k8s-be-32001--uid1"},
+	}
+	pathMatcher := got.PathMatchers[0]
+	if diff := cmp.Diff(mirrorPolicy, pathMatcher.DefaultRouteAction); diff != "" {
+		t.Errorf("DefaultRouteAction mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(mirrorPolicy, pathMatcher.PathRules[0].RouteAction); diff != "" {
+		t.Errorf("PathRules[0].RouteAction mismatch (-want +got):\n%s", diff)
+	}
+	wantOwn := &composite.HttpRouteAction{
+		RequestMirrorPolicy: &composite.RequestMirrorPolicy{BackendService: "global/backendServices/k8s-be-32002--uid1"},
+	}
+	if diff := cmp.Diff(wantOwn, pathMatcher.PathRules[1].RouteAction); diff != "" {
+		t.Errorf("PathRules[1].RouteAction mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestToComputeURLMapWithErrorResponsePolicy(t *testing.T) {
+	t.Parallel()
+
+	namer := namer_util.NewNamer("uid1", "fw1", klog.TODO())
+	backend := utils.ServicePort{NodePort: 32000, BackendNamer: namer}
+	errorsBackend := utils.ServicePort{NodePort: 32001, BackendNamer: namer}
+	rules := []utils.ErrorResponseRule{
+		{MatchResponseCodes: []string{"4xx"}, Path: "/404.html", OverrideResponseCode: 404},
+		{MatchResponseCodes: []string{"500", "503"}, Path: "/500.html"},
+	}
+	wantRules := []*composite.CustomErrorResponsePolicyCustomErrorResponseRule{
+		{MatchResponseCodes: []string{"4xx"}, Path: "/404.html", OverrideResponseCode: 404},
+		{MatchResponseCodes: []string{"500", "503"}, Path: "/500.html"},
+	}
+	feNamer := namer_util.NewFrontendNamerFactory(namer, "", klog.TODO()).NamerForLoadBalancer("ns/lb-name")
+
+	for _, tc := range []struct {
+		desc   string
+		policy *utils.ErrorResponsePolicy
+		key    *meta.Key
+		want   *composite.CustomErrorResponsePolicy
+	}{
+		{
+			desc: "No policy",
+			key:  meta.GlobalKey("ns-lb-name"),
+		},
+		{
+			desc:   "Service",
+			policy: &utils.ErrorResponsePolicy{Backend: &errorsBackend, Rules: rules},
+			key:    meta.GlobalKey("ns-lb-name"),
+			want:   &composite.CustomErrorResponsePolicy{ErrorService: "global/backendServices/k8s-be-32001--uid1", ErrorResponseRules: wantRules},
+		},
+		{
+			desc:   "Service of a regional load balancer",
+			policy: &utils.ErrorResponsePolicy{Backend: &errorsBackend, Rules: rules},
+			key:    meta.RegionalKey("ns-lb-name", "us-central1"),
+			want:   &composite.CustomErrorResponsePolicy{ErrorService: "regions/us-central1/backendServices/k8s-be-32001--uid1", ErrorResponseRules: wantRules},
+		},
+		{
+			desc:   "Backend bucket",
+			policy: &utils.ErrorResponsePolicy{BackendBucket: "error-pages", Rules: rules},
+			key:    meta.GlobalKey("ns-lb-name"),
+			want:   &composite.CustomErrorResponsePolicy{ErrorService: "global/backendBuckets/error-pages", ErrorResponseRules: wantRules},
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			gceURLMap := utils.NewGCEURLMap(klog.TODO())
+			gceURLMap.DefaultBackend = &backend
+			gceURLMap.ErrorResponsePolicy = tc.policy
+
+			got := ToCompositeURLMap(gceURLMap, feNamer, tc.key)
+			if diff := cmp.Diff(tc.want, got.DefaultCustomErrorResponsePolicy); diff != "" {
+				t.Errorf("DefaultCustomErrorResponsePolicy mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestToComputeURLMapWithBackendBuckets(t *testing.T) {
+	t.Parallel()
+
+	namer := namer_util.NewNamer("uid1", "fw1", klog.TODO())
+	backend := utils.ServicePort{NodePort: 32000, BackendNamer: namer}
+	assets := &utils.BackendBucket{ID: types.NamespacedName{Namespace: "ns", Name: "assets"}, BucketName: "assets-bucket"}
+	gceURLMap := utils.NewGCEURLMap(klog.TODO())
+	gceURLMap.DefaultBackend = &backend
+	gceURLMap.PutPathRulesForHost("foo.com", []utils.PathRule{
+		{Path: "/*", Backend: backend},
+		{Path: "/assets/*", BackendBucket: assets},
+	})
+	gceURLMap.PutPathRulesForHost("bar.com", []utils.PathRule{
+		{Path: "/assets/*", BackendBucket: assets},
+	})
+	gceURLMap.PutRouteRulesForHost("bar.com", []utils.RouteRule{
+		{Priority: 1, Backend: backend, Matches: []utils.RouteMatch{{PrefixMatch: "/api/"}}},
+	})
+
+	feNamer := namer_util.NewFrontendNamerFactory(namer, "", klog.TODO()).NamerForLoadBalancer("ns/lb-name")
+	got := ToCompositeURLMap(gceURLMap, feNamer, meta.GlobalKey("ns-lb-name"))
+
+	bucketLink := "global/backendBuckets/" + feNamer.BackendBucket("ns", "assets")
+	if len(got.PathMatchers) != 2 {
+		t.Fatalf("len(PathMatchers) = %d, want 2", len(got.PathMatchers))
+	}
+	for _, pm := range got.PathMatchers {
+		switch pm.Name {
+		case getNameForPathMatcher("foo.com"):
+			wantServices := []string{"global/backendServices/k8s-be-32000--uid1", bucketLink}
+			var gotServices []string
+			for _, rule := range pm.PathRules {
+				gotServices = append(gotServices, rule.Service)
+			}
+			if diff := cmp.Diff(wantServices, gotServices); diff != "" {
+				t.Errorf("services of the path rules of foo.com mismatch (-want +got):\n%s", diff)
+			}
+		case getNameForPathMatcher("bar.com"):
+			if len(pm.RouteRules) != 2 || pm.RouteRules[1].Service != bucketLink {
+				t.Errorf("RouteRules of bar.com = %+v, want the second rule to use %s", pm.RouteRules, bucketLink)
+			}
+		}
+	}
+}
+
+func TestSetCorsPolicy(t *testing.T) {
+	t.Parallel()
+
+	namer := namer_util.NewNamer("uid1", "fw1", klog.TODO())
+	backend := utils.ServicePort{NodePort: 32000, BackendNamer: namer}
+	gceURLMap := utils.NewGCEURLMap(klog.TODO())
+	gceURLMap.DefaultBackend = &backend
+	gceURLMap.PutPathRulesForHost("foo.com", []utils.PathRule{
+		{Path: "/*", Backend: backend},
+		{Path: "/mirror/*", Backend: backend, Action: &utils.RouteAction{MirrorBackend: &backend}},
+	})
+	feNamer := namer_util.NewFrontendNamerFactory(namer, "", klog.TODO()).NamerForLoadBalancer("ns/lb-name")
+
+	corsPolicy := &frontendconfigv1beta1.CorsPolicy{
+		AllowOrigins:       []string{"https://example.com"},
+		AllowOriginRegexes: []string{`https://.*\.example\.com`},
+		AllowMethods:       []string{"GET", "POST"},
+		AllowHeaders:       []string{"Authorization"},
+		MaxAge:             3600,
+		AllowCredentials:   true,
+	}
+	wantPolicy := &composite.CorsPolicy{
+		AllowOrigins:       []string{"https://example.com"},
+		AllowOriginRegexes: []string{`https://.*\.example\.com`},
+		AllowMethods:       []string{"GET", "POST"},
+		AllowHeaders:       []string{"Authorization"},
+		MaxAge:             3600,
+		AllowCredentials:   true,
+	}
+
+	for _, tc := range []struct {
+		desc string
+		fc   *frontendconfigv1beta1.FrontendConfig
+		want *composite.CorsPolicy
+	}{
+		{
+			desc: "No FrontendConfig",
+		},
+		{
+			desc: "No CORS policy",
+			fc:   &frontendconfigv1beta1.FrontendConfig{},
+		},
+		{
+			desc: "CORS policy",
+			fc:   &frontendconfigv1beta1.FrontendConfig{Spec: frontendconfigv1beta1.FrontendConfigSpec{CorsPolicy: corsPolicy}},
+			want: wantPolicy,
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			m := ToCompositeURLMap(gceURLMap, feNamer, meta.GlobalKey("ns-lb-name"))
+			SetCorsPolicy(m, &Env{FrontendConfig: tc.fc})
+
+			if tc.want == nil {
+				if m.DefaultRouteAction != nil || m.PathMatchers[0].DefaultRouteAction != nil || m.PathMatchers[0].PathRules[0].RouteAction != nil {
+					t.Errorf("SetCorsPolicy() added route actions to %+v, want none", m)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.want, m.DefaultRouteAction.CorsPolicy); diff != "" {
+				t.Errorf("DefaultRouteAction.CorsPolicy mismatch (-want +got):\n%s", diff)
+			}
+			pathMatcher := m.PathMatchers[0]
+			if diff := cmp.Diff(tc.want, pathMatcher.DefaultRouteAction.CorsPolicy); diff != "" {
+				t.Errorf("PathMatchers[0].DefaultRouteAction.CorsPolicy mismatch (-want +got):\n%s", diff)
+			}
+			for i, rule := range pathMatcher.PathRules {
+				if diff := cmp.Diff(tc.want, rule.RouteAction.CorsPolicy); diff != "" {
+					t.Errorf("PathRules[%d].RouteAction.CorsPolicy mismatch (-want +got):\n%s", i, diff)
+				}
+			}
+			if pathMatcher.PathRules[1].RouteAction.RequestMirrorPolicy == nil {
+				t.Errorf("PathRules[1].RouteAction.RequestMirrorPolicy = nil, want the mirror policy to be kept")
+			}
+		})
+	}
+}
+
 func TestToRedirectUrlMap(t *testing.T) {
 	t.Parallel()
 
@@ -789,3 +1316,119 @@ func TestSslPolicyLink(t *testing.T) {
 		})
 	}
 }
+
+func TestServerTlsPolicyLink(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc    string
+		fc      *frontendconfigv1beta1.FrontendConfig
+		isL7ILB bool
+		want    *string
+		wantErr bool
+	}{
+		{
+			desc: "Empty frontendconfig",
+			fc:   nil,
+			want: nil,
+		},
+		{
+			desc: "frontendconfig with no server tls policy",
+			fc:   &frontendconfigv1beta1.FrontendConfig{Spec: frontendconfigv1beta1.FrontendConfigSpec{}},
+			want: nil,
+		},
+		{
+			desc: "frontendconfig with server tls policy",
+			fc:   &frontendconfigv1beta1.FrontendConfig{Spec: frontendconfigv1beta1.FrontendConfigSpec{ServerTlsPolicy: utils.NewStringPointer("test-policy")}},
+			want: utils.NewStringPointer("projects/test-project/locations/global/serverTlsPolicies/test-policy"),
+		},
+		{
+			desc: "frontendconfig with server tls policy resource name",
+			fc:   &frontendconfigv1beta1.FrontendConfig{Spec: frontendconfigv1beta1.FrontendConfigSpec{ServerTlsPolicy: utils.NewStringPointer("projects/other/locations/global/serverTlsPolicies/test-policy")}},
+			want: utils.NewStringPointer("projects/other/locations/global/serverTlsPolicies/test-policy"),
+		},
+		{
+			desc: "frontendconfig with empty string server tls policy",
+			fc:   &frontendconfigv1beta1.FrontendConfig{Spec: frontendconfigv1beta1.FrontendConfigSpec{ServerTlsPolicy: utils.NewStringPointer("")}},
+			want: utils.NewStringPointer(""),
+		},
+		{
+			desc:    "frontendconfig with server tls policy for L7 ILB",
+			fc:      &frontendconfigv1beta1.FrontendConfig{Spec: frontendconfigv1beta1.FrontendConfigSpec{ServerTlsPolicy: utils.NewStringPointer("test-policy")}},
+			isL7ILB: true,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			tr := NewTranslator(tc.isL7ILB, false, &testNamer{"test"})
+			env := &Env{FrontendConfig: tc.fc, Project: "test-project"}
+			result, err := tr.ServerTlsPolicyLink(env)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("ServerTlsPolicyLink() = %v, wantErr = %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(result, tc.want) {
+				t.Errorf("ServerTlsPolicyLink() = %v, want %v", result, tc.want)
+			}
+		})
+	}
+}
+
+func TestCertificateMapLink(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc    string
+		fc      *frontendconfigv1beta1.FrontendConfig
+		isL7ILB bool
+		want    string
+		wantErr bool
+	}{
+		{
+			desc: "Empty frontendconfig",
+			fc:   nil,
+		},
+		{
+			desc: "frontendconfig with no certificate map",
+			fc:   &frontendconfigv1beta1.FrontendConfig{Spec: frontendconfigv1beta1.FrontendConfigSpec{}},
+		},
+		{
+			desc: "frontendconfig with certificate map",
+			fc:   &frontendconfigv1beta1.FrontendConfig{Spec: frontendconfigv1beta1.FrontendConfigSpec{CertificateMap: "test-map"}},
+			want: "//certificatemanager.googleapis.com/projects/test-project/locations/global/certificateMaps/test-map",
+		},
+		{
+			desc: "frontendconfig with certificate map resource name",
+			fc:   &frontendconfigv1beta1.FrontendConfig{Spec: frontendconfigv1beta1.FrontendConfigSpec{CertificateMap: "projects/other/locations/global/certificateMaps/test-map"}},
+			want: "//certificatemanager.googleapis.com/projects/other/locations/global/certificateMaps/test-map",
+		},
+		{
+			desc: "frontendconfig with certificate map full resource name",
+			fc:   &frontendconfigv1beta1.FrontendConfig{Spec: frontendconfigv1beta1.FrontendConfigSpec{CertificateMap: "//certificatemanager.googleapis.com/projects/other/locations/global/certificateMaps/test-map"}},
+			want: "//certificatemanager.googleapis.com/projects/other/locations/global/certificateMaps/test-map",
+		},
+		{
+			desc:    "frontendconfig with certificate map for L7 ILB",
+			fc:      &frontendconfigv1beta1.FrontendConfig{Spec: frontendconfigv1beta1.FrontendConfigSpec{CertificateMap: "test-map"}},
+			isL7ILB: true,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			tr := NewTranslator(tc.isL7ILB, false, &testNamer{"test"})
+			env := &Env{FrontendConfig: tc.fc, Project: "test-project"}
+			result, err := tr.CertificateMapLink(env)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("CertificateMapLink() = %v, wantErr = %v", err, tc.wantErr)
+			}
+			if result != tc.want {
+				t.Errorf("CertificateMapLink() = %q, want %q", result, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/types"
+	gcsbackendv1beta1 "k8s.io/ingress-gce/pkg/apis/gcsbackend/v1beta1"
+)
+
+// BackendBucket is a Cloud Storage bucket served by a load balancer, as
+// configured by a GCSBackend.
+type BackendBucket struct {
+	// ID is the namespace and name of the GCSBackend.
+	ID types.NamespacedName
+	// BucketName is the name of the Cloud Storage bucket.
+	BucketName string
+	// Cdn is the Cloud CDN configuration of the backend bucket.
+	Cdn *gcsbackendv1beta1.CDNConfig
+}
+
+// backendBucketDescription is the description of the backend bucket of a
+// GCSBackend.
+type backendBucketDescription struct {
+	GCSBackendName string `json:"networking.gke.io/gcs-backend-name"`
+}
+
+// Description returns the description of the backend bucket, which names its
+// GCSBackend.
+func (bb *BackendBucket) Description() string {
+	desc, err := json.Marshal(backendBucketDescription{GCSBackendName: bb.ID.String()})
+	if err != nil {
+		return ""
+	}
+	return string(desc)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/ingress-gce/pkg/utils/namer"
+)
+
+// ingressDescription is the description of the frontend resources of an
+// Ingress.
+type ingressDescription struct {
+	IngressName string `json:"kubernetes.io/ingress-name"`
+}
+
+// DecodeResourceName decodes the name of a GCE resource created by the
+// controllers using the given prefix, see namer.DecodeName. If the description of the resource is
+// given, the owning object is resolved with it: the description of the
+// Ingress frontends, Description, NegDescription, L4LBResourceDescription or
+// the description of a backend bucket.
+func DecodeResourceName(name, prefix, description string) (*namer.DecodedName, error) {
+	decoded, err := namer.DecodeName(name, prefix)
+	if err != nil || description == "" {
+		return decoded, err
+	}
+
+	var ing ingressDescription
+	var svc Description
+	var neg NegDescription
+	var l4 L4LBResourceDescription
+	var bucket backendBucketDescription
+	var owner, kind string
+	switch {
+	case json.Unmarshal([]byte(description), &ing) == nil && ing.IngressName != "":
+		owner, kind = ing.IngressName, namer.KindIngress
+	case json.Unmarshal([]byte(description), &neg) == nil && neg.Namespace != "" && neg.ServiceName != "":
+		owner, kind = neg.Namespace+"/"+neg.ServiceName, namer.KindService
+		decoded.ClusterUID = neg.ClusterUID
+		decoded.Port = neg.Port
+	case json.Unmarshal([]byte(description), &svc) == nil && svc.ServiceName != "":
+		owner, kind = svc.ServiceName, namer.KindService
+	case l4.Unmarshal(description) == nil && l4.ServiceName != "":
+		owner, kind = l4.ServiceName, namer.KindService
+	case json.Unmarshal([]byte(description), &bucket) == nil && bucket.GCSBackendName != "":
+		owner, kind = bucket.GCSBackendName, namer.KindGCSBackend
+	default:
+		return decoded, nil
+	}
+
+	namespace, objName, ok := strings.Cut(owner, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid owner %q in the description of %q", owner, name)
+	}
+	if decoded.Kind != "" && decoded.Kind != kind && decoded.Kind != namer.KindServiceAttachment {
+		return nil, fmt.Errorf("description of %q names %s %s, but the name is owned by a %s", name, kind, owner, decoded.Kind)
+	}
+	decoded.Kind, decoded.Namespace, decoded.Name = kind, namespace, objName
+	return decoded, nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/ingress-gce/pkg/utils/namer"
+)
+
+func TestDecodeResourceName(t *testing.T) {
+	l4Desc, _ := MakeL4LBServiceDescription("my-ns/my-svc", "10.0.0.1", "ga", false, ILB)
+	for _, tc := range []struct {
+		desc        string
+		name        string
+		description string
+		wantKind    string
+		wantOwner   string
+		wantUID     string
+		wantErr     bool
+	}{
+		{
+			desc:      "name only",
+			name:      "k8s2-um-7kpbhpki-shop-web-uhmwf5xi",
+			wantKind:  namer.KindIngress,
+			wantOwner: "shop/web",
+			wantUID:   "7kpbhpki",
+		},
+		{
+			desc:        "Ingress description",
+			name:        "k8s2-um-7kpbhpki-my-shop-web-uhmwf5xi",
+			description: `{"kubernetes.io/ingress-name": "my-shop/web"}`,
+			wantKind:    namer.KindIngress,
+			wantOwner:   "my-shop/web",
+			wantUID:     "7kpbhpki",
+		},
+		{
+			desc:        "NEG description",
+			name:        "k8s1-2e5f4b4c-my-ns-my-svc-80-1c3a2b4d",
+			description: NegDescription{ClusterUID: "2e5f4b4c0123", Namespace: "my-ns", ServiceName: "my-svc", Port: "80"}.String(),
+			wantKind:    namer.KindService,
+			wantOwner:   "my-ns/my-svc",
+			wantUID:     "2e5f4b4c0123",
+		},
+		{
+			desc:        "backend service description",
+			name:        "k8s-be-30001--uid1",
+			description: Description{ServiceName: "my-ns/my-svc", ServicePort: "80"}.String(),
+			wantKind:    namer.KindService,
+			wantOwner:   "my-ns/my-svc",
+			wantUID:     "uid1",
+		},
+		{
+			desc:        "L4 description",
+			name:        "k8s2-7kpbhpki-my-ns-my-svc-cysix1wq",
+			description: l4Desc,
+			wantKind:    namer.KindService,
+			wantOwner:   "my-ns/my-svc",
+			wantUID:     "7kpbhpki",
+		},
+		{
+			desc:        "backend bucket description",
+			name:        "k8s2-bb-7kpbhpki-my-shop-assets-uhmwf5xi",
+			description: (&BackendBucket{ID: types.NamespacedName{Namespace: "my-shop", Name: "assets"}}).Description(),
+			wantKind:    namer.KindGCSBackend,
+			wantOwner:   "my-shop/assets",
+			wantUID:     "7kpbhpki",
+		},
+		{
+			desc:        "description of another kind",
+			name:        "k8s2-um-7kpbhpki-shop-web-uhmwf5xi",
+			description: l4Desc,
+			wantErr:     true,
+		},
+		{
+			desc:    "unknown name",
+			name:    "web",
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := DecodeResourceName(tc.name, "k8s", tc.description)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("DecodeResourceName(%q, _, %q) = %v, want error: %v", tc.name, tc.description, err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if owner := got.Namespace + "/" + got.Name; got.Kind != tc.wantKind || owner != tc.wantOwner || got.ClusterUID != tc.wantUID {
+				t.Errorf("DecodeResourceName(%q, _, %q) = %s %s of cluster %q, want %s %s of cluster %q", tc.name, tc.description, got.Kind, owner, got.ClusterUID, tc.wantKind, tc.wantOwner, tc.wantUID)
+			}
+		})
+	}
+}
@@ -15,8 +15,10 @@ package utils
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 )
 
@@ -30,6 +32,8 @@ type GCEURLMap struct {
 	DefaultBackend *ServicePort
 	// HostRules is an ordered list of hostnames, path rule tuples.
 	HostRules []HostRule
+	// ErrorResponsePolicy, if set, replaces the error responses of all hosts.
+	ErrorResponsePolicy *ErrorResponsePolicy
 	// hosts is a map of existing hosts.
 	hosts map[string]bool
 
@@ -40,12 +44,181 @@ type GCEURLMap struct {
 type HostRule struct {
 	Hostname string
 	Paths    []PathRule
+	// RouteRules are the advanced match rules for the host. When non-empty,
+	// the host is rendered with route rules instead of path rules.
+	RouteRules []RouteRule
+	// Action, if set, is applied to all requests of the host.
+	Action *RouteAction
 }
 
 // PathRule encapsulates the information for a single path -> backend mapping.
 type PathRule struct {
 	Path    string
 	Backend ServicePort
+	// BackendBucket, if set, serves the path instead of Backend.
+	BackendBucket *BackendBucket
+	// Action, if set, is applied to requests matching the path.
+	Action *RouteAction
+}
+
+// RouteRule encapsulates the information for a single advanced match -> backend mapping.
+// Backend is unset if the Action specifies WeightedBackends.
+type RouteRule struct {
+	Priority int64
+	Matches  []RouteMatch
+	Backend  ServicePort
+	Action   *RouteAction
+}
+
+// RouteAction encapsulates the route actions of a path or route rule.
+type RouteAction struct {
+	WeightedBackends []WeightedBackend
+	UrlRewrite       *UrlRewrite
+	// MirrorBackend, if set, receives a copy of the requests.
+	MirrorBackend        *ServicePort
+	RetryPolicy          *RetryPolicy
+	FaultInjectionPolicy *FaultInjectionPolicy
+}
+
+// RouteMatch is a match condition of a RouteRule. At most one of PrefixMatch
+// and FullPathMatch is set.
+type RouteMatch struct {
+	PrefixMatch           string
+	FullPathMatch         string
+	IgnoreCase            bool
+	HeaderMatches         []HeaderMatch
+	QueryParameterMatches []QueryParameterMatch
+}
+
+// HeaderMatch matches a request header.
+type HeaderMatch struct {
+	HeaderName   string
+	ExactMatch   string
+	PrefixMatch  string
+	SuffixMatch  string
+	RegexMatch   string
+	PresentMatch bool
+	InvertMatch  bool
+}
+
+// QueryParameterMatch matches a query parameter.
+type QueryParameterMatch struct {
+	Name         string
+	ExactMatch   string
+	RegexMatch   string
+	PresentMatch bool
+}
+
+// UrlRewrite modifies the request before it is forwarded to the backend.
+type UrlRewrite struct {
+	PathPrefixRewrite string
+	HostRewrite       string
+}
+
+// RetryPolicy describes when and how often requests are retried.
+type RetryPolicy struct {
+	RetryConditions []string
+	NumRetries      int64
+	PerTryTimeout   string
+}
+
+// FaultInjectionPolicy describes the faults injected in the requests.
+type FaultInjectionPolicy struct {
+	Delay *FaultDelay
+	Abort *FaultAbort
+}
+
+// FaultDelay delays a percentage of the requests.
+type FaultDelay struct {
+	FixedDelay string
+	Percentage float64
+}
+
+// FaultAbort aborts a percentage of the requests with the given status.
+type FaultAbort struct {
+	HttpStatus int64
+	Percentage float64
+}
+
+// ErrorResponsePolicy encapsulates the custom error responses of the UrlMap.
+// Exactly one of Backend and BackendBucket is set.
+type ErrorResponsePolicy struct {
+	// Backend serves the error content.
+	Backend *ServicePort
+	// BackendBucket is the name of the backend bucket serving the error content.
+	BackendBucket string
+	Rules         []ErrorResponseRule
+}
+
+// ErrorResponseRule maps response codes to a path on the error backend.
+type ErrorResponseRule struct {
+	MatchResponseCodes   []string
+	Path                 string
+	OverrideResponseCode int64
+}
+
+// equalErrorResponsePolicyMapping returns true if both policies point to the
+// same ServicePortID or backend bucket and have the same rules.
+func equalErrorResponsePolicyMapping(a, b *ErrorResponsePolicy) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if (a.Backend != nil) != (b.Backend != nil) {
+		return false
+	}
+	if a.Backend != nil && a.Backend.ID != b.Backend.ID {
+		return false
+	}
+	return a.BackendBucket == b.BackendBucket && reflect.DeepEqual(a.Rules, b.Rules)
+}
+
+// WeightedBackend is a backend receiving a weighted share of the traffic.
+type WeightedBackend struct {
+	Backend ServicePort
+	Weight  int64
+}
+
+// ServicePorts returns the ServicePorts referenced by the RouteAction.
+func (a *RouteAction) ServicePorts() []ServicePort {
+	if a == nil {
+		return nil
+	}
+	var svcPorts []ServicePort
+	for _, wb := range a.WeightedBackends {
+		svcPorts = append(svcPorts, wb.Backend)
+	}
+	if a.MirrorBackend != nil {
+		svcPorts = append(svcPorts, *a.MirrorBackend)
+	}
+	return svcPorts
+}
+
+// policies returns the fields of the RouteAction which do not reference
+// backends.
+func (a *RouteAction) policies() RouteAction {
+	if a == nil {
+		return RouteAction{}
+	}
+	return RouteAction{
+		UrlRewrite:           a.UrlRewrite,
+		RetryPolicy:          a.RetryPolicy,
+		FaultInjectionPolicy: a.FaultInjectionPolicy,
+	}
+}
+
+// equalActionMapping returns true if both actions point to the same ServicePortIDs
+// and handle requests the same way.
+func equalActionMapping(a, b *RouteAction) bool {
+	aPorts, bPorts := a.ServicePorts(), b.ServicePorts()
+	if len(aPorts) != len(bPorts) {
+		return false
+	}
+	for i := range aPorts {
+		if aPorts[i].ID != bPorts[i].ID {
+			return false
+		}
+	}
+	return reflect.DeepEqual(a.policies(), b.policies())
 }
 
 // NewGCEURLMap returns an empty GCEURLMap
@@ -62,6 +235,9 @@ func EqualMapping(a, b *GCEURLMap) bool {
 	if a.DefaultBackend != nil && a.DefaultBackend.ID != b.DefaultBackend.ID {
 		return false
 	}
+	if !equalErrorResponsePolicyMapping(a.ErrorResponsePolicy, b.ErrorResponsePolicy) {
+		return false
+	}
 
 	if len(a.HostRules) != len(b.HostRules) {
 		return false
@@ -72,6 +248,9 @@ func EqualMapping(a, b *GCEURLMap) bool {
 		if aRules.Hostname != bRules.Hostname {
 			return false
 		}
+		if !equalActionMapping(aRules.Action, bRules.Action) {
+			return false
+		}
 
 		if len(aRules.Paths) != len(bRules.Paths) {
 			return false
@@ -85,6 +264,35 @@ func EqualMapping(a, b *GCEURLMap) bool {
 			if aPath.Backend.ID != bPath.Backend.ID {
 				return false
 			}
+			if (aPath.BackendBucket != nil) != (bPath.BackendBucket != nil) {
+				return false
+			}
+			if aPath.BackendBucket != nil && aPath.BackendBucket.ID != bPath.BackendBucket.ID {
+				return false
+			}
+			if !equalActionMapping(aPath.Action, bPath.Action) {
+				return false
+			}
+		}
+
+		if len(aRules.RouteRules) != len(bRules.RouteRules) {
+			return false
+		}
+
+		for i, aRoute := range aRules.RouteRules {
+			bRoute := bRules.RouteRules[i]
+			if aRoute.Priority != bRoute.Priority {
+				return false
+			}
+			if !reflect.DeepEqual(aRoute.Matches, bRoute.Matches) {
+				return false
+			}
+			if aRoute.Backend.ID != bRoute.Backend.ID {
+				return false
+			}
+			if !equalActionMapping(aRoute.Action, bRoute.Action) {
+				return false
+			}
 		}
 	}
 	return true
@@ -127,6 +335,32 @@ func (g *GCEURLMap) PutPathRulesForHost(hostname string, pathRules []PathRule) {
 	return
 }
 
+// PutRouteRulesForHost sets the route rules for a single hostname, adding
+// the host with no path rules if it does not exist yet.
+func (g *GCEURLMap) PutRouteRulesForHost(hostname string, routeRules []RouteRule) {
+	if !g.hosts[hostname] {
+		g.HostRules = append(g.HostRules, HostRule{Hostname: hostname})
+		g.hosts[hostname] = true
+	}
+	for i := range g.HostRules {
+		if g.HostRules[i].Hostname == hostname {
+			g.HostRules[i].RouteRules = routeRules
+		}
+	}
+}
+
+// PutActionForHost sets the route action applied to all requests of an
+// existing hostname. It returns false if the hostname does not exist.
+func (g *GCEURLMap) PutActionForHost(hostname string, action *RouteAction) bool {
+	for i := range g.HostRules {
+		if g.HostRules[i].Hostname == hostname {
+			g.HostRules[i].Action = action
+			return true
+		}
+	}
+	return false
+}
+
 // AllServicePorts return a list of all ServicePorts contained in the GCEURLMap.
 func (g *GCEURLMap) AllServicePorts() (svcPorts []ServicePort) {
 
@@ -136,18 +370,57 @@ func (g *GCEURLMap) AllServicePorts() (svcPorts []ServicePort) {
 		uniqueServerPorts[*&g.DefaultBackend.ID] = true
 	}
 
+	addUnique := func(sp ServicePort) {
+		if !uniqueServerPorts[sp.ID] {
+			svcPorts = append(svcPorts, sp)
+			uniqueServerPorts[sp.ID] = true
+		}
+	}
+
 	for _, rules := range g.HostRules {
+		for _, sp := range rules.Action.ServicePorts() {
+			addUnique(sp)
+		}
 		for _, rule := range rules.Paths {
-			if !uniqueServerPorts[rule.Backend.ID] {
-				svcPorts = append(svcPorts, rule.Backend)
-				uniqueServerPorts[rule.Backend.ID] = true
+			if rule.BackendBucket == nil {
+				addUnique(rule.Backend)
+			}
+			for _, sp := range rule.Action.ServicePorts() {
+				addUnique(sp)
+			}
+		}
+		for _, rule := range rules.RouteRules {
+			// Route rules with weighted backends have no single Backend.
+			if rule.Backend.ID.Service.Name != "" {
+				addUnique(rule.Backend)
+			}
+			for _, sp := range rule.Action.ServicePorts() {
+				addUnique(sp)
 			}
 		}
 	}
+	if g.ErrorResponsePolicy != nil && g.ErrorResponsePolicy.Backend != nil {
+		addUnique(*g.ErrorResponsePolicy.Backend)
+	}
 
 	return
 }
 
+// AllBackendBuckets returns a list of all BackendBuckets contained in the
+// GCEURLMap.
+func (g *GCEURLMap) AllBackendBuckets() (buckets []BackendBucket) {
+	unique := make(map[types.NamespacedName]bool)
+	for _, rules := range g.HostRules {
+		for _, rule := range rules.Paths {
+			if rule.BackendBucket != nil && !unique[rule.BackendBucket.ID] {
+				buckets = append(buckets, *rule.BackendBucket)
+				unique[rule.BackendBucket.ID] = true
+			}
+		}
+	}
+	return
+}
+
 func (g *GCEURLMap) deleteHost(hostname string) {
 	// Iterate HostRules and remove any (should only be zero or one) with the provided hostname.
 	for i := len(g.HostRules) - 1; i >= 0; i-- {
@@ -192,6 +465,14 @@ func (g *GCEURLMap) String() string {
 		b.WriteString(fmt.Sprintf("%v\n", hostRule.Hostname))
 		for _, rule := range hostRule.Paths {
 			b.WriteString(fmt.Sprintf("\t%v: ", rule.Path))
+			if rule.BackendBucket != nil {
+				b.WriteString(fmt.Sprintf("%+v\n", *rule.BackendBucket))
+				continue
+			}
+			b.WriteString(fmt.Sprintf("%+v\n", rule.Backend))
+		}
+		for _, rule := range hostRule.RouteRules {
+			b.WriteString(fmt.Sprintf("\tpriority %v %+v: ", rule.Priority, rule.Matches))
 			b.WriteString(fmt.Sprintf("%+v\n", rule.Backend))
 		}
 	}
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestGCEURLMap(t *testing.T) {
@@ -158,6 +159,70 @@ func TestGCEURLMapEquals(t *testing.T) {
 	if EqualMapping(someMap, diffPaths) {
 		t.Errorf("EqualMapping(%+v, %+v) = true, want false", someMap, diffPaths)
 	}
+
+	// Serve a PathRule from a backend bucket.
+	diffBuckets := newTestMap()
+	diffBuckets.HostRules[0].Paths[0].BackendBucket = &BackendBucket{ID: types.NamespacedName{Namespace: "ns", Name: "assets"}}
+	if EqualMapping(someMap, diffBuckets) {
+		t.Errorf("EqualMapping(%+v, %+v) = true, want false", someMap, diffBuckets)
+	}
+	// Change a PathRule's backend bucket.
+	otherBuckets := newTestMap()
+	otherBuckets.HostRules[0].Paths[0].BackendBucket = &BackendBucket{ID: types.NamespacedName{Namespace: "ns", Name: "images"}}
+	if EqualMapping(diffBuckets, otherBuckets) {
+		t.Errorf("EqualMapping(%+v, %+v) = true, want false", diffBuckets, otherBuckets)
+	}
+
+	// Change a PathRule's retry policy.
+	diffPolicies := newTestMap()
+	diffPolicies.HostRules[0].Paths[0].Action = &RouteAction{RetryPolicy: &RetryPolicy{RetryConditions: []string{"5xx"}}}
+	if EqualMapping(someMap, diffPolicies) {
+		t.Errorf("EqualMapping(%+v, %+v) = true, want false", someMap, diffPolicies)
+	}
+	// An empty action is equal to no action.
+	emptyAction := newTestMap()
+	emptyAction.HostRules[0].Paths[0].Action = &RouteAction{}
+	if !EqualMapping(someMap, emptyAction) {
+		t.Errorf("EqualMapping(%+v, %+v) = false, want true", someMap, emptyAction)
+	}
+
+	// Add an error response policy.
+	diffErrorResponses := newTestMap()
+	diffErrorResponses.ErrorResponsePolicy = &ErrorResponsePolicy{
+		BackendBucket: "errors",
+		Rules:         []ErrorResponseRule{{MatchResponseCodes: []string{"5xx"}, Path: "/500.html"}},
+	}
+	if EqualMapping(someMap, diffErrorResponses) {
+		t.Errorf("EqualMapping(%+v, %+v) = true, want false", someMap, diffErrorResponses)
+	}
+	// Change the error response policy's backend.
+	errorsBackend := newServicePortWithID("svc-E", "ns", v1.ServiceBackendPort{Number: 80})
+	otherErrorResponses := newTestMap()
+	otherErrorResponses.ErrorResponsePolicy = &ErrorResponsePolicy{
+		Backend: &errorsBackend,
+		Rules:   diffErrorResponses.ErrorResponsePolicy.Rules,
+	}
+	if EqualMapping(diffErrorResponses, otherErrorResponses) {
+		t.Errorf("EqualMapping(%+v, %+v) = true, want false", diffErrorResponses, otherErrorResponses)
+	}
+
+	// Change a RouteRule's matches.
+	routeRuleBackend := newServicePortWithID("svc-R", "ns", v1.ServiceBackendPort{Number: 80})
+	headerRule := newTestMap()
+	headerRule.HostRules[0].RouteRules = []RouteRule{{
+		Priority: 1,
+		Matches:  []RouteMatch{{HeaderMatches: []HeaderMatch{{HeaderName: "x-canary", ExactMatch: "true"}}}},
+		Backend:  routeRuleBackend,
+	}}
+	queryRule := newTestMap()
+	queryRule.HostRules[0].RouteRules = []RouteRule{{
+		Priority: 1,
+		Matches:  []RouteMatch{{QueryParameterMatches: []QueryParameterMatch{{Name: "tenant", ExactMatch: "a"}}}},
+		Backend:  routeRuleBackend,
+	}}
+	if EqualMapping(headerRule, queryRule) {
+		t.Errorf("EqualMapping(%+v, %+v) = true, want false", headerRule, queryRule)
+	}
 }
 
 func TestAllServicePorts(t *testing.T) {
@@ -202,6 +267,104 @@ func TestAllServicePortsDistinct(t *testing.T) {
 	}
 }
 
+func TestAllServicePortsWithRouteRules(t *testing.T) {
+	t.Parallel()
+	m := newTestMap()
+	m.PutRouteRulesForHost("example.com", []RouteRule{
+		{Priority: 1, Backend: newServicePortWithID("svc-E", "ns", v1.ServiceBackendPort{Number: 80})},
+		{Priority: 2, Backend: newServicePortWithID("svc-A", "ns", v1.ServiceBackendPort{Number: 80})},
+	})
+	m.PutRouteRulesForHost("new.com", []RouteRule{
+		{Priority: 1, Backend: newServicePortWithID("svc-F", "ns", v1.ServiceBackendPort{Number: 80})},
+	})
+
+	wantPorts := []ServicePort{
+		newServicePortWithID("svc-X", "ns", v1.ServiceBackendPort{Number: 80}),
+		newServicePortWithID("svc-A", "ns", v1.ServiceBackendPort{Number: 80}),
+		newServicePortWithID("svc-B", "ns", v1.ServiceBackendPort{Number: 80}),
+		newServicePortWithID("svc-E", "ns", v1.ServiceBackendPort{Number: 80}),
+		newServicePortWithID("svc-C", "ns", v1.ServiceBackendPort{Number: 80}),
+		newServicePortWithID("svc-D", "ns", v1.ServiceBackendPort{Number: 80}),
+		newServicePortWithID("svc-F", "ns", v1.ServiceBackendPort{Number: 80}),
+	}
+
+	gotPorts := m.AllServicePorts()
+	if !reflect.DeepEqual(gotPorts, wantPorts) {
+		t.Errorf("AllServicePorts(%+v) = \n%+v\nwant\n%+v", m, gotPorts, wantPorts)
+	}
+	if !m.HostExists("new.com") {
+		t.Errorf("HostExists(%q) = false, want true", "new.com")
+	}
+}
+
+func TestAllServicePortsWithMirror(t *testing.T) {
+	t.Parallel()
+	m := newTestMap()
+	pathMirror := newServicePortWithID("svc-M", "ns", v1.ServiceBackendPort{Number: 80})
+	hostMirror := newServicePortWithID("svc-H", "ns", v1.ServiceBackendPort{Number: 80})
+	m.PutPathRulesForHost("mirror.com", []PathRule{
+		{Path: "/", Backend: newServicePortWithID("svc-A", "ns", v1.ServiceBackendPort{Number: 80}), Action: &RouteAction{MirrorBackend: &pathMirror}},
+	})
+	if !m.PutActionForHost("mirror.com", &RouteAction{MirrorBackend: &hostMirror}) {
+		t.Fatalf("PutActionForHost(%q) = false, want true", "mirror.com")
+	}
+	if m.PutActionForHost("missing.com", &RouteAction{MirrorBackend: &hostMirror}) {
+		t.Errorf("PutActionForHost(%q) = true, want false", "missing.com")
+	}
+
+	gotPorts := m.AllServicePorts()
+	for _, want := range []ServicePort{pathMirror, hostMirror} {
+		found := false
+		for _, sp := range gotPorts {
+			if sp.ID == want.ID {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("AllServicePorts() = %+v, want %v to be included", gotPorts, want.ID)
+		}
+	}
+}
+
+func TestAllServicePortsWithErrorResponsePolicy(t *testing.T) {
+	t.Parallel()
+	m := newTestMap()
+	errorsBackend := newServicePortWithID("svc-E", "ns", v1.ServiceBackendPort{Number: 80})
+	m.ErrorResponsePolicy = &ErrorResponsePolicy{Backend: &errorsBackend}
+
+	gotPorts := m.AllServicePorts()
+	for _, sp := range gotPorts {
+		if sp.ID == errorsBackend.ID {
+			return
+		}
+	}
+	t.Errorf("AllServicePorts() = %+v, want %v to be included", gotPorts, errorsBackend.ID)
+}
+
+func TestAllBackendBuckets(t *testing.T) {
+	t.Parallel()
+	m := newTestMap()
+	assets := &BackendBucket{ID: types.NamespacedName{Namespace: "ns", Name: "assets"}, BucketName: "assets-bucket"}
+	images := &BackendBucket{ID: types.NamespacedName{Namespace: "ns", Name: "images"}, BucketName: "images-bucket"}
+	m.PutPathRulesForHost("static.com", []PathRule{
+		{Path: "/assets/*", BackendBucket: assets},
+		{Path: "/images/*", BackendBucket: images},
+		{Path: "/static/*", BackendBucket: assets},
+	})
+
+	wantBuckets := []BackendBucket{*assets, *images}
+	gotBuckets := m.AllBackendBuckets()
+	if !reflect.DeepEqual(gotBuckets, wantBuckets) {
+		t.Errorf("AllBackendBuckets(%+v) = \n%+v\nwant\n%+v", m, gotBuckets, wantBuckets)
+	}
+
+	// The service ports of the map are unchanged by the backend buckets.
+	wantPorts := newTestMap().AllServicePorts()
+	if gotPorts := m.AllServicePorts(); !reflect.DeepEqual(gotPorts, wantPorts) {
+		t.Errorf("AllServicePorts(%+v) = \n%+v\nwant\n%+v", m, gotPorts, wantPorts)
+	}
+}
+
 func newTestMap() *GCEURLMap {
 	m := NewGCEURLMap(klog.TODO())
 	b := newServicePortWithID("svc-X", "ns", v1.ServiceBackendPort{Number: 80})
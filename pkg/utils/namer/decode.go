@@ -0,0 +1,332 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Resource types of decoded names.
+const (
+	ResourceURLMap                    = "url-map"
+	ResourceRedirectURLMap            = "redirect-url-map"
+	ResourceHTTPForwardingRule        = "http-forwarding-rule"
+	ResourceHTTPSForwardingRule       = "https-forwarding-rule"
+	ResourceTargetHTTPProxy           = "target-http-proxy"
+	ResourceTargetHTTPSProxy          = "target-https-proxy"
+	ResourceSSLCertificate            = "ssl-certificate"
+	ResourceBackendService            = "backend-service"
+	ResourceInstanceGroup             = "instance-group"
+	ResourceFirewall                  = "firewall"
+	ResourceNEG                       = "neg"
+	ResourceRegionalNEGBackendService = "regional-neg-backend-service"
+	ResourceServiceAttachment         = "service-attachment"
+	ResourceBackendBucket             = "backend-bucket"
+	// ResourceL4 is the backend service, health check, node firewall and
+	// NEGs of an L4 load balancer, which share the same name.
+	ResourceL4                          = "l4-resource"
+	ResourceL4ForwardingRule            = "l4-forwarding-rule"
+	ResourceL4HealthCheckFirewall       = "l4-health-check-firewall"
+	ResourceL4SharedHealthCheck         = "l4-shared-health-check"
+	ResourceL4SharedHealthCheckFirewall = "l4-shared-health-check-firewall"
+)
+
+// Naming schemes of decoded names.
+const (
+	// SchemeV1 is the naming scheme of the Ingress v1 frontend namer, of
+	// instance groups, instance group backends and the L7 firewall.
+	SchemeV1 = "v1"
+	// SchemeV2 is the naming scheme of the Ingress v2 frontend namer.
+	SchemeV2 = "v2"
+	// SchemeL4 is the naming scheme of L4Namer.
+	SchemeL4 = "l4"
+	// SchemeNEG is the naming scheme of NEGs and the backends using them.
+	SchemeNEG = "neg"
+	// SchemeServiceAttachment is the naming scheme of ServiceAttachmentNamer.
+	SchemeServiceAttachment = "service-attachment"
+)
+
+// Kinds of the objects owning decoded names.
+const (
+	KindIngress           = "Ingress"
+	KindService           = "Service"
+	KindServiceAttachment = "ServiceAttachment"
+	KindGCSBackend        = "GCSBackend"
+)
+
+// v1FrontendResources maps the prefixes of the v1 frontend names to their
+// resource type.
+var v1FrontendResources = map[string]string{
+	urlMapPrefix:              ResourceURLMap,
+	redirectMapPrefix:         ResourceRedirectURLMap,
+	forwardingRulePrefix:      ResourceHTTPForwardingRule,
+	httpsForwardingRulePrefix: ResourceHTTPSForwardingRule,
+	targetHTTPProxyPrefix:     ResourceTargetHTTPProxy,
+	targetHTTPSProxyPrefix:    ResourceTargetHTTPSProxy,
+}
+
+// v2FrontendResources maps the prefixes of the v2 frontend names to their
+// resource type.
+var v2FrontendResources = map[string]string{
+	urlMapPrefixV2:              ResourceURLMap,
+	redirectUrlMapPrefixV2:      ResourceRedirectURLMap,
+	forwardingRulePrefixV2:      ResourceHTTPForwardingRule,
+	httpsForwardingRulePrefixV2: ResourceHTTPSForwardingRule,
+	targetHTTPProxyPrefixV2:     ResourceTargetHTTPProxy,
+	targetHTTPSProxyPrefixV2:    ResourceTargetHTTPSProxy,
+}
+
+// l4Protocols are the protocols of L4 forwarding rule names.
+var l4Protocols = map[string]bool{"tcp": true, "udp": true, "l3_default": true}
+
+// DecodedName is what the name of a GCE resource created by the controllers
+// tells about the Kubernetes object owning it.
+type DecodedName struct {
+	// ResourceName is the decoded name.
+	ResourceName string
+	// Scheme is the naming scheme of the name, e.g. SchemeV2.
+	Scheme string
+	// Resource is the type of the resource, e.g. ResourceURLMap.
+	Resource string
+	// Kind is the kind of the owning object, empty for the resources
+	// shared by the cluster.
+	Kind string
+	// ClusterUID is the cluster UID as encoded in the name: the cluster name
+	// of v1 names, the first 8 characters of the cluster name for NEGs, and
+	// a hash of the kube-system UID for the other schemes.
+	ClusterUID string
+	// Descriptor is the "<namespace>-<name>" part of the name, truncated to
+	// fit the GCE limits. Since namespaces and names may contain hyphens it
+	// does not always tell them apart, see Namespace and Name.
+	Descriptor string
+	// Namespace and Name of the owning object, set when the descriptor
+	// can only be split in one way.
+	Namespace string
+	Name      string
+	// Port is the Service port of NEG names, or the node port of v1
+	// instance group backends.
+	Port string
+	// Protocol is the protocol of L4 forwarding rules.
+	Protocol string
+	// IPv6 is true for the IPv6 resources of L4 load balancers.
+	IPv6 bool
+	// Hash is the hash suffix of the name, computed from the cluster UID and
+	// the owning object.
+	Hash string
+}
+
+// DecodeName works the naming schemes of Namer, the frontend namers,
+// L4Namer and ServiceAttachmentNamer in reverse, for the controllers using
+// the given prefix, "k8s" by default. L4Namer always uses the default prefix.
+// Only the resource type and scheme of a name can be decoded with certainty:
+// its namespace and name may be truncated, and a name may also be chosen by
+// a user to look like one of the controllers. Resolve the owner with the
+// description of the resource where possible.
+func DecodeName(name, prefix string) (*DecodedName, error) {
+	d := &DecodedName{ResourceName: name}
+	var ok bool
+	switch {
+	case strings.HasPrefix(name, prefix+schemaVersionV2+"-"):
+		ok = d.decodeV2(strings.TrimPrefix(name, prefix+schemaVersionV2+"-"))
+	case strings.HasPrefix(name, prefix+schemaVersionV1+"-"):
+		ok = d.decodeNEG(strings.TrimPrefix(name, prefix+schemaVersionV1+"-"))
+	case strings.HasPrefix(name, prefix+"-"):
+		ok = d.decodeV1(strings.TrimPrefix(name, prefix+"-"))
+	}
+	if !ok && prefix != defaultPrefix && strings.HasPrefix(name, defaultPrefix+schemaVersionV2+"-") {
+		d = &DecodedName{ResourceName: name}
+		ok = d.decodeV2(strings.TrimPrefix(name, defaultPrefix+schemaVersionV2+"-")) && d.Scheme == SchemeL4
+	}
+	if !ok {
+		return nil, fmt.Errorf("%q does not follow a naming scheme of the controllers", name)
+	}
+	d.splitDescriptor()
+	return d, nil
+}
+
+// decodeV1 decodes the names of the v1 frontend namer, instance groups,
+// instance group backends and the L7 firewall, without their "k8s-" prefix.
+func (d *DecodedName) decodeV1(name string) bool {
+	d.Scheme = SchemeV1
+	base, uid, hasUID := name, "", false
+	if i := strings.LastIndex(name, clusterNameDelimiter); i >= 0 {
+		base, uid, hasUID = name[:i], name[i+len(clusterNameDelimiter):], true
+	}
+	d.ClusterUID = uid
+	tokens := strings.Split(base, "-")
+
+	switch {
+	case base == igPrefix && hasUID:
+		d.Resource = ResourceInstanceGroup
+		return true
+	case base == "fw-"+globalFirewallSuffix:
+		// The L7 firewall is suffixed with the firewall name, which
+		// defaults to the cluster name.
+		d.Resource = ResourceFirewall
+		return true
+	case len(tokens) == 2 && tokens[0] == backendPrefix && hasUID:
+		d.Resource = ResourceBackendService
+		d.Port = tokens[1]
+		return true
+	case len(tokens) >= 4 && tokens[0] == backendBucketPrefix:
+		// k8s-bb-<namespace>-<name>-<hash>--<cluster uid>
+		d.Resource = ResourceBackendBucket
+		d.Kind = KindGCSBackend
+		d.Descriptor = strings.Join(tokens[1:len(tokens)-1], "-")
+		d.Hash = tokens[len(tokens)-1]
+		return true
+	case len(tokens) >= 2 && tokens[0] == sslCertPrefix:
+		// k8s-ssl-<lb hash>-<secret hash>--<cluster uid>, or the legacy
+		// k8s-ssl-<lb name>.
+		d.Resource = ResourceSSLCertificate
+		d.Kind = KindIngress
+		return true
+	}
+	if resource, ok := v1FrontendResources[tokens[0]]; ok && len(tokens) >= 2 {
+		// k8s-<resource>-<namespace>-<name>--<cluster uid>
+		d.Resource = resource
+		d.Kind = KindIngress
+		d.Descriptor = strings.Join(tokens[1:], "-")
+		return true
+	}
+	return false
+}
+
+// decodeV2 decodes the names of the v2 frontend namer and of L4Namer,
+// without their "k8s2-" prefix.
+func (d *DecodedName) decodeV2(name string) bool {
+	tokens := strings.Split(name, "-")
+	if len(tokens) < 2 {
+		return false
+	}
+	if resource, ok := v2FrontendResources[tokens[0]]; ok && len(tokens) >= 5 {
+		// k8s2-<resource>-<cluster uid>-<namespace>-<name>-<hash>
+		d.Scheme = SchemeV2
+		d.Resource = resource
+		d.Kind = KindIngress
+		d.ClusterUID = tokens[1]
+		d.Descriptor = strings.Join(tokens[2:len(tokens)-1], "-")
+		d.Hash = tokens[len(tokens)-1]
+		return true
+	}
+	if tokens[0] == backendBucketPrefixV2 && len(tokens) >= 5 {
+		// k8s2-bb-<cluster uid>-<namespace>-<name>-<hash>
+		d.Scheme = SchemeV2
+		d.Resource = ResourceBackendBucket
+		d.Kind = KindGCSBackend
+		d.ClusterUID = tokens[1]
+		d.Descriptor = strings.Join(tokens[2:len(tokens)-1], "-")
+		d.Hash = tokens[len(tokens)-1]
+		return true
+	}
+	if tokens[0] == sslCertPrefixV2 && len(tokens) == 4 {
+		// k8s2-cr-<cluster uid>-<lb hash>-<secret hash>
+		d.Scheme = SchemeV2
+		d.Resource = ResourceSSLCertificate
+		d.Kind = KindIngress
+		d.ClusterUID = tokens[1]
+		d.Hash = tokens[2]
+		return true
+	}
+
+	d.Scheme = SchemeL4
+	if strings.HasSuffix(name, "-"+ipv6Suffix) {
+		d.IPv6 = true
+		name = strings.TrimSuffix(name, "-"+ipv6Suffix)
+	}
+	if strings.HasSuffix(name, "-"+sharedHcSuffix) {
+		// k8s2-<cluster uid>-l4-shared-hc
+		d.Resource = ResourceL4SharedHealthCheck
+		d.ClusterUID = strings.TrimSuffix(name, "-"+sharedHcSuffix)
+		return !d.IPv6 && !strings.Contains(d.ClusterUID, "-")
+	}
+	if strings.HasSuffix(name, "-"+sharedFirewallHcSuffix) {
+		// k8s2-<cluster uid>-l4-shared-hc-fw[-ipv6]
+		d.Resource = ResourceL4SharedHealthCheckFirewall
+		d.ClusterUID = strings.TrimSuffix(name, "-"+sharedFirewallHcSuffix)
+		return !strings.Contains(d.ClusterUID, "-")
+	}
+
+	d.Kind = KindService
+	d.Resource = ResourceL4
+	if strings.HasSuffix(name, firewallHcSuffix) {
+		d.Resource = ResourceL4HealthCheckFirewall
+		name = strings.TrimSuffix(name, firewallHcSuffix)
+	}
+	tokens = strings.Split(name, "-")
+	if l4Protocols[tokens[0]] {
+		if d.Resource != ResourceL4 {
+			return false
+		}
+		// k8s2-<protocol>-<cluster uid>-<namespace>-<name>-<hash>[-ipv6]
+		d.Resource = ResourceL4ForwardingRule
+		d.Protocol = tokens[0]
+		tokens = tokens[1:]
+	}
+	if len(tokens) < 4 {
+		return false
+	}
+	// k8s2-<cluster uid>-<namespace>-<name>-<hash>[-fw][-ipv6]
+	d.ClusterUID = tokens[0]
+	d.Descriptor = strings.Join(tokens[1:len(tokens)-1], "-")
+	d.Hash = tokens[len(tokens)-1]
+	return true
+}
+
+// decodeNEG decodes the names of NEGs, of the regional backends using them
+// and of service attachments, without their "k8s1-" prefix.
+func (d *DecodedName) decodeNEG(name string) bool {
+	tokens := strings.Split(name, "-")
+	if tokens[0] == "sa" && len(tokens) >= 5 {
+		// k8s1-sa-<cluster uid>-<namespace>-<name>-<hash>
+		d.Scheme = SchemeServiceAttachment
+		d.Resource = ResourceServiceAttachment
+		d.Kind = KindServiceAttachment
+		d.ClusterUID = tokens[1]
+		d.Descriptor = strings.Join(tokens[2:len(tokens)-1], "-")
+		d.Hash = tokens[len(tokens)-1]
+		return true
+	}
+	if len(tokens) < 5 {
+		return false
+	}
+	// k8s1-<cluster uid>-<namespace>-<name>-<port>-<hash>, the regional
+	// external backends have "e-" before the namespace. NEG names are also
+	// used by the backend services and health checks of the NEGs.
+	d.Scheme = SchemeNEG
+	d.Resource = ResourceNEG
+	d.Kind = KindService
+	d.ClusterUID = tokens[0]
+	tokens = tokens[1:]
+	if tokens[0] == "e" && len(tokens) >= 5 {
+		d.Resource = ResourceRegionalNEGBackendService
+		tokens = tokens[1:]
+	}
+	d.Hash = tokens[len(tokens)-1]
+	d.Port = tokens[len(tokens)-2]
+	d.Descriptor = strings.Join(tokens[:len(tokens)-2], "-")
+	return true
+}
+
+// splitDescriptor sets the namespace and name of the owning object when the
+// descriptor has a single hyphen.
+func (d *DecodedName) splitDescriptor() {
+	if parts := strings.Split(d.Descriptor, "-"); len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+		d.Namespace, d.Name = parts[0], parts[1]
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namer
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/ingress-gce/pkg/utils/common"
+	"k8s.io/klog/v2"
+)
+
+// TestDecodeName asserts that the names built by the namers are decoded back
+// to their owners.
+func TestDecodeName(t *testing.T) {
+	namer := NewNamer(clusterUID, "", klog.TODO())
+	l4Namer := NewL4Namer(kubeSystemUID, namer)
+	ing := newIngress("shop", "web")
+	v1Namer := newV1IngressFrontendNamer(ing, namer, klog.TODO())
+	v2Namer := newV2IngressFrontendNamer(ing, kubeSystemUID, defaultPrefix)
+	saNamer := NewServiceAttachmentNamer(namer, kubeSystemUID)
+	v2UID := common.ContentHash(kubeSystemUID, clusterUIDLength)
+
+	ingress := DecodedName{Kind: KindIngress, Namespace: "shop", Name: "web", Descriptor: "shop-web"}
+	v1 := func(resource string) DecodedName {
+		d := ingress
+		d.Scheme, d.Resource, d.ClusterUID = SchemeV1, resource, clusterUID
+		return d
+	}
+	v2 := func(resource string) DecodedName {
+		d := ingress
+		d.Scheme, d.Resource, d.ClusterUID = SchemeV2, resource, v2UID
+		return d
+	}
+	l4 := DecodedName{Scheme: SchemeL4, Resource: ResourceL4, Kind: KindService, ClusterUID: v2UID, Namespace: "shop", Name: "web", Descriptor: "shop-web"}
+	l4Firewall := l4
+	l4Firewall.Resource = ResourceL4HealthCheckFirewall
+	l4Rule := l4
+	l4Rule.Resource, l4Rule.Protocol = ResourceL4ForwardingRule, "udp"
+	l4RuleIPv6 := l4Rule
+	l4RuleIPv6.IPv6 = true
+
+	for _, tc := range []struct {
+		name string
+		want DecodedName
+	}{
+		{v1Namer.UrlMap(), v1(ResourceURLMap)},
+		{v1Namer.TargetProxy(HTTPSProtocol), v1(ResourceTargetHTTPSProxy)},
+		{v1Namer.ForwardingRule(HTTPProtocol), v1(ResourceHTTPForwardingRule)},
+		{v2Namer.UrlMap(), v2(ResourceURLMap)},
+		{v2Namer.ForwardingRule(HTTPSProtocol), v2(ResourceHTTPSForwardingRule)},
+		{v2Namer.TargetProxy(HTTPProtocol), v2(ResourceTargetHTTPProxy)},
+		{v2Namer.SSLCertName("secrethash"), DecodedName{Scheme: SchemeV2, Resource: ResourceSSLCertificate, Kind: KindIngress, ClusterUID: v2UID}},
+		{v1Namer.BackendBucket("shop", "assets"), DecodedName{Scheme: SchemeV1, Resource: ResourceBackendBucket, Kind: KindGCSBackend, ClusterUID: clusterUID, Namespace: "shop", Name: "assets", Descriptor: "shop-assets"}},
+		{v2Namer.BackendBucket("shop", "assets"), DecodedName{Scheme: SchemeV2, Resource: ResourceBackendBucket, Kind: KindGCSBackend, ClusterUID: v2UID, Namespace: "shop", Name: "assets", Descriptor: "shop-assets"}},
+		{namer.IGBackend(30001), DecodedName{Scheme: SchemeV1, Resource: ResourceBackendService, ClusterUID: clusterUID, Port: "30001"}},
+		{namer.InstanceGroup(), DecodedName{Scheme: SchemeV1, Resource: ResourceInstanceGroup, ClusterUID: clusterUID}},
+		{namer.FirewallRule(), DecodedName{Scheme: SchemeV1, Resource: ResourceFirewall, ClusterUID: clusterUID}},
+		{namer.NEG("shop", "web", 80), DecodedName{Scheme: SchemeNEG, Resource: ResourceNEG, Kind: KindService, ClusterUID: clusterUID, Namespace: "shop", Name: "web", Descriptor: "shop-web", Port: "80"}},
+		{namer.RXLBBackendName("shop", "web", 80), DecodedName{Scheme: SchemeNEG, Resource: ResourceRegionalNEGBackendService, Kind: KindService, ClusterUID: clusterUID, Namespace: "shop", Name: "web", Descriptor: "shop-web", Port: "80"}},
+		{saNamer.ServiceAttachment("shop", "web", "sa-uid"), DecodedName{Scheme: SchemeServiceAttachment, Resource: ResourceServiceAttachment, Kind: KindServiceAttachment, ClusterUID: v2UID, Namespace: "shop", Name: "web", Descriptor: "shop-web"}},
+		{l4Namer.L4Backend("shop", "web"), l4},
+		{l4Namer.L4HealthCheckFirewall("shop", "web", false), l4Firewall},
+		{l4Namer.L4ForwardingRule("shop", "web", "udp"), l4Rule},
+		{l4Namer.L4IPv6ForwardingRule("shop", "web", "udp"), l4RuleIPv6},
+		{l4Namer.L4HealthCheck("shop", "web", true), DecodedName{Scheme: SchemeL4, Resource: ResourceL4SharedHealthCheck, ClusterUID: v2UID}},
+		{l4Namer.L4IPv6HealthCheckFirewall("shop", "web", true), DecodedName{Scheme: SchemeL4, Resource: ResourceL4SharedHealthCheckFirewall, ClusterUID: v2UID, IPv6: true}},
+	} {
+		got, err := DecodeName(tc.name, defaultPrefix)
+		if err != nil {
+			t.Errorf("DecodeName(%q) = %v, want nil", tc.name, err)
+			continue
+		}
+		tc.want.ResourceName = tc.name
+		if diff := cmp.Diff(&tc.want, got, cmpopts.IgnoreFields(DecodedName{}, "Hash")); diff != "" {
+			t.Errorf("DecodeName(%q) mismatch (-want +got):\n%s", tc.name, diff)
+		}
+	}
+}
+
+func TestDecodeNameAmbiguous(t *testing.T) {
+	name := newV2IngressFrontendNamer(newIngress("my-shop", "web"), kubeSystemUID, defaultPrefix).UrlMap()
+	got, err := DecodeName(name, defaultPrefix)
+	if err != nil {
+		t.Fatalf("DecodeName(%q) = %v, want nil", name, err)
+	}
+	if got.Descriptor != "my-shop-web" || got.Namespace != "" || got.Name != "" {
+		t.Errorf("DecodeName(%q) = %+v, want the descriptor my-shop-web without namespace and name", name, got)
+	}
+
+	for _, name := range []string{"web", "k8s-xx", "k8s2-tcp", "gke-cluster-default-pool"} {
+		if got, err := DecodeName(name, defaultPrefix); err == nil {
+			t.Errorf("DecodeName(%q) = %+v, want error", name, got)
+		}
+	}
+}
+
+// TestDecodeNamePrefix asserts that the names of the controllers using a
+// custom prefix are decoded with that prefix only, except the L4 names which
+// always use the default prefix.
+func TestDecodeNamePrefix(t *testing.T) {
+	namer := NewNamerWithPrefix("mci", clusterUID, "", klog.TODO())
+	ing := newIngress("shop", "web")
+	v1Namer := newV1IngressFrontendNamer(ing, namer, klog.TODO())
+	v2Namer := newV2IngressFrontendNamer(ing, kubeSystemUID, "mci")
+	l4Name := NewL4Namer(kubeSystemUID, namer).L4Backend("shop", "web")
+
+	for _, tc := range []struct {
+		name         string
+		prefix       string
+		wantResource string
+	}{
+		{v1Namer.UrlMap(), "mci", ResourceURLMap},
+		{v2Namer.UrlMap(), "mci", ResourceURLMap},
+		{namer.NEG("shop", "web", 80), "mci", ResourceNEG},
+		{l4Name, "mci", ResourceL4},
+		{l4Name, defaultPrefix, ResourceL4},
+		{v1Namer.UrlMap(), defaultPrefix, ""},
+		{v2Namer.UrlMap(), defaultPrefix, ""},
+		{newV2IngressFrontendNamer(ing, kubeSystemUID, defaultPrefix).UrlMap(), "mci", ""},
+	} {
+		got, err := DecodeName(tc.name, tc.prefix)
+		if tc.wantResource == "" {
+			if err == nil {
+				t.Errorf("DecodeName(%q, %q) = %+v, want error", tc.name, tc.prefix, got)
+			}
+			continue
+		}
+		if err != nil || got.Resource != tc.wantResource {
+			t.Errorf("DecodeName(%q, %q) = %+v, %v, want a %s", tc.name, tc.prefix, got, err, tc.wantResource)
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 
 	v1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/ingress-gce/pkg/annotations"
 	"k8s.io/ingress-gce/pkg/utils/common"
 	"k8s.io/klog/v2"
 )
@@ -50,6 +51,8 @@ const (
 	targetHTTPSProxyPrefixV2 = "ts"
 	// sslCertPrefixV2 is ssl certificate prefix for v2 naming scheme.
 	sslCertPrefixV2 = "cr"
+	// backendBucketPrefixV2 is backend bucket prefix for v2 naming scheme.
+	backendBucketPrefixV2 = "bb"
 	// clusterUIDLength is length of cluster UID to be included in resource names.
 	clusterUIDLength = 8
 )
@@ -110,6 +113,11 @@ func (ln *V1IngressFrontendNamer) IsLegacySSLCert(certName string) bool {
 	return ln.namer.IsLegacySSLCert(ln.lbName, certName)
 }
 
+// BackendBucket implements IngressFrontendNamer.
+func (ln *V1IngressFrontendNamer) BackendBucket(namespace, name string) string {
+	return ln.namer.BackendBucket(namespace, name)
+}
+
 // LoadBalancer implements IngressFrontendNamer.
 func (ln *V1IngressFrontendNamer) LoadBalancer() LoadBalancerName {
 	return ln.lbName
@@ -145,6 +153,7 @@ type V2IngressFrontendNamer struct {
 // Target HTTPS Proxy    : k8s2-ts-uid01234-namespace-ingress-cysix1wq
 // URL Map               : k8s2-um-uid01234-namespace-ingress-cysix1wq
 // SSL Certificate       : k8s2-cr-uid01234-<lb-hash>-<secret-hash>
+// Backend Bucket        : k8s2-bb-uid01234-<namespace>-<gcsbackend>-<hash>
 func newV2IngressFrontendNamer(ing *v1.Ingress, kubeSystemUID string, prefix string) IngressFrontendNamer {
 	clusterUID := common.ContentHash(kubeSystemUID, clusterUIDLength)
 	namer := &V2IngressFrontendNamer{ing: ing, prefix: prefix, clusterUID: clusterUID}
@@ -210,6 +219,15 @@ func (vn *V2IngressFrontendNamer) IsLegacySSLCert(certName string) bool {
 	return false
 }
 
+// BackendBucket returns the name of the backend bucket of a GCSBackend. It
+// does not depend on the ingress, so that the load balancers of the cluster
+// share the backend bucket of a GCSBackend.
+func (vn *V2IngressFrontendNamer) BackendBucket(namespace, name string) string {
+	truncFields := TrimFieldsEvenly(maximumAllowedCombinedLength, namespace, name)
+	hash := common.ContentHash(strings.Join([]string{vn.clusterUID, namespace, name}, ";"), 8)
+	return fmt.Sprintf("%s%s-%s-%s-%s-%s-%s", vn.prefix, schemaVersionV2, backendBucketPrefixV2, vn.clusterUID, truncFields[0], truncFields[1], hash)
+}
+
 // LoadBalancer returns loadbalancer name.
 // Note that this is used for generating GCE resource names.
 func (vn *V2IngressFrontendNamer) LoadBalancer() LoadBalancerName {
@@ -234,6 +252,76 @@ func (vn *V2IngressFrontendNamer) lbNameToHash() string {
 	return common.ContentHash(vn.lbName.String(), 16)
 }
 
+// AdoptedIngressFrontendNamer implements IngressFrontendNamer for an Ingress
+// which adopts existing GCE resources. The adopted resources keep their
+// names, the other resources are named by the wrapped namer.
+type AdoptedIngressFrontendNamer struct {
+	IngressFrontendNamer
+	adopted *annotations.AdoptedResources
+	// err is set if the adopted resources cannot be adopted, in which case
+	// all the resources are named by the wrapped namer.
+	err error
+}
+
+// newAdoptedIngressFrontendNamer returns a namer for the resources adopted by
+// an Ingress, wrapping the namer of its naming scheme. Names following the
+// naming schemes of the controllers with the given prefix are not adopted,
+// since they may be the resources of another load balancer.
+func newAdoptedIngressFrontendNamer(namer IngressFrontendNamer, adopted *annotations.AdoptedResources, prefix string) IngressFrontendNamer {
+	an := &AdoptedIngressFrontendNamer{IngressFrontendNamer: namer, adopted: adopted}
+	for _, name := range []string{adopted.URLMap, adopted.TargetHTTPProxy, adopted.TargetHTTPSProxy, adopted.HTTPForwardingRule, adopted.HTTPSForwardingRule} {
+		if d, err := DecodeName(name, prefix); err == nil {
+			an.err = fmt.Errorf("cannot adopt %q which is named like a %s of the controllers", name, d.Resource)
+			an.adopted = &annotations.AdoptedResources{}
+			break
+		}
+	}
+	return an
+}
+
+// Err returns the reason why the resources listed by the Ingress are not
+// adopted, if any.
+func (an *AdoptedIngressFrontendNamer) Err() error {
+	return an.err
+}
+
+// ForwardingRule returns the name of the adopted forwarding rule for the
+// given protocol, if any.
+func (an *AdoptedIngressFrontendNamer) ForwardingRule(protocol NamerProtocol) string {
+	switch {
+	case protocol == HTTPProtocol && an.adopted.HTTPForwardingRule != "":
+		return an.adopted.HTTPForwardingRule
+	case protocol == HTTPSProtocol && an.adopted.HTTPSForwardingRule != "":
+		return an.adopted.HTTPSForwardingRule
+	}
+	return an.IngressFrontendNamer.ForwardingRule(protocol)
+}
+
+// TargetProxy returns the name of the adopted target proxy for the given
+// protocol, if any.
+func (an *AdoptedIngressFrontendNamer) TargetProxy(protocol NamerProtocol) string {
+	switch {
+	case protocol == HTTPProtocol && an.adopted.TargetHTTPProxy != "":
+		return an.adopted.TargetHTTPProxy
+	case protocol == HTTPSProtocol && an.adopted.TargetHTTPSProxy != "":
+		return an.adopted.TargetHTTPSProxy
+	}
+	return an.IngressFrontendNamer.TargetProxy(protocol)
+}
+
+// UrlMap returns the name of the adopted URL map, if any.
+func (an *AdoptedIngressFrontendNamer) UrlMap() string {
+	if an.adopted.URLMap != "" {
+		return an.adopted.URLMap
+	}
+	return an.IngressFrontendNamer.UrlMap()
+}
+
+// IsValidLoadBalancer implements IngressFrontendNamer.
+func (an *AdoptedIngressFrontendNamer) IsValidLoadBalancer() bool {
+	return an.IngressFrontendNamer.IsValidLoadBalancer() && isValidGCEResourceName(an.UrlMap())
+}
+
 // FrontendNamerFactory implements IngressFrontendNamerFactory.
 type FrontendNamerFactory struct {
 	namer *Namer
@@ -256,7 +344,12 @@ func (rn *FrontendNamerFactory) Namer(ing *v1.Ingress) IngressFrontendNamer {
 	case V1NamingScheme:
 		return newV1IngressFrontendNamer(ing, rn.namer, rn.logger)
 	case V2NamingScheme:
-		return newV2IngressFrontendNamer(ing, rn.kubeSystemUID, rn.namer.prefix)
+		namer := newV2IngressFrontendNamer(ing, rn.kubeSystemUID, rn.namer.prefix)
+		// An invalid annotation is reported by the sync of the load balancer.
+		if adopted, found, err := annotations.FromIngress(ing).AdoptedResources(); err == nil && found {
+			return newAdoptedIngressFrontendNamer(namer, adopted, rn.namer.prefix)
+		}
+		return namer
 	default:
 		rn.logger.Error(nil, "Unexpected frontend naming scheme", "namingScheme", namingScheme)
 		return newV1IngressFrontendNamer(ing, rn.namer, rn.logger)
@@ -267,3 +360,10 @@ func (rn *FrontendNamerFactory) Namer(ing *v1.Ingress) IngressFrontendNamer {
 func (rn *FrontendNamerFactory) NamerForLoadBalancer(lbName LoadBalancerName) IngressFrontendNamer {
 	return newV1IngressFrontendNamerForLoadBalancer(lbName, rn.namer)
 }
+
+// IsBackendBucket implements IngressFrontendNamerFactory.
+func (rn *FrontendNamerFactory) IsBackendBucket(name string) bool {
+	clusterUID := common.ContentHash(rn.kubeSystemUID, clusterUIDLength)
+	v2Prefix := fmt.Sprintf("%s%s-%s-%s-", rn.namer.prefix, schemaVersionV2, backendBucketPrefixV2, clusterUID)
+	return strings.HasPrefix(name, v2Prefix) || rn.namer.IsBackendBucket(name)
+}
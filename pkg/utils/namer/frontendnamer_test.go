@@ -25,6 +25,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	v1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/ingress-gce/pkg/annotations"
+	"k8s.io/ingress-gce/pkg/utils/common"
 )
 
 const (
@@ -441,3 +443,146 @@ func TestV2IngressFrontendNamer(t *testing.T) {
 		}
 	}
 }
+
+// TestAdoptedIngressFrontendNamer tests that the adopted resources of an
+// Ingress keep their names, and that the other ones use the v2 names.
+func TestAdoptedIngressFrontendNamer(t *testing.T) {
+	ing := newIngress("namespace", "name")
+	ing.Finalizers = []string{common.FinalizerKeyV2}
+	ing.Annotations = map[string]string{annotations.AdoptResourcesKey: `{"urlMap":"web","targetHttpsProxy":"web-https"}`}
+	factory := NewFrontendNamerFactory(NewNamer(clusterUID, "fw1", klog.TODO()), kubeSystemUID, klog.TODO())
+	v2Namer := newV2IngressFrontendNamer(ing, kubeSystemUID, defaultPrefix)
+
+	namer := factory.Namer(ing)
+	for _, tc := range []struct {
+		desc string
+		got  string
+		want string
+	}{
+		{"UrlMap()", namer.UrlMap(), "web"},
+		{"TargetProxy(HTTPSProtocol)", namer.TargetProxy(HTTPSProtocol), "web-https"},
+		{"TargetProxy(HTTPProtocol)", namer.TargetProxy(HTTPProtocol), v2Namer.TargetProxy(HTTPProtocol)},
+		{"ForwardingRule(HTTPSProtocol)", namer.ForwardingRule(HTTPSProtocol), v2Namer.ForwardingRule(HTTPSProtocol)},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("namer.%s = %q, want %q", tc.desc, tc.got, tc.want)
+		}
+	}
+	if !namer.IsValidLoadBalancer() {
+		t.Errorf("namer.IsValidLoadBalancer() = false, want true")
+	}
+	if err := namer.(*AdoptedIngressFrontendNamer).Err(); err != nil {
+		t.Errorf("namer.Err() = %v, want nil", err)
+	}
+
+	// The names of the resources of other load balancers are not adopted.
+	otherIng := newIngress("other", "ing")
+	for _, name := range []string{
+		newV2IngressFrontendNamer(otherIng, kubeSystemUID, defaultPrefix).UrlMap(),
+		newV1IngressFrontendNamer(otherIng, NewNamer(clusterUID, "fw1", klog.TODO()), klog.TODO()).TargetProxy(HTTPSProtocol),
+	} {
+		ing.Annotations[annotations.AdoptResourcesKey] = fmt.Sprintf(`{"urlMap":"web","targetHttpsProxy":%q}`, name)
+		namer := factory.Namer(ing)
+		if err := namer.(*AdoptedIngressFrontendNamer).Err(); err == nil {
+			t.Errorf("namer.Err() = nil adopting %q, want error", name)
+		}
+		if got, want := namer.UrlMap(), v2Namer.UrlMap(); got != want {
+			t.Errorf("namer.UrlMap() = %q adopting %q, want %q", got, name, want)
+		}
+		if got, want := namer.TargetProxy(HTTPSProtocol), v2Namer.TargetProxy(HTTPSProtocol); got != want {
+			t.Errorf("namer.TargetProxy(HTTPSProtocol) = %q adopting %q, want %q", got, name, want)
+		}
+	}
+
+	// An invalid annotation is ignored by the namer.
+	ing.Annotations[annotations.AdoptResourcesKey] = "web"
+	if got, want := factory.Namer(ing).UrlMap(), v2Namer.UrlMap(); got != want {
+		t.Errorf("namer.UrlMap() = %q with an invalid annotation, want %q", got, want)
+	}
+}
+
+// TestBackendBucket tests that the backend bucket of a GCSBackend is shared
+// by the Ingresses using the same naming scheme, and recognized by the namer
+// factory of the cluster only.
+func TestBackendBucket(t *testing.T) {
+	longString := "01234567890123456789012345678901234567890123456789"
+	factory := NewFrontendNamerFactory(NewNamer(clusterUID, "", klog.TODO()), kubeSystemUID, klog.TODO())
+	otherFactory := NewFrontendNamerFactory(NewNamer("uid2", "", klog.TODO()), "kubesystem-uid2", klog.TODO())
+
+	v1Ing := newIngress("namespace", "web")
+	v2Ing := newIngress("namespace", "web")
+	v2Ing.Finalizers = []string{common.FinalizerKeyV2}
+	otherV2Ing := newIngress("other", "shop")
+	otherV2Ing.Finalizers = []string{common.FinalizerKeyV2}
+
+	for _, tc := range []struct {
+		desc       string
+		ing        *v1.Ingress
+		otherIng   *v1.Ingress
+		namespace  string
+		name       string
+		wantBucket string
+	}{
+		{
+			desc:       "v1 naming scheme",
+			ing:        v1Ing,
+			otherIng:   newIngress("other", "shop"),
+			namespace:  "namespace",
+			name:       "assets",
+			wantBucket: fmt.Sprintf("k8s-bb-namespace-assets-%s--uid1", common.ContentHash("uid1;namespace;assets", 8)),
+		},
+		{
+			desc:      "v1 naming scheme with long names",
+			ing:       v1Ing,
+			otherIng:  newIngress("other", "shop"),
+			namespace: longString,
+			name:      longString,
+			wantBucket: fmt.Sprintf("k8s-bb-01234567890123456789-0123456789012345678-%s--uid1",
+				common.ContentHash(fmt.Sprintf("uid1;%s;%s", longString, longString), 8)),
+		},
+		{
+			desc:       "v2 naming scheme",
+			ing:        v2Ing,
+			otherIng:   otherV2Ing,
+			namespace:  "namespace",
+			name:       "assets",
+			wantBucket: fmt.Sprintf("k8s2-bb-7kpbhpki-namespace-assets-%s", common.ContentHash("7kpbhpki;namespace;assets", 8)),
+		},
+		{
+			desc:      "v2 naming scheme with long names",
+			ing:       v2Ing,
+			otherIng:  otherV2Ing,
+			namespace: longString,
+			name:      longString,
+			wantBucket: fmt.Sprintf("k8s2-bb-7kpbhpki-012345678901234567-012345678901234567-%s",
+				common.ContentHash(fmt.Sprintf("7kpbhpki;%s;%s", longString, longString), 8)),
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			name := factory.Namer(tc.ing).BackendBucket(tc.namespace, tc.name)
+			if diff := cmp.Diff(tc.wantBucket, name); diff != "" {
+				t.Errorf("BackendBucket(%q, %q) mismatch (-want +got):\n%s", tc.namespace, tc.name, diff)
+			}
+			if len(name) > maxResourceNameLength {
+				t.Errorf("len(BackendBucket(%q, %q)) = %d, want <= %d", tc.namespace, tc.name, len(name), maxResourceNameLength)
+			}
+			if otherName := factory.Namer(tc.otherIng).BackendBucket(tc.namespace, tc.name); otherName != name {
+				t.Errorf("BackendBucket(%q, %q) = %q for Ingress %s, want %q", tc.namespace, tc.name, otherName, tc.otherIng.Name, name)
+			}
+			if !factory.IsBackendBucket(name) {
+				t.Errorf("IsBackendBucket(%q) = false, want true", name)
+			}
+			if otherFactory.IsBackendBucket(name) {
+				t.Errorf("IsBackendBucket(%q) = true for another cluster, want false", name)
+			}
+			if urlMap := factory.Namer(tc.ing).UrlMap(); factory.IsBackendBucket(urlMap) {
+				t.Errorf("IsBackendBucket(%q) = true, want false", urlMap)
+			}
+			// The hyphens of the namespace and name must not make two
+			// GCSBackends share a backend bucket.
+			if otherName := factory.Namer(tc.ing).BackendBucket(tc.namespace+"-x", "y"); otherName == factory.Namer(tc.ing).BackendBucket(tc.namespace, "x-y") {
+				t.Errorf("BackendBucket(%q, %q) = BackendBucket(%q, %q) = %q, want different names", tc.namespace+"-x", "y", tc.namespace, "x-y", otherName)
+			}
+		})
+	}
+}
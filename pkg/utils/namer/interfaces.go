@@ -33,6 +33,10 @@ type IngressFrontendNamer interface {
 	// and cert is managed by this ingress.
 	// old naming convention is of the form k8s-ssl-<lbName> or k8s-ssl-1-<lbName>.
 	IsLegacySSLCert(certName string) bool
+	// BackendBucket returns the name of the backend bucket of the GCSBackend
+	// with the given namespace and name. Backend buckets are shared by the
+	// load-balancers of the cluster using the same naming scheme.
+	BackendBucket(namespace, name string) string
 	// LoadBalancer returns load-balancer name for the ingress.
 	LoadBalancer() LoadBalancerName
 	// IsValidLoadBalancer returns if the derived loadbalancer is valid.
@@ -47,6 +51,9 @@ type IngressFrontendNamerFactory interface {
 	// NamerForLoadBalancer returns IngressFrontendNamer given a load-balancer
 	// name. This used only for v1 naming scheme.
 	NamerForLoadBalancer(loadBalancer LoadBalancerName) IngressFrontendNamer
+	// IsBackendBucket returns true if name is the name of a backend bucket
+	// given by the frontend namers of this cluster, in any naming scheme.
+	IsBackendBucket(name string) bool
 }
 
 // BackendNamer is an interface to name GCE backend resources. It wraps backend
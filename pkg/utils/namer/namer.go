@@ -24,6 +24,7 @@ import (
 	"strings"
 	"sync"
 
+	"k8s.io/ingress-gce/pkg/utils/common"
 	"k8s.io/klog/v2"
 )
 
@@ -42,6 +43,9 @@ const (
 	httpsForwardingRulePrefix = "fws"
 	urlMapPrefix              = "um"
 	redirectMapPrefix         = "rm"
+	// Backend buckets are shared by the loadbalancers serving the same
+	// GCSBackend. Tagged with the namespace/name of the GCSBackend.
+	backendBucketPrefix = "bb"
 
 	// This allows sharing of backends across loadbalancers.
 	backendPrefix = "be"
@@ -398,6 +402,28 @@ func (n *Namer) SSLCertName(lbName LoadBalancerName, secretHash string) string {
 	return n.decorateName(fmt.Sprintf("%s-%s-%s-%s", n.prefix, sslCertPrefix, lbNameHash, secretHash))
 }
 
+// BackendBucket returns the name of the backend bucket of the GCSBackend with
+// the given namespace and name. The namespace and name are trimmed so that
+// the name keeps the cluster UID, and the hash tells apart the GCSBackends
+// whose namespace and name only differ once joined or trimmed.
+func (n *Namer) BackendBucket(namespace, name string) string {
+	// k8s-bb-[namespace]-[name]-[hash]--[clusterUID]
+	uid := n.UID()
+	prefix := fmt.Sprintf("%s-%s-", n.prefix, backendBucketPrefix)
+	hash := common.ContentHash(strings.Join([]string{uid, namespace, name}, ";"), 8)
+	// Two hyphens separate the namespace, name and hash.
+	maxLength := nameLenLimit - len(prefix) - 2 - len(hash) - len(clusterNameDelimiter) - len(uid)
+	truncFields := TrimFieldsEvenly(maxLength, namespace, name)
+	return n.decorateName(fmt.Sprintf("%s%s-%s-%s", prefix, truncFields[0], truncFields[1], hash))
+}
+
+// IsBackendBucket returns true if the resourceName is the name of a backend
+// bucket of this cluster.
+func (n *Namer) IsBackendBucket(resourceName string) bool {
+	prefix := fmt.Sprintf("%s-%s-", n.prefix, backendBucketPrefix)
+	return strings.HasPrefix(resourceName, prefix) && n.NameBelongsToCluster(resourceName)
+}
+
 // ForwardingRule returns the name of the forwarding rule prefix.
 func (n *Namer) ForwardingRule(lbName LoadBalancerName, protocol NamerProtocol) string {
 	switch protocol {
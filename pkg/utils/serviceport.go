@@ -73,6 +73,10 @@ type ServicePort struct {
 	// Traffic policy fields that apply if non-nil.
 	MaxRatePerEndpoint *float64
 	CapacityScaler     *float64
+	// InternetNEGEnabled is set for ExternalName Services, which are served
+	// by a global INTERNET_FQDN_PORT NEG pointing to ExternalName:Port.
+	InternetNEGEnabled bool
+	ExternalName       string
 }
 
 // GetDescription returns a Description for this ServicePort.
@@ -87,7 +91,7 @@ func (sp *ServicePort) GetDescription() Description {
 func (sp *ServicePort) BackendName() string {
 	if sp.L7XLBRegionalEnabled {
 		return sp.BackendNamer.RXLBBackendName(sp.ID.Service.Namespace, sp.ID.Service.Name, sp.Port)
-	} else if sp.NEGEnabled || sp.VMIPNEGEnabled || sp.L4RBSEnabled {
+	} else if sp.NEGEnabled || sp.InternetNEGEnabled || sp.VMIPNEGEnabled || sp.L4RBSEnabled {
 		// L4 ILB and RBS (with NEGs), Ingress ILB and GXLB are using NEG Name for all backend resources.
 		return sp.NEGName()
 	}
@@ -644,9 +644,41 @@ func TraverseIngressBackends(ing *networkingv1.Ingress, process func(id ServiceP
 			}
 		}
 	}
+
+	// Check the services referenced by the advanced routing annotations.
+	// Parse errors are surfaced during translation, so they are ignored here.
+	var services []networkingv1.IngressServiceBackend
+	ingAnnotations := annotations.FromIngress(ing)
+	routeRules, _ := ingAnnotations.RouteRules()
+	for _, rule := range routeRules {
+		if rule.Service != nil {
+			services = append(services, *rule.Service)
+		}
+		if rule.Action != nil {
+			services = append(services, routeActionServices(*rule.Action)...)
+		}
+	}
+	pathActions, _ := ingAnnotations.PathActions()
+	for _, pathAction := range pathActions {
+		services = append(services, routeActionServices(pathAction.Action)...)
+	}
+	for _, svc := range services {
+		if process(ServicePortID{Service: types.NamespacedName{Namespace: ing.Namespace, Name: svc.Name}, Port: svc.Port}) {
+			return
+		}
+	}
 	return
 }
 
+// routeActionServices returns the Services referenced by a route action.
+func routeActionServices(action annotations.RouteAction) []networkingv1.IngressServiceBackend {
+	var services []networkingv1.IngressServiceBackend
+	for _, wb := range action.WeightedBackends {
+		services = append(services, wb.Service)
+	}
+	return services
+}
+
 func ServiceKeyFunc(namespace, name string) string {
 	return fmt.Sprintf("%s/%s", namespace, name)
 }
@@ -504,6 +504,53 @@ func TestTraverseIngressBackends(t *testing.T) {
 				},
 			},
 		},
+		{
+			"route rules and path actions",
+			&networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						annotations.RouteRulesKey:  `[{"priority":1,"matches":[{"prefixMatch":"/"}],"service":{"name":"route-service","port":{"number":80}}}]`,
+						annotations.PathActionsKey: `[{"path":"/foo","action":{"weightedBackends":[{"service":{"name":"canary-service","port":{"number":8080}},"weight":10}]}}]`,
+					},
+				},
+				Spec: networkingv1.IngressSpec{
+					DefaultBackend: &networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{
+							Name: "foo-service",
+							Port: networkingv1.ServiceBackendPort{
+								Number: 80,
+							},
+						},
+					},
+				},
+			},
+			[]networkingv1.IngressBackend{
+				{
+					Service: &networkingv1.IngressServiceBackend{
+						Name: "foo-service",
+						Port: networkingv1.ServiceBackendPort{
+							Number: 80,
+						},
+					},
+				},
+				{
+					Service: &networkingv1.IngressServiceBackend{
+						Name: "route-service",
+						Port: networkingv1.ServiceBackendPort{
+							Number: 80,
+						},
+					},
+				},
+				{
+					Service: &networkingv1.IngressServiceBackend{
+						Name: "canary-service",
+						Port: networkingv1.ServiceBackendPort{
+							Number: 8080,
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {